@@ -0,0 +1,80 @@
+// Package aiff encodes the chunks of AIFF files and writes complete files.
+// AIFF is an IFF FORM of type "AIFF"; all values are big-endian.
+package aiff
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrShortChunk is returned when a chunk is smaller than its fixed layout.
+var ErrShortChunk = errors.New("aiff: chunk too short")
+
+// Common is the content of a COMM chunk.
+type Common struct {
+	Channels     int16
+	SampleFrames uint32
+	// SampleSize is the number of bits per sample, 1 to 32.
+	SampleSize int16
+	SampleRate float64
+}
+
+// commChunk is the on-disk layout of a COMM chunk.
+type commChunk struct {
+	Channels     int16
+	SampleFrames uint32
+	SampleSize   int16
+	SampleRate   [10]byte
+}
+
+// EncodeComm writes a COMM chunk with the sample rate as an 80-bit extended
+// float.
+func EncodeComm(w *chunk.Writer, c Common) error {
+	return w.WriteBE(commChunk{c.Channels, c.SampleFrames, c.SampleSize, EncodeFloat80(c.SampleRate)})
+}
+
+// DecodeComm reads a COMM chunk.
+func DecodeComm(r *chunk.Reader) (Common, error) {
+	if r.Size < 18 {
+		return Common{}, ErrShortChunk
+	}
+	var c commChunk
+	if err := r.ReadBE(&c); err != nil {
+		return Common{}, err
+	}
+	return Common{c.Channels, c.SampleFrames, c.SampleSize, DecodeFloat80(c.SampleRate)}, nil
+}
+
+// EncodeFloat80 converts f to the big-endian 80-bit IEEE 754 extended
+// format used for AIFF sample rates. Infinities and NaN are not supported.
+func EncodeFloat80(f float64) [10]byte {
+	var b [10]byte
+	if f == 0 {
+		return b
+	}
+	var sign uint16
+	if f < 0 {
+		sign, f = 0x8000, -f
+	}
+	frac, exp := math.Frexp(f) // f = frac * 2^exp, frac in [0.5, 1)
+	binary.BigEndian.PutUint16(b[:], sign|uint16(exp+16382))
+	binary.BigEndian.PutUint64(b[2:], uint64(math.Ldexp(frac, 64)))
+	return b
+}
+
+// DecodeFloat80 converts a big-endian 80-bit IEEE 754 extended float.
+func DecodeFloat80(b [10]byte) float64 {
+	se := binary.BigEndian.Uint16(b[:])
+	mant := binary.BigEndian.Uint64(b[2:])
+	if mant == 0 {
+		return 0
+	}
+	f := math.Ldexp(float64(mant), int(se&0x7fff)-16383-63)
+	if se&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
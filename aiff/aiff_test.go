@@ -0,0 +1,47 @@
+package aiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestFloat80(t *testing.T) {
+	for _, c := range []struct {
+		f    float64
+		want [10]byte
+	}{
+		{44100, [10]byte{0x40, 0x0e, 0xac, 0x44}},
+		{48000, [10]byte{0x40, 0x0e, 0xbb, 0x80}},
+		{8000, [10]byte{0x40, 0x0b, 0xfa}},
+		{0, [10]byte{}},
+		{-1.5, [10]byte{0xbf, 0xff, 0xc0}},
+	} {
+		if got := EncodeFloat80(c.f); got != c.want {
+			t.Fatalf("EncodeFloat80(%v) = % x, want % x", c.f, got, c.want)
+		}
+		if got := DecodeFloat80(c.want); got != c.f {
+			t.Fatalf("DecodeFloat80(% x) = %v, want %v", c.want, got, c.f)
+		}
+	}
+}
+
+func TestEncodeComm(t *testing.T) {
+	var buf bytes.Buffer
+	c := Common{Channels: 2, SampleFrames: 1000, SampleSize: 16, SampleRate: 44100}
+	if err := EncodeComm(&chunk.Writer{W: &buf}, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0, 2, 0, 0, 0x03, 0xe8, 0, 16, 0x40, 0x0e, 0xac, 0x44, 0, 0, 0, 0, 0, 0}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+	}
+	got, err := DecodeComm(&chunk.Reader{Size: buf.Len(), R: &buf})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != c {
+		t.Fatalf("unexpected COMM %+v", got)
+	}
+}
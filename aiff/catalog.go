@@ -0,0 +1,42 @@
+package aiff
+
+import "github.com/CWBudde/chunk"
+
+// Specifications of the chunks in catalog.
+const (
+	specAIFF = "Audio Interchange File Format 1.3"
+	specAIFC = "AIFF-C draft 1991"
+	specIFF  = "EA IFF 85"
+)
+
+// catalog lists the chunks of AIFF and AIFF-C files registered with
+// chunk.RegisterChunk.
+var catalog = []struct{ id, name, spec string }{
+	{"FORM", "IFF form", specIFF},
+	{"LIST", "IFF list", specIFF},
+	{"CAT ", "IFF concatenation", specIFF},
+	{"PROP", "IFF shared properties", specIFF},
+	{"FVER", "format version", specAIFC},
+	{"COMM", "common", specAIFF},
+	{"SSND", "sound data", specAIFF},
+	{"MARK", "markers", specAIFF},
+	{"INST", "instrument", specAIFF},
+	{"COMT", "comments", specAIFF},
+	{"MIDI", "MIDI data", specAIFF},
+	{"AESD", "AES channel status", specAIFF},
+	{"APPL", "application specific", specAIFF},
+	{"NAME", "name", specAIFF},
+	{"AUTH", "author", specAIFF},
+	{"(c) ", "copyright", specAIFF},
+	{"ANNO", "annotation", specAIFF},
+	{"FLLR", "filler", ""},
+	{"ID3 ", "ID3 tag", "ID3v2"},
+}
+
+func init() {
+	for _, c := range catalog {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte(c.id)), Format: "aiff", Name: c.name, Spec: c.spec})
+	}
+	// the spec allows any order, but streaming players need COMM first
+	chunk.RegisterOrder(chunk.OrderRule{Format: "aiff", ID: chunk.FourCC([]byte("COMM")), Before: chunk.FourCC([]byte("SSND"))})
+}
@@ -0,0 +1,30 @@
+package aiff
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/CWBudde/chunk"
+)
+
+func init() {
+	chunk.RegisterDescriber("COMM", describeComm)
+}
+
+// describeComm summarizes a COMM chunk as "44.1kHz 16-bit stereo, 1000
+// frames".
+func describeComm(r *chunk.Reader) (string, error) {
+	c, err := DecodeComm(r)
+	if err != nil {
+		return "", err
+	}
+	channels := fmt.Sprintf("%d channels", c.Channels)
+	switch c.Channels {
+	case 1:
+		channels = "mono"
+	case 2:
+		channels = "stereo"
+	}
+	rate := strconv.FormatFloat(c.SampleRate/1000, 'f', -1, 64)
+	return fmt.Sprintf("%skHz %d-bit %s, %d frames", rate, c.SampleSize, channels, c.SampleFrames), nil
+}
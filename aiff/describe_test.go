@@ -0,0 +1,19 @@
+package aiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestDescribeComm(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeComm(&chunk.Writer{W: &buf}, Common{Channels: 2, SampleFrames: 1000, SampleSize: 16, SampleRate: 44100}); err != nil {
+		t.Fatalf("EncodeComm: %v", err)
+	}
+	r := &chunk.Reader{ID: [4]byte{'C', 'O', 'M', 'M'}, Size: buf.Len(), R: &buf}
+	if got, want := chunk.Describe(r), "44.1kHz 16-bit stereo, 1000 frames"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,38 @@
+package aiff
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+func init() {
+	chunk.RegisterFormat(module{})
+}
+
+// module is the chunk.FormatModule of AIFF and AIFF-C files, registered
+// when the package is imported.
+type module struct{}
+
+func (module) Name() string { return "aiff" }
+
+func (module) Detect(header []byte) bool {
+	return len(header) >= 12 && string(header[:4]) == "FORM" &&
+		(string(header[8:12]) == "AIFF" || string(header[8:12]) == "AIFC")
+}
+
+func (module) NewScanner(r io.Reader) (chunk.ChunkScanner, error) {
+	s, err := container.NewScanner(r)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m module) Describe(header []byte) string {
+	if m.Detect(header) && string(header[8:12]) == "AIFC" {
+		return "AIFF-C audio"
+	}
+	return "AIFF audio"
+}
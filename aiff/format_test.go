@@ -0,0 +1,39 @@
+package aiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestFormatModule(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Common{Channels: 1, SampleSize: 16, SampleRate: 44100})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	w.WriteRaw([]byte{0, 1, 0, 2})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	m, err := chunk.DetectFormat(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name() != "aiff" || m.Describe(buf.Bytes()) != "AIFF audio" {
+		t.Fatalf("unexpected module %s: %s", m.Name(), m.Describe(buf.Bytes()))
+	}
+	s, err := m.NewScanner(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for s.Next() {
+		ids = append(ids, string(s.Chunk().ID[:]))
+	}
+	if s.Err() != nil || len(ids) != 2 || ids[0] != "COMM" || ids[1] != "SSND" {
+		t.Fatalf("unexpected chunks %q (%v)", ids, s.Err())
+	}
+}
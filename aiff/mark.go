@@ -0,0 +1,51 @@
+package aiff
+
+import (
+	"encoding/binary"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Marker is an entry of a MARK chunk, a named position in the sound data.
+type Marker struct {
+	// ID identifies the marker for INST loops; it must be positive and
+	// unique.
+	ID int16 `chunk:"be"`
+	// Position is the sample frame the marker stands before.
+	Position uint32 `chunk:"be"`
+	Name     string `chunk:"pstring"`
+}
+
+// EncodeMark writes a MARK chunk with the markers in the given order. Names
+// longer than 255 bytes fail with chunk.ErrStringTooLong.
+func EncodeMark(w *chunk.Writer, markers []Marker) error {
+	if err := chunk.Write(w, uint16(len(markers)), binary.BigEndian); err != nil {
+		return err
+	}
+	for i := range markers {
+		if err := w.WriteStruct(&markers[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeMark reads the markers of a MARK chunk in stored order.
+func DecodeMark(r *chunk.Reader) ([]Marker, error) {
+	n, err := chunk.Read[uint16](r, binary.BigEndian)
+	if err != nil {
+		return nil, err
+	}
+	if int(n)*8 > r.Size-r.Pos {
+		return nil, ErrShortChunk
+	}
+	markers := make([]Marker, 0, n)
+	for range n {
+		var m Marker
+		if err := r.ReadStruct(&m); err != nil {
+			return nil, err
+		}
+		markers = append(markers, m)
+	}
+	return markers, nil
+}
@@ -0,0 +1,47 @@
+package aiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodeMark(t *testing.T) {
+	in := []Marker{{ID: 1, Position: 0, Name: "Intro"}, {ID: 2, Position: 44100, Name: "Verse"}}
+	var buf bytes.Buffer
+	if err := EncodeMark(&chunk.Writer{W: &buf}, in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{
+		0, 2,
+		0, 1, 0, 0, 0, 0, 5, 'I', 'n', 't', 'r', 'o',
+		0, 2, 0, 0, 0xac, 0x44, 5, 'V', 'e', 'r', 's', 'e',
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("unexpected MARK\n got % x\nwant % x", buf.Bytes(), want)
+	}
+
+	got, err := DecodeMark(&chunk.Reader{ID: [4]byte{'M', 'A', 'R', 'K'}, Size: buf.Len(), R: &buf})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != in[0] || got[1] != in[1] {
+		t.Fatalf("unexpected markers %+v", got)
+	}
+
+	t.Run("pads even names", func(t *testing.T) {
+		var buf bytes.Buffer
+		EncodeMark(&chunk.Writer{W: &buf}, []Marker{{ID: 1, Name: "ab"}})
+		if buf.Len() != 2+6+4 {
+			t.Fatalf("expected 12 bytes, got % x", buf.Bytes())
+		}
+	})
+
+	t.Run("rejects crafted counts", func(t *testing.T) {
+		b := []byte{0xff, 0xff, 0, 1}
+		if _, err := DecodeMark(&chunk.Reader{Size: len(b), R: bytes.NewReader(b)}); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
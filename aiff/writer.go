@@ -0,0 +1,177 @@
+package aiff
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+var (
+	// ErrPartialFrame is returned by WriteFrames when the number of samples
+	// is not a multiple of the channel count.
+	ErrPartialFrame = errors.New("aiff: sample count is not a multiple of the channel count")
+	// ErrSampleType is returned by WriteFrames for unsupported sample
+	// slices or sample sizes.
+	ErrSampleType = errors.New("aiff: unsupported sample type")
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("aiff: writer is closed")
+)
+
+// Writer writes an AIFF file: a COMM chunk followed by the SSND chunk
+// holding the sample frames. The frame count in COMM and all sizes are fixed
+// up on Close, by seeking when w is an io.WriteSeeker and by buffering the
+// file in memory otherwise.
+type Writer struct {
+	cw      *container.Writer
+	common  Common
+	comm    *container.Reservation
+	ssnd    *chunk.Writer
+	width   int
+	buf     []byte
+	markers []Marker
+	closed  bool
+}
+
+// NewWriter writes the header of an AIFF file with the given channel count,
+// sample size and rate to w; c.SampleFrames is ignored.
+func NewWriter(w io.Writer, c Common) (*Writer, error) {
+	if c.Channels <= 0 || c.SampleSize <= 0 || c.SampleSize > 32 {
+		return nil, ErrSampleType
+	}
+	aw := &Writer{
+		cw:     container.NewWriter(w, container.IFF),
+		common: c,
+		width:  (int(c.SampleSize) + 7) / 8,
+	}
+	if err := aw.cw.BeginForm("AIFF"); err != nil {
+		return nil, err
+	}
+	var err error
+	if aw.comm, err = aw.cw.Reserve("COMM", 18); err != nil {
+		return nil, err
+	}
+	if aw.ssnd, err = aw.cw.BeginChunk("SSND"); err != nil {
+		return nil, err
+	}
+	// offset and block size, both unused
+	if _, err := aw.ssnd.Write(make([]byte, 8)); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Frames returns the number of complete frames written so far.
+func (aw *Writer) Frames() int64 {
+	return int64(aw.ssnd.Size-8) / int64(aw.width*int(aw.common.Channels))
+}
+
+// SetMarkers sets the markers written as a MARK chunk after the sound data
+// on Close, replacing earlier ones.
+func (aw *Writer) SetMarkers(markers []Marker) {
+	aw.markers = markers
+}
+
+// WriteRaw writes big-endian sample bytes to the SSND chunk as they are.
+func (aw *Writer) WriteRaw(p []byte) (int, error) {
+	if aw.closed {
+		return 0, ErrClosed
+	}
+	return aw.ssnd.Write(p)
+}
+
+// WriteFrames encodes interleaved samples and writes them to the SSND chunk.
+// It takes []int16 or []int32 holding values in the range of the sample
+// size, or []float32/[]float64 in [-1, 1] which are scaled and clipped.
+func (aw *Writer) WriteFrames(samples any) error {
+	if aw.closed {
+		return ErrClosed
+	}
+	var err error
+	switch s := samples.(type) {
+	case []int16:
+		aw.buf, err = appendInts(aw.buf[:0], s, int(aw.common.SampleSize), int(aw.common.Channels))
+	case []int32:
+		aw.buf, err = appendInts(aw.buf[:0], s, int(aw.common.SampleSize), int(aw.common.Channels))
+	case []float32:
+		aw.buf, err = appendFloats(aw.buf[:0], s, int(aw.common.SampleSize), int(aw.common.Channels))
+	case []float64:
+		aw.buf, err = appendFloats(aw.buf[:0], s, int(aw.common.SampleSize), int(aw.common.Channels))
+	default:
+		return ErrSampleType
+	}
+	if err != nil {
+		return err
+	}
+	_, err = aw.ssnd.Write(aw.buf)
+	return err
+}
+
+// Close ends the SSND chunk, writes the COMM chunk with the final frame
+// count and the MARK chunk, if any, and fixes up the FORM size. It does not close the underlying writer.
+func (aw *Writer) Close() error {
+	if aw.closed {
+		return nil
+	}
+	aw.closed = true
+	if err := aw.cw.EndChunk(); err != nil {
+		return err
+	}
+	c := aw.common
+	c.SampleFrames = uint32(aw.Frames())
+	var comm bytes.Buffer
+	if err := EncodeComm(&chunk.Writer{W: &comm}, c); err != nil {
+		return err
+	}
+	if err := aw.comm.Fill(comm.Bytes()); err != nil {
+		return err
+	}
+	if len(aw.markers) > 0 {
+		ch, err := aw.cw.BeginChunk("MARK")
+		if err != nil {
+			return err
+		}
+		if err := EncodeMark(ch, aw.markers); err != nil {
+			return err
+		}
+		if err := aw.cw.EndChunk(); err != nil {
+			return err
+		}
+	}
+	return aw.cw.Close()
+}
+
+func appendInts[T int16 | int32](dst []byte, s []T, bits, channels int) ([]byte, error) {
+	if len(s)%channels != 0 {
+		return dst, ErrPartialFrame
+	}
+	for _, v := range s {
+		dst = appendInt(dst, int32(v), bits)
+	}
+	return dst, nil
+}
+
+func appendFloats[T float32 | float64](dst []byte, s []T, bits, channels int) ([]byte, error) {
+	if len(s)%channels != 0 {
+		return dst, ErrPartialFrame
+	}
+	limit := float64(int64(1)<<(bits-1)) - 1
+	for _, v := range s {
+		dst = appendInt(dst, int32(math.Round(max(-1, min(1, float64(v)))*limit)), bits)
+	}
+	return dst, nil
+}
+
+// appendInt appends a big-endian two's complement sample of the given bit
+// size, left-justified in whole bytes as AIFF requires.
+func appendInt(dst []byte, v int32, bits int) []byte {
+	width := (bits + 7) / 8
+	u := uint32(v) << (8*width - bits)
+	for i := width - 1; i >= 0; i-- {
+		dst = append(dst, byte(u>>(8*i)))
+	}
+	return dst
+}
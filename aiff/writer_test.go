@@ -0,0 +1,89 @@
+package aiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestWriter(t *testing.T) {
+	t.Run("16-bit stereo", func(t *testing.T) {
+		var buf bytes.Buffer
+		aw, err := NewWriter(&buf, Common{Channels: 2, SampleSize: 16, SampleRate: 44100})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		aw.WriteFrames([]int16{1, -1})
+		aw.WriteFrames([]float64{1, -1})
+		if err := aw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []byte{
+			'F', 'O', 'R', 'M', 0, 0, 0, 54, 'A', 'I', 'F', 'F',
+			'C', 'O', 'M', 'M', 0, 0, 0, 18,
+			0, 2, 0, 0, 0, 2, 0, 16, 0x40, 0x0e, 0xac, 0x44, 0, 0, 0, 0, 0, 0,
+			'S', 'S', 'N', 'D', 0, 0, 0, 16, 0, 0, 0, 0, 0, 0, 0, 0,
+			0, 1, 0xff, 0xff, 0x7f, 0xff, 0x80, 0x01,
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+
+		s, err := container.NewScanner(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.Next()
+		c, err := DecodeComm(s.Chunk())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.SampleFrames != 2 || c.SampleRate != 44100 {
+			t.Fatalf("unexpected COMM %+v", c)
+		}
+	})
+
+	t.Run("left-justifies short samples", func(t *testing.T) {
+		var buf bytes.Buffer
+		aw, _ := NewWriter(&buf, Common{Channels: 1, SampleSize: 12, SampleRate: 8000})
+		aw.WriteFrames([]int16{-1, 1})
+		aw.Close()
+		got := buf.Bytes()[54:]
+		if !bytes.Equal(got, []byte{0xff, 0xf0, 0x00, 0x10}) {
+			t.Fatalf("unexpected samples % x", got)
+		}
+	})
+
+	t.Run("writes markers after the samples", func(t *testing.T) {
+		var buf bytes.Buffer
+		aw, _ := NewWriter(&buf, Common{Channels: 1, SampleSize: 16, SampleRate: 8000})
+		aw.WriteFrames([]int16{1, 2})
+		aw.SetMarkers([]Marker{{ID: 1, Position: 1, Name: "x"}})
+		if err := aw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s, _ := container.NewScanner(bytes.NewReader(buf.Bytes()))
+		var ids []string
+		for s.Next() {
+			ids = append(ids, string(s.Chunk().ID[:]))
+		}
+		if len(ids) != 3 || ids[2] != "MARK" {
+			t.Fatalf("unexpected chunks %q", ids)
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		if _, err := NewWriter(&bytes.Buffer{}, Common{Channels: 1, SampleSize: 40}); err != ErrSampleType {
+			t.Fatalf("expected ErrSampleType, got %v", err)
+		}
+		aw, _ := NewWriter(&bytes.Buffer{}, Common{Channels: 2, SampleSize: 16, SampleRate: 8000})
+		if err := aw.WriteFrames([]int16{1}); err != ErrPartialFrame {
+			t.Fatalf("expected ErrPartialFrame, got %v", err)
+		}
+		aw.Close()
+		if err := aw.WriteFrames([]int16{1, 2}); err != ErrClosed {
+			t.Fatalf("expected ErrClosed, got %v", err)
+		}
+	})
+}
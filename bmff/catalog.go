@@ -0,0 +1,35 @@
+package bmff
+
+import "github.com/CWBudde/chunk"
+
+// specBMFF is the specification of the boxes in catalog.
+const specBMFF = "ISO/IEC 14496-12"
+
+// catalog lists the common top-level and track boxes registered with
+// chunk.RegisterChunk.
+var catalog = []struct{ id, name string }{
+	{"ftyp", "file type"},
+	{"styp", "segment type"},
+	{"moov", "movie"},
+	{"mvhd", "movie header"},
+	{"trak", "track"},
+	{"tkhd", "track header"},
+	{"mdia", "media"},
+	{"minf", "media information"},
+	{"stbl", "sample table"},
+	{"mdat", "media data"},
+	{"moof", "movie fragment"},
+	{"mfra", "movie fragment random access"},
+	{"sidx", "segment index"},
+	{"udta", "user data"},
+	{"meta", "metadata"},
+	{"free", "free space"},
+	{"skip", "free space"},
+	{"uuid", "user extension"},
+}
+
+func init() {
+	for _, c := range catalog {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte(c.id)), Format: "bmff", Name: c.name, Spec: specBMFF})
+	}
+}
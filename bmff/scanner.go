@@ -0,0 +1,179 @@
+package bmff
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrInvalidSize is returned by Scanner.Next for boxes whose size is
+// smaller than their header.
+var ErrInvalidSize = errors.New("bmff: box size smaller than its header")
+
+func init() {
+	chunk.RegisterFormat(module{})
+}
+
+// Scanner reads consecutive boxes from a stream, such as the top-level
+// boxes of an MP4 file or, through NewScanner(ch), the boxes nested in a
+// box payload. Chunk IDs are the box types; Size excludes the header. A box
+// of size 0 runs to the end of the stream: when r is an io.Seeker its Size
+// is computed, otherwise it is math.MaxInt and reading it ends at io.EOF.
+type Scanner struct {
+	r    io.Reader
+	cur  *chunk.Reader
+	lr   *io.LimitedReader
+	last bool
+	off  int64
+	next int64
+	err  error
+}
+
+// NewScanner returns a Scanner reading boxes from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: r}
+}
+
+// Next advances to the next box, skipping the unread rest of the current
+// one. It returns false at the end of the stream or on error.
+func (s *Scanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	if s.cur != nil {
+		s.cur = nil
+		if _, err := io.Copy(io.Discard, s.lr); err != nil {
+			s.err = err
+			return false
+		}
+		if s.last {
+			return false
+		}
+		if s.lr.N > 0 {
+			s.err = io.ErrUnexpectedEOF
+			return false
+		}
+	}
+
+	var hdr [16]byte
+	if n, err := io.ReadFull(s.r, hdr[:8]); err != nil {
+		if n > 0 || err != io.EOF {
+			s.setErr(err)
+		}
+		return false
+	}
+	header := int64(8)
+	size := int64(binary.BigEndian.Uint32(hdr[:4]))
+	payload := int64(-1)
+	switch size {
+	case 0:
+		s.last = true
+		payload = s.remaining()
+	case 1:
+		if _, err := io.ReadFull(s.r, hdr[8:]); err != nil {
+			s.setErr(err)
+			return false
+		}
+		header = 16
+		large := binary.BigEndian.Uint64(hdr[8:])
+		if large > math.MaxInt64 {
+			s.err = chunk.ErrSizeOverflow
+			return false
+		}
+		size = int64(large)
+	}
+	if payload < 0 {
+		if size < header {
+			s.err = ErrInvalidSize
+			return false
+		}
+		payload = size - header
+	}
+	if payload > math.MaxInt || !s.last && size > math.MaxInt64-s.next {
+		s.err = chunk.ErrSizeOverflow
+		return false
+	}
+	s.lr = &io.LimitedReader{R: s.r, N: payload}
+	s.cur = &chunk.Reader{ID: [4]byte(hdr[4:8]), Size: int(payload), R: s.lr}
+	s.off = s.next
+	if !s.last {
+		s.next += size
+	}
+	return true
+}
+
+// remaining returns the number of bytes left in the stream if it can seek,
+// or math.MaxInt.
+func (s *Scanner) remaining() int64 {
+	sk, ok := s.r.(io.Seeker)
+	if !ok {
+		return math.MaxInt
+	}
+	cur, err := sk.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return math.MaxInt
+	}
+	end, err := sk.Seek(0, io.SeekEnd)
+	if err != nil {
+		return math.MaxInt
+	}
+	if _, err := sk.Seek(cur, io.SeekStart); err != nil {
+		return math.MaxInt
+	}
+	return end - cur
+}
+
+// Chunk returns the current box.
+func (s *Scanner) Chunk() *chunk.Reader {
+	return s.cur
+}
+
+// Offset returns the offset of the current box header relative to where the
+// scanner started reading.
+func (s *Scanner) Offset() int64 {
+	return s.off
+}
+
+// Err returns the first error encountered, or nil at a clean end of stream.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) setErr(err error) {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	s.err = err
+}
+
+// module is the chunk.FormatModule of ISO BMFF files, registered when the
+// package is imported. Files are recognized by a leading ftyp box, or one
+// of the boxes old QuickTime files start with.
+type module struct{}
+
+func (module) Name() string { return "bmff" }
+
+func (module) Detect(header []byte) bool {
+	if len(header) < 8 {
+		return false
+	}
+	switch string(header[4:8]) {
+	case "ftyp", "styp", "moov", "mdat", "free", "skip", "wide":
+		return true
+	}
+	return false
+}
+
+func (module) NewScanner(r io.Reader) (chunk.ChunkScanner, error) {
+	return NewScanner(r), nil
+}
+
+func (module) Describe(header []byte) string {
+	if len(header) >= 12 && string(header[4:8]) == "ftyp" {
+		return "ISO BMFF, brand " + string(header[8:12])
+	}
+	return "ISO BMFF"
+}
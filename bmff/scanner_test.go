@@ -0,0 +1,94 @@
+package bmff
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// testMP4 returns an ftyp box and a moov box holding a udta box.
+func testMP4(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	bw := NewWriter(&buf)
+	ch, _ := bw.Begin("ftyp")
+	ch.Write([]byte("isom\x00\x00\x02\x00"))
+	bw.End()
+	bw.Begin("moov")
+	ch, _ = bw.Begin("udta")
+	ch.Write([]byte("meta"))
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("reads top-level and nested boxes", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(testMP4(t)))
+		if !s.Next() || string(s.Chunk().ID[:]) != "ftyp" || s.Chunk().Size != 8 {
+			t.Fatalf("unexpected first box (%v)", s.Err())
+		}
+		if !s.Next() || string(s.Chunk().ID[:]) != "moov" || s.Offset() != 16 || s.Chunk().Size != 12 {
+			t.Fatalf("unexpected second box (%v)", s.Err())
+		}
+		inner := NewScanner(s.Chunk())
+		if !inner.Next() || string(inner.Chunk().ID[:]) != "udta" || inner.Chunk().Size != 4 {
+			t.Fatalf("unexpected nested box (%v)", inner.Err())
+		}
+		if s.Next() || s.Err() != nil {
+			t.Fatalf("expected a clean end, got %v", s.Err())
+		}
+	})
+
+	t.Run("largesize and size 0", func(t *testing.T) {
+		b := []byte{
+			0, 0, 0, 1, 'f', 'r', 'e', 'e', 0, 0, 0, 0, 0, 0, 0, 18, 'h', 'i',
+			0, 0, 0, 0, 'm', 'd', 'a', 't', 1, 2, 3,
+		}
+		s := NewScanner(bytes.NewReader(b))
+		if !s.Next() || s.Chunk().Size != 2 {
+			t.Fatalf("unexpected largesize box (%v)", s.Err())
+		}
+		if !s.Next() || string(s.Chunk().ID[:]) != "mdat" || s.Chunk().Size != 3 || s.Offset() != 18 {
+			t.Fatalf("unexpected box to the end (%v)", s.Err())
+		}
+		if s.Next() || s.Err() != nil {
+			t.Fatalf("expected a clean end, got %v", s.Err())
+		}
+
+		// MultiReader hides the Seeker
+		s = NewScanner(io.MultiReader(bytes.NewReader(b[18:])))
+		if !s.Next() || s.Chunk().Size != math.MaxInt {
+			t.Fatalf("unexpected size on a stream (%v)", s.Err())
+		}
+		if payload, err := io.ReadAll(s.Chunk()); err != nil || len(payload) != 3 {
+			t.Fatalf("unexpected payload %v (%v)", payload, err)
+		}
+	})
+
+	t.Run("invalid sizes", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader([]byte{0, 0, 0, 4, 'f', 'r', 'e', 'e'}))
+		if s.Next() || !errors.Is(s.Err(), ErrInvalidSize) {
+			t.Fatalf("expected ErrInvalidSize, got %v", s.Err())
+		}
+		s = NewScanner(bytes.NewReader([]byte{0, 0, 0, 12, 'f', 'r', 'e', 'e', 1}))
+		for s.Next() {
+		}
+		if !errors.Is(s.Err(), io.ErrUnexpectedEOF) {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+	})
+
+	t.Run("is a registered format", func(t *testing.T) {
+		b := testMP4(t)
+		m, err := chunk.DetectFormat(bytes.NewReader(b))
+		if err != nil || m.Name() != "bmff" || m.Describe(b) != "ISO BMFF, brand isom" {
+			t.Fatalf("unexpected format %v (%v)", m, err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Package bmff reads and writes ISO base media file format (MP4, MOV, HEIF,
+// ...) boxes. Unlike RIFF chunks, a box size is big-endian, counts its own
+// header and may be widened to a 64-bit largesize; boxes are not padded.
+package bmff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+var (
+	// ErrNoOpenBox is returned by End when no box is open.
+	ErrNoOpenBox = errors.New("bmff: no open box")
+	// ErrBoxTooLarge is returned when a box begun with Begin on a seekable
+	// output outgrows its 32-bit size field; use BeginLarge for such boxes.
+	ErrBoxTooLarge = errors.New("bmff: box exceeds 32-bit size")
+)
+
+// maxSize32 is the largest box size that fits the 32-bit size field. It is
+// a variable so tests can exercise the largesize code paths.
+var maxSize32 int64 = math.MaxUint32
+
+// box is a box that has been begun but not ended yet.
+type box struct {
+	typ [4]byte
+	// large forces a 64-bit largesize header.
+	large bool
+	// start is the absolute offset of the box header when seeking.
+	start int64
+	// buf holds the payload when the output cannot seek.
+	buf *bytes.Buffer
+	ch  *chunk.Writer
+}
+
+// Writer writes nested boxes to an underlying stream. When the stream
+// implements io.WriteSeeker sizes are patched in place once a box is ended,
+// otherwise every open box is buffered in memory until it is ended, which
+// also allows picking the header size automatically.
+type Writer struct {
+	w     io.Writer
+	ws    io.WriteSeeker
+	stack []*box
+}
+
+// NewWriter returns a Writer producing boxes on w.
+func NewWriter(w io.Writer) *Writer {
+	bw := &Writer{w: w}
+	if ws, ok := w.(io.WriteSeeker); ok {
+		if _, err := ws.Seek(0, io.SeekCurrent); err == nil {
+			bw.ws = ws
+		}
+	}
+	return bw
+}
+
+// Begin opens a box of the given type, e.g. "moov" or "ftyp", and returns a
+// chunk Writer for its payload. The type must be four bytes but, as in
+// "\xa9nam", need not be ASCII. Boxes begun before it is ended are nested in
+// it. On buffered outputs the box gets a largesize header if it needs one.
+func (bw *Writer) Begin(typ string) (*chunk.Writer, error) {
+	return bw.begin(typ, false)
+}
+
+// BeginLarge is like Begin but always writes a 16 byte header with a 64-bit
+// largesize, e.g. for an mdat box streamed to a seekable file whose final
+// size is unknown.
+func (bw *Writer) BeginLarge(typ string) (*chunk.Writer, error) {
+	return bw.begin(typ, true)
+}
+
+// End closes the innermost open box and writes its size.
+func (bw *Writer) End() error {
+	if len(bw.stack) == 0 {
+		return ErrNoOpenBox
+	}
+	b := bw.stack[len(bw.stack)-1]
+	bw.stack = bw.stack[:len(bw.stack)-1]
+	if b.buf != nil {
+		return bw.flush(b)
+	}
+	return bw.patch(b)
+}
+
+// Close ends every box that is still open.
+func (bw *Writer) Close() error {
+	for len(bw.stack) > 0 {
+		if err := bw.End(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bw *Writer) begin(typ string, large bool) (*chunk.Writer, error) {
+	if len(typ) != 4 {
+		return nil, chunk.ErrInvalidFourCC
+	}
+	b := &box{typ: [4]byte([]byte(typ)), large: large}
+	var err error
+	if bw.ws == nil {
+		b.buf = bytes.NewBuffer(chunk.GetBuffer()[:0])
+		b.ch = &chunk.Writer{ID: b.typ, W: b.buf}
+	} else {
+		if b.start, err = bw.ws.Seek(0, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+		if err := writeHeader(bw.w, b.typ, 0, large); err != nil {
+			return nil, err
+		}
+		b.ch = &chunk.Writer{ID: b.typ, W: bw.w}
+	}
+	bw.stack = append(bw.stack, b)
+	return b.ch, nil
+}
+
+// sink returns where the header of the next box has to be written.
+func (bw *Writer) sink() io.Writer {
+	if n := len(bw.stack); n > 0 {
+		return bw.stack[n-1].ch
+	}
+	return bw.w
+}
+
+func (bw *Writer) flush(b *box) error {
+	payload := int64(b.buf.Len())
+	large := b.large || payload+8 > maxSize32
+	dst := bw.sink()
+	if err := writeHeader(dst, b.typ, payload, large); err != nil {
+		return err
+	}
+	if _, err := b.buf.WriteTo(dst); err != nil {
+		return err
+	}
+	chunk.PutBuffer(b.buf.Bytes())
+	b.buf = nil
+	return nil
+}
+
+func (bw *Writer) patch(b *box) error {
+	end, err := bw.ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	header := int64(8)
+	if b.large {
+		header = 16
+	}
+	payload := end - b.start - header
+	if !b.large && payload+8 > maxSize32 {
+		return ErrBoxTooLarge
+	}
+	if _, err := bw.ws.Seek(b.start, io.SeekStart); err != nil {
+		return err
+	}
+	if err := writeHeader(bw.w, b.typ, payload, b.large); err != nil {
+		return err
+	}
+	if _, err := bw.ws.Seek(end, io.SeekStart); err != nil {
+		return err
+	}
+	// the enclosing box has to account for the whole nested box
+	if n := len(bw.stack); n > 0 {
+		bw.stack[n-1].ch.Size += int(end - b.start)
+	}
+	return nil
+}
+
+// writeHeader writes a box header for a payload of the given size: the
+// 32-bit size and type, or size 1, type and a 64-bit largesize.
+func writeHeader(dst io.Writer, typ [4]byte, payload int64, large bool) error {
+	var hdr [16]byte
+	copy(hdr[4:8], typ[:])
+	if !large {
+		binary.BigEndian.PutUint32(hdr[:], uint32(payload+8))
+		_, err := dst.Write(hdr[:8])
+		return err
+	}
+	binary.BigEndian.PutUint32(hdr[:], 1)
+	binary.BigEndian.PutUint64(hdr[8:], uint64(payload+16))
+	_, err := dst.Write(hdr[:])
+	return err
+}
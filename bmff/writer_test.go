@@ -0,0 +1,141 @@
+package bmff
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// seekBuffer is an in-memory io.WriteSeeker.
+type seekBuffer struct {
+	buf []byte
+	pos int
+}
+
+func (s *seekBuffer) Write(p []byte) (int, error) {
+	if end := s.pos + len(p); end > len(s.buf) {
+		s.buf = append(s.buf, make([]byte, end-len(s.buf))...)
+	}
+	n := copy(s.buf[s.pos:], p)
+	s.pos += n
+	return n, nil
+}
+
+func (s *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(s.pos) + offset
+	case io.SeekEnd:
+		abs = int64(len(s.buf)) + offset
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	s.pos = int(abs)
+	return abs, nil
+}
+
+func writeMovie(t *testing.T, bw *Writer, largeMdat bool) {
+	t.Helper()
+	ch, err := bw.Begin("ftyp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ch.Write([]byte("isom"))
+	bw.End()
+	bw.Begin("moov")
+	ch, _ = bw.Begin("mvhd")
+	ch.Write([]byte{0, 0, 0, 0})
+	bw.End()
+	bw.End()
+	if largeMdat {
+		ch, _ = bw.BeginLarge("mdat")
+	} else {
+		ch, _ = bw.Begin("mdat")
+	}
+	ch.Write([]byte{1, 2})
+	if err := bw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	small := []byte{
+		0, 0, 0, 12, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm',
+		0, 0, 0, 20, 'm', 'o', 'o', 'v',
+		0, 0, 0, 12, 'm', 'v', 'h', 'd', 0, 0, 0, 0,
+		0, 0, 0, 10, 'm', 'd', 'a', 't', 1, 2,
+	}
+	large := append(append([]byte{}, small[:32]...),
+		0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0, 0, 0, 0, 0, 0, 18, 1, 2)
+
+	for _, c := range []struct {
+		name  string
+		large bool
+		want  []byte
+	}{{"32-bit", false, small}, {"forced largesize", true, large}} {
+		t.Run(c.name+" buffered", func(t *testing.T) {
+			var buf bytes.Buffer
+			writeMovie(t, NewWriter(&buf), c.large)
+			if !bytes.Equal(buf.Bytes(), c.want) {
+				t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), c.want)
+			}
+		})
+		t.Run(c.name+" seeking", func(t *testing.T) {
+			sb := &seekBuffer{}
+			writeMovie(t, NewWriter(sb), c.large)
+			if !bytes.Equal(sb.buf, c.want) {
+				t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, c.want)
+			}
+		})
+	}
+
+	t.Run("widens oversized boxes when buffering", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 9
+		var buf bytes.Buffer
+		bw := NewWriter(&buf)
+		ch, _ := bw.Begin("mdat")
+		ch.Write([]byte{1, 2})
+		bw.Close()
+		want := []byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0, 0, 0, 0, 0, 0, 18, 1, 2}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+
+	t.Run("rejects oversized boxes when seeking", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 9
+		bw := NewWriter(&seekBuffer{})
+		ch, _ := bw.Begin("mdat")
+		ch.Write([]byte{1, 2})
+		if err := bw.End(); err != ErrBoxTooLarge {
+			t.Fatalf("expected ErrBoxTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("accepts non-ASCII types", func(t *testing.T) {
+		var buf bytes.Buffer
+		bw := NewWriter(&buf)
+		if _, err := bw.Begin("\xa9nam"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := bw.Begin("name"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := bw.Begin("nam"); err == nil {
+			t.Fatal("expected error for three byte type")
+		}
+	})
+
+	t.Run("End without box", func(t *testing.T) {
+		if err := NewWriter(&bytes.Buffer{}).End(); err != ErrNoOpenBox {
+			t.Fatalf("expected ErrNoOpenBox, got %v", err)
+		}
+	})
+}
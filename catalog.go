@@ -0,0 +1,90 @@
+package chunk
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ChunkInfo describes a chunk ID defined by a specification.
+type ChunkInfo struct {
+	ID FourCC `json:"id"`
+	// Format is the name of the format defining the chunk, as returned by
+	// FormatModule.Name, e.g. "wav".
+	Format string `json:"format"`
+	// Name is what the chunk holds, e.g. "broadcast audio extension".
+	Name string `json:"name"`
+	// Spec names the specification defining the chunk, e.g. "EBU Tech
+	// 3285".
+	Spec string `json:"spec,omitempty"`
+}
+
+var catalog struct {
+	sync.RWMutex
+	chunks []ChunkInfo
+}
+
+// RegisterChunk adds c to the catalog of known chunks, replacing an earlier
+// entry for the same format and ID. The format packages register the chunks
+// they know when imported; Describe, Validate and the chunk command look
+// them up to flag IDs that are unknown but resemble a known one.
+func RegisterChunk(c ChunkInfo) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	for i, old := range catalog.chunks {
+		if old.Format == c.Format && old.ID == c.ID {
+			catalog.chunks[i] = c
+			return
+		}
+	}
+	catalog.chunks = append(catalog.chunks, c)
+}
+
+// Catalog returns the known chunks of the given format, or of all formats
+// if format is "", sorted by format and ID.
+func Catalog(format string) []ChunkInfo {
+	catalog.RLock()
+	var out []ChunkInfo
+	for _, c := range catalog.chunks {
+		if format == "" || c.Format == format {
+			out = append(out, c)
+		}
+	}
+	catalog.RUnlock()
+	slices.SortFunc(out, func(a, b ChunkInfo) int {
+		if n := strings.Compare(a.Format, b.Format); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return out
+}
+
+// LookupChunk returns the known chunk with the given ID in format, or in
+// any format if format is "".
+func LookupChunk(format string, id FourCC) (ChunkInfo, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	for _, c := range catalog.chunks {
+		if c.ID == id && (format == "" || c.Format == format) {
+			return c, true
+		}
+	}
+	return ChunkInfo{}, false
+}
+
+// Resembles reports whether id is unknown in format ("" meaning any) but
+// differs from a known chunk ID only in NUL padding or the case of its
+// letters, and returns that chunk. Such IDs are typical of broken encoders,
+// e.g. "Data" for "data".
+func Resembles(format string, id FourCC) (ChunkInfo, bool) {
+	if _, ok := LookupChunk(format, id); ok {
+		return ChunkInfo{}, false
+	}
+	for _, c := range Catalog(format) {
+		if c.ID.EqualFold(id) {
+			return c, true
+		}
+	}
+	return ChunkInfo{}, false
+}
@@ -0,0 +1,36 @@
+package chunk
+
+import "testing"
+
+func TestCatalog(t *testing.T) {
+	RegisterChunk(ChunkInfo{ID: FourCC{'t', 'e', 's', 't'}, Format: "cat-test", Name: "first"})
+	RegisterChunk(ChunkInfo{ID: FourCC{'a', 'b', 'c', 'd'}, Format: "cat-test", Name: "letters", Spec: "none"})
+	RegisterChunk(ChunkInfo{ID: FourCC{'t', 'e', 's', 't'}, Format: "cat-test", Name: "test chunk"})
+
+	t.Run("lists a format sorted by ID", func(t *testing.T) {
+		got := Catalog("cat-test")
+		if len(got) != 2 || got[0].ID.String() != "abcd" || got[1].Name != "test chunk" {
+			t.Fatalf("unexpected catalog %+v", got)
+		}
+	})
+
+	t.Run("looks up IDs", func(t *testing.T) {
+		if c, ok := LookupChunk("", FourCC{'a', 'b', 'c', 'd'}); !ok || c.Spec != "none" {
+			t.Fatalf("expected abcd, got %+v, %v", c, ok)
+		}
+		if _, ok := LookupChunk("other", FourCC{'a', 'b', 'c', 'd'}); ok {
+			t.Fatal("expected no abcd in another format")
+		}
+	})
+
+	t.Run("finds resembling IDs", func(t *testing.T) {
+		if c, ok := Resembles("cat-test", FourCC{'T', 'e', 's', 't'}); !ok || c.Name != "test chunk" {
+			t.Fatalf("expected Test to resemble test, got %+v, %v", c, ok)
+		}
+		for _, id := range []FourCC{{'t', 'e', 's', 't'}, {'t', 'e', 's', 'x'}} {
+			if c, ok := Resembles("cat-test", id); ok {
+				t.Fatalf("%q: unexpected resemblance to %+v", id.String(), c)
+			}
+		}
+	})
+}
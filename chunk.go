@@ -1,11 +1,17 @@
 package chunk
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"io"
 )
 
+// ErrUnread is returned by Reader.Close in strict mode for chunks whose
+// payload was not read to the end.
+var ErrUnread = errors.New("chunk closed before its payload was read")
+
 // Reader is a struct representing a data chunk. Its reader is shared with the
 // container but convenience methods are provided.
 type Reader struct {
@@ -13,16 +19,51 @@ type Reader struct {
 	Size int
 	R    io.Reader
 	Pos  int
+	// Context, if set, is checked between the slices in which Done and Jump
+	// skip the payload, so skipping a huge chunk can be canceled; its error
+	// is returned.
+	Context context.Context
+	// Progress, if set, is called as Done and Jump skip the payload with the
+	// bytes skipped so far by that call and the total to skip.
+	Progress func(skipped, total int64)
+	// StrictClose makes Close fail with ErrUnread instead of skipping the
+	// unread rest of the payload, for handlers that must consume it all.
+	StrictClose bool
+
+	scratch [8]byte
+	closed  bool
+	// verify, if set, is run once the payload has been consumed, see
+	// VerifyCRC.
+	verify func() error
 }
 
 // Done makes sure the entire Reader was read.
 func (ch *Reader) Done() error {
 	if !ch.IsFullyRead() {
-		return ch.drain()
+		if err := ch.drain(); err != nil {
+			return err
+		}
+	}
+	if ch != nil && ch.verify != nil {
+		return ch.verify()
 	}
 	return nil
 }
 
+// Close finishes the Reader like Done, so chunks can be handled with defer
+// like files; with StrictClose an unread rest is an error instead. Closing
+// a Reader again does nothing.
+func (ch *Reader) Close() error {
+	if ch == nil || ch.closed {
+		return nil
+	}
+	ch.closed = true
+	if ch.StrictClose && !ch.IsFullyRead() {
+		return ErrUnread
+	}
+	return ch.Done()
+}
+
 // Read implements the io.Reader interface.
 func (ch *Reader) Read(p []byte) (n int, err error) {
 	if ch == nil || ch.R == nil {
@@ -53,6 +94,22 @@ func (ch *Reader) ReadByte() (byte, error) {
 	return b, err
 }
 
+// Peek returns the next n bytes of the Reader without advancing it. Fewer
+// bytes and an error are returned if the underlying reader ends early.
+func (ch *Reader) Peek(n int) ([]byte, error) {
+	if ch == nil || ch.R == nil {
+		return nil, errors.New("nil Reader/reader pointer")
+	}
+	if remaining := ch.Size - ch.Pos; n > remaining {
+		n = remaining
+	}
+	n = max(n, 0)
+	buf := make([]byte, n)
+	m, err := io.ReadFull(ch.R, buf)
+	ch.R = io.MultiReader(bytes.NewReader(buf[:m]), ch.R)
+	return buf[:m], err
+}
+
 // IsFullyRead checks if we're finished reading the Reader
 func (ch *Reader) IsFullyRead() bool {
 	if ch == nil || ch.R == nil {
@@ -61,12 +118,12 @@ func (ch *Reader) IsFullyRead() bool {
 	return ch.Size <= ch.Pos
 }
 
-// Jump jumps ahead in the Reader
+// Jump jumps ahead in the Reader, honoring Context and Progress.
 func (ch *Reader) Jump(bytesAhead int) error {
 	var err error
 	var n int64
 	if bytesAhead > 0 {
-		n, err = io.CopyN(io.Discard, ch.R, int64(bytesAhead))
+		n, err = discard(ch.Context, ch.R, int64(bytesAhead), ch.Progress)
 		ch.Pos += int(n)
 	}
 	return err
@@ -90,7 +147,8 @@ func (ch *Reader) readWithByteOrder(dst any, byteOrder binary.ByteOrder) error {
 func (ch *Reader) drain() error {
 	bytesAhead := ch.Size - ch.Pos
 	if bytesAhead > 0 {
-		_, err := io.CopyN(io.Discard, ch.R, int64(bytesAhead))
+		n, err := discard(ch.Context, ch.R, int64(bytesAhead), ch.Progress)
+		ch.Pos += int(n)
 		return err
 	}
 	return nil
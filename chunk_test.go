@@ -2,6 +2,7 @@ package chunk
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"io"
 	"testing"
@@ -295,6 +296,36 @@ func TestReader_Jump(t *testing.T) {
 		}
 	})
 
+	t.Run("reports progress in bounded slices", func(t *testing.T) {
+		data := make([]byte, 3*DefaultBufferSize+10)
+		var seen []int64
+		r := &Reader{
+			Size: len(data),
+			R:    bytes.NewReader(data),
+			Progress: func(skipped, total int64) {
+				seen = append(seen, skipped)
+			},
+		}
+		if err := r.Jump(len(data)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(seen) != 4 || seen[0] != DefaultBufferSize || seen[3] != int64(len(data)) {
+			t.Fatalf("unexpected progress %v", seen)
+		}
+	})
+
+	t.Run("returns the context error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r := &Reader{Size: 10, R: bytes.NewReader(make([]byte, 10)), Context: ctx}
+		if err := r.Jump(5); err != context.Canceled {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if err := r.Done(); err != context.Canceled || r.Pos != 0 {
+			t.Fatalf("expected context.Canceled at Pos 0, got %v at %d", err, r.Pos)
+		}
+	})
+
 	t.Run("jump zero does nothing", func(t *testing.T) {
 		r := &Reader{
 			Size: 5,
@@ -379,6 +410,42 @@ func TestReader_Done(t *testing.T) {
 	})
 }
 
+func TestReader_Close(t *testing.T) {
+	t.Run("drains remaining data", func(t *testing.T) {
+		r := &Reader{Size: 5, R: bytes.NewReader([]byte("hello"))}
+		r.ReadByte()
+		if err := r.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Pos != 5 {
+			t.Fatalf("expected position 5, got %d", r.Pos)
+		}
+	})
+
+	t.Run("strict mode rejects an unread rest", func(t *testing.T) {
+		r := &Reader{Size: 5, R: bytes.NewReader([]byte("hello")), StrictClose: true}
+		r.ReadByte()
+		if err := r.Close(); err != ErrUnread {
+			t.Fatalf("expected ErrUnread, got %v", err)
+		}
+		if r.Pos != 1 {
+			t.Fatalf("expected position 1, got %d", r.Pos)
+		}
+	})
+
+	t.Run("closing again does nothing", func(t *testing.T) {
+		r := &Reader{Size: 5, R: bytes.NewReader([]byte("hello")), StrictClose: true}
+		r.Close()
+		if err := r.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var nilReader *Reader
+		if err := nilReader.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
 func TestReader_readWithByteOrder(t *testing.T) {
 	t.Run("returns error for nil reader pointer", func(t *testing.T) {
 		var r *Reader
@@ -567,3 +634,38 @@ func TestReader_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestReader_Peek(t *testing.T) {
+	t.Run("does not advance", func(t *testing.T) {
+		r := &Reader{Size: 4, R: bytes.NewReader([]byte("INFOrest"))}
+		b, err := r.Peek(4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(b) != "INFO" {
+			t.Fatalf("expected INFO, got %q", b)
+		}
+		if r.Pos != 0 {
+			t.Fatalf("expected Pos=0, got %d", r.Pos)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(r, buf); err != nil || string(buf) != "INFO" {
+			t.Fatalf("expected INFO after peek, got %q, %v", buf, err)
+		}
+	})
+
+	t.Run("is limited to the chunk size", func(t *testing.T) {
+		r := &Reader{Size: 2, R: bytes.NewReader([]byte("abcd"))}
+		b, err := r.Peek(4)
+		if err != nil || string(b) != "ab" {
+			t.Fatalf("expected 'ab', got %q, %v", b, err)
+		}
+	})
+
+	t.Run("nil reader returns error", func(t *testing.T) {
+		var r *Reader
+		if _, err := r.Peek(1); err == nil {
+			t.Fatal("expected error for nil Reader pointer")
+		}
+	})
+}
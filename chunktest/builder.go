@@ -0,0 +1,219 @@
+// Package chunktest builds container fixtures for tests, so tests of code
+// built on the chunk packages need not assemble binary data by hand:
+//
+//	wav := chunktest.RIFF("WAVE").
+//		Chunk("fmt ", uint16(1), uint16(2), uint32(48000), uint32(192000), uint16(4), uint16(16)).
+//		List("INFO").
+//		Chunk("INAM", "Take 1").
+//		End().
+//		Chunk("data", make([]byte, 64)).
+//		Bytes()
+//
+// Sizes and pad bytes are filled in when the fixture is built, in the byte
+// order of the container. IDs are not validated, so fixtures can hold the
+// odd chunks real files contain.
+package chunktest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// Builder assembles a container. Its methods return the Builder itself so
+// calls can be chained; misuse such as an End without an open group panics,
+// as it is a bug in the test.
+type Builder struct {
+	format container.Format
+	root   *node
+	// open holds the groups being built, the root first.
+	open []*node
+}
+
+// node is a chunk of the fixture, or raw bytes if id is empty.
+type node struct {
+	id, typ  string
+	group    bool
+	payload  []byte
+	children []*node
+}
+
+// RIFF starts a little-endian RIFF container of the given form type.
+func RIFF(typ string) *Builder { return newBuilder(container.RIFF, "RIFF", typ) }
+
+// RIFX starts a big-endian RIFX container.
+func RIFX(typ string) *Builder { return newBuilder(container.RIFX, "RIFX", typ) }
+
+// RF64 starts a RF64 container. Its ds64 chunk is written first and holds
+// the sizes of the container and of the first top-level data chunk, whose
+// size fields are set to 0xFFFFFFFF as in large files.
+func RF64(typ string) *Builder { return newBuilder(container.RF64, "RF64", typ) }
+
+// BW64 starts a BW64 container, see RF64.
+func BW64(typ string) *Builder { return newBuilder(container.BW64, "BW64", typ) }
+
+// FORM starts a big-endian IFF FORM of the given type, e.g. "AIFF".
+func FORM(typ string) *Builder { return newBuilder(container.IFF, "FORM", typ) }
+
+func newBuilder(format container.Format, id, typ string) *Builder {
+	root := &node{id: id, typ: typ, group: true}
+	return &Builder{format: format, root: root, open: []*node{root}}
+}
+
+// Chunk adds a chunk whose payload is the concatenation of values: []byte
+// and strings as they are, anything else encoded with binary.Write in the
+// byte order of the container.
+func (b *Builder) Chunk(id string, values ...any) *Builder {
+	b.add(&node{id: id, payload: b.encode(values)})
+	return b
+}
+
+// List opens a LIST group of the given type; chunks added until the
+// matching End go into it.
+func (b *Builder) List(typ string) *Builder {
+	return b.Group("LIST", typ)
+}
+
+// Group opens a group with the given ID and type, such as a nested FORM.
+func (b *Builder) Group(id, typ string) *Builder {
+	n := &node{id: id, typ: typ, group: true}
+	b.add(n)
+	b.open = append(b.open, n)
+	return b
+}
+
+// End closes the innermost group opened by List or Group.
+func (b *Builder) End() *Builder {
+	if len(b.open) == 1 {
+		panic("chunktest: End without an open group")
+	}
+	b.open = b.open[:len(b.open)-1]
+	return b
+}
+
+// Raw adds bytes as they are, without a chunk header or padding, e.g. to
+// build a file with a missing pad byte or trailing garbage.
+func (b *Builder) Raw(values ...any) *Builder {
+	b.add(&node{payload: b.encode(values)})
+	return b
+}
+
+// Bytes returns the encoded container. Groups left open are closed.
+func (b *Builder) Bytes() []byte {
+	var buf bytes.Buffer
+	order := b.format.ByteOrder()
+	if b.format != container.RF64 && b.format != container.BW64 {
+		b.root.write(&buf, order)
+		return buf.Bytes()
+	}
+
+	// RF64 sizes go into a ds64 chunk written before the other chunks
+	var dataSize int64
+	for _, c := range b.root.children {
+		if c.id == "data" {
+			dataSize = c.size()
+			break
+		}
+	}
+	ds := &node{id: "ds64", payload: make([]byte, 28)}
+	riffSize := b.root.size() + ds.size() + 8
+	binary.LittleEndian.PutUint64(ds.payload[0:], uint64(riffSize))
+	binary.LittleEndian.PutUint64(ds.payload[8:], uint64(dataSize))
+	buf.WriteString(b.root.id)
+	writeUint32(&buf, order, math.MaxUint32)
+	buf.WriteString(b.root.typ)
+	ds.write(&buf, order)
+	marked := false
+	for _, c := range b.root.children {
+		if c.id == "data" && !marked {
+			marked = true
+			c.writeSized(&buf, order, math.MaxUint32)
+			continue
+		}
+		c.write(&buf, order)
+	}
+	return buf.Bytes()
+}
+
+// Reader returns the encoded container as a *bytes.Reader, which is an
+// io.Reader, io.ReaderAt and io.Seeker.
+func (b *Builder) Reader() *bytes.Reader {
+	return bytes.NewReader(b.Bytes())
+}
+
+func (b *Builder) add(n *node) {
+	parent := b.open[len(b.open)-1]
+	parent.children = append(parent.children, n)
+}
+
+func (b *Builder) encode(values []any) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		switch v := v.(type) {
+		case []byte:
+			buf.Write(v)
+		case string:
+			buf.WriteString(v)
+		default:
+			if err := binary.Write(&buf, b.format.ByteOrder(), v); err != nil {
+				panic(fmt.Sprintf("chunktest: cannot encode %T: %v", v, err))
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// size returns the payload size of n: its type and children for groups.
+func (n *node) size() int64 {
+	if !n.group {
+		return int64(len(n.payload))
+	}
+	size := int64(len(n.typ))
+	for _, c := range n.children {
+		size += c.encodedSize()
+	}
+	return size
+}
+
+// encodedSize returns the number of bytes n takes in its parent.
+func (n *node) encodedSize() int64 {
+	if n.id == "" {
+		return int64(len(n.payload))
+	}
+	size := n.size()
+	return 8 + size + size%2
+}
+
+func (n *node) write(buf *bytes.Buffer, order binary.ByteOrder) {
+	n.writeSized(buf, order, uint32(n.size()))
+}
+
+// writeSized writes n with the given value in its size field.
+func (n *node) writeSized(buf *bytes.Buffer, order binary.ByteOrder, size uint32) {
+	if n.id == "" {
+		buf.Write(n.payload)
+		return
+	}
+	buf.WriteString(n.id)
+	writeUint32(buf, order, size)
+	if n.group {
+		buf.WriteString(n.typ)
+		for _, c := range n.children {
+			c.write(buf, order)
+		}
+	} else {
+		buf.Write(n.payload)
+	}
+	if n.size()%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+func writeUint32(buf *bytes.Buffer, order binary.ByteOrder, v uint32) {
+	var b [4]byte
+	order.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
@@ -0,0 +1,85 @@
+package chunktest
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("matches container.Writer", func(t *testing.T) {
+		var want bytes.Buffer
+		cw := container.NewWriter(&want, container.RIFF)
+		cw.BeginForm("WAVE")
+		ch, _ := cw.BeginChunk("fmt ")
+		ch.Write([]byte{1, 0, 2, 0})
+		cw.EndChunk()
+		cw.BeginList("INFO")
+		ch, _ = cw.BeginChunk("INAM")
+		ch.Write([]byte("odd"))
+		cw.EndChunk()
+		cw.EndChunk()
+		ch, _ = cw.BeginChunk("data")
+		ch.Write([]byte("samples"))
+		if err := cw.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		got := RIFF("WAVE").
+			Chunk("fmt ", uint16(1), uint16(2)).
+			List("INFO").
+			Chunk("INAM", "odd").
+			End().
+			Chunk("data", []byte("samples")).
+			Bytes()
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Fatalf("expected\n% x, got\n% x", want.Bytes(), got)
+		}
+	})
+
+	t.Run("big-endian", func(t *testing.T) {
+		got := FORM("AIFF").Chunk("COMM", int16(2)).Bytes()
+		want := []byte("FORM\x00\x00\x00\x0eAIFFCOMM\x00\x00\x00\x02\x00\x02")
+		if !bytes.Equal(got, want) {
+			t.Fatalf("expected % x, got % x", want, got)
+		}
+	})
+
+	t.Run("RF64", func(t *testing.T) {
+		s, err := container.NewScanner(BW64("WAVE").Chunk("fmt ", "format").Chunk("data", "abc").Reader())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.Header.Format != container.BW64 || s.DS64 == nil || s.DS64.DataSize != 3 || s.Header.Size != 66 {
+			t.Fatalf("unexpected header %+v %+v", s.Header, s.DS64)
+		}
+		var ids []string
+		for s.Next() {
+			ids = append(ids, string(s.Chunk().ID[:]))
+			if b, _ := io.ReadAll(s.Chunk()); ids[len(ids)-1] == "data" && string(b) != "abc" {
+				t.Fatalf("unexpected data %q", b)
+			}
+		}
+		if s.Err() != nil || len(ids) != 2 {
+			t.Fatalf("unexpected chunks %q (%v)", ids, s.Err())
+		}
+	})
+
+	t.Run("raw bytes", func(t *testing.T) {
+		got := RIFF("WAVE").Chunk("odd ", "x").Raw("junk").Bytes()
+		if want := "RIFF\x12\x00\x00\x00WAVEodd \x01\x00\x00\x00x\x00junk"; string(got) != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("End without a group", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+		RIFF("WAVE").End()
+	})
+}
@@ -0,0 +1,132 @@
+package chunktest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// contextBytes is the number of payload bytes shown around a difference.
+const contextBytes = 16
+
+// AssertEqualContainers reports an error through t unless the containers in
+// want and got are byte for byte the same. The message lists the chunks
+// that were added, removed or changed, with sizes and the bytes around the
+// first difference of each changed payload, rather than a single offset into
+// the file. Differences outside the payloads, such as chunk order or pad
+// bytes, are reported with the first differing file offset.
+func AssertEqualContainers(t testing.TB, want, got io.ReaderAt) {
+	t.Helper()
+	if msg := compareContainers(want, got); msg != "" {
+		t.Error(msg)
+	}
+}
+
+// compareContainers returns a description of the differences between want
+// and got, or "" if they are equal.
+func compareContainers(want, got io.ReaderAt) string {
+	at, err := firstDifference(section(want), section(got))
+	if err != nil {
+		return fmt.Sprintf("containers cannot be compared: %v", err)
+	}
+	if at < 0 {
+		return ""
+	}
+	changes, err := container.Diff(want, got)
+	if err != nil {
+		return fmt.Sprintf("containers differ at offset %d and cannot be parsed: %v", at, err)
+	}
+	var b strings.Builder
+	b.WriteString("containers differ:")
+	for _, c := range changes {
+		b.WriteString("\n  ")
+		switch {
+		case c.Path == "":
+			fmt.Fprintf(&b, "outermost group %s %s, want %s %s", c.B.ID, c.B.Type, c.A.ID, c.A.Type)
+		case c.Kind == container.Added:
+			fmt.Fprintf(&b, "unexpected %s at %d (%d bytes)", c.Path, c.B.Offset, c.B.Size)
+		case c.Kind == container.Removed:
+			fmt.Fprintf(&b, "missing %s (%d bytes)", c.Path, c.A.Size)
+		default:
+			describeChange(&b, c)
+		}
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(&b, "\n  no chunk payload differs, but the files do at offset %d (chunk order, pad bytes or sizes)", at)
+	}
+	return b.String()
+}
+
+// describeChange writes the sizes of a changed chunk and both payloads
+// around their first difference.
+func describeChange(b *strings.Builder, c container.Change) {
+	fmt.Fprintf(b, "%s", c.Path)
+	if c.A.Size != c.B.Size {
+		fmt.Fprintf(b, ": size %d, want %d", c.B.Size, c.A.Size)
+	}
+	at, err := firstDifference(c.A.Payload(), c.B.Payload())
+	if err != nil {
+		fmt.Fprintf(b, ": %v", err)
+		return
+	}
+	if at < 0 {
+		return
+	}
+	start := at - at%contextBytes
+	fmt.Fprintf(b, ": first difference at payload offset %d\n", at)
+	fmt.Fprintf(b, "    want %s\n", window(c.A, start))
+	fmt.Fprintf(b, "     got %s", window(c.B, start))
+}
+
+// window formats up to contextBytes payload bytes of n from start, with
+// their file offset.
+func window(n *container.Node, start int64) string {
+	buf := make([]byte, contextBytes)
+	r := n.Payload()
+	k := 0
+	if _, err := r.Seek(start, io.SeekStart); err == nil {
+		k, _ = io.ReadFull(r, buf)
+	}
+	if k == 0 {
+		return fmt.Sprintf("%08x  (end of payload)", n.Offset+8+start)
+	}
+	return fmt.Sprintf("%08x  % x", n.Offset+8+start, buf[:k])
+}
+
+// firstDifference returns the offset of the first byte in which a and b
+// differ, counting a shorter stream as differing at its end, or -1.
+func firstDifference(a, b io.Reader) (int64, error) {
+	bufA := make([]byte, 32<<10)
+	bufB := make([]byte, 32<<10)
+	var off int64
+	for {
+		na, errA := io.ReadFull(a, bufA)
+		nb, errB := io.ReadFull(b, bufB)
+		for i := range min(na, nb) {
+			if bufA[i] != bufB[i] {
+				return off + int64(i), nil
+			}
+		}
+		if na != nb {
+			return off + int64(min(na, nb)), nil
+		}
+		off += int64(na)
+		endA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		endB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		switch {
+		case errA != nil && !endA:
+			return 0, errA
+		case errB != nil && !endB:
+			return 0, errB
+		case endA || endB:
+			return -1, nil
+		}
+	}
+}
+
+func section(ra io.ReaderAt) io.Reader {
+	return io.NewSectionReader(ra, 0, 1<<62)
+}
@@ -0,0 +1,76 @@
+package chunktest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recorder captures the errors reported by an assertion.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestAssertEqualContainers(t *testing.T) {
+	fixture := func(name, data string) *bytes.Reader {
+		return RIFF("WAVE").
+			Chunk("fmt ", "format").
+			List("INFO").Chunk("INAM", name).End().
+			Chunk("data", data).
+			Reader()
+	}
+	assert := func(want, got *bytes.Reader) []string {
+		r := &recorder{TB: t}
+		AssertEqualContainers(r, want, got)
+		return r.errors
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		if errs := assert(fixture("odd", "samples"), fixture("odd", "samples")); len(errs) != 0 {
+			t.Fatalf("unexpected errors %q", errs)
+		}
+	})
+
+	t.Run("changed chunks", func(t *testing.T) {
+		errs := assert(fixture("odd", "samples"), fixture("even", "sAmples"))
+		if len(errs) != 1 {
+			t.Fatalf("expected one error, got %q", errs)
+		}
+		want := "containers differ:\n" +
+			"  LIST:INFO/INAM: size 4, want 3: first difference at payload offset 0\n" +
+			"    want 0000002e  6f 64 64\n" +
+			"     got 0000002e  65 76 65 6e\n" +
+			"  data: first difference at payload offset 1\n" +
+			"    want 0000003a  73 61 6d 70 6c 65 73\n" +
+			"     got 0000003a  73 41 6d 70 6c 65 73"
+		if errs[0] != want {
+			t.Fatalf("expected\n%s\ngot\n%s", want, errs[0])
+		}
+	})
+
+	t.Run("added and removed chunks", func(t *testing.T) {
+		want := RIFF("WAVE").Chunk("fmt ", "x").Chunk("JUNK", "12").Reader()
+		got := RIFF("WAVE").Chunk("fmt ", "x").Chunk("bext", "1234").Reader()
+		errs := assert(want, got)
+		if len(errs) != 1 || !strings.Contains(errs[0], "\n  missing JUNK (2 bytes)") || !strings.Contains(errs[0], "\n  unexpected bext at 22 (4 bytes)") {
+			t.Fatalf("unexpected errors %q", errs)
+		}
+	})
+
+	t.Run("same payloads in another order", func(t *testing.T) {
+		want := RIFF("WAVE").Chunk("fmt ", "x").Chunk("data", "y").Reader()
+		got := RIFF("WAVE").Chunk("data", "y").Chunk("fmt ", "x").Reader()
+		errs := assert(want, got)
+		if len(errs) != 1 || !strings.Contains(errs[0], "no chunk payload differs, but the files do at offset 12") {
+			t.Fatalf("unexpected errors %q", errs)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package chunktest
+
+import "github.com/CWBudde/chunk/container"
+
+// CorpusOptions configures Builder.Corpus.
+type CorpusOptions struct {
+	// MaxChunk is the payload size of the largest chunks generated, 64 KiB
+	// if zero.
+	MaxChunk int
+	// Depth is the nesting of the deepest group in the nested variant,
+	// counting the outermost one; container.MaxDepth if zero, the deepest
+	// nesting ParseTree accepts.
+	Depth int
+}
+
+// Corpus returns variations of the container built by b that are
+// structurally valid but unusual, to seed fuzzers of decoders built on the
+// chunk packages:
+//
+//	for _, seed := range fixture.Corpus(chunktest.CorpusOptions{}) {
+//		f.Add(seed)
+//	}
+//
+// Besides the container itself there are variants with each chunk emptied,
+// cut or grown to an odd size and grown to MaxChunk bytes, one with the
+// chunks nested in groups up to Depth levels deep and one with empty groups
+// and zero-size chunks around them. Variants that come out the same are
+// listed once; b is not modified.
+func (b *Builder) Corpus(opts CorpusOptions) [][]byte {
+	if opts.MaxChunk <= 0 {
+		opts.MaxChunk = 64 << 10
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = container.MaxDepth
+	}
+	var corpus [][]byte
+	seen := make(map[string]bool)
+	add := func(v *Builder) {
+		seed := v.Bytes()
+		if !seen[string(seed)] {
+			seen[string(seed)] = true
+			corpus = append(corpus, seed)
+		}
+	}
+	add(b)
+	for i := range len(b.leaves()) {
+		for _, resize := range []func([]byte) []byte{
+			func([]byte) []byte { return nil },
+			oddSize,
+			func(p []byte) []byte { return grow(p, opts.MaxChunk) },
+		} {
+			v := b.clone()
+			leaf := v.leaves()[i]
+			leaf.payload = resize(leaf.payload)
+			add(v)
+		}
+	}
+
+	deep := b.clone()
+	inner := deep.root
+	children := inner.children
+	for range opts.Depth - b.root.depth() {
+		n := &node{id: "LIST", typ: "NEST", group: true}
+		inner.children = []*node{n}
+		inner = n
+	}
+	inner.children = children
+	add(deep)
+
+	sparse := b.clone()
+	empty := func() []*node {
+		return []*node{{id: "LIST", typ: "NONE", group: true}, {id: "JUNK"}}
+	}
+	sparse.root.children = append(append(empty(), sparse.root.children...), empty()...)
+	add(sparse)
+	return corpus
+}
+
+// leaves returns the chunks of b that are not groups, in file order.
+func (b *Builder) leaves() []*node {
+	var leaves []*node
+	var walk func(n *node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			switch {
+			case c.group:
+				walk(c)
+			case c.id != "":
+				leaves = append(leaves, c)
+			}
+		}
+	}
+	walk(b.root)
+	return leaves
+}
+
+// depth returns the nesting of the deepest group in n, counting n.
+func (n *node) depth() int {
+	d := 0
+	for _, c := range n.children {
+		if c.group {
+			d = max(d, c.depth())
+		}
+	}
+	return d + 1
+}
+
+// clone returns a deep copy of b with all groups closed.
+func (b *Builder) clone() *Builder {
+	root := b.root.clone()
+	return &Builder{format: b.format, root: root, open: []*node{root}}
+}
+
+func (n *node) clone() *node {
+	c := *n
+	c.payload = append([]byte(nil), n.payload...)
+	c.children = make([]*node, len(n.children))
+	for i, child := range n.children {
+		c.children[i] = child.clone()
+	}
+	return &c
+}
+
+// oddSize cuts or extends p to an odd length.
+func oddSize(p []byte) []byte {
+	switch {
+	case len(p)%2 == 1:
+		return append(p, 0, 0)
+	case len(p) > 0:
+		return p[:len(p)-1]
+	}
+	return []byte{0}
+}
+
+// grow extends p with zeros to size bytes.
+func grow(p []byte, size int) []byte {
+	g := make([]byte, max(size, len(p)))
+	copy(g, p)
+	return g
+}
@@ -0,0 +1,52 @@
+package chunktest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestCorpus(t *testing.T) {
+	for _, b := range []*Builder{
+		RIFF("WAVE").Chunk("fmt ", "format").List("INFO").Chunk("INAM", "odd").End().Chunk("data", "samples"),
+		FORM("AIFF").Chunk("COMM", make([]byte, 18)).Chunk("SSND", make([]byte, 8)),
+		RF64("WAVE").Chunk("data", ""),
+	} {
+		base := b.Bytes()
+		corpus := b.Corpus(CorpusOptions{MaxChunk: 1000})
+		if want := 1 + 3*len(b.leaves()) + 2; len(corpus) < want-1 || len(corpus) > want {
+			t.Fatalf("expected %d seeds, got %d", want, len(corpus))
+		}
+		if !bytes.Equal(b.Bytes(), base) {
+			t.Fatal("Corpus modified the builder")
+		}
+		for i, seed := range corpus {
+			findings, err := container.Validate(bytes.NewReader(seed), int64(len(seed)), container.ValidateOptions{})
+			if err != nil || len(findings) != 0 {
+				t.Fatalf("seed %d is not well-formed: %v %v", i, findings, err)
+			}
+			for _, other := range corpus[:i] {
+				if bytes.Equal(seed, other) {
+					t.Fatalf("seed %d is listed twice", i)
+				}
+			}
+		}
+	}
+
+	t.Run("nesting", func(t *testing.T) {
+		corpus := RIFF("WAVE").Chunk("data", "x").Corpus(CorpusOptions{})
+		deep := corpus[len(corpus)-2]
+		tree, err := container.ParseTree(bytes.NewReader(deep), container.TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		depth := 0
+		for n := tree.Root; len(n.Children) > 0; n = n.Children[0] {
+			depth++
+		}
+		if depth != container.MaxDepth {
+			t.Fatalf("expected the data chunk %d levels deep, got %d", container.MaxDepth, depth)
+		}
+	})
+}
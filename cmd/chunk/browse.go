@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// stdin is where browse reads keys from; tests replace it.
+var stdin io.Reader = os.Stdin
+
+// Keys understood by the browser; arrow keys are mapped to them.
+const (
+	keyUp    = 'k'
+	keyDown  = 'j'
+	keyOpen  = 'l'
+	keyBack  = 'h'
+	keyQuit  = 'q'
+	keyEnter = '\r'
+	keyCtrlC = 0x03
+)
+
+// browse navigates the chunk tree of a container interactively.
+func browse(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("browse", "file", stderr)
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	t, err := container.ParseTree(f, container.TreeOptions{})
+	if err != nil {
+		return err
+	}
+	b := newBrowser(pos[0], t)
+	if in, ok := stdin.(*os.File); ok {
+		if restore, err := makeRaw(in); err == nil {
+			defer restore()
+		}
+		if h := termHeight(in); h > 0 {
+			b.height = h
+		}
+	}
+	return b.run(bufio.NewReader(stdin), stdout)
+}
+
+// browser is the state of browse: the groups opened from the root down,
+// the selected child of the innermost one and, while a chunk is shown, that
+// chunk and the first line in view.
+type browser struct {
+	title  string
+	path   []*container.Node
+	sel    []int
+	view   *container.Node
+	top    int
+	height int
+}
+
+// newBrowser returns a browser of t on a 24 line screen, with the children
+// of the root listed.
+func newBrowser(title string, t *container.Tree) *browser {
+	return &browser{title: title, path: []*container.Node{t.Root}, sel: []int{0}, height: 24}
+}
+
+// run renders the browser and handles keys read from r until q, Ctrl-C or
+// the end of input.
+func (b *browser) run(r *bufio.Reader, w io.Writer) error {
+	for {
+		if err := b.render(w); err != nil {
+			return err
+		}
+		key, err := readKey(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if key == keyQuit || key == keyCtrlC {
+			return nil
+		}
+		b.handle(key)
+	}
+}
+
+// readKey reads a key, translating the escape sequences of arrow keys.
+func readKey(r *bufio.Reader) (byte, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil || c != 0x1b {
+			return c, err
+		}
+		if c, err = r.ReadByte(); err != nil || c != '[' {
+			continue
+		}
+		if c, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		switch c {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			return keyOpen, nil
+		case 'D':
+			return keyBack, nil
+		}
+	}
+}
+
+// group returns the innermost open group and the index of its selected
+// child.
+func (b *browser) group() (*container.Node, *int) {
+	return b.path[len(b.path)-1], &b.sel[len(b.sel)-1]
+}
+
+func (b *browser) handle(key byte) {
+	if b.view != nil {
+		switch key {
+		case keyUp:
+			b.top = max(b.top-1, 0)
+		case keyDown:
+			b.top = min(b.top+1, max(b.viewLines()-b.rows(), 0))
+		case keyBack, 0x7f:
+			b.view = nil
+		}
+		return
+	}
+	g, sel := b.group()
+	switch key {
+	case keyUp:
+		*sel = max(*sel-1, 0)
+	case keyDown:
+		*sel = min(*sel+1, max(len(g.Children)-1, 0))
+	case keyOpen, keyEnter:
+		if len(g.Children) == 0 {
+			return
+		}
+		if c := g.Children[*sel]; c.IsGroup() {
+			b.path = append(b.path, c)
+			b.sel = append(b.sel, 0)
+		} else {
+			b.view, b.top = c, 0
+		}
+	case keyBack, 0x7f:
+		if len(b.path) > 1 {
+			b.path = b.path[:len(b.path)-1]
+			b.sel = b.sel[:len(b.sel)-1]
+		}
+	}
+}
+
+// rows returns the number of lines between the title and the key help.
+func (b *browser) rows() int {
+	return max(b.height-3, 1)
+}
+
+// viewLines returns the number of lines of the shown chunk: its
+// description, if known, and one per 16 payload bytes.
+func (b *browser) viewLines() int {
+	return len(b.viewHeader()) + int((b.view.Size+15)/16)
+}
+
+func (b *browser) viewHeader() []string {
+	if d := chunk.Describe(b.view.Chunk()); d != "" {
+		return []string{d, ""}
+	}
+	return nil
+}
+
+// render draws the screen: the title with the path of the open group, its
+// children or the shown chunk, and the keys.
+func (b *browser) render(w io.Writer) error {
+	var names []string
+	for _, n := range b.path {
+		names = append(names, nodeName(n))
+	}
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&buf, "%s: %s\n", b.title, strings.Join(names, " / "))
+	if b.view != nil {
+		if err := b.renderChunk(&buf); err != nil {
+			return err
+		}
+		buf.WriteString("j/k scroll  h back  q quit\n")
+	} else {
+		b.renderGroup(&buf)
+		buf.WriteString("j/k move  l open  h back  q quit\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// renderGroup lists the children of the open group around the selection.
+func (b *browser) renderGroup(buf *bytes.Buffer) {
+	g, sel := b.group()
+	fmt.Fprintf(buf, "  %10s %10s  %s\n", "OFFSET", "SIZE", "CHUNK")
+	rows := b.rows() - 1
+	start := max(*sel-rows+1, 0)
+	for i := start; i < len(g.Children) && i < start+rows; i++ {
+		c := g.Children[i]
+		mark := ' '
+		if i == *sel {
+			mark = '>'
+		}
+		fmt.Fprintf(buf, "%c %10d %10d  %s\n", mark, c.Offset, c.Size, nodeName(c))
+	}
+}
+
+// renderChunk shows the description and a hex dump of the lines of the
+// shown chunk in view. Only the payload bytes on screen are read.
+func (b *browser) renderChunk(buf *bytes.Buffer) error {
+	lines := b.viewHeader()
+	fmt.Fprintf(buf, "%s at %d, %d bytes\n", b.view.ID, b.view.Offset, b.view.Size)
+	rows := b.rows() - 1
+	first := b.top
+	for ; first < len(lines) && rows > 0; first++ {
+		buf.WriteString(lines[first] + "\n")
+		rows--
+	}
+	if rows == 0 {
+		return nil
+	}
+	start := int64(first-len(lines)) * 16
+	r := b.view.Payload()
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	return hexDump(buf, io.LimitReader(r, int64(rows)*16), b.view.Offset+8+start)
+}
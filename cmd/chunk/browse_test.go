@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// browseKeys runs browse on path with keys as input and returns the screens
+// it drew.
+func browseKeys(t *testing.T, path, keys string) []string {
+	t.Helper()
+	old := stdin
+	stdin = strings.NewReader(keys)
+	defer func() { stdin = old }()
+	code, stdout, stderr := runCmd("browse", path)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	return strings.Split(stdout, "\x1b[H\x1b[2J")[1:]
+}
+
+func TestBrowse(t *testing.T) {
+	path := writeTestFile(t)
+
+	t.Run("lists the root", func(t *testing.T) {
+		screens := browseKeys(t, path, "q")
+		want := "" +
+			path + ": RIFF WAVE\n" +
+			"      OFFSET       SIZE  CHUNK\n" +
+			">         12          6  fmt \n" +
+			"          26         16  LIST INFO\n" +
+			"          50          7  data\n" +
+			"j/k move  l open  h back  q quit\n"
+		if len(screens) != 1 || screens[0] != want {
+			t.Fatalf("unexpected screens %q\nwant %q", screens, want)
+		}
+	})
+
+	t.Run("opens groups and chunks", func(t *testing.T) {
+		// the arrow down key, then into LIST and INAM
+		screens := browseKeys(t, path, "\x1b[Bl\r")
+		if len(screens) != 4 {
+			t.Fatalf("expected 4 screens, got %d", len(screens))
+		}
+		if !strings.Contains(screens[2], `RIFF WAVE / LIST INFO`) || !strings.Contains(screens[2], `>         38          3  INAM: "odd"`) {
+			t.Fatalf("unexpected group screen\n%s", screens[2])
+		}
+		want := "" +
+			"INAM at 38, 3 bytes\n" +
+			"\"odd\"\n" +
+			"\n" +
+			"0000002e  6f 64 64                                          |odd|\n"
+		if !strings.Contains(screens[3], want) {
+			t.Fatalf("unexpected chunk screen\n%s", screens[3])
+		}
+	})
+
+	t.Run("goes back", func(t *testing.T) {
+		screens := browseKeys(t, path, "jlhhhjj")
+		last := screens[len(screens)-1]
+		if !strings.HasPrefix(last, path+": RIFF WAVE\n") || !strings.Contains(last, ">         50          7  data") {
+			t.Fatalf("unexpected screen\n%s", last)
+		}
+	})
+}
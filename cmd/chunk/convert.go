@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+	"github.com/CWBudde/chunk/wav"
+)
+
+var (
+	errConversion = errors.New("unsupported conversion")
+	errSameFile   = errors.New("input and output are the same file")
+)
+
+// convert rewrites a WAVE or AIFF file as WAV, RF64 or AIFF.
+func convert(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", "-to wav|rf64|aiff in out", stderr)
+	to := fs.String("to", "", "target `format`: wav, rf64 or aiff; required")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || *to != "wav" && *to != "rf64" && *to != "aiff" {
+		fs.Usage()
+		return errUsage
+	}
+	// creating the output would truncate the input before it is read
+	if sameFile(pos[0], pos[1]) {
+		return errSameFile
+	}
+	in, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	h, err := container.ReadHeader(in)
+	if err != nil {
+		return err
+	}
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	// chunks that do not carry over are reported, not silently lost
+	warn := func(id chunk.FourCC) {
+		fmt.Fprintf(stderr, "chunk convert: dropped %q chunk\n", id)
+	}
+
+	out, err := os.Create(pos[1])
+	if err != nil {
+		return err
+	}
+	switch typ := h.Type.String(); {
+	case typ == "WAVE" && h.Format != container.RIFX && *to == "aiff":
+		err = wav.ToAIFF(out, in, warn)
+	case typ == "WAVE" && h.Format == container.RIFX && *to == "wav":
+		err = wav.ConvertOrder(out, in, container.RIFF, nil)
+	case typ == "WAVE" && h.Format != container.RIFX && *to == "wav":
+		err = rewrapWAV(out, in, container.RIFF)
+	case typ == "WAVE" && h.Format != container.RIFX && *to == "rf64":
+		err = rewrapWAV(out, in, container.RF64)
+	case typ == "AIFF" && *to != "aiff":
+		format := container.RIFF
+		if *to == "rf64" {
+			format = container.RF64
+		}
+		err = wav.FromAIFF(out, in, format, warn)
+	default:
+		err = errConversion
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(pos[1])
+		return err
+	}
+	return out.Close()
+}
+
+// rewrapWAV copies the chunks of the WAVE file in src to a new container of
+// the given format. The output is a seekable file, so the data chunk is
+// streamed and only its size patched at the end.
+func rewrapWAV(dst io.Writer, src io.Reader, format container.Format) error {
+	s, err := container.NewScanner(src)
+	if err != nil {
+		return err
+	}
+	cw := container.NewWriter(dst, format)
+	cw.ValidateID = nil
+	if err := cw.BeginForm("WAVE"); err != nil {
+		return err
+	}
+	var blockAlign int64
+	for i := 0; s.Next(); i++ {
+		ch := s.Chunk()
+		switch string(ch.ID[:]) {
+		case "JUNK":
+			// the space reserved for a ds64 chunk; the writer reserves its own
+			if i == 0 && (format == container.RF64 || format == container.BW64) {
+				continue
+			}
+		case "fmt ":
+			b, err := io.ReadAll(ch)
+			if err != nil {
+				return err
+			}
+			f, err := wav.DecodeFmt(&chunk.Reader{ID: ch.ID, Size: len(b), R: bytes.NewReader(b)})
+			if err != nil {
+				return err
+			}
+			blockAlign = int64(f.BlockAlign)
+			if err := writeChunk(cw, "fmt ", b); err != nil {
+				return err
+			}
+			continue
+		case "data":
+			if blockAlign > 0 {
+				cw.SetSampleCount(uint64(int64(ch.Size) / blockAlign))
+			}
+		}
+		if err := container.CopyChunk(cw, ch); err != nil {
+			return err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+func writeChunk(cw *container.Writer, id string, payload []byte) error {
+	ch, err := cw.BeginChunk(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ch.Write(payload); err != nil {
+		return err
+	}
+	return cw.EndChunk()
+}
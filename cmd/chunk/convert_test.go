@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/aiff"
+	"github.com/CWBudde/chunk/wav"
+)
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.wav")
+	var buf bytes.Buffer
+	ww, err := wav.NewWriter(&buf, wav.WaveFormat{FormatTag: wav.FormatPCM, Channels: 2, SampleRate: 44100, BitsPerSample: 16})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	ww.WriteFrames([]int16{1, -2, 0x1234, 0x5678})
+	if err := ww.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	os.WriteFile(src, buf.Bytes(), 0o644)
+
+	convertTo := func(t *testing.T, to, in string) string {
+		t.Helper()
+		out := filepath.Join(dir, strings.TrimSuffix(filepath.Base(in), filepath.Ext(in))+"-"+to+"."+to)
+		if code, _, stderr := runCmd("convert", "-to", to, in, out); code != 0 {
+			t.Fatalf("convert -to %s: %d %s", to, code, stderr)
+		}
+		return out
+	}
+
+	t.Run("WAV to AIFF and back", func(t *testing.T) {
+		out := convertTo(t, "aiff", src)
+		code, stdout, _ := runCmd("extract", out, "SSND")
+		if code != 0 || stdout != "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\xff\xfe\x12\x34\x56\x78" {
+			t.Fatalf("unexpected SSND % x", stdout)
+		}
+		_, comm, _ := runCmd("extract", out, "COMM")
+		if c, err := aiff.DecodeComm(&chunk.Reader{Size: len(comm), R: strings.NewReader(comm)}); err != nil || c.SampleFrames != 2 || c.SampleRate != 44100 {
+			t.Fatalf("unexpected COMM %+v (%v)", c, err)
+		}
+
+		back := convertTo(t, "wav", out)
+		b, _ := os.ReadFile(back)
+		if !bytes.Equal(b, buf.Bytes()) {
+			t.Fatalf("round trip differs\n got % x\nwant % x", b, buf.Bytes())
+		}
+	})
+
+	t.Run("WAV to RF64 and back", func(t *testing.T) {
+		out := convertTo(t, "rf64", src)
+		_, tree, _ := runCmd("ls", out)
+		if !strings.Contains(tree, "JUNK") {
+			t.Fatalf("expected the ds64 space to be reserved\n%s", tree)
+		}
+		back := convertTo(t, "wav", out)
+		_, data, _ := runCmd("extract", back, "data")
+		if !bytes.Equal([]byte(data), buf.Bytes()[44:]) {
+			t.Fatalf("unexpected samples % x", data)
+		}
+	})
+
+	t.Run("8-bit AIFF to WAV", func(t *testing.T) {
+		var a bytes.Buffer
+		aw, _ := aiff.NewWriter(&a, aiff.Common{Channels: 1, SampleSize: 8, SampleRate: 8000})
+		aw.WriteRaw([]byte{0x80, 0x00, 0x7f})
+		aw.Close()
+		in := filepath.Join(dir, "eight.aiff")
+		os.WriteFile(in, a.Bytes(), 0o644)
+		_, data, _ := runCmd("extract", convertTo(t, "wav", in), "data")
+		if data != "\x00\x80\xff" {
+			t.Fatalf("unexpected samples % x", data)
+		}
+	})
+
+	t.Run("dropped chunks and errors", func(t *testing.T) {
+		withList := filepath.Join(dir, "list.wav")
+		os.WriteFile(withList, buf.Bytes(), 0o644)
+		if code, _, stderr := runCmd("set", withList, "PEAK", "-from", src); code != 0 {
+			t.Fatalf("set: %s", stderr)
+		}
+		out := filepath.Join(dir, "list.aiff")
+		code, _, stderr := runCmd("convert", "-to", "aiff", withList, out)
+		if code != 0 || stderr != "chunk convert: dropped \"PEAK\" chunk\n" {
+			t.Fatalf("expected a warning, got %d %q", code, stderr)
+		}
+		bad := filepath.Join(dir, "bad.aiff")
+		if code, _, _ := runCmd("convert", "-to", "aiff", out, bad); code != 1 {
+			t.Fatalf("expected AIFF to AIFF to fail, got %d", code)
+		}
+		if _, err := os.Stat(bad); !os.IsNotExist(err) {
+			t.Fatalf("expected the output to be removed, got %v", err)
+		}
+	})
+
+	t.Run("refuses to overwrite the input", func(t *testing.T) {
+		before, _ := os.ReadFile(src)
+		code, _, stderr := runCmd("convert", "-to", "rf64", src, src)
+		if code != 1 || !strings.Contains(stderr, "same file") {
+			t.Fatalf("expected an error, got %d %q", code, stderr)
+		}
+		if after, _ := os.ReadFile(src); !bytes.Equal(after, before) {
+			t.Fatal("input changed")
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// patterns is a repeatable flag collecting chunk paths.
+type patterns []string
+
+func (p *patterns) String() string { return strings.Join(*p, ",") }
+
+func (p *patterns) Set(s string) error {
+	*p = append(*p, s)
+	return nil
+}
+
+// match reports whether one of the patterns matches the chunk path. A
+// pattern without a slash matches any element of the path, such as data or
+// LIST:INFO; a longer one the path or the groups it starts with. Indexes
+// may be left out of patterns.
+func (p patterns) match(path string) bool {
+	elems := strings.Split(path, "/")
+	for _, pat := range p {
+		pe := strings.Split(pat, "/")
+		if len(pe) == 1 {
+			for _, e := range elems {
+				if matchElem(pe[0], e) {
+					return true
+				}
+			}
+			continue
+		}
+		if len(pe) > len(elems) {
+			continue
+		}
+		matched := true
+		for i := range pe {
+			matched = matched && matchElem(pe[i], elems[i])
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchElem reports whether pat names the path element e, with or without
+// its index.
+func matchElem(pat, e string) bool {
+	if pat == e {
+		return true
+	}
+	if i := strings.IndexByte(e, '['); i >= 0 {
+		return pat == e[:i]
+	}
+	return false
+}
+
+// diffEntry is the JSON form of a container.Change.
+type diffEntry struct {
+	Kind container.ChangeKind `json:"kind"`
+	Path string               `json:"path"`
+	A    *diffSide            `json:"a,omitempty"`
+	B    *diffSide            `json:"b,omitempty"`
+}
+
+type diffSide struct {
+	ID     string `json:"id"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+}
+
+func newDiffSide(n *container.Node) *diffSide {
+	if n == nil {
+		return nil
+	}
+	id := n.ID.String()
+	if n.IsGroup() {
+		id += " " + n.Type.String()
+	}
+	return &diffSide{ID: id, Offset: n.Offset, Size: n.Size}
+}
+
+// diff lists the chunks that differ between two containers and exits with
+// status 1 if there are any.
+func diff(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", "[-ignore path]... [-json] a b", stderr)
+	var ignore patterns
+	fs.Var(&ignore, "ignore", "skip chunks matching `path`, e.g. data or LIST:INFO; repeatable")
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	a, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	b, err := os.Open(pos[1])
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	changes, err := container.Diff(a, b)
+	if err != nil {
+		return err
+	}
+
+	entries := []diffEntry{}
+	for _, c := range changes {
+		if c.Path != "" && ignore.match(c.Path) {
+			continue
+		}
+		entries = append(entries, diffEntry{Kind: c.Kind, Path: c.Path, A: newDiffSide(c.A), B: newDiffSide(c.B)})
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return err
+		}
+	} else {
+		for _, e := range entries {
+			printChange(stdout, e)
+		}
+	}
+	if len(entries) > 0 {
+		return errFindings
+	}
+	return nil
+}
+
+func printChange(w io.Writer, e diffEntry) {
+	switch {
+	case e.Path == "":
+		fmt.Fprintf(w, "changed outermost group: %s -> %s\n", e.A.ID, e.B.ID)
+	case e.Kind == container.Added:
+		fmt.Fprintf(w, "added %s: %d bytes\n", e.Path, e.B.Size)
+	case e.Kind == container.Removed:
+		fmt.Fprintf(w, "removed %s: %d bytes\n", e.Path, e.A.Size)
+	case e.A.Size != e.B.Size:
+		fmt.Fprintf(w, "changed %s: %d -> %d bytes\n", e.Path, e.A.Size, e.B.Size)
+	default:
+		fmt.Fprintf(w, "changed %s: %d bytes\n", e.Path, e.A.Size)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+)
+
+// writeFixture writes the container built by b to a temp file named name.
+func writeFixture(t *testing.T, name string, b *chunktest.Builder) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	a := writeFixture(t, "a.wav", chunktest.RIFF("WAVE").
+		Chunk("fmt ", "format").
+		List("INFO").Chunk("INAM", "odd").End().
+		Chunk("data", "samples"))
+	b := writeFixture(t, "b.wav", chunktest.RIFF("WAVE").
+		Chunk("fmt ", "format").
+		List("INFO").Chunk("INAM", "even").End().
+		Chunk("data", "SAMPLES").
+		Chunk("iXML", "<BWFXML/>"))
+
+	t.Run("lists changes", func(t *testing.T) {
+		code, stdout, _ := runCmd("diff", a, b)
+		want := "" +
+			"changed LIST:INFO/INAM: 3 -> 4 bytes\n" +
+			"changed data: 7 bytes\n" +
+			"added iXML: 9 bytes\n"
+		if code != 1 || stdout != want {
+			t.Fatalf("unexpected output (exit code %d)\n%s\nwant\n%s", code, stdout, want)
+		}
+	})
+
+	t.Run("ignores paths", func(t *testing.T) {
+		code, stdout, _ := runCmd("diff", "--ignore", "data", "-ignore", "LIST:INFO", a, b)
+		if code != 1 || stdout != "added iXML: 9 bytes\n" {
+			t.Fatalf("unexpected output (exit code %d)\n%s", code, stdout)
+		}
+		code, stdout, stderr := runCmd("diff", a, b, "-ignore", "data", "-ignore", "LIST:INFO/INAM", "-ignore", "iXML")
+		if code != 0 || stdout != "" {
+			t.Fatalf("unexpected output (exit code %d)\n%s%s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("as JSON", func(t *testing.T) {
+		_, stdout, _ := runCmd("diff", "-json", a, b)
+		var entries []struct {
+			Kind string `json:"kind"`
+			Path string `json:"path"`
+			A    *struct {
+				Offset int64 `json:"offset"`
+			} `json:"a"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil || len(entries) != 3 {
+			t.Fatalf("unexpected JSON %s (%v)", stdout, err)
+		}
+		if e := entries[0]; e.Kind != "changed" || e.Path != "LIST:INFO/INAM" || e.A == nil || e.A.Offset != 38 {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+		if entries[2].A != nil {
+			t.Fatalf("added chunk with an old side")
+		}
+	})
+}
+
+func TestPatterns(t *testing.T) {
+	p := patterns{"data", "LIST:INFO/ICMT", "JUNK[1]"}
+	for path, want := range map[string]bool{
+		"data":           true,
+		"data[2]":        true,
+		"LIST:INFO/ICMT": true,
+		"LIST:INFO/INAM": false,
+		"JUNK":           false,
+		"JUNK[1]":        true,
+		"bext":           false,
+	} {
+		if got := p.match(path); got != want {
+			t.Errorf("match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk"
+)
+
+// dump prints a hex and ASCII dump of a chunk payload.
+func dump(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dump", "[-nth N] [-limit N] [-pretty] file path", stderr)
+	nth := fs.Int("nth", 0, "dump the `N`th matching chunk, counting from 0")
+	limit := fs.Int64("limit", 0, "dump at most `N` bytes; 0 for the whole payload")
+	pretty := fs.Bool("pretty", false, "print text payloads as text, with XML and JSON indented, instead of a hex dump")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || *nth < 0 || *limit < 0 {
+		fs.Usage()
+		return errUsage
+	}
+	path, err := parsePath(pos[1])
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := lookup(f, path, *nth)
+	if err != nil {
+		return err
+	}
+	var r io.Reader = n.Payload()
+	if *limit > 0 {
+		r = io.LimitReader(r, *limit)
+	}
+	if *pretty {
+		if t := chunk.Sniff(n.Chunk()); t.IsText() {
+			return printText(stdout, r, t)
+		}
+	}
+	return hexDump(stdout, r, n.Offset+8)
+}
+
+// printText writes the text read from r, indenting XML and JSON. Documents
+// that fail to parse, for instance because of -limit, are written as is.
+func printText(w io.Writer, r io.Reader, t chunk.ContentType) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	b = bytes.TrimRight(b, "\x00")
+	var out bytes.Buffer
+	switch t {
+	case chunk.XML:
+		err = indentXML(&out, b)
+	case chunk.JSON:
+		err = json.Indent(&out, b, "", "  ")
+	}
+	if t == chunk.Text || err != nil {
+		out.Reset()
+		out.Write(b)
+	}
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+// indentXML writes the XML document b indented by two spaces per level.
+// Names keep their prefixes rather than being resolved to namespaces.
+func indentXML(w io.Writer, b []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			t.Name = rawName(t.Name)
+			for i := range t.Attr {
+				t.Attr[i].Name = rawName(t.Attr[i].Name)
+			}
+			tok = t
+		case xml.EndElement:
+			t.Name = rawName(t.Name)
+			tok = t
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		case xml.ProcInst:
+			// the encoder puts the root element on the declaration line
+			if err := enc.EncodeToken(t); err != nil {
+				return err
+			}
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// rawName folds the prefix of n into its local name.
+func rawName(n xml.Name) xml.Name {
+	if n.Space != "" {
+		n.Local = n.Space + ":" + n.Local
+		n.Space = ""
+	}
+	return n
+}
+
+// hexDump writes the bytes of r in the canonical layout of hexdump -C, 16
+// per line, each line starting with the offset of its first byte, counted
+// from off.
+func hexDump(w io.Writer, r io.Reader, off int64) error {
+	bw := bufio.NewWriter(w)
+	br := bufio.NewReader(r)
+	var line [16]byte
+	for {
+		n, err := io.ReadFull(br, line[:])
+		if n > 0 {
+			fmt.Fprintf(bw, "%08x ", off)
+			for i := range line {
+				if i%8 == 0 {
+					bw.WriteByte(' ')
+				}
+				if i < n {
+					fmt.Fprintf(bw, "%02x ", line[i])
+				} else {
+					bw.WriteString("   ")
+				}
+			}
+			bw.WriteString(" |")
+			for _, c := range line[:n] {
+				if c < 0x20 || c > 0x7e {
+					c = '.'
+				}
+				bw.WriteByte(c)
+			}
+			bw.WriteString("|\n")
+			off += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			bw.Flush()
+			return err
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+)
+
+func TestDump(t *testing.T) {
+	path := writeTestFile(t)
+
+	t.Run("absolute offsets", func(t *testing.T) {
+		code, stdout, stderr := runCmd("dump", path, "LIST:INFO/INAM")
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		want := "0000002e  6f 64 64                                          |odd|\n"
+		if stdout != want {
+			t.Fatalf("expected\n%q, got\n%q", want, stdout)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		code, stdout, _ := runCmd("dump", "-limit", "2", path, "data")
+		if code != 0 || stdout != "0000003a  73 61                                             |sa|\n" {
+			t.Fatalf("unexpected dump %q", stdout)
+		}
+	})
+
+	t.Run("pretty", func(t *testing.T) {
+		path := writeFixture(t, "meta.wav", chunktest.RIFF("WAVE").
+			Chunk("iXML", `<?xml version="1.0"?><BWFXML><ebu:SCENE a="1">12</ebu:SCENE></BWFXML>`+"\x00").
+			Chunk("json", `{"take":[1,2]}`).
+			Chunk("data", "\x00\x01"))
+		for _, tc := range []struct{ path, want string }{
+			{"iXML", "<?xml version=\"1.0\"?>\n<BWFXML>\n  <ebu:SCENE a=\"1\">12</ebu:SCENE>\n</BWFXML>\n"},
+			{"json", "{\n  \"take\": [\n    1,\n    2\n  ]\n}\n"},
+			{"data", "00000078  00 01                                             |..|\n"},
+		} {
+			code, stdout, stderr := runCmd("dump", "-pretty", path, tc.path)
+			if code != 0 || stdout != tc.want {
+				t.Fatalf("%s: expected\n%q, got %d\n%q %s", tc.path, tc.want, code, stdout, stderr)
+			}
+		}
+		if code, stdout, _ := runCmd("dump", "-pretty", "-limit", "8", path, "iXML"); code != 0 || stdout != "<?xml ve\n" {
+			t.Fatalf("expected truncated XML as is, got %d %q", code, stdout)
+		}
+	})
+
+	t.Run("full lines", func(t *testing.T) {
+		var b strings.Builder
+		data := []byte("0123456789abcdef\x00\x01\xff")
+		if err := hexDump(&b, bytes.NewReader(data), 0x100); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "" +
+			"00000100  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|\n" +
+			"00000110  00 01 ff                                          |...|\n"
+		if b.String() != want {
+			t.Fatalf("expected\n%s, got\n%s", want, b.String())
+		}
+	})
+}
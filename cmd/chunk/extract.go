@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// extract writes the payload of a chunk to stdout or a file.
+func extract(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("extract", "[-nth N] [-o out] file path", stderr)
+	nth := fs.Int("nth", 0, "extract the `N`th matching chunk, counting from 0")
+	out := fs.String("o", "", "write the payload to `file` instead of stdout")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || *nth < 0 {
+		fs.Usage()
+		return errUsage
+	}
+	path, err := parsePath(pos[1])
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *out == "" {
+		return copyPayload(stdout, f, path, *nth)
+	}
+	o, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := copyPayload(o, f, path, *nth); err != nil {
+		o.Close()
+		return err
+	}
+	return o.Close()
+}
+
+// copyPayload copies the payload of the nth chunk matching path to dst. A
+// top-level chunk is streamed, reading no further than needed.
+func copyPayload(dst io.Writer, f *os.File, path []pathElem, nth int) error {
+	if len(path) == 1 && path[0].typ == "" && path[0].index < 0 {
+		_, err := container.Extract(f, path[0].id, nth, dst)
+		return err
+	}
+	n, err := lookup(f, path, nth)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(dst, n.Payload())
+	if err == nil && written < n.Size {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
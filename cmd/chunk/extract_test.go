@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	path := writeTestFile(t)
+
+	for _, c := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"top-level chunk", []string{path, "data"}, "samples"},
+		{"nested chunk", []string{path, "LIST:INFO/INAM"}, "odd"},
+		{"group", []string{path, "LIST"}, "INFOINAM\x03\x00\x00\x00odd\x00"},
+		{"flags after arguments", []string{path, "LIST:INFO/INAM", "--nth", "0"}, "odd"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			code, stdout, stderr := runCmd(append([]string{"extract"}, c.args...)...)
+			if code != 0 || stdout != c.want {
+				t.Fatalf("expected %q, got %q (%d: %s)", c.want, stdout, code, stderr)
+			}
+		})
+	}
+
+	t.Run("to a file", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "fmt.bin")
+		if code, _, stderr := runCmd("extract", "-o", out, path, "fmt "); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if b, err := os.ReadFile(out); err != nil || string(b) != "format" {
+			t.Fatalf("unexpected payload %q (%v)", b, err)
+		}
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		for _, args := range [][]string{{path, "data", "-nth", "1"}, {path, "LIST/INAM[1]"}} {
+			code, _, stderr := runCmd(append([]string{"extract"}, args...)...)
+			if code != 1 || !strings.Contains(stderr, "not found") {
+				t.Fatalf("%q: expected not found, got %d %q", args, code, stderr)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// errNoMatch is returned by grep when no file matched, which exits with
+// status 1 as grep does.
+var errNoMatch = errors.New("no match")
+
+// grep searches chunk payloads of many files for bytes or, with -regexp,
+// for a regular expression in text chunks.
+func grep(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("grep", "[-hex | -regexp] pattern file...", stderr)
+	asHex := fs.Bool("hex", false, "the pattern is hex encoded bytes, e.g. a UMID")
+	asRegexp := fs.Bool("regexp", false, "the pattern is a regular expression matched against text chunks")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 || *asHex && *asRegexp {
+		fs.Usage()
+		return errUsage
+	}
+	search, err := searcher(pos[0], *asHex, *asRegexp)
+	if err != nil {
+		return err
+	}
+
+	failed, found := 0, false
+	for _, path := range pos[1:] {
+		matches, err := searchFile(path, search)
+		for _, m := range matches {
+			found = true
+			if m.Text != "" {
+				fmt.Fprintf(stdout, "%s:%s:%d: %s\n", path, m.Path, m.Offset, m.Text)
+			} else {
+				fmt.Fprintf(stdout, "%s:%s:%d\n", path, m.Path, m.Offset)
+			}
+		}
+		// one broken file must not stop a search through thousands
+		if err != nil {
+			failed++
+			fmt.Fprintf(stderr, "chunk grep: %s: %v\n", path, err)
+		}
+	}
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d of %d files could not be searched", failed, len(pos)-1)
+	case !found:
+		return errNoMatch
+	}
+	return nil
+}
+
+// searcher returns the search for pattern.
+func searcher(pattern string, asHex, asRegexp bool) (func(io.ReaderAt) ([]container.SearchMatch, error), error) {
+	switch {
+	case asRegexp:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(ra io.ReaderAt) ([]container.SearchMatch, error) { return container.SearchText(ra, re) }, nil
+	case asHex:
+		b, err := hex.DecodeString(strings.NewReplacer(" ", "", ":", "", "-", "").Replace(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("bad hex pattern: %v", err)
+		}
+		pattern = string(b)
+	}
+	return func(ra io.ReaderAt) ([]container.SearchMatch, error) { return container.Search(ra, []byte(pattern)) }, nil
+}
+
+func searchFile(path string, search func(io.ReaderAt) ([]container.SearchMatch, error)) ([]container.SearchMatch, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return search(f)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+)
+
+func TestGrep(t *testing.T) {
+	path := writeTestFile(t)
+	other := writeFixture(t, "other.wav", chunktest.RIFF("WAVE").
+		Chunk("bext", []byte{0xde, 0xad, 0xbe, 0xef}).
+		List("INFO").Chunk("ICMT", "take 12\x00").End())
+
+	t.Run("finds bytes", func(t *testing.T) {
+		code, stdout, stderr := runCmd("grep", "m", path, other)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if want := path + ":fmt :23\n" + path + ":data:60\n"; stdout != want {
+			t.Fatalf("unexpected output\n%s\nwant\n%s", stdout, want)
+		}
+	})
+
+	t.Run("hex patterns", func(t *testing.T) {
+		_, stdout, _ := runCmd("grep", "-hex", "ad:be", path, other)
+		if want := other + ":bext:21\n"; stdout != want {
+			t.Fatalf("unexpected output\n%s\nwant\n%s", stdout, want)
+		}
+	})
+
+	t.Run("regular expressions", func(t *testing.T) {
+		_, stdout, _ := runCmd("grep", "-regexp", `take \d+|o.d`, path, other)
+		want := path + ":LIST:INFO/INAM:46: odd\n" + other + ":LIST:INFO/ICMT:44: take 12\n"
+		if stdout != want {
+			t.Fatalf("unexpected output\n%s\nwant\n%s", stdout, want)
+		}
+	})
+
+	t.Run("no match and broken files", func(t *testing.T) {
+		if code, stdout, _ := runCmd("grep", "nothing", path); code != 1 || stdout != "" {
+			t.Fatalf("expected exit code 1 without output, got %d %q", code, stdout)
+		}
+		missing := filepath.Join(t.TempDir(), "missing.wav")
+		code, stdout, stderr := runCmd("grep", "odd", missing, path)
+		if code != 1 || stdout != path+":LIST:INFO/INAM:46\n" || !strings.Contains(stderr, "1 of 2 files could not be searched") {
+			t.Fatalf("unexpected result %d %q %q", code, stdout, stderr)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// hash prints the SHA-256 of every chunk payload as a JSON manifest or, with
+// -verify, checks a file against one and exits with status 1 on a mismatch.
+func hash(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("hash", "[-verify manifest.json] file", stderr)
+	verify := fs.String("verify", "", "check the payloads against the manifest `file` written before")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *verify == "" {
+		h, err := container.HashPayloads(f)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(h)
+	}
+	b, err := os.ReadFile(*verify)
+	if err != nil {
+		return err
+	}
+	var h container.Hashes
+	if err := json.Unmarshal(b, &h); err != nil {
+		return fmt.Errorf("%s: %v", *verify, err)
+	}
+	changes, err := h.Verify(f)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		fmt.Fprintln(stdout, c)
+	}
+	if len(changes) > 0 {
+		return errFindings
+	}
+	return nil
+}
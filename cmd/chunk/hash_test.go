@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	path := writeTestFile(t)
+	code, manifest, stderr := runCmd("hash", path)
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var h map[string]string
+	if err := json.Unmarshal([]byte(manifest), &h); err != nil || len(h) != 3 || len(h["data"]) != 64 {
+		t.Fatalf("unexpected manifest %s: %v", manifest, err)
+	}
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("verifies intact files", func(t *testing.T) {
+		if code, stdout, stderr := runCmd("hash", "-verify", manifestPath, path); code != 0 || stdout != "" {
+			t.Fatalf("exit code %d, output %q: %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("reports corrupt chunks", func(t *testing.T) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b[60] ^= 0xff
+		corrupt := filepath.Join(t.TempDir(), "corrupt.wav")
+		if err := os.WriteFile(corrupt, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		code, stdout, _ := runCmd("hash", "-verify", manifestPath, corrupt)
+		if code != 1 || stdout != "changed data\n" {
+			t.Fatalf("exit code %d, output %q", code, stdout)
+		}
+	})
+
+	t.Run("rejects bad manifests", func(t *testing.T) {
+		if code, _, stderr := runCmd("hash", "-verify", path, path); code != 1 || stderr == "" {
+			t.Fatalf("expected an error, got exit code %d", code)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ids lists the known chunks of the registered formats, or those
+// resembling the IDs given as arguments.
+func ids(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("ids", "[-format name] [-json] [id]...", stderr)
+	format := fs.String("format", "", "list only the chunks of the format with this `name`, e.g. wav")
+	asJSON := fs.Bool("json", false, "print the chunks as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	known := chunk.Catalog(*format)
+	if len(pos) > 0 {
+		var found []chunk.ChunkInfo
+		for _, id := range pos {
+			if len(id) != 4 {
+				return fmt.Errorf("bad chunk ID %q", id)
+			}
+			found = append(found, matchIDs(known, chunk.FourCC([]byte(id)))...)
+		}
+		if len(found) == 0 {
+			return errNoMatch
+		}
+		known = found
+	}
+	if *asJSON {
+		if known == nil {
+			known = []chunk.ChunkInfo{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(known)
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, c := range known {
+		fmt.Fprintf(tw, "%s\t%q\t%s\t%s\n", c.Format, c.ID.String(), c.Name, c.Spec)
+	}
+	return tw.Flush()
+}
+
+// matchIDs returns the chunks in known with the given ID or, if there are
+// none, those it resembles.
+func matchIDs(known []chunk.ChunkInfo, id chunk.FourCC) []chunk.ChunkInfo {
+	var exact, similar []chunk.ChunkInfo
+	for _, c := range known {
+		switch {
+		case c.ID == id:
+			exact = append(exact, c)
+		case c.ID.EqualFold(id):
+			similar = append(similar, c)
+		}
+	}
+	if exact != nil {
+		return exact
+	}
+	return similar
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestIDs(t *testing.T) {
+	t.Run("lists a format", func(t *testing.T) {
+		code, stdout, stderr := runCmd("ids", "-format", "wav")
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if !strings.Contains(stdout, `"bext"`) || !strings.Contains(stdout, "EBU Tech 3285") || strings.Contains(stdout, "IHDR") {
+			t.Fatalf("unexpected output\n%s", stdout)
+		}
+	})
+
+	t.Run("resolves resembling IDs", func(t *testing.T) {
+		code, stdout, stderr := runCmd("ids", "-json", "-format", "wav", "Data")
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		var got []chunk.ChunkInfo
+		if err := json.Unmarshal([]byte(stdout), &got); err != nil || len(got) != 1 || got[0].ID.String() != "data" {
+			t.Fatalf("unexpected output %s (%v)", stdout, err)
+		}
+	})
+
+	t.Run("unknown IDs", func(t *testing.T) {
+		if code, stdout, _ := runCmd("ids", "zzzz"); code != 1 || stdout != "" {
+			t.Fatalf("expected exit code 1 without output, got %d %q", code, stdout)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// ls prints the chunk tree of a container, or the top-level chunks of a
+// file in another registered format.
+func ls(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("ls", "[-json | -dot] file", stderr)
+	asJSON := fs.Bool("json", false, "print the tree as JSON")
+	asDOT := fs.Bool("dot", false, "print the tree as a Graphviz graph, boxes sized by chunk size")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 || *asJSON && *asDOT {
+		fs.Usage()
+		return errUsage
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// print what could be parsed before reporting an error
+	t, err := container.ParseTree(f, container.TreeOptions{})
+	if errors.Is(err, container.ErrUnknownFormat) {
+		if *asDOT {
+			return errors.New("-dot needs a RIFF or IFF container")
+		}
+		return lsFormat(stdout, f, *asJSON)
+	}
+	if t != nil {
+		switch {
+		case *asJSON:
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(t); err != nil {
+				return err
+			}
+		case *asDOT:
+			if err := t.WriteDOT(stdout, container.DumpOptions{Describe: true}); err != nil {
+				return err
+			}
+		default:
+			if err := printTree(stdout, t); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+func printTree(w io.Writer, t *container.Tree) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "OFFSET\tSIZE\tDEPTH\tCHUNK\n")
+	var walk func(n *container.Node, depth int)
+	walk = func(n *container.Node, depth int) {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%*s%s\n", n.Offset, n.Size, depth, 2*depth, "", nodeName(n))
+		for _, c := range n.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(t.Root, 0)
+	return tw.Flush()
+}
+
+// nodeName returns the ID of n followed by the type of groups or the
+// description of known chunks.
+func nodeName(n *container.Node) string {
+	name := n.ID.String()
+	switch {
+	case n.IsGroup() && n.Type != ([4]byte{}):
+		name += " " + n.Type.String()
+	case !n.IsGroup():
+		if d := chunk.Describe(n.Chunk()); d != "" {
+			name += ": " + d
+		}
+	}
+	return name
+}
+
+// listing is what ls prints for files in a registered format other than
+// the containers: the top-level chunks without nesting.
+type listing struct {
+	Format      string        `json:"format"`
+	Description string        `json:"description"`
+	Chunks      []listedChunk `json:"chunks"`
+}
+
+type listedChunk struct {
+	ID          chunk.FourCC `json:"id"`
+	Offset      int64        `json:"offset"`
+	Size        int64        `json:"size"`
+	Description string       `json:"description,omitempty"`
+}
+
+// lsFormat lists the chunks of f with the format module recognizing it.
+func lsFormat(w io.Writer, f *os.File, asJSON bool) error {
+	m, err := chunk.DetectFormat(f)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, chunk.DetectSize)
+	n, _ := f.ReadAt(header, 0)
+	l := listing{Format: m.Name(), Description: m.Describe(header[:n])}
+	s, err := m.NewScanner(f)
+	if err != nil {
+		return err
+	}
+	for s.Next() {
+		ch := s.Chunk()
+		l.Chunks = append(l.Chunks, listedChunk{
+			ID:          ch.ID,
+			Offset:      s.Offset(),
+			Size:        int64(ch.Size),
+			Description: chunk.Describe(ch),
+		})
+	}
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(l); err != nil {
+			return err
+		}
+		return s.Err()
+	}
+	fmt.Fprintf(w, "%s (%s)\n", l.Description, l.Format)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "OFFSET\tSIZE\tDEPTH\tCHUNK\n")
+	for _, c := range l.Chunks {
+		name := c.ID.String()
+		if c.Description != "" {
+			name += ": " + c.Description
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\n", c.Offset, c.Size, 0, name)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return s.Err()
+}
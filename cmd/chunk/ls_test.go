@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/png"
+)
+
+func TestLs(t *testing.T) {
+	path := writeTestFile(t)
+
+	t.Run("prints the tree", func(t *testing.T) {
+		code, stdout, stderr := runCmd("ls", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		want := "" +
+			"OFFSET  SIZE  DEPTH  CHUNK\n" +
+			"0       58    0      RIFF WAVE\n" +
+			"12      6     1        fmt \n" +
+			"26      16    1        LIST INFO\n" +
+			"38      3     2          INAM: \"odd\"\n" +
+			"50      7     1        data\n"
+		if stdout != want {
+			t.Fatalf("unexpected output\n%s\nwant\n%s", stdout, want)
+		}
+	})
+
+	t.Run("as JSON", func(t *testing.T) {
+		code, stdout, stderr := runCmd("ls", "-json", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		var tree struct {
+			Nodes int `json:"nodes"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &tree); err != nil || tree.Nodes != 5 {
+			t.Fatalf("unexpected JSON %s (%v)", stdout, err)
+		}
+	})
+
+	t.Run("as DOT", func(t *testing.T) {
+		code, stdout, stderr := runCmd("ls", "-dot", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if !strings.HasPrefix(stdout, "digraph chunks {\n") || !strings.Contains(stdout, `[label="INAM\n3 bytes\n\"odd\""`) {
+			t.Fatalf("unexpected DOT\n%s", stdout)
+		}
+		if code, _, _ := runCmd("ls", "-dot", "-json", path); code != 2 {
+			t.Fatalf("expected usage error, got exit code %d", code)
+		}
+	})
+
+	t.Run("lists other registered formats", func(t *testing.T) {
+		var buf bytes.Buffer
+		png.WriteSignature(&buf)
+		png.WriteChunk(&buf, "tEXt", []byte("Comment\x00hi"))
+		png.WriteChunk(&buf, "IEND", nil)
+		path := filepath.Join(t.TempDir(), "test.png")
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		code, stdout, stderr := runCmd("ls", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		want := "" +
+			"PNG image (png)\n" +
+			"OFFSET  SIZE  DEPTH  CHUNK\n" +
+			"8       10    0      tEXt\n" +
+			"30      0     0      IEND\n"
+		if stdout != want {
+			t.Fatalf("unexpected output\n%s\nwant\n%s", stdout, want)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+// Chunk inspects and edits RIFF, RIFX, RF64, BW64 and IFF containers from the
+// command line:
+//
+//	chunk ls [-json | -dot] file
+//	chunk extract [-nth N] [-o out] file path
+//	chunk set -from payload [-in-place] file id
+//	chunk dump [-nth N] [-limit N] [-pretty] file path
+//	chunk validate file
+//	chunk convert -to wav|rf64|aiff in out
+//	chunk browse file
+//	chunk diff [-ignore path]... [-json] a b
+//	chunk grep [-hex | -regexp] pattern file...
+//	chunk meta [-json | -set metadata.json] [-charset name] file
+//	chunk hash [-verify manifest.json] file
+//	chunk strip [-keep id]... [-o out] file
+//	chunk ids [-format name] [-json] [id]...
+//
+// ls prints the chunk tree with the offset of every header, the payload
+// size, the nesting depth and a summary of known chunks; files in the other
+// registered formats, such as PNG and MP4, get a flat list of their
+// top-level chunks; -dot prints a Graphviz graph of the tree instead.
+// extract writes the payload of a chunk to stdout or out. set replaces the
+// payload of a top-level chunk or appends the chunk, through a transaction
+// or with -in-place without rewriting the file. dump prints a hex and ASCII
+// dump of a chunk payload with absolute offsets, or with -pretty text
+// payloads as text, XML and JSON indented. validate checks sizes, IDs,
+// pad bytes and the chunks WAVE and AIFF files need, or the CRCs and chunk
+// order of PNG streams, prints the findings as JSON and exits with status 1
+// if there are any. convert streams the samples of a WAVE or AIFF file into
+// a new WAV, RF64 or AIFF file. browse navigates the chunk tree in the
+// terminal, showing the summary and a hex dump of the chunk picked. diff lists the chunks added, removed or changed between two
+// files, leaving out those matching an -ignore path, and exits with status 1
+// if there are any. grep prints file, chunk path and offset of every
+// occurrence of a byte pattern in chunk payloads, or of a regular expression
+// in text chunks, and exits with status 1 if there is none. meta prints the
+// INFO, bext, iXML, cue, smpl, plst and ID3 metadata of a file, with -json
+// as one document; -set writes the metadata of such a document to the file.
+// hash prints the SHA-256 of every chunk payload as a JSON manifest; with
+// -verify it lists the chunks that no longer match one and exits with status
+// 1 if there are any. strip removes all chunks but the ones WAVE and AIFF
+// files need and those given with -keep, to scrub metadata before
+// publishing. ids lists the chunk IDs the formats define with their name and
+// specification; given IDs it lists just those, or the known ones an unknown
+// ID resembles, and exits with status 1 if there is none.
+//
+// Paths name a chunk by its ID, groups as ID:TYPE, nested chunks separated
+// by slashes and repeated ones indexed from 0, as in LIST:INFO/INAM or
+// JUNK[1]. Flags may follow the other arguments.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	// formats listed by ls besides the containers
+	_ "github.com/CWBudde/chunk/bmff"
+	_ "github.com/CWBudde/chunk/png"
+)
+
+// errUsage is returned by commands called with bad arguments or -h; their
+// flag set has printed the usage already.
+var errUsage = errors.New("usage")
+
+// command runs a subcommand with the arguments following its name.
+type command func(args []string, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"browse":   browse,
+	"convert":  convert,
+	"diff":     diff,
+	"dump":     dump,
+	"extract":  extract,
+	"grep":     grep,
+	"hash":     hash,
+	"ids":      ids,
+	"ls":       ls,
+	"meta":     meta,
+	"set":      set,
+	"strip":    strip,
+	"validate": validate,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code: 2 for usage
+// errors, 1 for anything else that failed.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || commands[args[0]] == nil {
+		usage(stderr)
+		return 2
+	}
+	err := commands[args[0]](args[1:], stdout, stderr)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case errors.Is(err, errFindings), errors.Is(err, errNoMatch):
+		return 1
+	}
+	fmt.Fprintf(stderr, "chunk %s: %v\n", args[0], err)
+	return 1
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "usage: chunk <command> [arguments]\n\ncommands: %v\n", names)
+}
+
+// parseArgs parses the flags in args, which may come before, between or
+// after the positional arguments, and returns the positional ones.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet returns a flag set printing its usage line and defaults to
+// stderr on errors.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: chunk %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// sameFile reports whether the output path out names the existing file in,
+// through a link or another spelling of the path.
+func sameFile(in, out string) bool {
+	a, err := os.Stat(in)
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(out)
+	return err == nil && os.SameFile(a, b)
+}
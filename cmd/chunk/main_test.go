@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// writeTestFile writes a RIFF WAVE file with a fmt chunk, a LIST/INFO
+// holding an odd-sized INAM and a data chunk, and returns its path.
+func writeTestFile(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	cw := container.NewWriter(&buf, container.RIFF)
+	cw.BeginForm("WAVE")
+	ch, _ := cw.BeginChunk("fmt ")
+	ch.Write([]byte("format"))
+	cw.EndChunk()
+	cw.BeginList("INFO")
+	ch, _ = cw.BeginChunk("INAM")
+	ch.Write([]byte("odd"))
+	cw.EndChunk()
+	cw.EndChunk()
+	ch, _ = cw.BeginChunk("data")
+	ch.Write([]byte("samples"))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "test.wav")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+// runCmd runs the command line and returns its exit code and output.
+func runCmd(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("unknown command", func(t *testing.T) {
+		code, _, stderr := runCmd("frobnicate")
+		if code != 2 || !strings.Contains(stderr, "usage: chunk") {
+			t.Fatalf("expected usage and exit code 2, got %d %q", code, stderr)
+		}
+	})
+
+	t.Run("bad flags", func(t *testing.T) {
+		if code, _, _ := runCmd("ls", "-nope", "x"); code != 2 {
+			t.Fatalf("expected exit code 2, got %d", code)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		code, _, stderr := runCmd("ls", filepath.Join(t.TempDir(), "missing.wav"))
+		if code != 1 || !strings.HasPrefix(stderr, "chunk ls: ") {
+			t.Fatalf("expected exit code 1, got %d %q", code, stderr)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/CWBudde/chunk/wav"
+)
+
+// meta prints the decoded metadata chunks of a file, one field per line or
+// as a JSON document, or with -set writes those of a JSON document to it.
+func meta(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("meta", "[-json | -set metadata.json] [-charset name] file", stderr)
+	asJSON := fs.Bool("json", false, "print the metadata as one JSON document")
+	var charset wav.Charset
+	fs.TextVar(&charset, "charset", wav.AutoCharset, "decode INFO tags as `name`: auto, utf-8, latin-1 or windows-1252")
+	set := fs.String("set", "", "write the metadata of a JSON `document` to the file in place")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 || *asJSON && *set != "" {
+		fs.Usage()
+		return errUsage
+	}
+	if *set != "" {
+		return setMeta(pos[0], *set)
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	m, err := wav.ReadMetadata(f, wav.WithCharset(charset))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+
+	// the lines follow the JSON form, so both name fields alike
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	printFields(tw, "", v)
+	return tw.Flush()
+}
+
+// setMeta applies the metadata of the JSON document at doc to the file at
+// path. Unknown fields are rejected, so typos do not go by unnoticed.
+func setMeta(path, doc string) error {
+	data, err := os.ReadFile(doc)
+	if err != nil {
+		return err
+	}
+	var m wav.Metadata
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return fmt.Errorf("%s: %w", doc, err)
+	}
+	return wav.ApplyMetadataFile(path, m)
+}
+
+// printFields prints a line per value in v, named by its path such as
+// bext.originator or cue[0].sampleOffset.
+func printFields(w io.Writer, name string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if name != "" {
+			name += "."
+		}
+		for _, k := range keys {
+			printFields(w, name+k, v[k])
+		}
+	case []any:
+		for i, e := range v {
+			printFields(w, fmt.Sprintf("%s[%d]", name, i), e)
+		}
+	default:
+		fmt.Fprintf(w, "%s\t%v\n", name, v)
+	}
+}
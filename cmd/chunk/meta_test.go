@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+)
+
+func TestMeta(t *testing.T) {
+	bext := make([]byte, 602)
+	copy(bext, "Take 1")
+	copy(bext[256:], "Recorder")
+	bext[341] = 0x80 // time reference of 2^31 samples
+	path := writeFixture(t, "meta.wav", chunktest.RIFF("WAVE").
+		Chunk("bext", bext).
+		List("INFO").Chunk("INAM", "Song\x00").End().
+		Chunk("data", make([]byte, 4)))
+
+	t.Run("fields", func(t *testing.T) {
+		code, stdout, stderr := runCmd("meta", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		fields := map[string]string{}
+		for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+			name, value, _ := strings.Cut(line, " ")
+			fields[name] = strings.TrimSpace(value)
+		}
+		want := map[string]string{"bext.description": "Take 1", "bext.originator": "Recorder", "bext.timeReference": "2147483648", "info.INAM": "Song"}
+		for name, value := range want {
+			if fields[name] != value {
+				t.Fatalf("expected %s %q in\n%s", name, value, stdout)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		code, stdout, stderr := runCmd("meta", "-json", path)
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		var m struct {
+			Info map[string]string
+			Bext struct{ Originator string }
+		}
+		if err := json.Unmarshal([]byte(stdout), &m); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		if m.Info["INAM"] != "Song" || m.Bext.Originator != "Recorder" {
+			t.Fatalf("unexpected metadata %+v", m)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		dir := t.TempDir()
+		doc := filepath.Join(dir, "meta.json")
+		os.WriteFile(doc, []byte(`{"info":{"INAM":"Take 2"},"bext":{"originator":"Tagger"}}`), 0o644)
+		tagged := writeFixture(t, "tagged.wav", chunktest.RIFF("WAVE").
+			List("INFO").Chunk("INAM", "Song\x00").Chunk("IART", "Band\x00").End().
+			Chunk("data", make([]byte, 4)))
+		if code, _, stderr := runCmd("meta", "-set", doc, tagged); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		_, stdout, _ := runCmd("meta", "-json", tagged)
+		var m struct {
+			Info map[string]string
+			Bext struct{ Originator string }
+		}
+		if err := json.Unmarshal([]byte(stdout), &m); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		if m.Info["INAM"] != "Take 2" || m.Info["IART"] != "Band" || m.Bext.Originator != "Tagger" {
+			t.Fatalf("unexpected metadata %+v", m)
+		}
+
+		os.WriteFile(doc, []byte(`{"titel":"typo"}`), 0o644)
+		if code, _, stderr := runCmd("meta", "-set", doc, tagged); code != 1 || !strings.Contains(stderr, "unknown field") {
+			t.Fatalf("expected unknown field error, got %d: %s", code, stderr)
+		}
+		if code, _, _ := runCmd("meta", "-json", "-set", doc, tagged); code != 2 {
+			t.Fatalf("expected usage error, got %d", code)
+		}
+	})
+
+	t.Run("charset", func(t *testing.T) {
+		legacy := writeFixture(t, "legacy.wav", chunktest.RIFF("WAVE").
+			List("INFO").Chunk("IART", "Bj\xf6rk\x00").End())
+		if _, stdout, _ := runCmd("meta", legacy); !strings.Contains(stdout, "Björk") {
+			t.Fatalf("expected Björk in\n%s", stdout)
+		}
+		if code, _, _ := runCmd("meta", "-charset", "ebcdic", legacy); code != 2 {
+			t.Fatalf("expected usage error, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// pathElem is one element of a chunk path: an ID, the type for groups and
+// the index among the chunks of the same name, -1 for any.
+type pathElem struct {
+	id, typ string
+	index   int
+}
+
+// parsePath splits a path such as LIST:INFO/INAM or JUNK[1] into its
+// elements.
+func parsePath(path string) ([]pathElem, error) {
+	var elems []pathElem
+	for _, s := range strings.Split(path, "/") {
+		e := pathElem{index: -1}
+		if i := strings.IndexByte(s, '['); i >= 0 && strings.HasSuffix(s, "]") {
+			n, err := strconv.Atoi(s[i+1 : len(s)-1])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("bad index in %q", s)
+			}
+			e.index = n
+			s = s[:i]
+		}
+		e.id, e.typ, _ = strings.Cut(s, ":")
+		if len(e.id) != 4 || e.typ != "" && len(e.typ) != 4 {
+			return nil, fmt.Errorf("bad path element %q", s)
+		}
+		elems = append(elems, e)
+	}
+	return elems, nil
+}
+
+// find returns the chunks below n matching path, in file order.
+func find(n *container.Node, path []pathElem) []*container.Node {
+	var found []*container.Node
+	e := path[0]
+	seen := 0
+	for _, c := range n.Children {
+		if c.ID.String() != e.id || e.typ != "" && (!c.IsGroup() || c.Type.String() != e.typ) {
+			continue
+		}
+		seen++
+		if e.index >= 0 && seen-1 != e.index {
+			continue
+		}
+		if len(path) == 1 {
+			found = append(found, c)
+		} else if c.IsGroup() {
+			found = append(found, find(c, path[1:])...)
+		}
+	}
+	return found
+}
+
+// lookup parses the chunk tree of ra and returns the nth chunk matching
+// path.
+func lookup(ra io.ReaderAt, path []pathElem, nth int) (*container.Node, error) {
+	t, err := container.ParseTree(ra, container.TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	found := find(t.Root, path)
+	if nth >= len(found) {
+		return nil, container.ErrChunkNotFound
+	}
+	return found[nth], nil
+}
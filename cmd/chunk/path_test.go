@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	got, err := parsePath("LIST:INFO[2]/INAM")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []pathElem{{"LIST", "INFO", 2}, {"INAM", "", -1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for _, bad := range []string{"", "dat", "LIST:IN", "JUNK[x]", "JUNK[-1]", "LIST/"} {
+		if _, err := parsePath(bad); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
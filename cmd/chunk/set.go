@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// set replaces the payload of a top-level chunk, or appends the chunk if the
+// container has none.
+func set(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("set", "-from payload [-in-place] file id", stderr)
+	from := fs.String("from", "", "read the new payload from `file`; required")
+	inPlace := fs.Bool("in-place", false, "overwrite the chunk without rewriting the file, using adjacent filler chunks; fails if it does not fit")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || *from == "" {
+		fs.Usage()
+		return errUsage
+	}
+	path, id := pos[0], pos[1]
+	if err := chunk.ValidateFourCC(id); err != nil {
+		return err
+	}
+	if *inPlace {
+		payload, err := os.ReadFile(*from)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return err
+		}
+		if err := container.ReplaceInPlace(f, id, payload, container.ConsumeFiller()); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	payload, err := os.Open(*from)
+	if err != nil {
+		return err
+	}
+	defer payload.Close()
+	exists, err := hasChunk(path, id)
+	if err != nil {
+		return err
+	}
+	tx, err := container.Begin(path)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if exists {
+		err = tx.Replace(id, payload)
+	} else {
+		err = tx.Insert(id, payload, container.AtEnd)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// hasChunk reports whether the outermost group of the container at path
+// holds a chunk with the given ID, padded with spaces or NULs, as
+// Tx.Replace finds it.
+func hasChunk(path, id string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	_, entries, err := container.Index(f)
+	if err != nil {
+		return false, err
+	}
+	want := chunk.FourCC([]byte(id))
+	for _, e := range entries {
+		if e.ID.Matches(want) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+)
+
+func TestSet(t *testing.T) {
+	writePayload := func(t *testing.T, b string) string {
+		t.Helper()
+		p := filepath.Join(t.TempDir(), "payload.bin")
+		if err := os.WriteFile(p, []byte(b), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		return p
+	}
+	payloadOf := func(t *testing.T, path, id string) string {
+		t.Helper()
+		code, stdout, stderr := runCmd("extract", path, id)
+		if code != 0 {
+			t.Fatalf("extract %s: %d %s", id, code, stderr)
+		}
+		return stdout
+	}
+
+	t.Run("replaces a chunk", func(t *testing.T) {
+		path := writeTestFile(t)
+		if code, _, stderr := runCmd("set", path, "data", "-from", writePayload(t, "new samples")); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if got := payloadOf(t, path, "data"); got != "new samples" {
+			t.Fatalf("unexpected payload %q", got)
+		}
+		if got := payloadOf(t, path, "LIST:INFO/INAM"); got != "odd" {
+			t.Fatalf("other chunks changed: %q", got)
+		}
+	})
+
+	t.Run("appends a missing chunk", func(t *testing.T) {
+		path := writeTestFile(t)
+		if code, _, stderr := runCmd("set", "-from", writePayload(t, "xml"), path, "iXML"); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		_, stdout, _ := runCmd("ls", path)
+		if got := payloadOf(t, path, "iXML"); got != "xml" || !bytes.Contains([]byte(stdout), []byte("66      3     1        iXML")) {
+			t.Fatalf("unexpected result %q\n%s", got, stdout)
+		}
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		path := writeTestFile(t)
+		before, _ := os.ReadFile(path)
+		if code, _, stderr := runCmd("set", "-in-place", "-from", writePayload(t, "SAMPLES"), path, "data"); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		after, _ := os.ReadFile(path)
+		if !bytes.Equal(after, bytes.Replace(before, []byte("samples"), []byte("SAMPLES"), 1)) {
+			t.Fatalf("unexpected file % x", after)
+		}
+		if code, _, _ := runCmd("set", "-in-place", "-from", writePayload(t, "too many samples"), path, "data"); code != 1 {
+			t.Fatalf("expected a payload that does not fit to fail, got %d", code)
+		}
+	})
+
+	t.Run("replaces a NUL-padded chunk", func(t *testing.T) {
+		path := writeFixture(t, "a.wav", chunktest.RIFF("WAVE").Chunk("fmt\x00", "format").Chunk("data", "samples"))
+		if code, _, stderr := runCmd("set", "-from", writePayload(t, "FORMAT"), path, "fmt "); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		want := chunktest.RIFF("WAVE").Chunk("fmt\x00", "FORMAT").Chunk("data", "samples").Bytes()
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+			t.Fatalf("unexpected file % x", got)
+		}
+	})
+
+	t.Run("requires a payload", func(t *testing.T) {
+		if code, _, _ := runCmd("set", writeTestFile(t), "data"); code != 2 {
+			t.Fatalf("expected exit code 2, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+// strip removes every chunk a player does not need from a container, in
+// place or into a new file.
+func strip(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("strip", "[-keep id]... [-o out] file", stderr)
+	var keep patterns
+	fs.Var(&keep, "keep", "also keep chunks with this `id`, or groups given as ID:TYPE such as LIST:INFO; repeatable")
+	out := fs.String("o", "", "write the stripped file to `out` instead of replacing file; naming file itself strips it in place")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	// creating out would truncate the input before it is read
+	if *out == "" || sameFile(pos[0], *out) {
+		return container.StripMetadataFile(pos[0], keep...)
+	}
+	in, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := container.StripMetadata(f, in, keep...); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	return f.Close()
+}
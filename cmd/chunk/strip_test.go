@@ -0,0 +1,41 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	t.Run("into a new file", func(t *testing.T) {
+		path := writeTestFile(t)
+		out := filepath.Join(t.TempDir(), "clean.wav")
+		if code, _, stderr := runCmd("strip", "-o", out, path); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		_, stdout, _ := runCmd("ls", out)
+		if want := "OFFSET  SIZE  DEPTH  CHUNK\n0       34    0      RIFF WAVE\n12      6     1        fmt \n26      7     1        data\n"; stdout != want {
+			t.Fatalf("unexpected tree\n%s", stdout)
+		}
+	})
+
+	t.Run("into the input file", func(t *testing.T) {
+		path := writeTestFile(t)
+		if code, _, stderr := runCmd("strip", "-o", path, path); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		_, stdout, _ := runCmd("ls", path)
+		if want := "OFFSET  SIZE  DEPTH  CHUNK\n0       34    0      RIFF WAVE\n12      6     1        fmt \n26      7     1        data\n"; stdout != want {
+			t.Fatalf("unexpected tree\n%s", stdout)
+		}
+	})
+
+	t.Run("in place keeping a group", func(t *testing.T) {
+		path := writeTestFile(t)
+		if code, _, stderr := runCmd("strip", "-keep", "LIST:INFO", path); code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		if code, stdout, _ := runCmd("extract", path, "LIST:INFO/INAM"); code != 0 || stdout != "odd" {
+			t.Fatalf("expected INAM to be kept, got %d %q", code, stdout)
+		}
+	})
+}
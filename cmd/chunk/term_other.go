@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported here; browse then reads keys a line at a time.
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("raw terminal mode not supported")
+}
+
+func termHeight(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+	"github.com/CWBudde/chunk/png"
+)
+
+// errFindings is returned by validate when it found problems and by diff
+// when the files differ; the report has been printed already.
+var errFindings = errors.New("findings")
+
+// required lists the chunks each form type needs.
+var required = map[string][]string{
+	"WAVE": {"fmt ", "data"},
+	"AIFF": {"COMM"},
+	"AIFC": {"FVER", "COMM"},
+}
+
+// formatNames maps form types to the format their chunk IDs are checked
+// against.
+var formatNames = map[string]string{
+	"WAVE": "wav",
+	"AIFF": "aiff",
+	"AIFC": "aiff",
+}
+
+// validators check streams of the registered formats that are not RIFF or
+// IFF containers, by the name of their chunk.FormatModule.
+var validators = map[string]func(io.Reader) ([]container.Finding, error){
+	"png": png.Validate,
+}
+
+// report is the JSON output of validate. Format is the container format,
+// e.g. "RIFF", or the name of the registered format, e.g. "png"; Type is
+// the form type of containers.
+type report struct {
+	File     string              `json:"file"`
+	Format   string              `json:"format"`
+	Type     string              `json:"type"`
+	Findings []container.Finding `json:"findings"`
+}
+
+// validate checks the structure of a container, or of a stream of another
+// registered format with a validator, and prints the findings as JSON.
+func validate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("validate", "file", stderr)
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	r := report{File: pos[0]}
+	h, err := container.ReadHeader(f)
+	switch {
+	case errors.Is(err, container.ErrUnknownFormat):
+		m, err := chunk.DetectFormat(f)
+		if err != nil {
+			return err
+		}
+		check, ok := validators[m.Name()]
+		if !ok {
+			return fmt.Errorf("cannot validate %s files", m.Name())
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		r.Format = m.Name()
+		if r.Findings, err = check(f); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		r.Format, r.Type = h.Format.String(), h.Type.String()
+		opts := container.ValidateOptions{Required: required[r.Type], Format: formatNames[r.Type]}
+		if r.Findings, err = container.Validate(f, info.Size(), opts); err != nil {
+			return err
+		}
+	}
+	findings := r.Findings
+	if r.Findings == nil {
+		r.Findings = []container.Finding{}
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return errFindings
+	}
+	return nil
+}
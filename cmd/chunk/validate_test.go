@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+	"github.com/CWBudde/chunk/png"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("well-formed", func(t *testing.T) {
+		code, stdout, stderr := runCmd("validate", writeTestFile(t))
+		if code != 0 {
+			t.Fatalf("exit code %d: %s", code, stderr)
+		}
+		var r report
+		if err := json.Unmarshal([]byte(stdout), &r); err != nil || r.Type != "WAVE" || r.Findings == nil || len(r.Findings) != 0 {
+			t.Fatalf("unexpected report %s (%v)", stdout, err)
+		}
+	})
+
+	t.Run("findings", func(t *testing.T) {
+		path := writeTestFile(t)
+		b, _ := os.ReadFile(path)
+		copy(b[12:], "fmtx") // no fmt chunk
+		b[49] = 0xff         // pad after INAM
+		os.WriteFile(path, b, 0o644)
+
+		code, stdout, stderr := runCmd("validate", path)
+		if code != 1 || stderr != "" {
+			t.Fatalf("expected exit code 1 without a message, got %d %q", code, stderr)
+		}
+		var r struct {
+			Findings []struct {
+				Check  string `json:"check"`
+				Offset int64  `json:"offset"`
+				ID     string `json:"id"`
+			} `json:"findings"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+			t.Fatalf("unexpected report %s (%v)", stdout, err)
+		}
+		if len(r.Findings) != 2 || r.Findings[0].Check != "pad" || r.Findings[0].Offset != 38 ||
+			r.Findings[1].Check != "required" || r.Findings[1].ID != "fmt " {
+			t.Fatalf("unexpected findings %s", stdout)
+		}
+	})
+
+	t.Run("chunks out of order", func(t *testing.T) {
+		path := writeFixture(t, "order.wav", chunktest.RIFF("WAVE").
+			Chunk("data", "samples").
+			Chunk("fmt ", "format"))
+		code, stdout, _ := runCmd("validate", path)
+		if code != 1 || !strings.Contains(stdout, `"check": "order"`) || !strings.Contains(stdout, `must come before \"data\"`) {
+			t.Fatalf("expected an order finding, got %d %s", code, stdout)
+		}
+	})
+	t.Run("PNG streams", func(t *testing.T) {
+		var buf bytes.Buffer
+		png.WriteSignature(&buf)
+		png.WriteChunk(&buf, "IHDR", make([]byte, 13))
+		png.WriteChunk(&buf, "tEXt", []byte("Comment\x00hi"))
+		png.WriteChunk(&buf, "IEND", nil)
+		b := buf.Bytes()
+		b[41] ^= 1 // tEXt payload
+		path := filepath.Join(t.TempDir(), "test.png")
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		code, stdout, stderr := runCmd("validate", path)
+		if code != 1 || stderr != "" {
+			t.Fatalf("expected exit code 1 without a message, got %d %q", code, stderr)
+		}
+		var r struct {
+			Format   string `json:"format"`
+			Findings []struct {
+				Check  string `json:"check"`
+				Offset int64  `json:"offset"`
+			} `json:"findings"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &r); err != nil || r.Format != "png" || len(r.Findings) != 1 ||
+			r.Findings[0].Check != "crc" || r.Findings[0].Offset != 33 {
+			t.Fatalf("unexpected report %s (%v)", stdout, err)
+		}
+	})
+}
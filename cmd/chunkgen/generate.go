@@ -0,0 +1,441 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// basic describes a fixed-size scalar type.
+type basic struct {
+	size int
+	kind string // uint, int, float or bool
+}
+
+var basics = map[string]basic{
+	"bool":    {1, "bool"},
+	"byte":    {1, "uint"},
+	"uint8":   {1, "uint"},
+	"int8":    {1, "int"},
+	"uint16":  {2, "uint"},
+	"int16":   {2, "int"},
+	"uint32":  {4, "uint"},
+	"int32":   {4, "int"},
+	"float32": {4, "float"},
+	"uint64":  {8, "uint"},
+	"int64":   {8, "int"},
+	"float64": {8, "float"},
+}
+
+// generator collects the declarations of a package and emits the code for
+// the requested types.
+type generator struct {
+	pkg     string
+	types   map[string]ast.Expr
+	imports map[string]bool
+
+	read, write bytes.Buffer
+	off         int
+}
+
+// generate returns the formatted source of the methods for the named struct
+// types of the package in dir.
+func generate(dir string, names []string) ([]byte, error) {
+	g := &generator{types: map[string]ast.Expr{}, imports: map[string]bool{"io": true}}
+	if err := g.parse(dir); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		expr, ok := g.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", name)
+		}
+		st, ok := expr.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct", name)
+		}
+		g.read.Reset()
+		g.write.Reset()
+		g.off = 0
+		if err := g.structFields(st, "v", "binary.LittleEndian"); err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		g.emit(&body, name)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by chunkgen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkg)
+	paths := map[string]string{
+		"binary": "encoding/binary", "bytes": "bytes", "chunk": "github.com/CWBudde/chunk",
+		"io": "io", "math": "math", "strings": "strings",
+	}
+	var imports []string
+	for name := range g.imports {
+		if name != "chunk" {
+			imports = append(imports, paths[name])
+		}
+	}
+	slices.Sort(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	if g.imports["chunk"] {
+		fmt.Fprintf(&out, "\n\t%q\n", paths["chunk"])
+	}
+	out.WriteString(")\n")
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// parse collects the type declarations of the non-test files in dir.
+func (g *generator) parse(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return err
+		}
+		g.pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				g.types[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+	if g.pkg == "" {
+		return fmt.Errorf("no Go files in %s", dir)
+	}
+	return nil
+}
+
+func (g *generator) emit(w *bytes.Buffer, name string) {
+	fmt.Fprintf(w, `
+// ReadFrom reads the %[2]d byte layout of %[1]s from r. It implements
+// io.ReaderFrom.
+func (v *%[1]s) ReadFrom(r io.Reader) (int64, error) {
+	var buf [%[2]d]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+%[3]s	return int64(n), nil
+}
+
+// WriteTo writes the %[2]d byte layout of %[1]s to w. It implements
+// io.WriterTo.
+func (v *%[1]s) WriteTo(w io.Writer) (int64, error) {
+	var buf [%[2]d]byte
+%[4]s	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+`, name, g.off, g.read.String(), g.write.String())
+}
+
+// fieldTag is the parsed form of the `chunk:` options chunkgen supports.
+type fieldTag struct {
+	ignore bool
+	order  string
+	skip   int
+	pad    int
+	width  int
+}
+
+func parseTag(lit *ast.BasicLit, order string) (fieldTag, error) {
+	ft := fieldTag{order: order}
+	if lit == nil {
+		return ft, nil
+	}
+	raw, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ft, err
+	}
+	tag := reflect.StructTag(raw).Get("chunk")
+	if tag == "" {
+		return ft, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		key, val, hasVal := strings.Cut(strings.TrimSpace(opt), "=")
+		var n int
+		if hasVal {
+			if n, err = strconv.Atoi(val); err != nil || n < 0 {
+				return ft, fmt.Errorf("invalid chunk tag option %q", opt)
+			}
+		}
+		switch key {
+		case "-":
+			ft.ignore = true
+		case "le":
+			ft.order = "binary.LittleEndian"
+		case "be":
+			ft.order = "binary.BigEndian"
+		case "skip":
+			ft.skip = n
+		case "pad":
+			ft.pad = n
+		case "string":
+			ft.width = n
+		case "cstring", "pstring":
+			return ft, fmt.Errorf("variable length option %q is not supported", key)
+		default:
+			return ft, fmt.Errorf("unknown chunk tag option %q", opt)
+		}
+	}
+	return ft, nil
+}
+
+func (g *generator) structFields(st *ast.StructType, path, order string) error {
+	for _, field := range st.Fields.List {
+		ft, err := parseTag(field.Tag, order)
+		if err != nil {
+			return err
+		}
+		if ft.ignore {
+			continue
+		}
+		var names []string
+		for _, n := range field.Names {
+			names = append(names, n.Name)
+		}
+		if len(names) == 0 {
+			// embedded field, named after its type
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("unsupported embedded field %s", exprString(field.Type))
+			}
+			names = []string{ident.Name}
+		}
+		for _, name := range names {
+			if name != "_" && !ast.IsExported(name) {
+				continue
+			}
+			g.off += ft.skip
+			if name == "_" {
+				size, err := g.size(field.Type)
+				if err != nil {
+					return err
+				}
+				g.off += size
+			} else if err := g.field(field.Type, path+"."+name, ft); err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+			g.off += ft.pad
+		}
+	}
+	return nil
+}
+
+// field emits the code for a single field at the current offset.
+func (g *generator) field(typ ast.Expr, path string, ft fieldTag) error {
+	name, under, err := g.resolve(typ)
+	if err != nil {
+		return err
+	}
+	switch t := under.(type) {
+	case *ast.Ident:
+		if t.Name == "string" {
+			return g.stringField(name, path, ft.width)
+		}
+		b, ok := basics[t.Name]
+		if !ok {
+			return fmt.Errorf("unsupported type %s", t.Name)
+		}
+		g.scalar(path, name, t.Name, b, ft.order, strconv.Itoa(g.off))
+		g.off += b.size
+		return nil
+	case *ast.StructType:
+		return g.structFields(t, path, ft.order)
+	case *ast.ArrayType:
+		return g.array(t, path, ft.order)
+	}
+	return fmt.Errorf("unsupported type %s", exprString(typ))
+}
+
+func (g *generator) array(t *ast.ArrayType, path, order string) error {
+	n, err := arrayLen(t)
+	if err != nil {
+		return err
+	}
+	elem, under, err := g.resolve(t.Elt)
+	if err != nil {
+		return err
+	}
+	ident, ok := under.(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("unsupported array element %s", exprString(t.Elt))
+	}
+	b, ok := basics[ident.Name]
+	if !ok {
+		return fmt.Errorf("unsupported array element %s", ident.Name)
+	}
+	if elem == "byte" || elem == "uint8" {
+		fmt.Fprintf(&g.read, "\tcopy(%s[:], buf[%d:%d])\n", path, g.off, g.off+n)
+		fmt.Fprintf(&g.write, "\tcopy(buf[%d:%d], %s[:])\n", g.off, g.off+n, path)
+		g.off += n
+		return nil
+	}
+	off := fmt.Sprintf("%d+%d*i", g.off, b.size)
+	if b.size == 1 {
+		off = fmt.Sprintf("%d+i", g.off)
+	}
+	g.read.WriteString("\tfor i := range " + path + " {\n\t")
+	g.write.WriteString("\tfor i := range " + path + " {\n\t")
+	g.scalar(path+"[i]", elem, ident.Name, b, order, off)
+	g.read.WriteString("\t}\n")
+	g.write.WriteString("\t}\n")
+	g.off += n * b.size
+	return nil
+}
+
+// scalar emits the decoding and encoding of a basic value. typ is the type
+// as declared, kind the basic type it is based on.
+func (g *generator) scalar(path, typ, kind string, b basic, order, off string) {
+	conv := func(expr string) string {
+		if typ == kind || typ == "byte" && kind == "uint8" {
+			return expr
+		}
+		return typ + "(" + expr + ")"
+	}
+	bits := strconv.Itoa(8 * b.size)
+	if b.size == 1 {
+		switch b.kind {
+		case "bool":
+			fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv("buf["+off+"] != 0"))
+			fmt.Fprintf(&g.write, "\tif %s {\n\t\tbuf[%s] = 1\n\t}\n", path, off)
+		case "int":
+			fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv("int8(buf["+off+"])"))
+			fmt.Fprintf(&g.write, "\tbuf[%s] = byte(%s)\n", off, path)
+		default:
+			fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv("buf["+off+"]"))
+			fmt.Fprintf(&g.write, "\tbuf[%s] = byte(%s)\n", off, path)
+		}
+		return
+	}
+	g.imports["binary"] = true
+	get := fmt.Sprintf("%s.Uint%s(buf[%s:])", order, bits, off)
+	switch b.kind {
+	case "uint":
+		fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv(get))
+		val := path
+		if typ != kind {
+			val = kind + "(" + path + ")"
+		}
+		fmt.Fprintf(&g.write, "\t%s.PutUint%s(buf[%s:], %s)\n", order, bits, off, val)
+	case "int":
+		fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv(kind+"("+get+")"))
+		fmt.Fprintf(&g.write, "\t%s.PutUint%s(buf[%s:], uint%s(%s))\n", order, bits, off, bits, path)
+	case "float":
+		g.imports["math"] = true
+		fmt.Fprintf(&g.read, "\t%s = %s\n", path, conv("math.Float"+bits+"frombits("+get+")"))
+		val := path
+		if typ != kind {
+			val = kind + "(" + path + ")"
+		}
+		fmt.Fprintf(&g.write, "\t%s.PutUint%s(buf[%s:], math.Float%sbits(%s))\n", order, bits, off, bits, val)
+	}
+}
+
+func (g *generator) stringField(typ, path string, width int) error {
+	if width == 0 {
+		return fmt.Errorf("string fields need a string=N tag")
+	}
+	g.imports["bytes"], g.imports["strings"], g.imports["chunk"] = true, true, true
+	val := `strings.TrimRight(string(b), " ")`
+	if typ != "string" {
+		val = typ + "(" + val + ")"
+	}
+	fmt.Fprintf(&g.read, "\t{\n\t\tb := buf[%d:%d]\n", g.off, g.off+width)
+	fmt.Fprintf(&g.read, "\t\tif i := bytes.IndexByte(b, 0); i >= 0 {\n\t\t\tb = b[:i]\n\t\t}\n")
+	fmt.Fprintf(&g.read, "\t\t%s = %s\n\t}\n", path, val)
+	fmt.Fprintf(&g.write, "\tif len(%s) > %d {\n\t\treturn 0, chunk.ErrStringTooLong\n\t}\n", path, width)
+	fmt.Fprintf(&g.write, "\tcopy(buf[%d:%d], %s)\n", g.off, g.off+width, path)
+	g.off += width
+	return nil
+}
+
+// resolve returns the declared name of a type and the expression of its
+// underlying type, following named types of the package.
+func (g *generator) resolve(typ ast.Expr) (string, ast.Expr, error) {
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return exprString(typ), typ, nil
+	}
+	name := ident.Name
+	for range 16 {
+		decl, ok := g.types[ident.Name]
+		if !ok {
+			return name, ident, nil
+		}
+		if next, ok := decl.(*ast.Ident); ok {
+			ident = next
+			continue
+		}
+		return name, decl, nil
+	}
+	return "", nil, fmt.Errorf("type %s: cyclic declaration", name)
+}
+
+// size returns the encoded size of a type, used for blank fields.
+func (g *generator) size(typ ast.Expr) (int, error) {
+	_, under, err := g.resolve(typ)
+	if err != nil {
+		return 0, err
+	}
+	switch t := under.(type) {
+	case *ast.Ident:
+		if b, ok := basics[t.Name]; ok {
+			return b.size, nil
+		}
+	case *ast.ArrayType:
+		n, err := arrayLen(t)
+		if err != nil {
+			return 0, err
+		}
+		elem, err := g.size(t.Elt)
+		return n * elem, err
+	}
+	return 0, fmt.Errorf("unsupported blank field type %s", exprString(typ))
+}
+
+func arrayLen(t *ast.ArrayType) (int, error) {
+	if t.Len == nil {
+		return 0, fmt.Errorf("unsupported type %s", exprString(t))
+	}
+	lit, ok := t.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, fmt.Errorf("array length of %s must be an integer literal", exprString(t))
+	}
+	return strconv.Atoi(lit.Value)
+}
+
+func exprString(e ast.Expr) string {
+	var b bytes.Buffer
+	format.Node(&b, token.NewFileSet(), e)
+	return b.String()
+}
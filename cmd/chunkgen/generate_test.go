@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("matches the committed output", func(t *testing.T) {
+		dir := filepath.Join("internal", "records")
+		got, err := generate(dir, []string{"Preset", "Header"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want, err := os.ReadFile(filepath.Join(dir, "preset_chunk.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatal("generated code is out of date, run go generate ./...")
+		}
+	})
+
+	for _, c := range []struct {
+		name, src, typ, err string
+	}{
+		{"missing type", "type A struct{}", "B", "not found"},
+		{"not a struct", "type A uint16", "A", "not a struct"},
+		{"variable length", "type A struct{ S string `chunk:\"cstring\"` }", "A", "variable length"},
+		{"untagged string", "type A struct{ S string }", "A", "string=N"},
+		{"platform int", "type A struct{ N int }", "A", "unsupported type int"},
+		{"slice", "type A struct{ B []byte }", "A", "unsupported type"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package p\n\n" + c.src + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := generate(dir, []string{c.typ})
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected error containing %q, got %v", c.err, err)
+			}
+		})
+	}
+}
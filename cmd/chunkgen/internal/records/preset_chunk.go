@@ -0,0 +1,117 @@
+// Code generated by chunkgen; DO NOT EDIT.
+
+package records
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ReadFrom reads the 38 byte layout of Preset from r. It implements
+// io.ReaderFrom.
+func (v *Preset) ReadFrom(r io.Reader) (int64, error) {
+	var buf [38]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	{
+		b := buf[0:20]
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		v.Name = strings.TrimRight(string(b), " ")
+	}
+	v.Preset = binary.LittleEndian.Uint16(buf[20:])
+	v.Bank = binary.LittleEndian.Uint16(buf[22:])
+	v.BagNdx = binary.LittleEndian.Uint16(buf[24:])
+	v.Library = binary.LittleEndian.Uint32(buf[26:])
+	v.Genre = binary.LittleEndian.Uint32(buf[30:])
+	v.Morph = binary.LittleEndian.Uint32(buf[34:])
+	return int64(n), nil
+}
+
+// WriteTo writes the 38 byte layout of Preset to w. It implements
+// io.WriterTo.
+func (v *Preset) WriteTo(w io.Writer) (int64, error) {
+	var buf [38]byte
+	if len(v.Name) > 20 {
+		return 0, chunk.ErrStringTooLong
+	}
+	copy(buf[0:20], v.Name)
+	binary.LittleEndian.PutUint16(buf[20:], v.Preset)
+	binary.LittleEndian.PutUint16(buf[22:], v.Bank)
+	binary.LittleEndian.PutUint16(buf[24:], v.BagNdx)
+	binary.LittleEndian.PutUint32(buf[26:], v.Library)
+	binary.LittleEndian.PutUint32(buf[30:], v.Genre)
+	binary.LittleEndian.PutUint32(buf[34:], v.Morph)
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+// ReadFrom reads the 50 byte layout of Header from r. It implements
+// io.ReaderFrom.
+func (v *Header) ReadFrom(r io.Reader) (int64, error) {
+	var buf [50]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	v.Channels = int16(binary.BigEndian.Uint16(buf[0:]))
+	v.Rate = math.Float64frombits(binary.LittleEndian.Uint64(buf[2:]))
+	v.Gain = math.Float32frombits(binary.BigEndian.Uint32(buf[10:]))
+	v.Looped = buf[14] != 0
+	v.Kind = Generator(binary.LittleEndian.Uint16(buf[16:]))
+	for i := range v.Offsets {
+		v.Offsets[i] = int32(binary.LittleEndian.Uint32(buf[18+4*i:]))
+	}
+	copy(v.Tag[:], buf[30:34])
+	for i := range v.Flags {
+		v.Flags[i] = buf[34+i] != 0
+	}
+	v.Inner.A = binary.BigEndian.Uint16(buf[36:])
+	v.Inner.B = int8(buf[38])
+	{
+		b := buf[40:46]
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		v.Label = strings.TrimRight(string(b), " ")
+	}
+	return int64(n), nil
+}
+
+// WriteTo writes the 50 byte layout of Header to w. It implements
+// io.WriterTo.
+func (v *Header) WriteTo(w io.Writer) (int64, error) {
+	var buf [50]byte
+	binary.BigEndian.PutUint16(buf[0:], uint16(v.Channels))
+	binary.LittleEndian.PutUint64(buf[2:], math.Float64bits(v.Rate))
+	binary.BigEndian.PutUint32(buf[10:], math.Float32bits(v.Gain))
+	if v.Looped {
+		buf[14] = 1
+	}
+	binary.LittleEndian.PutUint16(buf[16:], uint16(v.Kind))
+	for i := range v.Offsets {
+		binary.LittleEndian.PutUint32(buf[18+4*i:], uint32(v.Offsets[i]))
+	}
+	copy(buf[30:34], v.Tag[:])
+	for i := range v.Flags {
+		if v.Flags[i] {
+			buf[34+i] = 1
+		}
+	}
+	binary.BigEndian.PutUint16(buf[36:], v.Inner.A)
+	buf[38] = byte(v.Inner.B)
+	if len(v.Label) > 6 {
+		return 0, chunk.ErrStringTooLong
+	}
+	copy(buf[40:46], v.Label)
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
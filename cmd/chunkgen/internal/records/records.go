@@ -0,0 +1,39 @@
+// Package records holds record types used to test the code generated by
+// chunkgen against chunk.Reader.ReadStruct.
+package records
+
+//go:generate go run github.com/CWBudde/chunk/cmd/chunkgen -type Preset,Header
+
+// Generator is a named scalar type.
+type Generator uint16
+
+// Preset mirrors a SoundFont phdr record.
+type Preset struct {
+	Name    string `chunk:"string=20"`
+	Preset  uint16
+	Bank    uint16
+	BagNdx  uint16
+	Library uint32
+	Genre   uint32
+	Morph   uint32
+}
+
+// Header exercises every supported field kind.
+type Header struct {
+	Channels int16 `chunk:"be"`
+	Rate     float64
+	Gain     float32 `chunk:"be"`
+	Looped   bool
+	Kind     Generator `chunk:"skip=1"`
+	Offsets  [3]int32
+	Tag      [4]byte
+	Flags    [2]bool
+	Inner    struct {
+		A uint16
+		B int8
+	} `chunk:"be,pad=1"`
+	Label    string `chunk:"string=6"`
+	_        [2]uint16
+	Ignored  int `chunk:"-"`
+	internal int
+}
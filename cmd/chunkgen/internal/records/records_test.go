@@ -0,0 +1,88 @@
+package records
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func testHeader() Header {
+	h := Header{Channels: -2, Rate: 44100.5, Gain: 0.25, Looped: true, Kind: 7,
+		Offsets: [3]int32{-1, 2, 3}, Tag: [4]byte{'s', 'm', 'p', 'l'}, Flags: [2]bool{false, true},
+		Label: "lead", Ignored: 9, internal: 1}
+	h.Inner.A = 0x0102
+	h.Inner.B = -3
+	return h
+}
+
+func TestGenerated_MatchesStruct(t *testing.T) {
+	h := testHeader()
+	var want bytes.Buffer
+	if err := (&chunk.Writer{W: &want}).WriteStruct(&h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got bytes.Buffer
+	n, err := h.WriteTo(&got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != int64(want.Len()) || !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("WriteTo differs from WriteStruct\n got % x\nwant % x", got.Bytes(), want.Bytes())
+	}
+
+	var back, ref Header
+	r := &chunk.Reader{Size: want.Len(), R: bytes.NewReader(want.Bytes())}
+	if _, err := back.ReadFrom(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Pos != want.Len() {
+		t.Fatalf("expected Pos=%d, got %d", want.Len(), r.Pos)
+	}
+	if err := (&chunk.Reader{Size: want.Len(), R: bytes.NewReader(want.Bytes())}).ReadStruct(&ref); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if back != ref {
+		t.Fatalf("ReadFrom differs from ReadStruct\n got %+v\nwant %+v", back, ref)
+	}
+	h.Ignored, h.internal = 0, 0
+	if back != h {
+		t.Fatalf("round trip mismatch\n got %+v\nwant %+v", back, h)
+	}
+
+	t.Run("rejects long strings", func(t *testing.T) {
+		p := Preset{Name: "a name longer than twenty bytes"}
+		if _, err := p.WriteTo(&bytes.Buffer{}); err != chunk.ErrStringTooLong {
+			t.Fatalf("expected ErrStringTooLong, got %v", err)
+		}
+	})
+
+	t.Run("short input", func(t *testing.T) {
+		var p Preset
+		if n, err := p.ReadFrom(bytes.NewReader(make([]byte, 10))); err == nil || n != 10 {
+			t.Fatalf("expected error after 10 bytes, got %d %v", n, err)
+		}
+	})
+}
+
+func BenchmarkPreset(b *testing.B) {
+	p := Preset{Name: "Grand Piano", Preset: 1, Bank: 0, BagNdx: 12}
+	var buf bytes.Buffer
+	p.WriteTo(&buf)
+	data := buf.Bytes()
+
+	b.Run("ReadFrom", func(b *testing.B) {
+		r := bytes.NewReader(data)
+		for b.Loop() {
+			r.Reset(data)
+			p.ReadFrom(r)
+		}
+	})
+	b.Run("ReadStruct", func(b *testing.B) {
+		r := bytes.NewReader(data)
+		for b.Loop() {
+			r.Reset(data)
+			(&chunk.Reader{Size: len(data), R: r}).ReadStruct(&p)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+// Chunkgen generates reflection-free ReadFrom and WriteTo methods for
+// structs with a fixed binary layout, honoring the same `chunk:` tags as
+// chunk.Reader.ReadStruct (le, be, skip=N, pad=N, string=N and "-"). It is
+// meant to be run by go generate:
+//
+//	//go:generate go run github.com/CWBudde/chunk/cmd/chunkgen -type Preset,Bag
+//
+// The generated methods decode a whole record from a single buffer, which
+// is an order of magnitude faster than ReadStruct for large record arrays.
+// Supported fields are fixed-size integers, floats and bools (and named
+// types based on them), arrays of those, fixed-width strings and nested
+// structs declared in the same package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	types := flag.String("type", "", "comma separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <first type>_chunk.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: chunkgen -type T[,T...] [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *types == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	names := strings.Split(*types, ",")
+	src, err := generate(dir, names)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chunkgen: %v\n", err)
+		os.Exit(1)
+	}
+	name := *output
+	if name == "" {
+		name = strings.ToLower(names[0]) + "_chunk.go"
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "chunkgen: %v\n", err)
+		os.Exit(1)
+	}
+}
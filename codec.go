@@ -0,0 +1,49 @@
+package chunk
+
+import (
+	"compress/zlib"
+	"errors"
+	"io"
+)
+
+// Codec compresses (or otherwise encodes) chunk payloads, so callers can
+// read and write plain data while the chunk stores the encoded bytes.
+type Codec struct {
+	// NewWriter returns a writer encoding into w; Close flushes it.
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader decoding from r.
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// Zlib is the zlib Codec used by PNG zTXt, iTXt and iCCP chunks.
+var Zlib = Codec{
+	NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriter(w), nil
+	},
+	NewReader: func(r io.Reader) (io.ReadCloser, error) {
+		return zlib.NewReader(r)
+	},
+}
+
+// Encode returns a writer that encodes everything written to it into the
+// chunk with c. Size counts the encoded bytes, so the container writes the
+// stored size; the returned writer must be closed before the chunk is ended.
+//
+//	zw, _ := ch.Encode(chunk.Zlib)
+//	zw.Write(text)
+//	zw.Close()
+func (ch *Writer) Encode(c Codec) (io.WriteCloser, error) {
+	if ch == nil || ch.W == nil {
+		return nil, errors.New("nil Writer/writer pointer")
+	}
+	return c.NewWriter(ch)
+}
+
+// Decode returns a reader that decodes the rest of the chunk payload with c.
+// Pos advances over the encoded bytes as they are consumed.
+func (ch *Reader) Decode(c Codec) (io.ReadCloser, error) {
+	if ch == nil || ch.R == nil {
+		return nil, errors.New("nil Reader/reader pointer")
+	}
+	return c.NewReader(io.LimitReader(ch, int64(max(ch.Size-ch.Pos, 0))))
+}
@@ -0,0 +1,59 @@
+package chunk
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestCodec_Zlib(t *testing.T) {
+	text := bytes.Repeat([]byte("chunk "), 64)
+
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	w.WriteCString("Comment")
+	w.WriteByte(0) // compression method
+	zw, err := w.Encode(Zlib)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zw.Write(text)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.Size != buf.Len() {
+		t.Fatalf("expected Size=%d, got %d", buf.Len(), w.Size)
+	}
+	if w.Size >= len(text) {
+		t.Fatalf("payload was not compressed: %d bytes", w.Size)
+	}
+
+	// trailing bytes belong to the next chunk and must not be consumed
+	data := append(buf.Bytes(), "NEXT"...)
+	r := &Reader{Size: buf.Len(), R: bytes.NewReader(data)}
+	if kw, _ := r.ReadCString(); kw != "Comment" {
+		t.Fatalf("unexpected keyword %q", kw)
+	}
+	r.ReadByte()
+	zr, err := r.Decode(Zlib)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, text) {
+		t.Fatalf("unexpected text %q", got)
+	}
+	if !r.IsFullyRead() {
+		t.Fatalf("expected chunk to be fully read, Pos=%d Size=%d", r.Pos, r.Size)
+	}
+
+	t.Run("nil reader", func(t *testing.T) {
+		var r *Reader
+		if _, err := r.Decode(Zlib); err == nil {
+			t.Fatal("expected error for nil reader")
+		}
+	})
+}
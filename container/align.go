@@ -0,0 +1,76 @@
+package container
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInvalidAlignment is returned by Align for alignments that are not a
+// power of two of at least 2.
+var ErrInvalidAlignment = errors.New("container: alignment must be a power of two")
+
+// Align makes every chunk begun afterwards start at a multiple of n bytes,
+// counted from the start of the output; n is typically 4 or 8. Gaps are filled
+// with chunks of the given filler ID (JUNK for RIFF, PAD or FLLR when a player
+// expects them), which need at least 8 bytes and may therefore span more than
+// one alignment step. An empty filler fills gaps with zero bytes instead; only
+// use this for formats whose readers skip stray pad bytes.
+func (cw *Writer) Align(n int, filler string) error {
+	if n < 2 || n&(n-1) != 0 {
+		return ErrInvalidAlignment
+	}
+	if filler != "" {
+		if err := cw.validate(filler); err != nil {
+			return err
+		}
+	}
+	cw.align, cw.filler = n, filler
+	return nil
+}
+
+// offset returns the absolute position at which the next chunk header would
+// be written.
+func (cw *Writer) offset() (int64, error) {
+	if cw.ws != nil {
+		return cw.ws.Seek(0, io.SeekCurrent)
+	}
+	if n := len(cw.stack); n > 0 {
+		top := cw.stack[n-1]
+		return top.start + cw.headerSize() + int64(top.ch.Size), nil
+	}
+	return cw.written, nil
+}
+
+// alignNext inserts a filler chunk or pad bytes so the next chunk header
+// starts at the configured alignment.
+func (cw *Writer) alignNext() error {
+	if cw.align <= 2 || cw.padding {
+		return nil
+	}
+	off, err := cw.offset()
+	if err != nil {
+		return err
+	}
+	gap := (int64(cw.align) - off%int64(cw.align)) % int64(cw.align)
+	if gap == 0 {
+		return nil
+	}
+	if cw.filler == "" {
+		_, err := cw.sink().Write(make([]byte, gap))
+		return err
+	}
+	for gap < cw.headerSize() {
+		gap += int64(cw.align)
+	}
+
+	cw.padding = true
+	defer func() { cw.padding = false }()
+	f, err := cw.beginID(fourCC(cw.filler), false)
+	if err != nil {
+		return err
+	}
+	if _, err := f.ch.Write(make([]byte, gap-cw.headerSize())); err != nil {
+		return err
+	}
+	return cw.EndChunk()
+}
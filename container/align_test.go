@@ -0,0 +1,127 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// writeAligned writes a RIFF form with two odd sized chunks at the given
+// alignment.
+func writeAligned(t *testing.T, cw *Writer, n int, filler string) {
+	t.Helper()
+	if err := cw.Align(n, filler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cw.BeginForm("WAVE"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range []struct{ id, payload string }{{"fmt ", "abc"}, {"data", "defgh"}} {
+		ch, err := cw.BeginChunk(c.id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch.Write([]byte(c.payload))
+		if err := cw.EndChunk(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWriter_Align(t *testing.T) {
+	want := []byte{
+		'R', 'I', 'F', 'F', 54, 0, 0, 0, 'W', 'A', 'V', 'E',
+		// 12 -> 16: a 4 byte gap is too small for JUNK, so it spans 12 bytes
+		'J', 'U', 'N', 'K', 4, 0, 0, 0, 0, 0, 0, 0,
+		'f', 'm', 't', ' ', 3, 0, 0, 0, 'a', 'b', 'c', 0,
+		// 36 -> 40
+		'J', 'U', 'N', 'K', 4, 0, 0, 0, 0, 0, 0, 0,
+		'd', 'a', 't', 'a', 5, 0, 0, 0, 'd', 'e', 'f', 'g', 'h', 0,
+	}
+
+	t.Run("JUNK filler while buffering", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAligned(t, NewWriter(&buf, RIFF), 8, "JUNK")
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+
+	t.Run("JUNK filler while seeking", func(t *testing.T) {
+		sb := &seekBuffer{}
+		writeAligned(t, NewWriter(sb, RIFF), 8, "JUNK")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("chunks start aligned", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAligned(t, NewWriter(&buf, RIFF), 8, "JUNK")
+		s, err := NewScanner(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []string
+		for s.Next() {
+			if off := s.Offset(); s.Chunk().ID != [4]byte{'J', 'U', 'N', 'K'} && off%8 != 0 {
+				t.Fatalf("chunk %q at unaligned offset %d", s.Chunk().ID, off)
+			}
+			ids = append(ids, string(s.Chunk().ID[:]))
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 4 || ids[1] != "fmt " || ids[3] != "data" {
+			t.Fatalf("unexpected chunks %q", ids)
+		}
+	})
+
+	t.Run("zero bytes without filler", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAligned(t, NewWriter(&buf, RIFF), 8, "")
+		want := []byte{
+			'R', 'I', 'F', 'F', 38, 0, 0, 0, 'W', 'A', 'V', 'E',
+			0, 0, 0, 0,
+			'f', 'm', 't', ' ', 3, 0, 0, 0, 'a', 'b', 'c', 0,
+			0, 0, 0, 0,
+			'd', 'a', 't', 'a', 5, 0, 0, 0, 'd', 'e', 'f', 'g', 'h', 0,
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+
+	t.Run("keeps ds64 first", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAligned(t, NewWriter(&buf, RF64), 8, "JUNK")
+		s, err := NewScanner(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for s.Next() {
+			if id := chunk.FourCC(s.Chunk().ID); id.String() != "JUNK" && s.Offset()%8 != 0 {
+				t.Fatalf("chunk %q at unaligned offset %d", id, s.Offset())
+			}
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects invalid alignments", func(t *testing.T) {
+		cw := NewWriter(&bytes.Buffer{}, RIFF)
+		for _, n := range []int{0, 1, 3, 12} {
+			if err := cw.Align(n, "JUNK"); err != ErrInvalidAlignment {
+				t.Fatalf("Align(%d): expected ErrInvalidAlignment, got %v", n, err)
+			}
+		}
+		if err := cw.Align(4, "J\x00NK"); err == nil {
+			t.Fatal("expected error for invalid filler ID")
+		}
+	})
+}
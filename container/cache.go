@@ -0,0 +1,92 @@
+package container
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/CWBudde/chunk"
+)
+
+// decodeKey identifies a decoded chunk by its offset, the key its decoder
+// was given and the type it was decoded into, so different decoders of the
+// same chunk do not collide.
+type decodeKey struct {
+	off  int64
+	key  any
+	kind any
+}
+
+type decodeEntry struct {
+	key   decodeKey
+	value any
+}
+
+// decodeCache is a least recently used cache of decoded chunks.
+type decodeCache struct {
+	mu    sync.Mutex
+	max   int
+	order list.List
+	items map[decodeKey]*list.Element
+}
+
+func newDecodeCache(max int) *decodeCache {
+	return &decodeCache{max: max, items: make(map[decodeKey]*list.Element)}
+}
+
+func (c *decodeCache) get(k decodeKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*decodeEntry).value, true
+}
+
+func (c *decodeCache) put(k decodeKey, v any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[k]; ok {
+		e.Value.(*decodeEntry).value = v
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[k] = c.order.PushFront(&decodeEntry{key: k, value: v})
+	if c.order.Len() > c.max {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*decodeEntry).key)
+	}
+}
+
+// Chunk returns a reader over the payload of n for the decoders of the
+// format packages, such as wav.DecodeFmt.
+func (n *Node) Chunk() *chunk.Reader {
+	return &chunk.Reader{ID: n.ID, Size: int(n.Size), R: n.Payload()}
+}
+
+// DecodeNode decodes n with decode. With TreeOptions.DecodeCache set, the
+// result is kept in a cache keyed by the offset of n, key and the type T,
+// and later calls with the same key return it without reading the chunk
+// again; callers must then treat it as read-only. Decoders that may give
+// different results for the same chunk, such as wav.DecodeInfo with
+// different options, need different keys. Errors are not cached.
+//
+//	f, err := container.DecodeNode(node, "fmt", wav.DecodeFmt)
+func DecodeNode[K comparable, T any](n *Node, key K, decode func(*chunk.Reader) (T, error)) (T, error) {
+	c := n.tree.decoded
+	if c == nil {
+		return decode(n.Chunk())
+	}
+	k := decodeKey{off: n.Offset, key: key, kind: (*T)(nil)}
+	if v, ok := c.get(k); ok {
+		return v.(T), nil
+	}
+	v, err := decode(n.Chunk())
+	if err != nil {
+		return v, err
+	}
+	c.put(k, v)
+	return v, nil
+}
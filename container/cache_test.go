@@ -0,0 +1,106 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestDecodeNode(t *testing.T) {
+	src := buildNested(t)
+	var calls int
+	readString := func(r *chunk.Reader) (string, error) {
+		calls++
+		b, err := io.ReadAll(r)
+		return string(b), err
+	}
+	readLen := func(r *chunk.Reader) (int, error) {
+		calls++
+		return r.Size, nil
+	}
+
+	t.Run("decodes without a cache", func(t *testing.T) {
+		tree, _ := ParseTree(bytes.NewReader(src), TreeOptions{})
+		calls = 0
+		for range 2 {
+			if s, err := DecodeNode(tree.Root.Children[0], "string", readString); err != nil || s != "format" {
+				t.Fatalf("unexpected result %q (%v)", s, err)
+			}
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 decodes, got %d", calls)
+		}
+	})
+
+	t.Run("caches by offset and type", func(t *testing.T) {
+		tree, _ := ParseTree(bytes.NewReader(src), TreeOptions{DecodeCache: 4})
+		fmtNode := tree.Root.Children[0]
+		calls = 0
+		for range 3 {
+			if s, _ := DecodeNode(fmtNode, "string", readString); s != "format" {
+				t.Fatalf("unexpected result %q", s)
+			}
+		}
+		if n, _ := DecodeNode(fmtNode, "len", readLen); n != 6 {
+			t.Fatalf("expected 6, got %d", n)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 decodes, got %d", calls)
+		}
+	})
+
+	t.Run("keeps decoders of one type apart by key", func(t *testing.T) {
+		tree, _ := ParseTree(bytes.NewReader(src), TreeOptions{DecodeCache: 4})
+		fmtNode := tree.Root.Children[0]
+		readUpper := func(r *chunk.Reader) (string, error) {
+			s, err := readString(r)
+			return strings.ToUpper(s), err
+		}
+		calls = 0
+		for range 2 {
+			if s, _ := DecodeNode(fmtNode, "string", readString); s != "format" {
+				t.Fatalf("unexpected result %q", s)
+			}
+			if s, _ := DecodeNode(fmtNode, "upper", readUpper); s != "FORMAT" {
+				t.Fatalf("unexpected upper case result %q", s)
+			}
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 decodes, got %d", calls)
+		}
+	})
+
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		tree, _ := ParseTree(bytes.NewReader(src), TreeOptions{DecodeCache: 2})
+		c := tree.Root.Children
+		calls = 0
+		DecodeNode(c[0], "string", readString)
+		DecodeNode(c[2], "string", readString)
+		DecodeNode(c[0], "string", readString)
+		DecodeNode(c[1], "string", readString) // evicts data
+		DecodeNode(c[0], "string", readString)
+		if calls != 3 {
+			t.Fatalf("expected 3 decodes, got %d", calls)
+		}
+		DecodeNode(c[2], "string", readString)
+		if calls != 4 {
+			t.Fatalf("expected data to be decoded again, got %d decodes", calls)
+		}
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		tree, _ := ParseTree(bytes.NewReader(src), TreeOptions{DecodeCache: 2})
+		errBad := errors.New("bad")
+		fail := func(*chunk.Reader) (string, error) { return "", errBad }
+		if _, err := DecodeNode(tree.Root.Children[0], "fail", fail); err != errBad {
+			t.Fatalf("expected errBad, got %v", err)
+		}
+		if s, _ := DecodeNode(tree.Root.Children[0], "string", readString); s != "format" {
+			t.Fatalf("unexpected result %q", s)
+		}
+	})
+}
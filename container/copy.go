@@ -0,0 +1,44 @@
+package container
+
+import (
+	"errors"
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrPartiallyRead is returned when a chunk that has already been read from
+// is passed to CopyChunk.
+var ErrPartiallyRead = errors.New("container: chunk is partially read")
+
+// CopyChunk streams src into dst as a new chunk with the same ID and payload.
+// The ID is copied as is, without dst.ValidateID, so chunks this package does
+// not understand survive a rewrite; the pad byte is written by dst. src must
+// not have been read from yet and is fully read afterwards.
+func CopyChunk(dst *Writer, src *chunk.Reader) error {
+	if err := copyPayload(dst, src); err != nil {
+		return err
+	}
+	return dst.EndChunk()
+}
+
+// copyPayload is CopyChunk leaving the new chunk open.
+func copyPayload(dst *Writer, src *chunk.Reader) error {
+	if src == nil || src.R == nil {
+		return errors.New("nil Reader/reader pointer")
+	}
+	if src.Pos != 0 {
+		return ErrPartiallyRead
+	}
+	f, err := dst.beginID(src.ID, false)
+	if err != nil {
+		return err
+	}
+	// *chunk.Writer implements io.ReaderFrom, so this copies through a
+	// pooled buffer
+	n, err := io.Copy(f.ch, io.LimitReader(src, int64(src.Size)))
+	if err == nil && n < int64(src.Size) {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
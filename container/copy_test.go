@@ -0,0 +1,63 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestCopyChunk(t *testing.T) {
+	t.Run("copies ID and payload with pad byte", func(t *testing.T) {
+		payload := []byte{1, 2, 3}
+		src := &chunk.Reader{
+			ID:   [4]byte{'e', 'l', 'm', '1'},
+			Size: len(payload),
+			R:    bytes.NewReader(payload),
+		}
+
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RIFF)
+		if err := CopyChunk(cw, src); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []byte{'e', 'l', 'm', '1', 3, 0, 0, 0, 1, 2, 3, 0}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("expected % x, got % x", want, buf.Bytes())
+		}
+		if !src.IsFullyRead() {
+			t.Fatal("expected source to be fully read")
+		}
+	})
+
+	t.Run("preserves IDs the writer would reject", func(t *testing.T) {
+		src := &chunk.Reader{
+			ID:   [4]byte{'x', 0, 0, 0},
+			Size: 0,
+			R:    bytes.NewReader(nil),
+		}
+		var buf bytes.Buffer
+		if err := CopyChunk(NewWriter(&buf, RIFF), src); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes()[:4], []byte{'x', 0, 0, 0}) {
+			t.Fatalf("expected ID to be preserved, got % x", buf.Bytes()[:4])
+		}
+	})
+
+	t.Run("rejects partially read chunks", func(t *testing.T) {
+		src := &chunk.Reader{Size: 2, R: bytes.NewReader([]byte{1, 2})}
+		src.ReadByte()
+		if err := CopyChunk(NewWriter(io.Discard, RIFF), src); err != ErrPartiallyRead {
+			t.Fatalf("expected ErrPartiallyRead, got %v", err)
+		}
+	})
+
+	t.Run("reports truncated source", func(t *testing.T) {
+		src := &chunk.Reader{ID: [4]byte{'d', 'a', 't', 'a'}, Size: 10, R: bytes.NewReader([]byte{1, 2})}
+		if err := CopyChunk(NewWriter(io.Discard, RIFF), src); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"slices"
+
+	"github.com/CWBudde/chunk"
+)
+
+// OutputOption configures the output of Merge and Rewrite.
+type OutputOption func(*outputConfig)
+
+type outputConfig struct {
+	dedup    *dedup
+	preserve bool
+}
+
+// DropDuplicates leaves out chunks whose ID and payload are byte for byte
+// the same as those of a chunk written before, such as repeated JUNK or PAD
+// chunks or the duplicated INFO lists of buggy exporters. Payloads are
+// compared by SHA-256. A chunk is only buffered when an earlier one has the
+// same ID and size, so large data chunks are still streamed.
+func DropDuplicates() OutputOption {
+	return func(c *outputConfig) {
+		c.dedup = &dedup{seen: make(map[dedupKey][][sha256.Size]byte)}
+	}
+}
+
+func newOutputConfig(opts []OutputOption) outputConfig {
+	var cfg outputConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+type dedupKey struct {
+	id   [4]byte
+	size int
+}
+
+// dedup remembers the digests of the chunks written so far, by ID and size.
+type dedup struct {
+	seen map[dedupKey][][sha256.Size]byte
+}
+
+// copy copies ch to cw with cp, usually CopyChunk, unless it duplicates a
+// chunk copied before. A nil dedup copies every chunk.
+func (d *dedup) copy(cw *Writer, ch *chunk.Reader, cp func(*Writer, *chunk.Reader) error) error {
+	if d == nil {
+		return cp(cw, ch)
+	}
+	k := dedupKey{ch.ID, ch.Size}
+	sums, ok := d.seen[k]
+	h := sha256.New()
+	if !ok {
+		// first of its kind: hash while streaming
+		err := cp(cw, &chunk.Reader{ID: ch.ID, Size: ch.Size, R: io.TeeReader(ch, h)})
+		d.seen[k] = [][sha256.Size]byte{[sha256.Size]byte(h.Sum(nil))}
+		return err
+	}
+	if ch.Pos != 0 {
+		return ErrPartiallyRead
+	}
+	data, err := io.ReadAll(io.LimitReader(ch, int64(ch.Size)))
+	if err != nil {
+		return err
+	}
+	if len(data) < ch.Size {
+		return io.ErrUnexpectedEOF
+	}
+	h.Write(data)
+	sum := [sha256.Size]byte(h.Sum(nil))
+	if slices.Contains(sums, sum) {
+		return nil
+	}
+	d.seen[k] = append(sums, sum)
+	return cp(cw, &chunk.Reader{ID: ch.ID, Size: ch.Size, R: bytes.NewReader(data)})
+}
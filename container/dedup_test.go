@@ -0,0 +1,53 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestDropDuplicates(t *testing.T) {
+	ids := func(t *testing.T, b []byte) string {
+		t.Helper()
+		s, err := NewScanner(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got string
+		for s.Next() {
+			p, _ := io.ReadAll(s.Chunk())
+			got += string(s.Chunk().ID[:]) + "=" + string(p) + " "
+		}
+		return got
+	}
+
+	t.Run("drops repeated chunks on rewrite", func(t *testing.T) {
+		src := buildWAV(t, "JUNK", "xx", "INAM", "a", "JUNK", "xx", "INAM", "b", "JUNK", "yy", "INAM", "a", "data", "samples")
+		var out bytes.Buffer
+		keep := func(*chunk.Reader) (Action, io.Reader) { return Keep, nil }
+		if err := Rewrite(&out, bytes.NewReader(src), keep, DropDuplicates()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := ids(t, out.Bytes()), "JUNK=xx INAM=a INAM=b JUNK=yy data=samples "; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		out.Reset()
+		if err := Rewrite(&out, bytes.NewReader(src), keep); err != nil || !bytes.Equal(out.Bytes(), src) {
+			t.Fatalf("expected an unchanged copy without the option (%v)", err)
+		}
+	})
+
+	t.Run("drops identical chunks kept from both sides on merge", func(t *testing.T) {
+		a := buildWAV(t, "fmt ", "format", "bext", "same")
+		b := buildWAV(t, "bext", "same", "bext", "other")
+		var out bytes.Buffer
+		if err := Merge(&out, bytes.NewReader(a), bytes.NewReader(b), KeepBoth, DropDuplicates()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := ids(t, out.Bytes()), "fmt =format bext=same bext=other "; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"strconv"
+)
+
+// ChangeKind is the kind of a Change found by Diff.
+type ChangeKind int
+
+// Kinds of changes.
+const (
+	Added ChangeKind = iota
+	Removed
+	Changed
+)
+
+var changeNames = [...]string{"added", "removed", "changed"}
+
+func (k ChangeKind) String() string {
+	if k >= 0 && int(k) < len(changeNames) {
+		return changeNames[k]
+	}
+	return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, so changes encode their
+// kind by name.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is a data chunk that differs between two containers.
+type Change struct {
+	Kind ChangeKind
+	// Path locates the chunk, see Diff.
+	Path string
+	// A and B are the chunk in either container, nil if it is missing.
+	A, B *Node
+}
+
+func (c Change) String() string {
+	return c.Kind.String() + " " + c.Path
+}
+
+// Diff compares the data chunks of the containers in a and b by path and
+// SHA-256 of their payload, so a test can assert that only the bext chunk of
+// a file changed. A path is made of the IDs of the chunk and the groups
+// enclosing it, groups with their type, e.g. "LIST:INFO/INAM"; repeated
+// siblings get their index, as in "JUNK[1]". Removed and changed chunks are
+// listed in the order of a, followed by the chunks added in b. A different
+// outermost group is reported as a change of the empty path.
+func Diff(a, b io.ReaderAt) ([]Change, error) {
+	ta, err := ParseTree(a, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tb, err := ParseTree(b, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	if ta.Root.ID != tb.Root.ID || ta.Root.Type != tb.Root.Type {
+		changes = append(changes, Change{Kind: Changed, A: ta.Root, B: tb.Root})
+	}
+	pa, pb := leafPaths(ta.Root, ""), leafPaths(tb.Root, "")
+	inB := make(map[string]*Node, len(pb))
+	for _, l := range pb {
+		inB[l.path] = l.node
+	}
+	inA := make(map[string]bool, len(pa))
+	for _, l := range pa {
+		inA[l.path] = true
+		nb := inB[l.path]
+		if nb == nil {
+			changes = append(changes, Change{Kind: Removed, Path: l.path, A: l.node})
+			continue
+		}
+		same, err := samePayload(l.node, nb)
+		if err != nil {
+			return changes, err
+		}
+		if !same {
+			changes = append(changes, Change{Kind: Changed, Path: l.path, A: l.node, B: nb})
+		}
+	}
+	for _, l := range pb {
+		if !inA[l.path] {
+			changes = append(changes, Change{Kind: Added, Path: l.path, B: l.node})
+		}
+	}
+	return changes, nil
+}
+
+type leaf struct {
+	path string
+	node *Node
+}
+
+// leafPaths lists the data chunks below n with their paths.
+func leafPaths(n *Node, prefix string) []leaf {
+	var leaves []leaf
+	for i, name := range childNames(n) {
+		c := n.Children[i]
+		if c.IsGroup() {
+			leaves = append(leaves, leafPaths(c, prefix+name+"/")...)
+		} else {
+			leaves = append(leaves, leaf{prefix + name, c})
+		}
+	}
+	return leaves
+}
+
+// childNames returns the path elements of the children of n: their IDs,
+// with the type for groups and the index for repeated siblings.
+func childNames(n *Node) []string {
+	names := make([]string, len(n.Children))
+	seen := make(map[string]int)
+	for i, c := range n.Children {
+		name := string(c.ID[:])
+		if c.IsGroup() {
+			name += ":" + string(c.Type[:])
+		}
+		if k := seen[name]; k > 0 {
+			seen[name]++
+			name += "[" + strconv.Itoa(k) + "]"
+		} else {
+			seen[name] = 1
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// samePayload reports whether a and b have equal payloads, hashing them
+// only if their sizes match.
+func samePayload(a, b *Node) (bool, error) {
+	if a.Size != b.Size {
+		return false, nil
+	}
+	ha, err := payloadSum(a)
+	if err != nil {
+		return false, err
+	}
+	hb, err := payloadSum(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ha, hb), nil
+}
+
+// payloadSum hashes the payload of n, as far as the file goes.
+func payloadSum(n *Node) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, n.Payload()); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
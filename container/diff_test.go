@@ -0,0 +1,61 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	diff := func(t *testing.T, a, b []byte) []string {
+		t.Helper()
+		changes, err := Diff(bytes.NewReader(a), bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		return got
+	}
+	expect := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+	}
+
+	t.Run("finds no changes in equal files", func(t *testing.T) {
+		src := buildNested(t)
+		expect(t, diff(t, src, bytes.Clone(src)))
+	})
+
+	t.Run("reports changed chunks only", func(t *testing.T) {
+		a := buildWAV(t, "fmt ", "format", "bext", "old", "data", "samples")
+		b := buildWAV(t, "fmt ", "format", "bext", "new", "data", "samples")
+		expect(t, diff(t, a, b), "changed bext")
+	})
+
+	t.Run("reports added and removed chunks by path", func(t *testing.T) {
+		a := buildWAV(t, "JUNK", "x", "JUNK", "y", "data", "samples")
+		b := buildNested(t)
+		expect(t, diff(t, a, b), "removed JUNK", "removed JUNK[1]", "added fmt ", "added LIST:INFO/INAM")
+	})
+
+	t.Run("reports different forms", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAIFFLike(t, NewWriter(&buf, IFF))
+		changes, err := Diff(bytes.NewReader(buildNested(t)), bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(changes) == 0 || changes[0].Kind != Changed || changes[0].Path != "" {
+			t.Fatalf("expected a changed root, got %v", changes)
+		}
+	})
+}
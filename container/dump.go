@@ -0,0 +1,206 @@
+package container
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/CWBudde/chunk"
+)
+
+// DumpOptions controls the reports written by Tree.WriteXML,
+// Tree.WriteYAML and Tree.WriteDOT.
+type DumpOptions struct {
+	// Preview is the number of leading payload bytes of data chunks included
+	// as hex; zero omits previews.
+	Preview int
+	// Describe adds the summary of data chunks with a registered
+	// describer, see chunk.Describe.
+	Describe bool
+}
+
+// xmlNode is the XML form of a Node.
+type xmlNode struct {
+	XMLName  xml.Name
+	Format   string    `xml:"format,attr,omitempty"`
+	ID       string    `xml:"id,attr"`
+	Type     string    `xml:"type,attr,omitempty"`
+	Offset   int64     `xml:"offset,attr"`
+	Size     int64     `xml:"size,attr"`
+	Describe string    `xml:"description,omitempty"`
+	Preview  string    `xml:"preview,omitempty"`
+	Children []xmlNode `xml:"chunk"`
+}
+
+// WriteXML writes the structure of t as an XML report for QC tools: a
+// container element holding nested chunk elements with their IDs, types,
+// offsets and sizes.
+//
+//	<container format="RIFF" id="RIFF" type="WAVE" offset="0" size="64">
+//	  <chunk id="fmt " offset="12" size="16">
+//	    <description>PCM 48kHz 16-bit stereo</description>
+//	    <preview>01000200</preview>
+//	  </chunk>
+//	</container>
+func (t *Tree) WriteXML(w io.Writer, opts DumpOptions) error {
+	root := t.xmlNode(t.Root, opts)
+	root.XMLName.Local = "container"
+	root.Format = t.Header.Format.String()
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (t *Tree) xmlNode(n *Node, opts DumpOptions) xmlNode {
+	x := xmlNode{
+		XMLName:  xml.Name{Local: "chunk"},
+		ID:       string(n.ID[:]),
+		Offset:   n.Offset,
+		Size:     n.Size,
+		Describe: describe(n, opts),
+		Preview:  preview(n, opts),
+	}
+	if n.IsGroup() {
+		x.Type = string(n.Type[:])
+	}
+	for _, c := range n.Children {
+		x.Children = append(x.Children, t.xmlNode(c, opts))
+	}
+	return x
+}
+
+// WriteYAML writes the structure of t as YAML, with the same content as
+// WriteXML:
+//
+//	format: RIFF
+//	id: "RIFF"
+//	type: "WAVE"
+//	offset: 0
+//	size: 64
+//	chunks:
+//	  - id: "fmt "
+//	    offset: 12
+//	    size: 16
+func (t *Tree) WriteYAML(w io.Writer, opts DumpOptions) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "format: %s\n", t.Header.Format)
+	writeYAMLNode(bw, t.Root, opts, "", "")
+	return bw.Flush()
+}
+
+// writeYAMLNode writes n as a mapping whose first line starts with first
+// and the others with indent.
+func writeYAMLNode(w *bufio.Writer, n *Node, opts DumpOptions, first, indent string) {
+	fmt.Fprintf(w, "%sid: %s\n", first, strconv.Quote(string(n.ID[:])))
+	if n.IsGroup() {
+		fmt.Fprintf(w, "%stype: %s\n", indent, strconv.Quote(string(n.Type[:])))
+	}
+	fmt.Fprintf(w, "%soffset: %d\n%ssize: %d\n", indent, n.Offset, indent, n.Size)
+	if d := describe(n, opts); d != "" {
+		fmt.Fprintf(w, "%sdescription: %q\n", indent, d)
+	}
+	if p := preview(n, opts); p != "" {
+		fmt.Fprintf(w, "%spreview: %q\n", indent, p)
+	}
+	if len(n.Children) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%schunks:\n", indent)
+	for _, c := range n.Children {
+		writeYAMLNode(w, c, opts, indent+"  - ", indent+"    ")
+	}
+}
+
+// Sizes of the boxes drawn by WriteDOT, in inches.
+const (
+	dotMaxWidth = 6.0
+	dotMinWidth = 0.3
+)
+
+// WriteDOT writes the chunk tree of t as a Graphviz graph, a box per chunk
+// labeled with its ID and size and an edge from every group to its chunks.
+// The area of a box is proportional to the size of the chunk, down to a
+// minimum width that keeps small chunks readable, so bloat such as large
+// JUNK chunks stands out; fillers are drawn grey. Render it with
+// `dot -Tsvg`.
+func (t *Tree) WriteDOT(w io.Writer, opts DumpOptions) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph chunks {\n")
+	bw.WriteString("\tnode [shape=box, style=filled, fixedsize=shape, fillcolor=\"#cfe2f3\"];\n")
+	id := 0
+	var walk func(n *Node) int
+	walk = func(n *Node) int {
+		self := id
+		id++
+		label := []string{n.ID.String(), fmt.Sprintf("%d bytes", n.Size)}
+		if n.IsGroup() {
+			label[0] += " " + n.Type.String()
+		}
+		if d := describe(n, opts); d != "" {
+			label = append(label, d)
+		}
+		width := dotMinWidth
+		if t.Root.Size > 0 {
+			width = max(dotMaxWidth*math.Sqrt(float64(n.Size)/float64(t.Root.Size)), dotMinWidth)
+		}
+		fmt.Fprintf(bw, "\tn%d [label=%s, width=%.2f, height=%.2f", self, dotQuote(strings.Join(label, "\n")), width, width/2)
+		switch {
+		case n.IsGroup():
+			bw.WriteString(", fillcolor=\"#f9cb9c\"")
+		case IsFiller(n.ID):
+			bw.WriteString(", fillcolor=\"#d9d9d9\"")
+		}
+		bw.WriteString("];\n")
+		for _, c := range n.Children {
+			fmt.Fprintf(bw, "\tn%d -> n%d;\n", self, walk(c))
+		}
+		return self
+	}
+	walk(t.Root)
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// dotQuote returns s as a DOT string with its lines centered. Control
+// characters and invalid UTF-8, as in damaged IDs, become dots.
+func dotQuote(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' || r == 0x7f {
+			return '.'
+		}
+		return r
+	}, strings.ToValidUTF8(s, "."))
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// preview returns the leading payload bytes of a data chunk as hex. Chunks
+// cut short by the end of the file show what there is.
+func preview(n *Node, opts DumpOptions) string {
+	if opts.Preview <= 0 || n.IsGroup() || n.tree == nil {
+		return ""
+	}
+	b := make([]byte, min(int64(opts.Preview), n.Size))
+	m, _ := io.ReadFull(n.Payload(), b)
+	return hex.EncodeToString(b[:m])
+}
+
+// describe returns the summary of a data chunk if opts asks for it.
+func describe(n *Node, opts DumpOptions) string {
+	if !opts.Describe || n.IsGroup() || n.tree == nil {
+		return ""
+	}
+	return chunk.Describe(n.Chunk())
+}
@@ -0,0 +1,135 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestTree_Dump(t *testing.T) {
+	tree, err := ParseTree(bytes.NewReader(buildNested(t)), TreeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("writes XML", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := tree.WriteXML(&buf, DumpOptions{Preview: 4}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got xmlNode
+		if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid XML: %v\n%s", err, buf.Bytes())
+		}
+		if got.XMLName.Local != "container" || got.Format != "RIFF" || got.Type != "WAVE" || len(got.Children) != 3 {
+			t.Fatalf("unexpected root %+v", got)
+		}
+		if c := got.Children[0]; c.ID != "fmt " || c.Offset != 12 || c.Size != 6 || c.Preview != "666f726d" {
+			t.Fatalf("unexpected chunk %+v", c)
+		}
+		if l := got.Children[1]; l.Type != "INFO" || l.Preview != "" || len(l.Children) != 1 || l.Children[0].Preview != "6f6464" {
+			t.Fatalf("unexpected list %+v", l)
+		}
+	})
+
+	t.Run("writes YAML", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := tree.WriteYAML(&buf, DumpOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := strings.Join([]string{
+			`format: RIFF`,
+			`id: "RIFF"`,
+			`type: "WAVE"`,
+			`offset: 0`,
+			`size: 58`,
+			`chunks:`,
+			`  - id: "fmt "`,
+			`    offset: 12`,
+			`    size: 6`,
+			`  - id: "LIST"`,
+			`    type: "INFO"`,
+			`    offset: 26`,
+			`    size: 16`,
+			`    chunks:`,
+			`      - id: "INAM"`,
+			`        offset: 38`,
+			`        size: 3`,
+			`  - id: "data"`,
+			`    offset: 50`,
+			`    size: 7`,
+			``,
+		}, "\n")
+		if buf.String() != want {
+			t.Fatalf("unexpected YAML\n%s", buf.String())
+		}
+	})
+
+	t.Run("writes DOT", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := tree.WriteDOT(&buf, DumpOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := strings.Join([]string{
+			`digraph chunks {`,
+			`	node [shape=box, style=filled, fixedsize=shape, fillcolor="#cfe2f3"];`,
+			`	n0 [label="RIFF WAVE\n58 bytes", width=6.00, height=3.00, fillcolor="#f9cb9c"];`,
+			`	n1 [label="fmt \n6 bytes", width=1.93, height=0.96];`,
+			`	n0 -> n1;`,
+			`	n2 [label="LIST INFO\n16 bytes", width=3.15, height=1.58, fillcolor="#f9cb9c"];`,
+			`	n3 [label="INAM\n3 bytes", width=1.36, height=0.68];`,
+			`	n2 -> n3;`,
+			`	n0 -> n2;`,
+			`	n4 [label="data\n7 bytes", width=2.08, height=1.04];`,
+			`	n0 -> n4;`,
+			`}`,
+			``,
+		}, "\n")
+		if buf.String() != want {
+			t.Fatalf("unexpected DOT\n%s", buf.String())
+		}
+	})
+
+	t.Run("quotes DOT labels", func(t *testing.T) {
+		if got := dotQuote("a\"b\\\x00\n\xff"); got != `"a\"b\\.\n."` {
+			t.Fatalf("unexpected label %s", got)
+		}
+	})
+
+	t.Run("marshals JSON", func(t *testing.T) {
+		b, err := json.Marshal(tree.Root.Children[1])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `{"id":"LIST","type":"INFO","offset":26,"size":16,"chunks":[{"id":"INAM","offset":38,"size":3}]}`
+		if string(b) != want {
+			t.Fatalf("unexpected JSON %s", b)
+		}
+	})
+
+	t.Run("adds descriptions", func(t *testing.T) {
+		chunk.RegisterDescriber("INAM", func(r *chunk.Reader) (string, error) {
+			b, err := io.ReadAll(r)
+			return "title " + string(b), err
+		})
+		var buf bytes.Buffer
+		if err := tree.WriteYAML(&buf, DumpOptions{Describe: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "        size: 3\n        description: \"title odd\"\n") {
+			t.Fatalf("missing description\n%s", buf.String())
+		}
+		buf.Reset()
+		if err := tree.WriteXML(&buf, DumpOptions{Describe: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "<description>title odd</description>") {
+			t.Fatalf("missing description\n%s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package container
+
+import (
+	"errors"
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrChunkNotFound is returned when an edit refers to a chunk that is not
+// part of the container.
+var ErrChunkNotFound = errors.New("container: chunk not found")
+
+type positionKind int
+
+const (
+	atEnd positionKind = iota
+	atIndex
+	beforeID
+	afterID
+)
+
+// Position selects where Insert places a new chunk within the outermost
+// group.
+type Position struct {
+	kind  positionKind
+	index int
+	id    [4]byte
+}
+
+// AtEnd places the new chunk after all existing chunks.
+var AtEnd = Position{kind: atEnd}
+
+// AtIndex places the new chunk so it becomes the i-th chunk of the group; an
+// index past the last chunk appends it.
+func AtIndex(i int) Position {
+	return Position{kind: atIndex, index: i}
+}
+
+// Before places the new chunk in front of the first chunk with the given ID.
+func Before(id string) Position {
+	return Position{kind: beforeID, id: fourCC(id)}
+}
+
+// After places the new chunk behind the first chunk with the given ID.
+func After(id string) Position {
+	return Position{kind: afterID, id: fourCC(id)}
+}
+
+// Insert copies the container read from src to dst with a new chunk holding
+// the payload read from r inserted at pos. The container size is recomputed.
+// Before and After find NUL padded chunk IDs too, unless StrictIDs is given.
+func Insert(dst io.Writer, src io.Reader, id string, r io.Reader, pos Position, opts ...LookupOption) error {
+	if err := chunk.ValidateFourCC(id); err != nil {
+		return err
+	}
+	e := edits{strict: lookup(opts).strict}
+	e.insert(id, r, pos)
+	return e.apply(dst, src)
+}
+
+// InsertFile inserts a chunk into the container stored at path, see Insert.
+// The edit runs as a transaction, so path is left untouched on error.
+func InsertFile(path, id string, r io.Reader, pos Position, opts ...LookupOption) error {
+	tx, err := Begin(path, opts...)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := tx.Insert(id, r, pos); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// edit copies the outermost group of the container in src to dst, calling
+// fn for every chunk with its index and finish before the group is closed.
+// fn is responsible for writing (or not writing) the chunk to cw.
+func edit(dst io.Writer, src io.Reader, fn func(cw *Writer, ch *chunk.Reader, i int) error, finish func(cw *Writer) error) error {
+	sc, err := NewScanner(src)
+	if err != nil {
+		return err
+	}
+	cw := NewWriter(dst, sc.Header.Format)
+	cw.ValidateID = nil
+	if sc.DS64 != nil {
+		for _, e := range sc.DS64.Table {
+			cw.DS64Table = append(cw.DS64Table, e.ID.String())
+		}
+	}
+	if err := beginOuter(cw, sc.Header); err != nil {
+		return err
+	}
+	if sc.DS64 != nil {
+		cw.SetSampleCount(sc.DS64.SampleCount)
+	}
+	// a chunk left open by copyOpen is ended once the scanner has read its
+	// pad bytes, when it moves on
+	var pad []byte
+	sc.ValidatePad = func(_ chunk.FourCC, _ int64, b []byte) error {
+		pad = b
+		return nil
+	}
+	for i := 0; sc.Next(); i++ {
+		if err := cw.endOpen(pad); err != nil {
+			return err
+		}
+		pad = nil
+		if err := fn(cw, sc.Chunk(), i); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if err := cw.endOpen(pad); err != nil {
+		return err
+	}
+	if finish != nil {
+		if err := finish(cw); err != nil {
+			return err
+		}
+	}
+	return cw.Close()
+}
+
+// edits is a set of insertions, removals and replacements applied to a
+// container in a single pass.
+type edits struct {
+	inserts []pendingInsert
+	removes []Match
+	replace map[[4]byte]io.Reader
+	strict  bool
+}
+
+type pendingInsert struct {
+	id       [4]byte
+	r        io.Reader
+	pos      Position
+	inserted bool
+}
+
+func (e *edits) insert(id string, r io.Reader, pos Position) {
+	e.inserts = append(e.inserts, pendingInsert{id: fourCC(id), r: r, pos: pos})
+}
+
+func (e *edits) replaceWith(id string, r io.Reader) {
+	if e.replace == nil {
+		e.replace = map[[4]byte]io.Reader{}
+	}
+	e.replace[fourCC(id)] = r
+}
+
+// apply streams the container from src to dst with all edits applied. A
+// replacement only affects the first chunk with its ID; replacements and
+// anchored inserts whose chunk is missing fail with ErrChunkNotFound.
+func (e *edits) apply(dst io.Writer, src io.Reader) error {
+	replaced := map[[4]byte]bool{}
+	return edit(dst, src, func(cw *Writer, ch *chunk.Reader, i int) error {
+		if err := e.insertAt(cw, func(p Position) bool {
+			return p.kind == atIndex && p.index == i || p.kind == beforeID && sameID(ch.ID, p.id, e.strict)
+		}); err != nil {
+			return err
+		}
+		id := ch.ID
+		if _, ok := e.replace[id]; !ok && !e.strict {
+			id = chunk.FourCC(id).Normalize()
+		}
+		var err error
+		switch r, ok := e.replace[id]; {
+		case ok && !replaced[id]:
+			replaced[id] = true
+			err = writeChunk(cw, ch.ID, r)
+		case e.removed(ch):
+		default:
+			err = CopyChunk(cw, ch)
+		}
+		if err != nil {
+			return err
+		}
+		return e.insertAt(cw, func(p Position) bool {
+			return p.kind == afterID && sameID(ch.ID, p.id, e.strict)
+		})
+	}, func(cw *Writer) error {
+		for id := range e.replace {
+			if !replaced[id] {
+				return ErrChunkNotFound
+			}
+		}
+		for i := range e.inserts {
+			ins := &e.inserts[i]
+			if ins.inserted {
+				continue
+			}
+			if ins.pos.kind == beforeID || ins.pos.kind == afterID {
+				return ErrChunkNotFound
+			}
+			ins.inserted = true
+			if err := writeChunk(cw, ins.id, ins.r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertAt writes the pending inserts whose position is selected by at.
+func (e *edits) insertAt(cw *Writer, at func(Position) bool) error {
+	for i := range e.inserts {
+		ins := &e.inserts[i]
+		if ins.inserted || !at(ins.pos) {
+			continue
+		}
+		ins.inserted = true
+		if err := writeChunk(cw, ins.id, ins.r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *edits) removed(ch *chunk.Reader) bool {
+	for _, match := range e.removes {
+		if match(ch) {
+			return true
+		}
+	}
+	return false
+}
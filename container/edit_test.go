@@ -0,0 +1,105 @@
+package container
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "data", "samples")
+
+	tests := []struct {
+		name string
+		pos  Position
+		want []byte
+	}{
+		{"before", Before("fmt "), buildWAV(t, "bext", "meta", "fmt ", "format", "data", "samples")},
+		{"after", After("fmt "), buildWAV(t, "fmt ", "format", "bext", "meta", "data", "samples")},
+		{"at index", AtIndex(1), buildWAV(t, "fmt ", "format", "bext", "meta", "data", "samples")},
+		{"index past end", AtIndex(9), buildWAV(t, "fmt ", "format", "data", "samples", "bext", "meta")},
+		{"at end", AtEnd, buildWAV(t, "fmt ", "format", "data", "samples", "bext", "meta")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Insert(&out, bytes.NewReader(src), "bext", strings.NewReader("meta"), tc.pos); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tc.want) {
+				t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), tc.want)
+			}
+		})
+	}
+
+	t.Run("missing anchor", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Insert(&out, bytes.NewReader(src), "bext", strings.NewReader("meta"), After("LIST"))
+		if err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+	})
+
+	t.Run("NUL-padded anchor", func(t *testing.T) {
+		padded := buildWAV(t, "fmt_", "format", "data", "samples")
+		padded[15] = 0 // fmt\x00
+		var out bytes.Buffer
+		if err := Insert(&out, bytes.NewReader(padded), "bext", strings.NewReader("meta"), After("fmt")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt_", "format", "bext", "meta", "data", "samples")
+		want[15] = 0
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+		err := Insert(&out, bytes.NewReader(padded), "bext", strings.NewReader("meta"), Before("fmt"), StrictIDs())
+		if err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound with StrictIDs, got %v", err)
+		}
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Insert(&out, bytes.NewReader(src), "id3", strings.NewReader(""), AtEnd); err == nil {
+			t.Fatal("expected error for invalid ID")
+		}
+	})
+}
+
+func TestInsertFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "take.wav")
+	if err := os.WriteFile(path, buildWAV(t, "fmt ", "format", "data", "samples"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InsertFile(path, "iXML", strings.NewReader("<BWFXML/>"), AtEnd); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := buildWAV(t, "fmt ", "format", "data", "samples", "iXML", "<BWFXML/>")
+	if !bytes.Equal(got, want) {
+		t.Fatalf("unexpected file\n got % x\nwant % x", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Fatalf("expected mode 0640, got %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("expected temporary file to be gone, got %d entries", len(entries))
+	}
+
+	t.Run("leaves original on error", func(t *testing.T) {
+		if err := InsertFile(path, "bext", strings.NewReader(""), Before("LIST")); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+		after, _ := os.ReadFile(path)
+		if !bytes.Equal(after, want) {
+			t.Fatal("expected file to be unchanged")
+		}
+	})
+}
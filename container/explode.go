@@ -0,0 +1,178 @@
+package container
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ManifestName is the name of the manifest file written by Explode.
+const ManifestName = "manifest.json"
+
+// ErrManifestPath is returned by Implode for manifest entries whose file or
+// directory is absolute or lies outside the directory of their group.
+var ErrManifestPath = errors.New("container: manifest path outside the exploded directory")
+
+// Manifest describes an exploded container, see Explode.
+type Manifest struct {
+	Format Format          `json:"format"`
+	ID     chunk.FourCC    `json:"id"`
+	Type   chunk.FourCC    `json:"type"`
+	Chunks []ManifestEntry `json:"chunks"`
+}
+
+// ManifestEntry is a chunk of an exploded container. Data chunks name the
+// file holding their payload, groups the directory holding their chunks.
+// Paths are relative to the directory of the enclosing group.
+type ManifestEntry struct {
+	ID     chunk.FourCC    `json:"id"`
+	Size   int64           `json:"size"`
+	File   string          `json:"file,omitempty"`
+	Type   chunk.FourCC    `json:"type,omitzero"`
+	Dir    string          `json:"dir,omitempty"`
+	Chunks []ManifestEntry `json:"chunks,omitempty"`
+}
+
+// Explode writes every chunk payload of the container read from src to a
+// file <index>_<id>.bin in dir, recursing into groups such as LIST as
+// subdirectories <index>_<id>, and describes the layout in a manifest. The
+// files can be inspected, diffed or edited and put back together with
+// Implode.
+func Explode(src io.Reader, dir string) error {
+	sc, err := NewScanner(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	m := Manifest{Format: sc.Header.Format, ID: sc.Header.ID, Type: sc.Header.Type}
+	if m.Chunks, err = explodeChunks(sc.Scanner, dir, sc.Header.Format.ByteOrder()); err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestName), append(buf, '\n'), 0o644)
+}
+
+func explodeChunks(s *chunk.Scanner, dir string, order binary.ByteOrder) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	for i := 0; s.Next(); i++ {
+		ch := s.Chunk()
+		e := ManifestEntry{ID: ch.ID, Size: int64(ch.Size)}
+		name := fmt.Sprintf("%03d_%s", i, fileSafe(ch.ID))
+		if IsGroup(ch.ID) && ch.Size >= 4 {
+			typ, err := ch.ReadFourCC()
+			if err != nil {
+				return nil, err
+			}
+			e.Type, e.Dir = typ, name
+			sub := filepath.Join(dir, name)
+			if err := os.MkdirAll(sub, 0o755); err != nil {
+				return nil, err
+			}
+			if e.Chunks, err = explodeChunks(chunk.NewScanner(ch, order), sub, order); err != nil {
+				return nil, err
+			}
+		} else {
+			e.File = name + ".bin"
+			if err := writeFile(filepath.Join(dir, e.File), ch); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, s.Err()
+}
+
+// Implode reassembles a container exploded into dir and writes it to dst.
+// Sizes are taken from the files, so payloads may have been edited. Paths
+// in the manifest must be local, see filepath.IsLocal, so a manifest
+// cannot make Implode read files outside dir.
+func Implode(dir string, dst io.Writer) error {
+	buf, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return err
+	}
+	var m Manifest
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return err
+	}
+	cw := NewWriter(dst, m.Format)
+	cw.ValidateID = nil
+	if err := beginOuter(cw, Header{ID: m.ID, Type: m.Type, Format: m.Format}); err != nil {
+		return err
+	}
+	if err := implodeChunks(cw, dir, m.Chunks); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+func implodeChunks(cw *Writer, dir string, entries []ManifestEntry) error {
+	for _, e := range entries {
+		if e.Dir != "" {
+			if !filepath.IsLocal(e.Dir) {
+				return ErrManifestPath
+			}
+			if err := cw.beginGroupID(e.ID, e.Type); err != nil {
+				return err
+			}
+			if err := implodeChunks(cw, filepath.Join(dir, e.Dir), e.Chunks); err != nil {
+				return err
+			}
+			if err := cw.EndChunk(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !filepath.IsLocal(e.File) {
+			return ErrManifestPath
+		}
+		f, err := os.Open(filepath.Join(dir, e.File))
+		if err != nil {
+			return err
+		}
+		err = writeChunk(cw, e.ID, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fileSafe turns a chunk ID into a file name component, replacing anything
+// but letters, digits, '-' and '_' with '_'.
+func fileSafe(id [4]byte) string {
+	b := make([]byte, 4)
+	for i, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			b[i] = c
+		default:
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,103 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func buildNested(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	cw := NewWriter(&buf, RIFF)
+	cw.BeginForm("WAVE")
+	ch, _ := cw.BeginChunk("fmt ")
+	ch.Write([]byte("format"))
+	cw.EndChunk()
+	cw.BeginList("INFO")
+	ch, _ = cw.BeginChunk("INAM")
+	ch.Write([]byte("odd"))
+	cw.EndChunk()
+	cw.EndChunk()
+	ch, _ = cw.BeginChunk("data")
+	ch.Write([]byte("samples"))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExplode(t *testing.T) {
+	src := buildNested(t)
+	dir := filepath.Join(t.TempDir(), "out")
+	if err := Explode(bytes.NewReader(src), dir); err != nil {
+		t.Fatalf("Explode: %v", err)
+	}
+
+	files := map[string]string{
+		"000_fmt_.bin":          "format",
+		"001_LIST/000_INAM.bin": "odd",
+		"002_data.bin":          "samples",
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestName)); err != nil {
+		t.Fatalf("expected manifest: %v", err)
+	}
+
+	t.Run("implode restores the container", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Implode(dir, &out); err != nil {
+			t.Fatalf("Implode: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), src) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), src)
+		}
+	})
+
+	t.Run("implode picks up edited payloads", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "001_LIST", "000_INAM.bin"), []byte("edited"), 0o644)
+		var out bytes.Buffer
+		if err := Implode(dir, &out); err != nil {
+			t.Fatalf("Implode: %v", err)
+		}
+		s, err := NewScanner(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatalf("NewScanner: %v", err)
+		}
+		var sizes []int
+		for s.Next() {
+			sizes = append(sizes, s.Chunk().Size)
+		}
+		if len(sizes) != 3 || sizes[1] != 4+8+6 {
+			t.Fatalf("unexpected chunk sizes %v", sizes)
+		}
+	})
+
+	t.Run("implode rejects paths outside the directory", func(t *testing.T) {
+		for _, e := range []ManifestEntry{
+			{ID: chunk.FourCC([]byte("data")), File: "../secret"},
+			{ID: chunk.FourCC([]byte("data")), File: filepath.Join(dir, "000_fmt_.bin")},
+			{ID: chunk.FourCC([]byte("LIST")), Type: chunk.FourCC([]byte("INFO")), Dir: ".."},
+		} {
+			evil := t.TempDir()
+			b, _ := json.Marshal(Manifest{Format: RIFF, ID: chunk.FourCC([]byte("RIFF")), Type: chunk.FourCC([]byte("WAVE")), Chunks: []ManifestEntry{e}})
+			os.WriteFile(filepath.Join(evil, ManifestName), b, 0o644)
+			if err := Implode(evil, io.Discard); err != ErrManifestPath {
+				t.Fatalf("%+v: expected ErrManifestPath, got %v", e, err)
+			}
+		}
+	})
+}
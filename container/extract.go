@@ -0,0 +1,192 @@
+package container
+
+import (
+	"io"
+	"io/fs"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/CWBudde/chunk"
+)
+
+// extractSegment is the largest read ExtractAll issues at once; bigger
+// payloads are split so a single huge chunk is read in parallel as well.
+var extractSegment int64 = 1 << 20
+
+// Extract copies the payload of the nth (counting from 0) chunk with the given
+// ID in the outermost group of the container read from src to dst. Reading
+// stops right after that chunk, so pulling a small chunk from the front of a
+// huge file is cheap, and nothing is buffered. It returns the number of bytes
+// copied. The ID matches NUL padded chunk IDs too, unless StrictIDs is given.
+func Extract(src io.Reader, id string, nth int, dst io.Writer, opts ...LookupOption) (int64, error) {
+	cfg := lookup(opts)
+	sc, err := NewScanner(src)
+	if err != nil {
+		return 0, err
+	}
+	target := fourCC(id)
+	for sc.Next() {
+		ch := sc.Chunk()
+		if !sameID(ch.ID, target, cfg.strict) {
+			continue
+		}
+		if nth > 0 {
+			nth--
+			continue
+		}
+		n, err := io.Copy(dst, ch)
+		if err == nil && n < int64(ch.Size) {
+			err = io.ErrUnexpectedEOF
+		}
+		return n, err
+	}
+	if err := sc.Err(); err != nil {
+		return 0, err
+	}
+	return 0, ErrChunkNotFound
+}
+
+// Inject is the counterpart of Extract: it copies the container read from src
+// to dst with the payload of the first chunk with the given ID replaced by the
+// content of payload, or with such a chunk appended if there is none. The
+// payload length does not need to be known up front; sizes are patched in
+// place when dst implements io.WriteSeeker and the output is buffered
+// otherwise. A chunk found through its NUL padded ID keeps that ID.
+func Inject(src io.Reader, id string, payload io.Reader, dst io.Writer, opts ...LookupOption) error {
+	if err := chunk.ValidateFourCC(id); err != nil {
+		return err
+	}
+	cfg := lookup(opts)
+	target := fourCC(id)
+	found := false
+	return edit(dst, src, func(cw *Writer, ch *chunk.Reader, _ int) error {
+		if !found && sameID(ch.ID, target, cfg.strict) {
+			found = true
+			return writeChunk(cw, ch.ID, payload)
+		}
+		return CopyChunk(cw, ch)
+	}, func(cw *Writer) error {
+		if found {
+			return nil
+		}
+		return writeChunk(cw, target, payload)
+	})
+}
+
+// readerSize returns the length of ra if it tells it through a Size or
+// Stat method.
+func readerSize(ra io.ReaderAt) (int64, bool) {
+	switch r := ra.(type) {
+	case interface{ Size() int64 }:
+		return r.Size(), true
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size(), true
+		}
+	}
+	return 0, false
+}
+
+// Extracted is a chunk payload read by ExtractAll.
+type Extracted struct {
+	Entry
+	Data []byte
+}
+
+// ExtractAll reads the payloads of every chunk in the outermost group of the
+// container in ra whose ID is one of ids, in file order. The chunk headers
+// are located with Index; the payloads are then read with up to concurrency
+// ReadAt calls in flight, large payloads split into 1 MiB segments, which
+// keeps fast disks and object stores busy. A concurrency below 1 is taken as
+// 1. Declared sizes beyond the end of ra are reported as
+// io.ErrUnexpectedEOF before anything is allocated. The length of ra is
+// taken from its Size method, as bytes.Reader and io.SectionReader have,
+// or from Stat, as os.File has; for other readers the last byte of every
+// payload is read to check it exists.
+func ExtractAll(ra io.ReaderAt, ids []string, concurrency int, opts ...LookupOption) ([]Extracted, error) {
+	cfg := lookup(opts)
+	want := make([]chunk.FourCC, len(ids))
+	for i, id := range ids {
+		if err := chunk.ValidateFourCC(id); err != nil {
+			return nil, err
+		}
+		want[i] = fourCC(id)
+	}
+	_, entries, err := Index(ra)
+	if err != nil {
+		return nil, err
+	}
+	size, sized := readerSize(ra)
+	var out []Extracted
+	for _, e := range entries {
+		if !slices.ContainsFunc(want, func(id chunk.FourCC) bool { return sameID(e.ID, id, cfg.strict) }) {
+			continue
+		}
+		end := e.Offset + 8 + e.Size
+		switch {
+		case e.Size < 0 || e.Size > math.MaxInt || end < e.Offset:
+			return nil, io.ErrUnexpectedEOF
+		case sized && end > size:
+			return nil, io.ErrUnexpectedEOF
+		case !sized && e.Size > 0:
+			var b [1]byte
+			if n, _ := ra.ReadAt(b[:], end-1); n != 1 {
+				return nil, io.ErrUnexpectedEOF
+			}
+		}
+		out = append(out, Extracted{Entry: e})
+	}
+	for i := range out {
+		out[i].Data = make([]byte, out[i].Size)
+	}
+
+	type segment struct {
+		dst []byte
+		off int64
+	}
+	segments := make(chan segment)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		failed   = make(chan struct{})
+	)
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range segments {
+				n, err := ra.ReadAt(s.dst, s.off)
+				if n == len(s.dst) {
+					continue
+				}
+				if err == nil || err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				once.Do(func() {
+					firstErr = err
+					close(failed)
+				})
+			}
+		}()
+	}
+
+schedule:
+	for _, x := range out {
+		for off := int64(0); off < x.Size; off += extractSegment {
+			s := segment{dst: x.Data[off:min(off+extractSegment, x.Size)], off: x.Offset + 8 + off}
+			select {
+			case segments <- s:
+			case <-failed:
+				break schedule
+			}
+		}
+	}
+	close(segments)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
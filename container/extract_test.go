@@ -0,0 +1,256 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestExtract(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "LIST", "INFOa", "LIST", "adtlb", "data", "samples")
+
+	t.Run("first match", func(t *testing.T) {
+		var out bytes.Buffer
+		n, err := Extract(bytes.NewReader(src), "data", 0, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 7 || out.String() != "samples" {
+			t.Fatalf("expected 'samples', got %q (%d)", out.String(), n)
+		}
+	})
+
+	t.Run("nth match", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := Extract(bytes.NewReader(src), "LIST", 1, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != "adtlb" {
+			t.Fatalf("expected 'adtlb', got %q", out.String())
+		}
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := Extract(bytes.NewReader(src), "LIST", 2, &out); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+	})
+
+	t.Run("stops after the chunk", func(t *testing.T) {
+		// everything after the requested chunk is garbage
+		truncated := append(append([]byte(nil), src[:26]...), 0xFF, 0xFF)
+		var out bytes.Buffer
+		if _, err := Extract(bytes.NewReader(truncated), "fmt ", 0, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != "format" {
+			t.Fatalf("expected 'format', got %q", out.String())
+		}
+	})
+
+	t.Run("truncated payload", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := Extract(bytes.NewReader(src[:len(src)-4]), "data", 0, &out); err == nil {
+			t.Fatal("expected error for truncated payload")
+		}
+	})
+
+	t.Run("NUL-padded IDs", func(t *testing.T) {
+		padded := buildWAV(t, "fmt_", "format", "data", "samples")
+		padded[15] = 0 // fmt\x00
+		var out bytes.Buffer
+		if _, err := Extract(bytes.NewReader(padded), "fmt", 0, &out); err != nil || out.String() != "format" {
+			t.Fatalf("expected 'format', got %q (%v)", out.String(), err)
+		}
+		if _, err := Extract(bytes.NewReader(padded), "fmt", 0, &out, StrictIDs()); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound with StrictIDs, got %v", err)
+		}
+	})
+}
+
+// unsized hides the length of a reader so the writer cannot rely on it.
+type unsized struct{ r io.Reader }
+
+func (u unsized) Read(p []byte) (int, error) { return u.r.Read(p) }
+
+func TestInject(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "iXML", "<old/>", "data", "samples")
+
+	t.Run("replaces existing chunk", func(t *testing.T) {
+		var out bytes.Buffer
+		payload := unsized{strings.NewReader("<newer/>")}
+		if err := Inject(bytes.NewReader(src), "iXML", payload, &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "iXML", "<newer/>", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("appends missing chunk to seekable output", func(t *testing.T) {
+		var sb seekBuffer
+		if err := Inject(bytes.NewReader(src), "bext", unsized{strings.NewReader("odd")}, &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "iXML", "<old/>", "data", "samples", "bext", "odd")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("replaces NUL-padded IDs", func(t *testing.T) {
+		padded := buildWAV(t, "fmt_", "format", "data", "samples")
+		padded[15] = 0 // fmt\x00
+		var out bytes.Buffer
+		if err := Inject(bytes.NewReader(padded), "fmt ", strings.NewReader("FORMAT"), &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := bytes.Replace(padded, []byte("format"), []byte("FORMAT"), 1)
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("round trips with Extract", func(t *testing.T) {
+		var out, got bytes.Buffer
+		Inject(bytes.NewReader(src), "iXML", strings.NewReader("<x/>"), &out)
+		if _, err := Extract(&out, "iXML", 0, &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.String() != "<x/>" {
+			t.Fatalf("expected '<x/>', got %q", got.String())
+		}
+	})
+}
+
+// countingReaderAt records the largest number of concurrent reads.
+type countingReaderAt struct {
+	r            io.ReaderAt
+	mu           sync.Mutex
+	active, peak int
+	reads        int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.mu.Lock()
+	c.active++
+	c.reads++
+	c.peak = max(c.peak, c.active)
+	c.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	defer func() {
+		c.mu.Lock()
+		c.active--
+		c.mu.Unlock()
+	}()
+	return c.r.ReadAt(p, off)
+}
+
+func TestExtractAll(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "LIST", "INFOa", "data", "samples", "LIST", "adtlb")
+
+	t.Run("returns matches in file order", func(t *testing.T) {
+		got, err := ExtractAll(bytes.NewReader(src), []string{"LIST", "fmt "}, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"format", "INFOa", "adtlb"}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d chunks, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if string(got[i].Data) != want[i] {
+				t.Fatalf("chunk %d: expected %q, got %q", i, want[i], got[i].Data)
+			}
+		}
+		if got[0].Offset != 12 || got[1].ID.String() != "LIST" {
+			t.Fatalf("unexpected entries %+v %+v", got[0].Entry, got[1].Entry)
+		}
+	})
+
+	t.Run("splits payloads across concurrent reads", func(t *testing.T) {
+		defer func(v int64) { extractSegment = v }(extractSegment)
+		extractSegment = 2
+
+		ra := &countingReaderAt{r: bytes.NewReader(src)}
+		got, err := ExtractAll(ra, []string{"data", "fmt "}, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(got[0].Data) != "format" || string(got[1].Data) != "samples" {
+			t.Fatalf("unexpected payloads %q %q", got[0].Data, got[1].Data)
+		}
+		// 3 + 4 segments on top of the index reads
+		if ra.peak < 2 || ra.peak > 3 || ra.reads < 7 {
+			t.Fatalf("expected up to 3 parallel reads, got peak %d over %d reads", ra.peak, ra.reads)
+		}
+	})
+
+	t.Run("rejects sizes beyond the input", func(t *testing.T) {
+		truncated := src[:len(src)-3]
+		if _, err := ExtractAll(bytes.NewReader(truncated), []string{"LIST"}, 2); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+		// without Size or Stat the size is probed with a read instead
+		ra := struct{ io.ReaderAt }{bytes.NewReader(truncated)}
+		if _, err := ExtractAll(ra, []string{"LIST"}, 2); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("rejects huge ds64 sizes in files", func(t *testing.T) {
+		b := []byte("RF64\xff\xff\xff\xffWAVEds64")
+		b = binary.LittleEndian.AppendUint32(b, 28)
+		b = binary.LittleEndian.AppendUint64(b, 1<<61) // RIFF size
+		b = binary.LittleEndian.AppendUint64(b, 1<<61) // data size
+		b = binary.LittleEndian.AppendUint64(b, 0)
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = append(b, "data\xff\xff\xff\xffabcd"...)
+		path := filepath.Join(t.TempDir(), "huge.wav")
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		defer f.Close()
+		if _, err := ExtractAll(f, []string{"data"}, 1); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+		if _, err := ExtractAll(struct{ io.ReaderAt }{f}, []string{"data"}, 1); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF without Stat, got %v", err)
+		}
+	})
+
+	t.Run("NUL-padded IDs", func(t *testing.T) {
+		padded := buildWAV(t, "fmt_", "format", "data", "samples")
+		padded[15] = 0 // fmt\x00
+		for _, strict := range []bool{false, true} {
+			var opts []LookupOption
+			if strict {
+				opts = append(opts, StrictIDs())
+			}
+			got, err := ExtractAll(bytes.NewReader(padded), []string{"fmt "}, 1, opts...)
+			if want := map[bool]int{false: 1, true: 0}[strict]; err != nil || len(got) != want {
+				t.Fatalf("StrictIDs=%v: expected %d chunks, got %d (%v)", strict, want, len(got), err)
+			}
+		}
+	})
+
+	t.Run("validates IDs", func(t *testing.T) {
+		if _, err := ExtractAll(bytes.NewReader(src), []string{"da"}, 1); err != chunk.ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package container
+
+import "github.com/CWBudde/chunk"
+
+// Filter selects the chunks a Walker or Scanner hands out, by the chunk and
+// its depth: 1 for the chunks of the outermost group, 2 for those of a
+// LIST inside it and so on. Like a Match it may peek at the payload but
+// must not consume it.
+type Filter func(ch *chunk.Reader, depth int) bool
+
+// WhereID selects chunks with any of the given IDs, matched as by MatchID.
+func WhereID(ids ...string) Filter {
+	return WhereMatch(MatchID(ids...))
+}
+
+// WhereSizeAbove selects chunks whose payload is larger than n bytes.
+func WhereSizeAbove(n int64) Filter {
+	return func(ch *chunk.Reader, _ int) bool {
+		return int64(ch.Size) > n
+	}
+}
+
+// WhereDepth selects the chunks found at the given depth.
+func WhereDepth(depth int) Filter {
+	return func(_ *chunk.Reader, d int) bool {
+		return d == depth
+	}
+}
+
+// WhereMatch selects the chunks m matches, at any depth.
+func WhereMatch(m Match) Filter {
+	return func(ch *chunk.Reader, _ int) bool {
+		return m(ch)
+	}
+}
+
+// And selects the chunks all of fs select, trying them in order.
+func And(fs ...Filter) Filter {
+	return func(ch *chunk.Reader, depth int) bool {
+		for _, f := range fs {
+			if !f(ch, depth) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or selects the chunks any of fs selects, trying them in order.
+func Or(fs ...Filter) Filter {
+	return func(ch *chunk.Reader, depth int) bool {
+		for _, f := range fs {
+			if f(ch, depth) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not selects the chunks f does not select.
+func Not(f Filter) Filter {
+	return func(ch *chunk.Reader, depth int) bool {
+		return !f(ch, depth)
+	}
+}
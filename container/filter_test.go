@@ -0,0 +1,79 @@
+package container
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestFilter(t *testing.T) {
+	src := buildNested(t) // fmt (6), LIST:INFO with INAM (3), data (7)
+	walk := func(t *testing.T, f Filter) string {
+		t.Helper()
+		var got []string
+		w := Walker{Filter: f}
+		w.Default = func(ch *chunk.Reader) error {
+			got = append(got, string(ch.ID[:]))
+			return nil
+		}
+		if err := w.Walk(bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return strings.Join(got, ",")
+	}
+
+	for _, c := range []struct {
+		name string
+		f    Filter
+		want string
+	}{
+		{"none", nil, "fmt ,INAM,data"},
+		{"by ID", WhereID("fmt ", "INAM"), "fmt ,INAM"},
+		{"by size", WhereSizeAbove(6), "data"},
+		{"by depth", WhereDepth(2), "INAM"},
+		{"by match", WhereMatch(MatchIDStrict("data")), "data"},
+		{"all of", And(WhereDepth(1), WhereSizeAbove(3)), "fmt ,data"},
+		{"any of", Or(WhereID("INAM"), WhereSizeAbove(6)), "INAM,data"},
+		{"none of", Not(Or(WhereID("fmt "), WhereDepth(2))), "data"},
+	} {
+		t.Run("walker "+c.name, func(t *testing.T) {
+			if got := walk(t, c.f); got != c.want {
+				t.Fatalf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+
+	t.Run("hands grouped chunks to group handlers", func(t *testing.T) {
+		var got []string
+		w := Walker{Filter: WhereID("LIST")}
+		w.Handle("LIST", func(ch *chunk.Reader) error {
+			got = append(got, string(ch.ID[:]))
+			return nil
+		})
+		w.Default = func(ch *chunk.Reader) error {
+			t.Fatalf("unexpected chunk %s", ch.ID)
+			return nil
+		}
+		if err := w.Walk(bytes.NewReader(src)); err != nil || len(got) != 1 {
+			t.Fatalf("expected the LIST chunk, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("scanner", func(t *testing.T) {
+		s, err := NewScanner(bytes.NewReader(src))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.Filter = Or(WhereID("LIST"), WhereSizeAbove(6))
+		var got []string
+		for s.Next() {
+			got = append(got, string(s.Chunk().ID[:])+"@"+strconv.FormatInt(s.Offset(), 10))
+		}
+		if s.Err() != nil || strings.Join(got, ",") != "LIST@26,data@50" {
+			t.Fatalf("unexpected chunks %q (%v)", got, s.Err())
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// fuzzSeeds are minimal containers of every format plus truncated and
+// oversized variants.
+func fuzzSeeds(f *testing.F) {
+	f.Helper()
+	var nested bytes.Buffer
+	cw := NewWriter(&nested, IFF)
+	cw.BeginForm("AIFF")
+	cw.BeginList("INFO")
+	ch, _ := cw.BeginChunk("NAME")
+	ch.Write([]byte("abc"))
+	cw.Close()
+	f.Add(nested.Bytes())
+
+	var wav bytes.Buffer
+	cw = NewWriter(&wav, RIFF)
+	cw.BeginForm("WAVE")
+	ch, _ = cw.BeginChunk("data")
+	ch.Write([]byte{1, 2, 3})
+	cw.Close()
+	f.Add(wav.Bytes())
+	f.Add(wav.Bytes()[:15])
+
+	ds64 := make([]byte, 12+36+8)
+	copy(ds64, "RF64\xff\xff\xff\xffWAVEds64")
+	binary.LittleEndian.PutUint32(ds64[16:], 28)
+	binary.LittleEndian.PutUint64(ds64[20:], 1<<63)
+	binary.LittleEndian.PutUint64(ds64[28:], 1<<62)
+	copy(ds64[48:], "data\xff\xff\xff\xff")
+	f.Add(ds64)
+}
+
+func FuzzNewScanner(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// once through io.SectionReaders, once as a plain stream
+		fuzzScan(t, bytes.NewReader(data))
+		fuzzScan(t, struct{ io.Reader }{bytes.NewReader(data)})
+	})
+}
+
+func fuzzScan(t *testing.T, r io.Reader) {
+	s, err := NewScanner(r)
+	if err != nil {
+		return
+	}
+	for s.Next() {
+		ch := s.Chunk()
+		if ch.Size < 0 || s.Offset() < 0 {
+			t.Fatalf("negative size %d or offset %d", ch.Size, s.Offset())
+		}
+		if IsGroup(ch.ID) {
+			sub := chunk.NewScanner(ch, s.Header.Format.ByteOrder())
+			for sub.Next() {
+			}
+		}
+	}
+}
+
+func FuzzParseTree(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tree, _ := ParseTree(bytes.NewReader(data), TreeOptions{MaxNodes: 10000})
+		if tree != nil && tree.Nodes > len(data) {
+			t.Fatalf("%d nodes from %d bytes", tree.Nodes, len(data))
+		}
+		Index(bytes.NewReader(data))
+		ExtractAll(bytes.NewReader(data), []string{"data", "LIST"}, 2)
+		Extract(bytes.NewReader(data), "data", 0, io.Discard)
+	})
+}
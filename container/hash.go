@@ -0,0 +1,45 @@
+package container
+
+import (
+	"hash"
+	"io"
+)
+
+// Digest is the checksum of a chunk payload, reported when the chunk is
+// ended.
+type Digest struct {
+	Entry
+	Sum []byte
+}
+
+// HashChunks feeds the payload of every data chunk begun afterwards into a
+// fresh hash from newHash and calls report with its sum on EndChunk, e.g. to
+// build a manifest while exporting. Groups, alignment fillers and the RF64
+// ds64 reservation are not hashed. Pass a nil newHash to stop hashing.
+//
+//	cw.HashChunks(sha256.New, func(d container.Digest) {
+//		fmt.Printf("%s %x\n", d.ID, d.Sum)
+//	})
+func (cw *Writer) HashChunks(newHash func() hash.Hash, report func(Digest)) {
+	cw.newHash, cw.report = newHash, report
+}
+
+// startHash tees the payload of f into a new hash if hashing is enabled.
+func (cw *Writer) startHash(f *frame) {
+	if cw.newHash == nil || f.group || cw.padding || cw.raw {
+		return
+	}
+	f.hash = cw.newHash()
+	f.ch.W = io.MultiWriter(f.ch.W, f.hash)
+}
+
+// reportHash passes the digest of an ended chunk to the report callback.
+func (cw *Writer) reportHash(f *frame) {
+	if f.hash == nil || cw.report == nil {
+		return
+	}
+	cw.report(Digest{
+		Entry: Entry{ID: f.id, Offset: f.start, Size: int64(f.ch.Size)},
+		Sum:   f.hash.Sum(nil),
+	})
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestWriter_HashChunks(t *testing.T) {
+	write := func(t *testing.T, cw *Writer) []Digest {
+		t.Helper()
+		var got []Digest
+		cw.HashChunks(sha256.New, func(d Digest) { got = append(got, d) })
+		if err := cw.Align(8, "JUNK"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cw.BeginForm("WAVE")
+		ch, _ := cw.BeginChunk("fmt ")
+		ch.Write([]byte("abc"))
+		cw.EndChunk()
+		cw.BeginList("INFO")
+		ch, _ = cw.BeginChunk("INAM")
+		ch.Write([]byte("name"))
+		if err := cw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+
+	check := func(t *testing.T, got []Digest) {
+		t.Helper()
+		if len(got) != 2 {
+			t.Fatalf("expected 2 digests, got %d", len(got))
+		}
+		abc, name := sha256.Sum256([]byte("abc")), sha256.Sum256([]byte("name"))
+		if got[0].ID.String() != "fmt " || got[0].Offset != 24 || got[0].Size != 3 || !bytes.Equal(got[0].Sum, abc[:]) {
+			t.Fatalf("unexpected digest %+v", got[0])
+		}
+		if got[1].ID.String() != "INAM" || got[1].Size != 4 || !bytes.Equal(got[1].Sum, name[:]) {
+			t.Fatalf("unexpected digest %+v", got[1])
+		}
+	}
+
+	t.Run("buffered", func(t *testing.T) {
+		check(t, write(t, NewWriter(&bytes.Buffer{}, RIFF)))
+	})
+
+	t.Run("seeking", func(t *testing.T) {
+		check(t, write(t, NewWriter(&seekBuffer{}, RIFF)))
+	})
+
+	t.Run("skips the ds64 reservation", func(t *testing.T) {
+		got := write(t, NewWriter(&bytes.Buffer{}, RF64))
+		if len(got) != 2 || got[0].ID.String() != "fmt " {
+			t.Fatalf("unexpected digests %+v", got)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrInvalidID3 is returned by WriteID3 for data that does not start with an
+// ID3v2 header.
+var ErrInvalidID3 = errors.New("container: not an ID3v2 tag")
+
+// WriteID3 writes tag, a complete ID3v2 tag as produced by a tag library,
+// as a chunk of its own so players that only understand ID3 find the
+// metadata: "id3 " in RIFF based containers, "ID3 " in IFF ones. The tag is
+// copied verbatim; its syncsafe sizes are left to the tag library.
+func WriteID3(cw *Writer, tag []byte) error {
+	if len(tag) < 10 || !bytes.HasPrefix(tag, []byte("ID3")) {
+		return ErrInvalidID3
+	}
+	id := "id3 "
+	if cw.format == IFF {
+		id = "ID3 "
+	}
+	ch, err := cw.BeginChunk(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ch.Write(tag); err != nil {
+		return err
+	}
+	return cw.EndChunk()
+}
+
+// IsID3 reports whether id is one of the chunk IDs used for embedded ID3v2
+// tags.
+func IsID3(id [4]byte) bool {
+	switch string(id[:]) {
+	case "id3 ", "ID3 ", "ID32":
+		return true
+	}
+	return false
+}
+
+// ID3Text returns the text frames of an ID3v2.3 or v2.4 tag by frame ID,
+// such as TIT2 (title) or TPE1 (artist). User defined TXXX frames are keyed
+// "TXXX:" followed by their description, and the values of frames holding
+// several are joined with "/". Other frames, and compressed or encrypted
+// ones, are skipped.
+func ID3Text(tag []byte) (map[string]string, error) {
+	if len(tag) < 10 || !bytes.HasPrefix(tag, []byte("ID3")) {
+		return nil, ErrInvalidID3
+	}
+	version, flags := tag[3], tag[5]
+	if version != 3 && version != 4 {
+		return nil, fmt.Errorf("container: ID3v2.%d tags are not supported", version)
+	}
+	size := int(syncsafe(tag[6:10]))
+	if len(tag) < 10+size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	body := tag[10 : 10+size]
+	if version == 3 && flags&0x80 != 0 {
+		body = unsync(body)
+	}
+	if flags&0x40 != 0 { // extended header
+		if len(body) < 4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := int(binary.BigEndian.Uint32(body)) + 4 // v2.3 leaves out the size itself
+		if version == 4 {
+			n = int(syncsafe(body))
+		}
+		if n > len(body) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		body = body[n:]
+	}
+
+	text := make(map[string]string)
+	for len(body) >= 10 && body[0] != 0 { // padding follows the last frame
+		id := string(body[:4])
+		n := int(binary.BigEndian.Uint32(body[4:8]))
+		if version == 4 {
+			n = int(syncsafe(body[4:8]))
+		}
+		format := body[9]
+		if n > len(body)-10 {
+			return text, io.ErrUnexpectedEOF
+		}
+		frame := body[10 : 10+n]
+		body = body[10+n:]
+
+		skip := format&0xc0 != 0 // compressed or encrypted
+		if version == 4 {
+			skip = format&0x0c != 0
+			if format&0x02 != 0 {
+				frame = unsync(frame)
+			}
+			if format&0x01 != 0 && len(frame) >= 4 { // data length indicator
+				frame = frame[4:]
+			}
+		}
+		if skip || id[0] != 'T' || len(frame) == 0 {
+			continue
+		}
+		values := strings.Split(strings.TrimRight(id3String(frame[0], frame[1:]), "\x00"), "\x00")
+		if id == "TXXX" {
+			id, values = "TXXX:"+values[0], values[1:]
+		}
+		text[id] = strings.Join(values, "/")
+	}
+	return text, nil
+}
+
+// syncsafe decodes a 28-bit integer stored 7 bits per byte.
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+// unsync removes the zero bytes the unsynchronisation scheme inserts after
+// 0xff.
+func unsync(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xff, 0x00}, []byte{0xff})
+}
+
+// id3String decodes the text of a frame in the given ID3 text encoding:
+// ISO-8859-1, UTF-16 with byte order mark, UTF-16BE or UTF-8.
+func id3String(encoding byte, b []byte) string {
+	switch encoding {
+	case 0:
+		r := make([]rune, len(b))
+		for i, c := range b {
+			r[i] = rune(c)
+		}
+		return string(r)
+	case 1, 2:
+		u := make([]uint16, len(b)/2)
+		for i := range u {
+			u[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+		// a byte order mark starts every string of a UTF-16 frame
+		little := false
+		for i := 0; i < len(u); i++ {
+			switch {
+			case encoding == 1 && u[i] == 0xfffe:
+				little = true
+			case encoding == 1 && u[i] == 0xfeff:
+				little = false
+			default:
+				if little {
+					u[i] = u[i]>>8 | u[i]<<8
+				}
+				continue
+			}
+			u = append(u[:i], u[i+1:]...)
+			i--
+		}
+		return string(utf16.Decode(u))
+	}
+	return string(b)
+}
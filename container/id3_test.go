@@ -0,0 +1,105 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteID3(t *testing.T) {
+	tag := []byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 1, 'x'}
+
+	for _, c := range []struct {
+		format Format
+		id     string
+	}{{RIFF, "id3 "}, {IFF, "ID3 "}} {
+		t.Run(c.format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			cw := NewWriter(&buf, c.format)
+			cw.BeginForm("TEST")
+			if err := WriteID3(cw, tag); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cw.Close()
+
+			s, err := NewScanner(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !s.Next() {
+				t.Fatalf("expected chunk: %v", s.Err())
+			}
+			ch := s.Chunk()
+			if string(ch.ID[:]) != c.id || !IsID3(ch.ID) || ch.Size != len(tag) {
+				t.Fatalf("unexpected chunk %q of %d bytes", ch.ID, ch.Size)
+			}
+		})
+	}
+
+	t.Run("rejects other data", func(t *testing.T) {
+		cw := NewWriter(&bytes.Buffer{}, RIFF)
+		cw.BeginForm("WAVE")
+		if err := WriteID3(cw, []byte("TAG not a v2 tag")); err != ErrInvalidID3 {
+			t.Fatalf("expected ErrInvalidID3, got %v", err)
+		}
+	})
+}
+
+// id3Tag builds an ID3v2 tag of the given version from frames.
+func id3Tag(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 4)...) // padding
+	n := len(body)
+	tag := []byte{'I', 'D', '3', version, 0, 0, byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+	return append(tag, body...)
+}
+
+func id3Frame(id string, payload string) []byte {
+	n := len(payload)
+	return append([]byte{id[0], id[1], id[2], id[3], byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n), 0, 0}, payload...)
+}
+
+func TestID3Text(t *testing.T) {
+	t.Run("decodes text frames", func(t *testing.T) {
+		tag := id3Tag(3,
+			id3Frame("TIT2", "\x00Caf\xe9"),
+			id3Frame("TPE1", "\x01\xff\xfeA\x00b\x00\x00\x00"),
+			id3Frame("TALB", "\x02\x00L\x00P"),
+			id3Frame("TXXX", "\x03mood\x00calm"),
+			id3Frame("APIC", "\x00image/png\x00"),
+		)
+		text, err := ID3Text(tag)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]string{"TIT2": "Café", "TPE1": "Ab", "TALB": "LP", "TXXX:mood": "calm"}
+		if len(text) != len(want) {
+			t.Fatalf("got %q, want %q", text, want)
+		}
+		for k, v := range want {
+			if text[k] != v {
+				t.Fatalf("got %q, want %q", text, want)
+			}
+		}
+	})
+
+	t.Run("joins multiple values of v2.4 frames", func(t *testing.T) {
+		text, err := ID3Text(id3Tag(4, id3Frame("TPE1", "\x03A\x00B")))
+		if err != nil || text["TPE1"] != "A/B" {
+			t.Fatalf("unexpected result %q, %v", text, err)
+		}
+	})
+
+	t.Run("rejects bad tags", func(t *testing.T) {
+		if _, err := ID3Text([]byte("TAG not a v2 tag")); err != ErrInvalidID3 {
+			t.Fatalf("expected ErrInvalidID3, got %v", err)
+		}
+		if _, err := ID3Text(id3Tag(2)); err == nil {
+			t.Fatalf("expected error for ID3v2.2")
+		}
+		tag := id3Tag(3, id3Frame("TIT2", "\x00title"))
+		tag[17] = 100 // frame size beyond the tag
+		if _, err := ID3Text(tag); err == nil {
+			t.Fatalf("expected error for truncated frame")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package container
+
+import (
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Entry is the location of a chunk within a container.
+type Entry struct {
+	ID chunk.FourCC
+	// Offset is the absolute offset of the chunk header.
+	Offset int64
+	// Size is the payload size, excluding the header and pad byte.
+	Size int64
+}
+
+// End returns the offset right after the chunk including its pad byte.
+func (e Entry) End() int64 {
+	return e.Offset + 8 + e.Size + e.Size%2
+}
+
+// Index reads the header and the chunk headers of the outermost group of the
+// container in ra, without reading any payload. RF64/BW64 sizes of data and
+// the chunks in the table are taken from the ds64 chunk, which itself is not
+// listed.
+func Index(ra io.ReaderAt) (Header, []Entry, error) {
+	sr := io.NewSectionReader(ra, 0, math.MaxInt64)
+	h, err := ReadHeader(sr)
+	if err != nil {
+		return h, nil, err
+	}
+	off := int64(12)
+	var ds *DS64
+	var sizes *ds64Sizes
+	if h.Format == RF64 || h.Format == BW64 {
+		var n int64
+		if ds, n, err = readDS64(sr); err != nil {
+			return h, nil, err
+		}
+		sizes = ds.cursor()
+		off += n
+		if h.Size == math.MaxUint32 {
+			h.Size = int64(ds.RIFFSize)
+		}
+	}
+
+	order := h.Format.ByteOrder()
+	end := 8 + h.Size
+	var entries []Entry
+	for off+8 <= end {
+		var hdr [8]byte
+		if _, err := ra.ReadAt(hdr[:], off); err != nil {
+			return h, entries, io.ErrUnexpectedEOF
+		}
+		e := Entry{Offset: off, Size: int64(order.Uint32(hdr[4:]))}
+		copy(e.ID[:], hdr[:4])
+		if ds != nil && e.Size == math.MaxUint32 {
+			if n, ok := sizes.size(e.ID); ok {
+				e.Size = n
+			}
+		}
+		entries = append(entries, e)
+		off = e.End()
+	}
+	return h, entries, nil
+}
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	t.Run("lists top-level chunks", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "abc", "data", "samples")
+		h, entries, err := Index(bytes.NewReader(src))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if h.Format != RIFF || h.Type.String() != "WAVE" {
+			t.Fatalf("unexpected header %+v", h)
+		}
+		want := []Entry{
+			{ID: [4]byte{'f', 'm', 't', ' '}, Offset: 12, Size: 3},
+			{ID: [4]byte{'d', 'a', 't', 'a'}, Offset: 24, Size: 7},
+		}
+		if len(entries) != len(want) {
+			t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+		}
+		for i := range want {
+			if entries[i] != want[i] {
+				t.Fatalf("entry %d: expected %+v, got %+v", i, want[i], entries[i])
+			}
+		}
+		if entries[1].End() != int64(len(src)) {
+			t.Fatalf("expected last chunk to end at %d, got %d", len(src), entries[1].End())
+		}
+	})
+
+	t.Run("resolves RF64 data size", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		var buf bytes.Buffer
+		writeBW64(t, NewWriter(&buf, BW64), make([]byte, 40))
+		_, entries, err := Index(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Size != 40 {
+			t.Fatalf("unexpected entries %+v", entries)
+		}
+	})
+
+	t.Run("reports truncation", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "abc", "data", "samples")
+		if _, _, err := Index(bytes.NewReader(src[:26])); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+}
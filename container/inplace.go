@@ -0,0 +1,114 @@
+package container
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNoRoom is returned when a replacement payload does not fit into the
+// space of the chunk it replaces.
+var ErrNoRoom = errors.New("container: replacement does not fit in place")
+
+// ReadWriterAt is the interface of files that can be edited in place.
+type ReadWriterAt interface {
+	io.ReaderAt
+	io.WriterAt
+}
+
+// InPlaceOption configures ReplaceInPlace.
+type InPlaceOption func(*inPlaceConfig)
+
+type inPlaceConfig struct {
+	consumeFiller bool
+	strict        bool
+}
+
+// ConsumeFiller lets ReplaceInPlace grow a chunk into the filler chunks
+// (JUNK, junk, PAD, FLLR) directly before and after it. Whatever space is
+// left over is written back as a single JUNK chunk, so typical metadata edits
+// never need a full rewrite as long as the writer reserved some slack.
+func ConsumeFiller() InPlaceOption {
+	return func(c *inPlaceConfig) {
+		c.consumeFiller = true
+	}
+}
+
+// InPlaceStrictIDs makes ReplaceInPlace find the chunk by its exact ID, see
+// StrictIDs.
+func InPlaceStrictIDs() InPlaceOption {
+	return func(c *inPlaceConfig) {
+		c.strict = true
+	}
+}
+
+// IsFiller reports whether id names a chunk that only reserves space.
+func IsFiller(id [4]byte) bool {
+	switch string(id[:]) {
+	case "JUNK", "junk", "PAD ", "FLLR":
+		return true
+	}
+	return false
+}
+
+// ReplaceInPlace overwrites the payload of the first chunk with the given ID
+// without rewriting the rest of f. The payload must have the same size as the
+// current one, or be small enough that the freed space can hold a JUNK chunk
+// (at least 8 bytes after padding), which is then written behind it with a
+// zeroed payload. With ConsumeFiller adjacent filler chunks add to the
+// available space. The container size stays the same either way. As with
+// Extract, NUL padded chunk IDs match unless InPlaceStrictIDs is given.
+func ReplaceInPlace(f ReadWriterAt, id string, payload []byte, opts ...InPlaceOption) error {
+	var cfg inPlaceConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	h, entries, err := Index(f)
+	if err != nil {
+		return err
+	}
+	target := fourCC(id)
+	for i, e := range entries {
+		if !sameID(e.ID, target, cfg.strict) {
+			continue
+		}
+		start, end := e.Offset, e.End()
+		if cfg.consumeFiller {
+			for j := i - 1; j >= 0 && IsFiller(entries[j].ID); j-- {
+				start = entries[j].Offset
+			}
+			for j := i + 1; j < len(entries) && IsFiller(entries[j].ID); j++ {
+				end = entries[j].End()
+			}
+		}
+		if start == e.Offset && end == e.End() && int64(len(payload)) == e.Size {
+			_, err := f.WriteAt(payload, e.Offset+8)
+			return err
+		}
+		return replaceRegion(f, h, e.ID, start, end, payload)
+	}
+	return ErrChunkNotFound
+}
+
+// replaceRegion writes a chunk with the given ID and payload at start and
+// fills the remaining space up to end with a JUNK chunk.
+func replaceRegion(f io.WriterAt, h Header, id [4]byte, start, end int64, payload []byte) error {
+	n := int64(len(payload))
+	used := 8 + n + n%2
+	free := end - start - used
+	if free < 0 || (free > 0 && free < 8) {
+		return ErrNoRoom
+	}
+
+	order := h.Format.ByteOrder()
+	buf := make([]byte, used+free)
+	copy(buf[:4], id[:])
+	order.PutUint32(buf[4:], uint32(n))
+	copy(buf[8:], payload)
+	if free > 0 {
+		junk := buf[used:]
+		copy(junk[:4], "JUNK")
+		order.PutUint32(junk[4:], uint32(free-8))
+	}
+	_, err := f.WriteAt(buf, start)
+	return err
+}
@@ -0,0 +1,120 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReplaceInPlace(t *testing.T) {
+	t.Run("same size", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "bext", "2024-01-01", "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", []byte("2025-12-31")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "bext", "2025-12-31", "data", "samples")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("smaller payload leaves JUNK", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "iXML", "0123456789abcdef", "data", "samples")}
+		size := len(sb.buf)
+		if err := ReplaceInPlace(sb, "iXML", []byte("short")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 16 bytes shrink to 5 + pad, leaving 10 bytes: a JUNK chunk of 2
+		want := buildWAV(t, "iXML", "short", "JUNK", "\x00\x00", "data", "samples")
+		if len(sb.buf) != size {
+			t.Fatalf("expected file size %d, got %d", size, len(sb.buf))
+		}
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("no room", func(t *testing.T) {
+		src := buildWAV(t, "bext", "abcd", "data", "samples")
+		sb := &seekBuffer{buf: append([]byte(nil), src...)}
+		if err := ReplaceInPlace(sb, "bext", []byte("abcdef")); err != ErrNoRoom {
+			t.Fatalf("expected ErrNoRoom, got %v", err)
+		}
+		if err := ReplaceInPlace(sb, "bext", []byte("ab")); err != ErrNoRoom {
+			t.Fatalf("expected ErrNoRoom for less than a JUNK header, got %v", err)
+		}
+		if !bytes.Equal(sb.buf, src) {
+			t.Fatal("expected file to be unchanged")
+		}
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", nil); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+	})
+
+	t.Run("NUL-padded IDs", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "fmt_", "format", "data", "samples")}
+		sb.buf[15] = 0 // fmt\x00
+		if err := ReplaceInPlace(sb, "fmt", []byte("format"), InPlaceStrictIDs()); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound with InPlaceStrictIDs, got %v", err)
+		}
+		want := bytes.Replace(sb.buf, []byte("format"), []byte("FORMAT"), 1)
+		if err := ReplaceInPlace(sb, "fmt", []byte("FORMAT")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+}
+
+func TestReplaceInPlace_ConsumeFiller(t *testing.T) {
+	t.Run("grows into following JUNK", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "bext", "ab", "JUNK", "0123456789", "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", []byte("abcdef"), ConsumeFiller()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "bext", "abcdef", "JUNK", "\x00\x00\x00\x00\x00\x00", "data", "samples")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("consumes the filler completely", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "bext", "ab", "PAD ", "xy", "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", []byte("abcdefghijkl"), ConsumeFiller()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "bext", "abcdefghijkl", "data", "samples")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("uses preceding filler", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "JUNK", "12345678", "iXML", "<a/>", "data", "samples")}
+		if err := ReplaceInPlace(sb, "iXML", []byte("<abcdefg/>"), ConsumeFiller()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "iXML", "<abcdefg/>", "JUNK", "\x00\x00", "data", "samples")
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("still fails without enough filler", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "bext", "ab", "JUNK", "", "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", []byte("abcdefghijkl"), ConsumeFiller()); err != ErrNoRoom {
+			t.Fatalf("expected ErrNoRoom, got %v", err)
+		}
+	})
+
+	t.Run("ignores filler without the option", func(t *testing.T) {
+		sb := &seekBuffer{buf: buildWAV(t, "bext", "ab", "JUNK", "0123456789", "data", "samples")}
+		if err := ReplaceInPlace(sb, "bext", []byte("abcdef")); err != ErrNoRoom {
+			t.Fatalf("expected ErrNoRoom, got %v", err)
+		}
+	})
+}
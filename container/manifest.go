@@ -0,0 +1,74 @@
+package container
+
+import (
+	"encoding/hex"
+	"io"
+	"sort"
+)
+
+// Hashes maps the paths of the data chunks of a container, as in Diff, to
+// the hex encoded SHA-256 of their payloads. Archives keep it next to a
+// file to detect silent corruption of single chunks rather than of the
+// whole file; its JSON form is an object of paths and sums.
+type Hashes map[string]string
+
+// HashPayloads returns the hashes of the container in ra. Every payload is
+// read once, streamed through the hash.
+func HashPayloads(ra io.ReaderAt) (Hashes, error) {
+	t, err := ParseTree(ra, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := hashLeaves(t)
+	return h, err
+}
+
+// hashLeaves returns the hashes of the data chunks of t and the chunks in
+// file order.
+func hashLeaves(t *Tree) (Hashes, []leaf, error) {
+	leaves := leafPaths(t.Root, "")
+	h := make(Hashes, len(leaves))
+	for _, l := range leaves {
+		sum, err := payloadSum(l.node)
+		if err != nil {
+			return h, leaves, err
+		}
+		h[l.path] = hex.EncodeToString(sum)
+	}
+	return h, leaves, nil
+}
+
+// Verify hashes the container in ra and returns the chunks that do not
+// match h: changed and added ones in file order, with B set to the chunk,
+// followed by the removed ones by path. No changes mean the payloads are
+// intact.
+func (h Hashes) Verify(ra io.ReaderAt) ([]Change, error) {
+	t, err := ParseTree(ra, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	got, leaves, err := hashLeaves(t)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, l := range leaves {
+		switch sum, ok := h[l.path]; {
+		case !ok:
+			changes = append(changes, Change{Kind: Added, Path: l.path, B: l.node})
+		case sum != got[l.path]:
+			changes = append(changes, Change{Kind: Changed, Path: l.path, B: l.node})
+		}
+	}
+	var removed []string
+	for path := range h {
+		if _, ok := got[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		changes = append(changes, Change{Kind: Removed, Path: path})
+	}
+	return changes, nil
+}
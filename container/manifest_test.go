@@ -0,0 +1,44 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestHashPayloads(t *testing.T) {
+	src := buildNested(t)
+	h, err := HashPayloads(bytes.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sum := sha256.Sum256([]byte("odd"))
+	if len(h) != 3 || h["LIST:INFO/INAM"] != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected hashes %v", h)
+	}
+
+	t.Run("verifies intact files", func(t *testing.T) {
+		changes, err := h.Verify(bytes.NewReader(src))
+		if err != nil || len(changes) != 0 {
+			t.Fatalf("expected no changes, got %v, %v", changes, err)
+		}
+	})
+
+	t.Run("finds corrupt, added and removed chunks", func(t *testing.T) {
+		corrupt := bytes.Clone(src)
+		corrupt[60] ^= 0xff // inside the data payload
+		stale := Hashes{"fmt ": h["fmt "], "data": h["data"], "bext": h["data"]}
+		changes, err := stale.Verify(bytes.NewReader(corrupt))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(changes); got != "[added LIST:INFO/INAM changed data removed bext]" {
+			t.Fatalf("unexpected changes %s", got)
+		}
+		if changes[1].B == nil || changes[1].B.Offset != 50 {
+			t.Fatalf("expected the data chunk, got %+v", changes[1].B)
+		}
+	})
+}
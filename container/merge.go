@@ -0,0 +1,186 @@
+package container
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrFormatMismatch is returned when containers of different formats are
+// combined.
+var ErrFormatMismatch = errors.New("container: container formats differ")
+
+// MergePolicy decides which chunk wins when both containers passed to Merge
+// have a chunk with the same ID (LIST chunks are compared by list type).
+type MergePolicy int
+
+const (
+	// KeepFirst keeps the chunk of the first container.
+	KeepFirst MergePolicy = iota
+	// KeepSecond puts the chunks of the second container in its place.
+	KeepSecond
+	// KeepBoth writes the chunks of the second container right after the one
+	// of the first.
+	KeepBoth
+)
+
+// Merge writes a container holding the chunks of a followed by the chunks of
+// b that a does not have. Conflicting chunks are resolved by policy. Both
+// containers must have the same format; the form type is taken from a.
+// With DropDuplicates, chunks repeating one already written, e.g. when
+// KeepBoth meets identical chunks, are left out. For RF64 and BW64 the
+// ds64 tables of the inputs reserve the table of the output, so the large
+// chunks kept keep their 64-bit sizes.
+func Merge(dst io.Writer, a, b io.ReaderAt, policy MergePolicy, opts ...OutputOption) error {
+	cfg := newOutputConfig(opts)
+	ha, ea, err := Index(a)
+	if err != nil {
+		return err
+	}
+	hb, eb, err := Index(b)
+	if err != nil {
+		return err
+	}
+	if ha.Format != hb.Format {
+		return ErrFormatMismatch
+	}
+
+	keysA := make(map[mergeKey]bool, len(ea))
+	for _, e := range ea {
+		keysA[keyOf(a, e)] = true
+	}
+	fromB := map[mergeKey][]Entry{}
+	var onlyB []Entry
+	for _, e := range eb {
+		k := keyOf(b, e)
+		if keysA[k] {
+			fromB[k] = append(fromB[k], e)
+		} else {
+			onlyB = append(onlyB, e)
+		}
+	}
+
+	cw := NewWriter(dst, ha.Format)
+	cw.ValidateID = nil
+	dsA, err := ds64At(a, ha)
+	if err != nil {
+		return err
+	}
+	dsB, err := ds64At(b, hb)
+	if err != nil {
+		return err
+	}
+	cw.DS64Table = mergeDS64Table(dsA.Table, dsB.Table, policy)
+	if err := beginOuter(cw, ha); err != nil {
+		return err
+	}
+	emitted := map[mergeKey]bool{}
+	for _, e := range ea {
+		k := keyOf(a, e)
+		others, conflict := fromB[k]
+		if !conflict || policy == KeepFirst {
+			if err := cfg.copyEntry(cw, a, e); err != nil {
+				return err
+			}
+			continue
+		}
+		if policy == KeepBoth {
+			if err := cfg.copyEntry(cw, a, e); err != nil {
+				return err
+			}
+		}
+		if emitted[k] {
+			continue
+		}
+		emitted[k] = true
+		for _, o := range others {
+			if err := cfg.copyEntry(cw, b, o); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range onlyB {
+		if err := cfg.copyEntry(cw, b, e); err != nil {
+			return err
+		}
+	}
+	return cw.Close()
+}
+
+// mergeDS64Table returns the IDs to reserve ds64 table slots for when
+// merging containers with the tables a and b. An ID gets as many slots as
+// the input listing it more often, since one of two conflicting chunks is
+// dropped; KeepBoth and LIST chunks, which conflict only by list type,
+// may keep the large chunks of both inputs and get the slots of both.
+func mergeDS64Table(a, b []DS64Size, policy MergePolicy) []string {
+	count := func(table []DS64Size) map[chunk.FourCC]int {
+		m := map[chunk.FourCC]int{}
+		for _, e := range table {
+			m[e.ID]++
+		}
+		return m
+	}
+	na, nb := count(a), count(b)
+	var ids []string
+	for _, e := range append(append([]DS64Size(nil), a...), b...) {
+		id := e.ID
+		n := max(na[id], nb[id])
+		if policy == KeepBoth || id == (chunk.FourCC{'L', 'I', 'S', 'T'}) {
+			n = na[id] + nb[id]
+		}
+		for range n {
+			ids = append(ids, id.String())
+		}
+		// every ID once, in the order of first appearance
+		na[id], nb[id] = 0, 0
+	}
+	return ids
+}
+
+// ds64At reads the ds64 chunk of the RF64 or BW64 container in ra with
+// header h. It returns an empty DS64 for other formats.
+func ds64At(ra io.ReaderAt, h Header) (*DS64, error) {
+	if h.Format != RF64 && h.Format != BW64 {
+		return &DS64{}, nil
+	}
+	ds, _, err := readDS64(io.NewSectionReader(ra, 12, math.MaxInt64-12))
+	return ds, err
+}
+
+// mergeKey identifies chunks that conflict when merging.
+type mergeKey struct {
+	id, listType [4]byte
+}
+
+func keyOf(ra io.ReaderAt, e Entry) mergeKey {
+	k := mergeKey{id: e.ID}
+	if e.ID == (chunk.FourCC{'L', 'I', 'S', 'T'}) && e.Size >= 4 {
+		ra.ReadAt(k.listType[:], e.Offset+8)
+	}
+	return k
+}
+
+// copyEntry copies the chunk at e from ra to cw, with its pad bytes if c
+// preserves them.
+func (c outputConfig) copyEntry(cw *Writer, ra io.ReaderAt, e Entry) error {
+	cp := CopyChunk
+	if c.preserves(e.ID) {
+		cp = func(cw *Writer, ch *chunk.Reader) error {
+			if err := copyPayload(cw, ch); err != nil {
+				return err
+			}
+			pad := make([]byte, cw.spec.Padding(e.Size))
+			if n, _ := ra.ReadAt(pad, e.Offset+8+e.Size); n == len(pad) {
+				cw.stack[len(cw.stack)-1].pad = pad
+			}
+			return cw.EndChunk()
+		}
+	}
+	return c.dedup.copy(cw, &chunk.Reader{
+		ID:   e.ID,
+		Size: int(e.Size),
+		R:    io.NewSectionReader(ra, e.Offset+8, e.Size),
+	}, cp)
+}
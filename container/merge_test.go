@@ -0,0 +1,58 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	audio := buildWAV(t, "fmt ", "format", "bext", "old", "data", "samples")
+	meta := buildWAV(t, "bext", "new", "LIST", "INFOINAM", "iXML", "<x/>")
+
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		want   []byte
+	}{
+		{"keep first", KeepFirst, buildWAV(t, "fmt ", "format", "bext", "old", "data", "samples",
+			"LIST", "INFOINAM", "iXML", "<x/>")},
+		{"keep second", KeepSecond, buildWAV(t, "fmt ", "format", "bext", "new", "data", "samples",
+			"LIST", "INFOINAM", "iXML", "<x/>")},
+		{"keep both", KeepBoth, buildWAV(t, "fmt ", "format", "bext", "old", "bext", "new", "data", "samples",
+			"LIST", "INFOINAM", "iXML", "<x/>")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Merge(&out, bytes.NewReader(audio), bytes.NewReader(meta), tc.policy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tc.want) {
+				t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), tc.want)
+			}
+		})
+	}
+
+	t.Run("LIST chunks conflict by type", func(t *testing.T) {
+		a := buildWAV(t, "LIST", "adtlnote")
+		b := buildWAV(t, "LIST", "INFOINAM", "LIST", "adtlother")
+		var out bytes.Buffer
+		if err := Merge(&out, bytes.NewReader(a), bytes.NewReader(b), KeepFirst); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "LIST", "adtlnote", "LIST", "INFOINAM")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("rejects mixed formats", func(t *testing.T) {
+		var aiff bytes.Buffer
+		writeAIFFLike(t, NewWriter(&aiff, IFF))
+		var out bytes.Buffer
+		err := Merge(&out, bytes.NewReader(audio), bytes.NewReader(aiff.Bytes()), KeepFirst)
+		if err != ErrFormatMismatch {
+			t.Fatalf("expected ErrFormatMismatch, got %v", err)
+		}
+	})
+}
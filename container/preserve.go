@@ -0,0 +1,45 @@
+package container
+
+import "github.com/CWBudde/chunk"
+
+// PreserveUnknown copies kept chunks that are not in the catalog of known
+// chunks (see chunk.LookupChunk) with their original pad bytes, which are
+// otherwise written as zeros. Vendor chunks such as AAPL, minf or elm1,
+// whose readers may check the pad byte, then come out byte for byte as
+// they went in; Rewrite and Merge never reorder the chunks they keep. A
+// chunk is known if any imported format package registered its ID, so
+// without one every chunk is preserved.
+func PreserveUnknown() OutputOption {
+	return func(c *outputConfig) {
+		c.preserve = true
+	}
+}
+
+// preserves reports whether chunks with the given ID keep their pad bytes.
+func (c outputConfig) preserves(id [4]byte) bool {
+	if !c.preserve {
+		return false
+	}
+	_, known := chunk.LookupChunk("", chunk.FourCC(id))
+	return !known
+}
+
+// copyOpen copies src to dst like CopyChunk but leaves the chunk open, for
+// edit to end it with the pad bytes of src.
+func copyOpen(dst *Writer, src *chunk.Reader) error {
+	if err := copyPayload(dst, src); err != nil {
+		return err
+	}
+	dst.open = true
+	return nil
+}
+
+// endOpen ends a chunk left open by copyOpen, with pad as its pad bytes.
+func (cw *Writer) endOpen(pad []byte) error {
+	if !cw.open {
+		return nil
+	}
+	cw.open = false
+	cw.stack[len(cw.stack)-1].pad = pad
+	return cw.EndChunk()
+}
@@ -0,0 +1,111 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestPreserveUnknown(t *testing.T) {
+	chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte("knwn")), Format: "container-test", Name: "known"})
+	// vendor chunks of odd size whose pad bytes are not zero
+	src := buildWAV(t, "fmt ", "format", "AAPL", "odd", "minf", "x", "knwn", "y", "elm1", "12345", "data", "samples")
+	_, entries, err := Index(bytes.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var knownPad int64
+	for _, e := range entries {
+		if e.Size%2 == 1 {
+			src[e.Offset+8+e.Size] = 0xaa
+		}
+		if string(e.ID[:]) == "knwn" {
+			knownPad = e.Offset + 8 + e.Size
+		}
+	}
+	want := bytes.Clone(src)
+	want[knownPad] = 0
+	keep := func(*chunk.Reader) (Action, io.Reader) { return Keep, nil }
+
+	t.Run("rewrite copies unknown chunks byte for byte", func(t *testing.T) {
+		for name, r := range map[string]func() io.Reader{
+			"sections": func() io.Reader { return bytes.NewReader(src) },
+			"stream":   func() io.Reader { return io.MultiReader(bytes.NewReader(src)) },
+		} {
+			var out bytes.Buffer
+			if err := Rewrite(&out, r(), keep, PreserveUnknown()); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Fatalf("%s: expected\n%q, got\n%q", name, want, out.Bytes())
+			}
+		}
+	})
+
+	t.Run("rewrite to a seekable output", func(t *testing.T) {
+		var ws seekBuffer
+		if err := Rewrite(&ws, bytes.NewReader(src), keep, PreserveUnknown()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(ws.buf, want) {
+			t.Fatalf("expected\n%q, got\n%q", want, ws.buf)
+		}
+	})
+
+	t.Run("keeps pads around dropped and replaced chunks", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Rewrite(&out, bytes.NewReader(src), func(ch *chunk.Reader) (Action, io.Reader) {
+			switch string(ch.ID[:]) {
+			case "minf":
+				return Drop, nil
+			case "elm1":
+				return Replace, bytes.NewReader([]byte("new"))
+			}
+			return Keep, nil
+		}, PreserveUnknown())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := out.Bytes()
+		if !bytes.Contains(got, []byte("AAPL\x03\x00\x00\x00odd\xaa")) || !bytes.Contains(got, []byte("elm1\x03\x00\x00\x00new\x00")) {
+			t.Fatalf("unexpected output %q", got)
+		}
+		if bytes.Contains(got, []byte("minf")) {
+			t.Fatalf("expected minf to be dropped, got %q", got)
+		}
+	})
+
+	t.Run("zeroes pads without the option", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Rewrite(&out, bytes.NewReader(src), keep); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bytes.Contains(out.Bytes(), []byte{0xaa}) {
+			t.Fatalf("expected zero pad bytes, got %q", out.Bytes())
+		}
+	})
+
+	t.Run("combines with DropDuplicates", func(t *testing.T) {
+		dup := buildWAV(t, "AAPL", "odd", "AAPL", "odd", "data", "x")
+		dup[12+8+3] = 0xaa
+		var out bytes.Buffer
+		if err := Rewrite(&out, bytes.NewReader(dup), keep, PreserveUnknown(), DropDuplicates()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := out.Bytes(); bytes.Count(got, []byte("AAPL")) != 1 || !bytes.Contains(got, []byte("odd\xaa")) {
+			t.Fatalf("unexpected output %q", got)
+		}
+	})
+
+	t.Run("merge copies unknown chunks byte for byte", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Merge(&out, bytes.NewReader(src), bytes.NewReader(buildWAV(t)), KeepFirst, PreserveUnknown()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("expected\n%q, got\n%q", want, out.Bytes())
+		}
+	})
+}
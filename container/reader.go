@@ -0,0 +1,338 @@
+package container
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+var (
+	// ErrUnknownFormat is returned when a stream does not start with a known
+	// container group chunk.
+	ErrUnknownFormat = errors.New("container: unknown container format")
+	// ErrMissingDS64 is returned for RF64/BW64 streams whose first chunk is not
+	// a valid ds64 chunk.
+	ErrMissingDS64 = errors.New("container: RF64 container without ds64 chunk")
+)
+
+// maxSize64 is the largest ds64 size accepted, leaving headroom for the
+// offsets computed from it.
+const maxSize64 = 1 << 62
+
+// Header describes the outermost group chunk of a container.
+type Header struct {
+	ID chunk.FourCC `json:"id"`
+	// Size is the size of the group payload, taken from the ds64 chunk for
+	// RF64 and BW64 containers.
+	Size   int64        `json:"size"`
+	Type   chunk.FourCC `json:"type"`
+	Format Format       `json:"format"`
+}
+
+// DS64 holds the 64-bit sizes of a RF64/BW64 container.
+type DS64 struct {
+	RIFFSize    uint64 `json:"riffSize"`
+	DataSize    uint64 `json:"dataSize"`
+	SampleCount uint64 `json:"sampleCount"`
+	// Table lists the sizes of chunks other than data whose header size is
+	// 0xFFFFFFFF, in the order they were written.
+	Table []DS64Size `json:"table,omitempty"`
+}
+
+// DS64Size is an entry of the ds64 table.
+type DS64Size struct {
+	ID   chunk.FourCC `json:"id"`
+	Size uint64       `json:"size"`
+}
+
+// Size returns the 64-bit size of chunks with the given ID: DataSize for
+// data chunks, else the first table entry for id. Tables list an ID once
+// per large chunk; scanners, Index and ParseTree hand the entries out per
+// occurrence, in file order.
+func (d *DS64) Size(id chunk.FourCC) (int64, bool) {
+	if id == (chunk.FourCC{'d', 'a', 't', 'a'}) {
+		return int64(d.DataSize), true
+	}
+	for _, e := range d.Table {
+		if e.ID == id {
+			return int64(e.Size), true
+		}
+	}
+	return 0, false
+}
+
+// ds64Sizes hands out the sizes of a ds64 table per occurrence: the nth
+// chunk with an ID gets the nth table entry for it, the last entry
+// standing for any further chunks. Data chunks get DataSize.
+type ds64Sizes struct {
+	d    *DS64
+	seen map[chunk.FourCC]int
+}
+
+func (d *DS64) cursor() *ds64Sizes {
+	return &ds64Sizes{d: d, seen: map[chunk.FourCC]int{}}
+}
+
+// size returns the size of the next chunk with the given ID.
+func (c *ds64Sizes) size(id chunk.FourCC) (int64, bool) {
+	if id == (chunk.FourCC{'d', 'a', 't', 'a'}) {
+		return int64(c.d.DataSize), true
+	}
+	n := c.seen[id]
+	c.seen[id]++
+	size, found := int64(0), false
+	for _, e := range c.d.Table {
+		if e.ID != id {
+			continue
+		}
+		size, found = int64(e.Size), true
+		if n--; n < 0 {
+			break
+		}
+	}
+	return size, found
+}
+
+// sizes returns the 64-bit sizes by chunk ID, as chunk.Scanner.Sizes64
+// takes them.
+func (d *DS64) sizes() map[chunk.FourCC]int64 {
+	m := map[chunk.FourCC]int64{{'d', 'a', 't', 'a'}: int64(d.DataSize)}
+	for i := len(d.Table) - 1; i >= 0; i-- {
+		if e := d.Table[i]; e.ID != (chunk.FourCC{'d', 'a', 't', 'a'}) {
+			m[e.ID] = int64(e.Size)
+		}
+	}
+	return m
+}
+
+// ReadHeader reads the 12 byte header of a container: group ID, size and
+// form type. RF64/BW64 sizes are not resolved; use NewScanner for that.
+func ReadHeader(r io.Reader) (Header, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Header{}, err
+	}
+	h := Header{}
+	copy(h.ID[:], hdr[:4])
+	copy(h.Type[:], hdr[8:])
+	switch string(hdr[:4]) {
+	case "RIFF":
+		h.Format = RIFF
+	case "RIFX":
+		h.Format = RIFX
+	case "RF64":
+		h.Format = RF64
+	case "BW64":
+		h.Format = BW64
+	case "FORM", "LIST", "CAT ":
+		h.Format = IFF
+	default:
+		return h, ErrUnknownFormat
+	}
+	h.Size = int64(h.Format.ByteOrder().Uint32(hdr[4:]))
+	return h, nil
+}
+
+// Scanner reads the chunks of a container's outermost group. It embeds a
+// chunk.Scanner limited to the group payload; the ds64 chunk of RF64/BW64
+// containers is consumed up front and its sizes applied to the data chunk
+// and those listed in its table.
+type Scanner struct {
+	*chunk.Scanner
+	Header Header
+	// DS64 is set for RF64 and BW64 containers.
+	DS64 *DS64
+	// Transform, if set, decodes the payload of matching chunks.
+	Transform *Transform
+	// Warn, if set, is called for the oddities chunk.Scanner.Warn reports,
+	// with absolute offsets, and, once Next returns false, for a container
+	// size running past the last chunk.
+	Warn func(chunk.Warning)
+	// Filter, if set, selects the chunks Next stops at, with depth 1; the
+	// others are skipped.
+	Filter Filter
+
+	base    int64
+	limit   int64
+	end     int64 // end of the last chunk payload
+	pad     int64 // pad byte the last chunk announced
+	done    bool
+	decoded *chunk.Reader
+	digest  hash.Hash
+}
+
+// NewScanner reads the container header from r and returns a Scanner
+// positioned before its first chunk. When r is also an io.ReaderAt and an
+// io.Seeker, such as *os.File or *bytes.Reader, chunks are read through
+// io.SectionReaders (see chunk.NewScannerAt) and r is not read beyond the
+// header.
+func NewScanner(r io.Reader) (*Scanner, error) {
+	start := sectionStart(r)
+	h, err := ReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scanner{Header: h, base: 12}
+	if h.Format == RF64 || h.Format == BW64 {
+		ds, n, err := readDS64(r)
+		if err != nil {
+			return nil, err
+		}
+		s.DS64 = ds
+		s.base += n
+		if h.Size == math.MaxUint32 {
+			s.Header.Size = int64(ds.RIFFSize)
+		}
+	}
+	limit := s.Header.Size - (s.base - 8)
+	if limit < 0 {
+		limit = 0
+	}
+	s.limit = limit
+	if ra, ok := r.(io.ReaderAt); ok && start >= 0 {
+		spec := chunk.RIFFHeader
+		spec.Order = h.Format.ByteOrder()
+		s.Scanner = chunk.NewScannerAt(ra, start+s.base, limit, spec)
+	} else {
+		s.Scanner = chunk.NewScanner(io.LimitReader(r, limit), h.Format.ByteOrder())
+	}
+	if s.DS64 != nil {
+		s.Sizes64 = s.DS64.sizes()
+		s.Size64 = s.DS64.cursor().size
+	}
+	return s, nil
+}
+
+// Next advances to the next chunk Filter selects, see chunk.Scanner.
+func (s *Scanner) Next() bool {
+	if s.Warn != nil && s.Scanner.Warn == nil {
+		s.Scanner.Warn = func(w chunk.Warning) {
+			w.Offset += s.base
+			s.Warn(w)
+		}
+	}
+	for s.Scanner.Next() {
+		ch := s.Scanner.Chunk()
+		s.end = s.Scanner.Offset() + 8 + int64(ch.Size)
+		s.pad = int64(ch.Size % 2)
+		if s.Filter == nil || s.Filter(ch, 1) {
+			return true
+		}
+	}
+	// ending cleanly before the limit means the stream ran out at a chunk
+	// boundary; a missing last pad byte is reported by the chunk.Scanner
+	if slack := s.limit - s.end - s.pad; !s.done && s.Err() == nil && slack > 0 && s.Warn != nil {
+		s.Warn(chunk.Warning{
+			Kind:    chunk.WarnSize,
+			Offset:  s.base + s.end + s.pad,
+			ID:      s.Header.ID,
+			Message: fmt.Sprintf("container size runs %d bytes past the last chunk", slack),
+		})
+	}
+	s.done = true
+	return false
+}
+
+// sectionStart returns the current offset of r if it can be read through
+// io.SectionReaders, or -1.
+func sectionStart(r io.Reader) int64 {
+	if _, ok := r.(io.ReaderAt); !ok {
+		return -1
+	}
+	sk, ok := r.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	off, err := sk.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	return off
+}
+
+// NewDigestScanner is NewScanner computing a digest of every byte consumed
+// from r as the container is walked, so ingestion pipelines get a content
+// hash without reading the file twice. h is SHA-256 if nil. Once Next has
+// returned false, Sum covers the whole container; bytes after its outermost
+// group are not read.
+func NewDigestScanner(r io.Reader, h hash.Hash) (*Scanner, error) {
+	if h == nil {
+		h = sha256.New()
+	}
+	s, err := NewScanner(io.TeeReader(r, h))
+	if err != nil {
+		return nil, err
+	}
+	s.digest = h
+	return s, nil
+}
+
+// Sum appends the digest of the bytes consumed so far to b. It returns nil
+// for scanners not created with NewDigestScanner.
+func (s *Scanner) Sum(b []byte) []byte {
+	if s.digest == nil {
+		return nil
+	}
+	return s.digest.Sum(b)
+}
+
+// Offset returns the absolute offset of the current chunk header.
+func (s *Scanner) Offset() int64 {
+	return s.base + s.Scanner.Offset()
+}
+
+// readDS64 reads the ds64 chunk and returns it along with the number of bytes
+// consumed including its pad byte. The table after the fixed fields grows
+// with the entries actually read, whatever length it claims, and space
+// beyond it is skipped without buffering it.
+func readDS64(r io.Reader) (*DS64, int64, error) {
+	var hdr [8 + ds64PayloadSize]byte
+	if _, err := io.ReadFull(r, hdr[:8]); err != nil {
+		return nil, 0, ErrMissingDS64
+	}
+	size := int64(binary.LittleEndian.Uint32(hdr[4:]))
+	if string(hdr[:4]) != "ds64" || size < ds64PayloadSize {
+		return nil, 0, ErrMissingDS64
+	}
+	payload := hdr[8:]
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	ds := &DS64{
+		RIFFSize:    binary.LittleEndian.Uint64(payload),
+		DataSize:    binary.LittleEndian.Uint64(payload[8:]),
+		SampleCount: binary.LittleEndian.Uint64(payload[16:]),
+	}
+	rest := size + size%2 - ds64PayloadSize
+	entries := min(int64(binary.LittleEndian.Uint32(payload[24:])), (size-ds64PayloadSize)/ds64EntrySize)
+	for range entries {
+		var e [ds64EntrySize]byte
+		if _, err := io.ReadFull(r, e[:]); err != nil {
+			return nil, 0, io.ErrUnexpectedEOF
+		}
+		ds.Table = append(ds.Table, DS64Size{ID: chunk.FourCC(e[:4]), Size: binary.LittleEndian.Uint64(e[4:])})
+		rest -= ds64EntrySize
+	}
+	if n, _ := io.CopyN(io.Discard, r, rest); n < rest {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	// the sizes end up in int64 offsets
+	if ds.RIFFSize > maxSize64 || ds.DataSize > maxSize64 {
+		return nil, 0, chunk.ErrSizeOverflow
+	}
+	for _, e := range ds.Table {
+		if e.Size > maxSize64 {
+			return nil, 0, chunk.ErrSizeOverflow
+		}
+	}
+	return ds, 8 + size + size%2, nil
+}
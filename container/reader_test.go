@@ -0,0 +1,200 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestReadHeader(t *testing.T) {
+	t.Run("detects formats", func(t *testing.T) {
+		tests := []struct {
+			hdr    string
+			format Format
+			size   int64
+		}{
+			{"RIFF\x04\x00\x00\x00WAVE", RIFF, 4},
+			{"RIFX\x00\x00\x00\x04WAVE", RIFX, 4},
+			{"FORM\x00\x00\x00\x04AIFF", IFF, 4},
+			{"BW64\xff\xff\xff\xffWAVE", BW64, 0xFFFFFFFF},
+		}
+		for _, tc := range tests {
+			h, err := ReadHeader(bytes.NewReader([]byte(tc.hdr)))
+			if err != nil {
+				t.Fatalf("%q: unexpected error: %v", tc.hdr, err)
+			}
+			if h.Format != tc.format || h.Size != tc.size {
+				t.Fatalf("%q: unexpected header %+v", tc.hdr, h)
+			}
+		}
+	})
+
+	t.Run("rejects unknown groups", func(t *testing.T) {
+		if _, err := ReadHeader(bytes.NewReader([]byte("OggS\x00\x00\x00\x00abcd"))); err != ErrUnknownFormat {
+			t.Fatalf("expected ErrUnknownFormat, got %v", err)
+		}
+	})
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("scans the outer group", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAIFFLike(t, NewWriter(&buf, IFF))
+		buf.WriteString("trailing garbage")
+
+		s, err := NewScanner(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.Header.Type.String() != "AIFF" {
+			t.Fatalf("expected AIFF, got %q", s.Header.Type.String())
+		}
+		var ids []string
+		var offsets []int64
+		for s.Next() {
+			ids = append(ids, string(s.Chunk().ID[:]))
+			offsets = append(offsets, s.Offset())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 2 || ids[0] != "COMM" || ids[1] != "LIST" {
+			t.Fatalf("unexpected chunks %q", ids)
+		}
+		if offsets[0] != 12 || offsets[1] != 24 {
+			t.Fatalf("unexpected offsets %v", offsets)
+		}
+	})
+
+	t.Run("reads sections of an io.ReaderAt", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "format", "odd ", "x", "data", "samples")
+		r := bytes.NewReader(append([]byte("prefix"), src...))
+		r.Seek(6, io.SeekStart)
+		s, err := NewScanner(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var chunks []*chunk.Reader
+		for s.Next() {
+			chunks = append(chunks, s.Chunk())
+		}
+		if err := s.Err(); err != nil || len(chunks) != 3 {
+			t.Fatalf("expected 3 chunks, got %d (%v)", len(chunks), err)
+		}
+		if pos, _ := r.Seek(0, io.SeekCurrent); pos != 18 {
+			t.Fatalf("expected the source to stay after the header, at %d", pos)
+		}
+		// siblings can be read in any order once the scan is done
+		for i, want := range []string{"samples", "x", "format"} {
+			got, _ := io.ReadAll(chunks[2-i])
+			if string(got) != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+	})
+
+	t.Run("resolves RF64 sizes", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		data := bytes.Repeat([]byte{1, 2, 3, 4}, 10)
+		var buf bytes.Buffer
+		writeBW64(t, NewWriter(&buf, BW64), data)
+
+		s, err := NewScanner(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.DS64 == nil || s.DS64.SampleCount != uint64(len(data)/2) {
+			t.Fatalf("unexpected ds64 %+v", s.DS64)
+		}
+		if s.Header.Size != int64(buf.Len()-8) {
+			t.Fatalf("expected size %d, got %d", buf.Len()-8, s.Header.Size)
+		}
+		if !s.Next() {
+			t.Fatalf("expected data chunk, got %v", s.Err())
+		}
+		if s.Chunk().Size != len(data) || s.Offset() != 12+8+ds64PayloadSize {
+			t.Fatalf("unexpected data chunk size %d at %d", s.Chunk().Size, s.Offset())
+		}
+		if s.Next() {
+			t.Fatal("expected a single chunk")
+		}
+	})
+
+	t.Run("warns about a container size past the last chunk", func(t *testing.T) {
+		b := buildWAV(t, "fmt ", "format", "data", "odd")
+		b = b[:len(b)-1]                                       // drop the last pad byte as well
+		binary.LittleEndian.PutUint32(b[4:], uint32(len(b)-4)) // 3 bytes of slack after the pad
+		var ws chunk.Warnings
+		s, err := NewScanner(io.MultiReader(bytes.NewReader(b)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.Warn = ws.Add
+		for s.Next() {
+		}
+		s.Next()
+		if s.Err() != nil || len(ws) != 2 {
+			t.Fatalf("expected two warnings, got %q (%v)", ws, s.Err())
+		}
+		if ws[0].String() != "data at 37: missing pad byte at the end" || ws[1].String() != "RIFF at 38: container size runs 3 bytes past the last chunk" {
+			t.Fatalf("unexpected warnings %q", ws)
+		}
+	})
+
+	t.Run("requires ds64 for RF64", func(t *testing.T) {
+		data := []byte("RF64\xff\xff\xff\xffWAVEfmt \x00\x00\x00\x00")
+		if _, err := NewScanner(bytes.NewReader(data)); err != ErrMissingDS64 {
+			t.Fatalf("expected ErrMissingDS64, got %v", err)
+		}
+	})
+}
+
+func TestNewDigestScanner(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "odd ", "x", "data", "samples")
+
+	t.Run("hashes the whole container", func(t *testing.T) {
+		s, err := NewDigestScanner(bytes.NewReader(append(src, "trailing"...)), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// read part of one chunk only, the rest is skipped
+		for s.Next() {
+			if string(s.Chunk().ID[:]) == "fmt " {
+				s.Chunk().ReadByte()
+			}
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := sha256.Sum256(src)
+		if got := s.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("unexpected digest\n got %x\nwant %x", got, want)
+		}
+	})
+
+	t.Run("takes a custom hash", func(t *testing.T) {
+		s, err := NewDigestScanner(bytes.NewReader(src), crc32.NewIEEE())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for s.Next() {
+		}
+		if got := binary.BigEndian.Uint32(s.Sum(nil)); got != crc32.ChecksumIEEE(src) {
+			t.Fatalf("unexpected CRC %08x", got)
+		}
+	})
+
+	t.Run("plain scanners have no digest", func(t *testing.T) {
+		s, _ := NewScanner(bytes.NewReader(src))
+		if s.Sum(nil) != nil {
+			t.Fatal("expected no digest")
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package container
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Match reports whether an edit applies to a chunk. It may peek at the
+// payload (see chunk.Reader.Peek) but must not consume it.
+type Match func(ch *chunk.Reader) bool
+
+// MatchID matches chunks with any of the given IDs. IDs shorter than four
+// characters are padded with spaces, and chunk IDs padded with NULs match
+// as well, so "fmt" matches both "fmt " and "fmt\x00".
+func MatchID(ids ...string) Match {
+	set := idSet(ids)
+	return func(ch *chunk.Reader) bool {
+		return set[ch.ID] || set[chunk.FourCC(ch.ID).Normalize()]
+	}
+}
+
+// MatchIDStrict is MatchID without the NUL padding fallback: chunk IDs
+// have to equal one of ids padded with spaces.
+func MatchIDStrict(ids ...string) Match {
+	set := idSet(ids)
+	return func(ch *chunk.Reader) bool {
+		return set[ch.ID]
+	}
+}
+
+// LookupOption configures how functions that take a chunk ID, such as
+// Extract, Inject and Insert, find the chunk.
+type LookupOption func(*lookupConfig)
+
+type lookupConfig struct {
+	strict bool
+}
+
+// StrictIDs finds chunks by their exact ID, as MatchIDStrict does. By
+// default an ID matches chunks padded with NULs as well, as MatchID does.
+func StrictIDs() LookupOption {
+	return func(c *lookupConfig) {
+		c.strict = true
+	}
+}
+
+func lookup(opts []LookupOption) lookupConfig {
+	var cfg lookupConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// sameID reports whether the chunk ID id is want, an ID padded with spaces
+// by fourCC, allowing NUL padding in id unless strict is set.
+func sameID(id, want [4]byte, strict bool) bool {
+	return id == want || !strict && chunk.FourCC(id).Normalize() == want
+}
+
+// CaseMismatch is called when a chunk ID matched only by ignoring case, as
+// the ID "Data" written by a broken encoder matches "data", so the file can
+// be flagged.
+type CaseMismatch func(id, want chunk.FourCC)
+
+// MatchIDFold is MatchID ignoring the case of ASCII letters. For every
+// chunk matched that way warn, if not nil, is called with the chunk ID and
+// the ID it matched.
+func MatchIDFold(warn CaseMismatch, ids ...string) Match {
+	want := make([]chunk.FourCC, len(ids))
+	for i, id := range ids {
+		want[i] = fourCC(id)
+	}
+	exact := MatchID(ids...)
+	return func(ch *chunk.Reader) bool {
+		if exact(ch) {
+			return true
+		}
+		for _, w := range want {
+			if w.EqualFold(ch.ID) {
+				if warn != nil {
+					warn(ch.ID, w)
+				}
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func idSet(ids []string) map[[4]byte]bool {
+	set := make(map[[4]byte]bool, len(ids))
+	for _, id := range ids {
+		set[fourCC(id)] = true
+	}
+	return set
+}
+
+// MatchList matches LIST chunks of the given list type, e.g. "INFO".
+func MatchList(listType string) Match {
+	typ := fourCC(listType)
+	return func(ch *chunk.Reader) bool {
+		if ch.ID != [4]byte{'L', 'I', 'S', 'T'} {
+			return false
+		}
+		b, err := ch.Peek(4)
+		return err == nil && [4]byte(b) == typ
+	}
+}
+
+// Remove copies the container read from src to dst without the chunks
+// selected by match. The container size is recomputed.
+func Remove(dst io.Writer, src io.Reader, match Match) error {
+	e := edits{removes: []Match{match}}
+	return e.apply(dst, src)
+}
+
+// RemoveFile removes the chunks selected by match from the container stored
+// at path. The edit runs as a transaction, so path is left untouched on
+// error.
+func RemoveFile(path string, match Match) error {
+	tx, err := Begin(path)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := tx.Remove(match); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
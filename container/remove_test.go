@@ -0,0 +1,89 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestRemove(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "LIST", "INFOINAM", "JUNK", "xx",
+		"LIST", "adtlnote", "PAD ", "", "data", "samples")
+
+	t.Run("by ID", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Remove(&out, bytes.NewReader(src), MatchID("JUNK", "PAD ")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "LIST", "INFOINAM", "LIST", "adtlnote", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("by list type", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Remove(&out, bytes.NewReader(src), MatchList("INFO")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "JUNK", "xx", "LIST", "adtlnote", "PAD ", "", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("NUL-padded IDs", func(t *testing.T) {
+		src := buildWAV(t, "fmt_", "format", "data", "samples")
+		src[15] = 0 // fmt\x00, which Writer refuses to write
+		var out bytes.Buffer
+		if err := Remove(&out, bytes.NewReader(src), MatchIDStrict("fmt")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), src) {
+			t.Fatal("expected MatchIDStrict to keep fmt\\x00")
+		}
+		out.Reset()
+		if err := Remove(&out, bytes.NewReader(src), MatchID("fmt")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := buildWAV(t, "data", "samples"); !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("ignoring case", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "format", "Data", "samples")
+		var out bytes.Buffer
+		if err := Remove(&out, bytes.NewReader(src), MatchID("data")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), src) {
+			t.Fatal("expected MatchID to keep Data")
+		}
+		out.Reset()
+		var warned []string
+		match := MatchIDFold(func(id, want chunk.FourCC) {
+			warned = append(warned, id.String()+"->"+want.String())
+		}, "data")
+		if err := Remove(&out, bytes.NewReader(src), match); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := buildWAV(t, "fmt ", "format"); !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+		if len(warned) != 1 || warned[0] != "Data->data" {
+			t.Fatalf("unexpected warnings %q", warned)
+		}
+	})
+
+	t.Run("nothing matches", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := Remove(&out, bytes.NewReader(src), MatchID("bext")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), src) {
+			t.Fatal("expected identical output")
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package container
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrRepairFormat is returned by Repair for RF64 and BW64 files, whose sizes
+// live in the ds64 chunk.
+var ErrRepairFormat = errors.New("container: cannot repair RF64/BW64 size fields")
+
+// Correction is a fix made by Repair.
+type Correction struct {
+	// Offset is the offset of the chunk header, in the repaired file.
+	Offset int64
+	ID     chunk.FourCC
+	// OldSize and NewSize are the size field before and after the fix.
+	OldSize, NewSize int64
+	// PadInserted is set when a missing pad byte was inserted after the
+	// chunk; the size field is unchanged then.
+	PadInserted bool
+}
+
+func (c Correction) String() string {
+	if c.PadInserted {
+		return fmt.Sprintf("%s at %d: inserted pad byte", c.ID, c.Offset)
+	}
+	return fmt.Sprintf("%s at %d: size %d -> %d", c.ID, c.Offset, c.OldSize, c.NewSize)
+}
+
+// Repair re-walks the container in f and fixes its size fields: chunks
+// claiming to extend beyond their group or the end of the file are cut to
+// what is there, missing pad bytes after odd-sized chunks are inserted,
+// moving the rest of the file and growing the enclosing groups, and the
+// container size is set to the chunks it actually holds. The walk of the
+// container ends at the end of the file or at the first header whose ID is
+// not printable, so trailing bytes that are not chunks, such as zero
+// padding, stay outside of it. Every correction is reported in file order,
+// followed by the one of the outermost group.
+func Repair(f io.ReadWriteSeeker) ([]Correction, error) {
+	length, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	h, err := ReadHeader(f)
+	if err != nil {
+		return nil, err
+	}
+	if h.Format == RF64 || h.Format == BW64 {
+		return nil, ErrRepairFormat
+	}
+	r := &repairer{f: f, order: h.Format.ByteOrder(), length: length}
+	r.ends = []int64{length}
+	end, err := r.group(12)
+	if err != nil {
+		return r.fixes, err
+	}
+	if err := r.fixSize(0, h.ID, h.Size, end-8); err != nil {
+		return r.fixes, err
+	}
+	return r.fixes, nil
+}
+
+type repairer struct {
+	f      io.ReadWriteSeeker
+	order  binary.ByteOrder
+	length int64
+	// ends holds the end offsets of the enclosing groups, innermost last.
+	ends  []int64
+	fixes []Correction
+}
+
+// group repairs the chunks from off to the end of the innermost group and
+// returns the offset after the last one.
+func (r *repairer) group(off int64) (int64, error) {
+	if len(r.ends) > MaxDepth {
+		return off, ErrTooDeep
+	}
+	for {
+		end := r.ends[len(r.ends)-1]
+		id, size, ok, err := r.header(off, end)
+		if err != nil || !ok {
+			return off, err
+		}
+		if declared := size; off+8+size > end {
+			size = end - off - 8
+			if err := r.fixSize(off, id, declared, size); err != nil {
+				return off, err
+			}
+		}
+		if IsGroup(id) && size >= 4 {
+			r.ends = append(r.ends, off+8+size)
+			_, err := r.group(off + 12)
+			inner := r.ends[len(r.ends)-1]
+			r.ends = r.ends[:len(r.ends)-1]
+			if err != nil {
+				return off, err
+			}
+			// the group may have grown by inserted pad bytes
+			declared, err := r.readSize(off)
+			if err != nil {
+				return off, err
+			}
+			size = inner - off - 8
+			if err := r.fixSize(off, id, declared, size); err != nil {
+				return off, err
+			}
+		}
+		next := off + 8 + size
+		if size%2 == 1 {
+			missing, err := r.missingPad(next)
+			if err != nil {
+				return off, err
+			}
+			if missing {
+				if err := r.insertPad(next); err != nil {
+					return off, err
+				}
+				r.fixes = append(r.fixes, Correction{Offset: off, ID: id, OldSize: size, NewSize: size, PadInserted: true})
+			}
+			next++
+		}
+		off = next
+	}
+}
+
+// header reads the chunk header at off. ok is false if there is no room for
+// one before end or its ID is not printable.
+func (r *repairer) header(off, end int64) (id chunk.FourCC, size int64, ok bool, err error) {
+	if off+8 > end {
+		return id, 0, false, nil
+	}
+	var hdr [8]byte
+	if err := r.readAt(hdr[:], off); err != nil {
+		return id, 0, false, err
+	}
+	copy(id[:], hdr[:4])
+	return id, int64(r.order.Uint32(hdr[4:])), id.Valid(), nil
+}
+
+// missingPad reports whether the pad byte expected at off is missing: the
+// group ends right there, or a plausible chunk header starts at off rather
+// than one byte later.
+func (r *repairer) missingPad(off int64) (bool, error) {
+	end := r.ends[len(r.ends)-1]
+	if off >= end {
+		return off == end, nil
+	}
+	here, err := r.plausible(off, end)
+	if err != nil || !here {
+		return false, err
+	}
+	after, err := r.plausible(off+1, end)
+	return !after, err
+}
+
+// plausible reports whether a chunk that fits before end starts at off.
+func (r *repairer) plausible(off, end int64) (bool, error) {
+	_, size, ok, err := r.header(off, end)
+	return ok && off+8+size <= end, err
+}
+
+// insertPad inserts a zero byte at off, moving the rest of the file back.
+func (r *repairer) insertPad(off int64) error {
+	buf := chunk.GetBuffer()
+	defer chunk.PutBuffer(buf)
+	for pos := r.length; pos > off; {
+		n := min(int64(len(buf)), pos-off)
+		pos -= n
+		if err := r.readAt(buf[:n], pos); err != nil {
+			return err
+		}
+		if err := r.writeAt(buf[:n], pos+1); err != nil {
+			return err
+		}
+	}
+	if err := r.writeAt([]byte{0}, off); err != nil {
+		return err
+	}
+	r.length++
+	for i := range r.ends {
+		if r.ends[i] >= off {
+			r.ends[i]++
+		}
+	}
+	return nil
+}
+
+// fixSize writes size into the header at off if it differs from old.
+func (r *repairer) fixSize(off int64, id chunk.FourCC, old, size int64) error {
+	if size == old {
+		return nil
+	}
+	if size > math.MaxUint32 {
+		return ErrChunkTooLarge
+	}
+	var b [4]byte
+	r.order.PutUint32(b[:], uint32(size))
+	if err := r.writeAt(b[:], off+4); err != nil {
+		return err
+	}
+	r.fixes = append(r.fixes, Correction{Offset: off, ID: id, OldSize: old, NewSize: size})
+	return nil
+}
+
+func (r *repairer) readSize(off int64) (int64, error) {
+	var b [4]byte
+	if err := r.readAt(b[:], off+4); err != nil {
+		return 0, err
+	}
+	return int64(r.order.Uint32(b[:])), nil
+}
+
+func (r *repairer) readAt(b []byte, off int64) error {
+	if _, err := r.f.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(r.f, b)
+	return err
+}
+
+func (r *repairer) writeAt(b []byte, off int64) error {
+	if _, err := r.f.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := r.f.Write(b)
+	return err
+}
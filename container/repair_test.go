@@ -0,0 +1,96 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	repair := func(t *testing.T, src []byte) ([]string, []byte) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "in.wav")
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		fixes, err := Repair(f)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, c := range fixes {
+			got = append(got, c.String())
+		}
+		out, _ := os.ReadFile(path)
+		return got, out
+	}
+	expect := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+	}
+
+	t.Run("leaves sound files alone", func(t *testing.T) {
+		src := buildNested(t)
+		fixes, out := repair(t, src)
+		expect(t, fixes)
+		if !bytes.Equal(out, src) {
+			t.Fatal("file changed")
+		}
+	})
+
+	t.Run("cuts chunks to the end of the file", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "format", "data", "samples and more")
+		fixes, out := repair(t, src[:len(src)-6])
+		expect(t, fixes, "data at 26: size 16 -> 10", "RIFF at 0: size 42 -> 36")
+		if want := buildWAV(t, "fmt ", "format", "data", "samples an"); !bytes.Equal(out, want) {
+			t.Fatalf("unexpected result %q", out)
+		}
+	})
+
+	t.Run("inserts missing pad bytes", func(t *testing.T) {
+		want := buildNested(t)
+		// drop the pad byte of INAM, shrinking LIST and RIFF accordingly
+		src := append(bytes.Clone(want[:49]), want[50:]...)
+		binary.LittleEndian.PutUint32(src[30:], 15)
+		binary.LittleEndian.PutUint32(src[4:], 57)
+		fixes, out := repair(t, src)
+		expect(t, fixes, "INAM at 38: inserted pad byte", "LIST at 26: size 15 -> 16", "RIFF at 0: size 57 -> 58")
+		if !bytes.Equal(out, want) {
+			t.Fatalf("unexpected result\n got %q\nwant %q", out, want)
+		}
+	})
+
+	t.Run("pads the last chunk", func(t *testing.T) {
+		want := buildWAV(t, "fmt ", "format", "data", "odd")
+		fixes, out := repair(t, want[:len(want)-1])
+		expect(t, fixes, "data at 26: inserted pad byte")
+		if !bytes.Equal(out, want) {
+			t.Fatalf("unexpected result %q", out)
+		}
+	})
+
+	t.Run("excludes trailing garbage", func(t *testing.T) {
+		want := buildWAV(t, "fmt ", "format")
+		src := append(bytes.Clone(want), 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(src[4:], uint32(len(src)-8))
+		fixes, out := repair(t, src)
+		expect(t, fixes, "RIFF at 0: size 28 -> 18")
+		if !bytes.Equal(out[:len(want)], want) {
+			t.Fatalf("unexpected result %q", out)
+		}
+	})
+}
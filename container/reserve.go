@@ -0,0 +1,75 @@
+package container
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	// ErrReservationSize is returned by Reservation.Fill for payloads that
+	// do not match the reserved size.
+	ErrReservationSize = errors.New("container: payload does not match reserved size")
+	// ErrReservationExpired is returned by Reservation.Fill when the output
+	// is buffered and the group holding the chunk has already been ended.
+	ErrReservationExpired = errors.New("container: reserved chunk has been flushed")
+	// ErrReserveNoGroup is returned by Reserve when the output is buffered
+	// and no group is open to hold the chunk until it is filled.
+	ErrReserveNoGroup = errors.New("container: Reserve needs an open group on a non-seekable writer")
+)
+
+// Reservation is a fixed size chunk whose payload is written later, e.g. a
+// fact chunk whose sample count is only known once the data chunk is done.
+type Reservation struct {
+	cw     *Writer
+	parent *frame
+	// off is the payload offset; absolute when the output is seekable,
+	// relative to the parent's buffer otherwise.
+	off  int64
+	size int
+}
+
+// Reserve writes a chunk of size zero bytes to be filled in with
+// Reservation.Fill. On buffered outputs Fill has to be called before the
+// group containing the chunk is ended, so a group has to be open. Reserved
+// payloads are neither hashed nor transformed.
+func (cw *Writer) Reserve(id string, size int) (*Reservation, error) {
+	if err := cw.validate(id); err != nil {
+		return nil, err
+	}
+	if cw.ws == nil && len(cw.stack) == 0 {
+		return nil, ErrReserveNoGroup
+	}
+	cw.raw = true
+	f, err := cw.beginID(fourCC(id), false)
+	cw.raw = false
+	if err != nil {
+		return nil, err
+	}
+	r := &Reservation{cw: cw, off: f.start + cw.headerSize(), size: size}
+	if f.buf != nil {
+		r.parent = cw.stack[len(cw.stack)-2]
+		r.off -= r.parent.start + cw.headerSize()
+	}
+	if _, err := f.ch.Write(make([]byte, size)); err != nil {
+		return nil, err
+	}
+	if err := cw.EndChunk(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Fill writes the payload of the reserved chunk.
+func (r *Reservation) Fill(payload []byte) error {
+	if len(payload) != r.size {
+		return ErrReservationSize
+	}
+	if r.parent == nil {
+		return r.cw.writeAt(payload, r.off)
+	}
+	if !slices.Contains(r.cw.stack, r.parent) {
+		return ErrReservationExpired
+	}
+	copy(r.parent.buf.Bytes()[r.off:], payload)
+	return nil
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriter_Reserve(t *testing.T) {
+	write := func(t *testing.T, cw *Writer) {
+		t.Helper()
+		cw.BeginForm("WAVE")
+		r, err := cw.Reserve("fact", 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch, _ := cw.BeginChunk("data")
+		ch.Write([]byte{1, 2, 3, 4})
+		cw.EndChunk()
+		if err := r.Fill([]byte{2, 0, 0, 0}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Fill([]byte{2}); err != ErrReservationSize {
+			t.Fatalf("expected ErrReservationSize, got %v", err)
+		}
+		if err := cw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []byte{
+		'R', 'I', 'F', 'F', 28, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'f', 'a', 'c', 't', 4, 0, 0, 0, 2, 0, 0, 0,
+		'd', 'a', 't', 'a', 4, 0, 0, 0, 1, 2, 3, 4,
+	}
+
+	t.Run("buffered", func(t *testing.T) {
+		var buf bytes.Buffer
+		write(t, NewWriter(&buf, RIFF))
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+
+	t.Run("seeking", func(t *testing.T) {
+		sb := &seekBuffer{}
+		write(t, NewWriter(sb, RIFF))
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("needs an open group when buffered", func(t *testing.T) {
+		if _, err := NewWriter(&bytes.Buffer{}, RIFF).Reserve("fact", 4); err != ErrReserveNoGroup {
+			t.Fatalf("expected ErrReserveNoGroup, got %v", err)
+		}
+	})
+
+	t.Run("expires with its group when buffered", func(t *testing.T) {
+		cw := NewWriter(&bytes.Buffer{}, RIFF)
+		cw.BeginForm("WAVE")
+		cw.BeginList("INFO")
+		r, _ := cw.Reserve("ICMT", 2)
+		cw.EndChunk()
+		if err := r.Fill([]byte("hi")); err != ErrReservationExpired {
+			t.Fatalf("expected ErrReservationExpired, got %v", err)
+		}
+	})
+}
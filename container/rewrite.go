@@ -0,0 +1,69 @@
+package container
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Action tells Rewrite what to do with a chunk.
+type Action int
+
+const (
+	// Keep copies the chunk unchanged.
+	Keep Action = iota
+	// Drop leaves the chunk out of the output.
+	Drop
+	// Replace writes the payload returned by the transform under the same ID.
+	Replace
+)
+
+// Rewrite copies the container read from src to dst, asking transform what
+// to do with each chunk of the outermost group. Kept chunks are copied byte
+// for byte, dropped chunks are skipped and replaced chunks get the payload of
+// the returned reader. Sizes are recomputed for the output, which has the
+// same format and form type as the input.
+//
+// transform may look at the chunk's ID and Size, and read from chunks it
+// drops or replaces; a kept chunk must be left unread. With DropDuplicates,
+// kept chunks repeating an earlier one are left out as well; with
+// PreserveUnknown, kept chunks keep their pad bytes.
+func Rewrite(dst io.Writer, src io.Reader, transform func(*chunk.Reader) (Action, io.Reader), opts ...OutputOption) error {
+	cfg := newOutputConfig(opts)
+	return edit(dst, src, func(cw *Writer, ch *chunk.Reader, _ int) error {
+		action, r := transform(ch)
+		switch action {
+		case Keep:
+			if cfg.preserves(ch.ID) {
+				return cfg.dedup.copy(cw, ch, copyOpen)
+			}
+			return cfg.dedup.copy(cw, ch, CopyChunk)
+		case Replace:
+			return writeChunk(cw, ch.ID, r)
+		}
+		return nil
+	}, nil)
+}
+
+// beginOuter opens the group described by h on cw.
+func beginOuter(cw *Writer, h Header) error {
+	switch string(h.ID[:]) {
+	case "LIST", "CAT ":
+		return cw.BeginGroup(string(h.ID[:]), string(h.Type[:]))
+	}
+	return cw.BeginForm(string(h.Type[:]))
+}
+
+// writeChunk writes a chunk with the given ID and the payload read from r.
+func writeChunk(cw *Writer, id [4]byte, r io.Reader) error {
+	f, err := cw.beginID(id, false)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		if _, err := io.Copy(f.ch, r); err != nil {
+			return err
+		}
+	}
+	return cw.EndChunk()
+}
@@ -0,0 +1,75 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func buildWAV(t *testing.T, chunks ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	cw := NewWriter(&buf, RIFF)
+	cw.BeginForm("WAVE")
+	for i := 0; i+1 < len(chunks); i += 2 {
+		ch, err := cw.BeginChunk(chunks[i])
+		if err != nil {
+			t.Fatalf("BeginChunk: %v", err)
+		}
+		ch.Write([]byte(chunks[i+1]))
+		cw.EndChunk()
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRewrite(t *testing.T) {
+	src := buildWAV(t, "fmt ", "format", "JUNK", "xyz", "data", "samples")
+
+	t.Run("keeps everything byte for byte", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Rewrite(&out, bytes.NewReader(src), func(*chunk.Reader) (Action, io.Reader) {
+			return Keep, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), src) {
+			t.Fatalf("expected identical output\n got % x\nwant % x", out.Bytes(), src)
+		}
+	})
+
+	t.Run("drops and replaces chunks", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Rewrite(&out, bytes.NewReader(src), func(ch *chunk.Reader) (Action, io.Reader) {
+			switch string(ch.ID[:]) {
+			case "JUNK":
+				return Drop, nil
+			case "fmt ":
+				return Replace, strings.NewReader("new")
+			}
+			return Keep, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "new", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("rejects unknown containers", func(t *testing.T) {
+		err := Rewrite(io.Discard, strings.NewReader("not a container"), func(*chunk.Reader) (Action, io.Reader) {
+			return Keep, nil
+		})
+		if err != ErrUnknownFormat {
+			t.Fatalf("expected ErrUnknownFormat, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package container
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ds64PayloadSize is the size of a ds64 chunk without a table: the RIFF size,
+// the data size and the sample count as 64-bit values plus the table length.
+const ds64PayloadSize = 28
+
+// ds64EntrySize is the size of a ds64 table entry: a chunk ID and its
+// 64-bit size.
+const ds64EntrySize = 12
+
+func init() {
+	chunk.RegisterDescriber("ds64", describeDS64)
+}
+
+// describeDS64 summarizes the 64-bit sizes of a ds64 chunk.
+func describeDS64(r *chunk.Reader) (string, error) {
+	var d struct{ RIFFSize, DataSize, SampleCount uint64 }
+	if err := r.ReadLE(&d); err != nil {
+		return "", err
+	}
+	s := fmt.Sprintf("RIFF size %d, data size %d, %d samples", d.RIFFSize, d.DataSize, d.SampleCount)
+	if n, err := chunk.Read[uint32](r, binary.LittleEndian); err == nil && n > 0 {
+		s += fmt.Sprintf(", %d table entries", n)
+	}
+	return s, nil
+}
+
+// ds64Info tracks the reserved JUNK chunk of a RF64/BW64 form and the 64-bit
+// sizes that have to go into the ds64 chunk replacing it.
+type ds64Info struct {
+	form *frame
+	// junk is the offset of the JUNK header; absolute when the output is
+	// seekable, relative to the form payload when it is buffered.
+	junk        int64
+	dataSize    uint64
+	sampleCount uint64
+	large       bool
+	// table holds the large chunks listed in Writer.DS64Table, which has
+	// slots entries reserved.
+	table []DS64Size
+	slots int
+}
+
+// SetSampleCount sets the sample count stored in the ds64 chunk of a RF64 or
+// BW64 container. It should match the fact chunk of non-PCM formats.
+func (cw *Writer) SetSampleCount(n uint64) {
+	if cw.ds64 != nil {
+		cw.ds64.sampleCount = n
+	}
+}
+
+// reserveDS64 writes the JUNK chunk that is converted to ds64 when the sizes
+// of the form turn out to exceed 32 bits.
+func (cw *Writer) reserveDS64() error {
+	form := cw.stack[len(cw.stack)-1]
+	// the ds64 chunk has to come first, whatever the alignment
+	cw.padding = true
+	ch, err := cw.BeginChunk("JUNK")
+	cw.padding = false
+	if err != nil {
+		return err
+	}
+	junk := cw.stack[len(cw.stack)-1]
+	d := &ds64Info{form: form, junk: junk.start, slots: len(cw.DS64Table)}
+	if form.buf != nil {
+		d.junk = junk.start - form.start - 8
+	}
+	if _, err := ch.Write(make([]byte, ds64PayloadSize+ds64EntrySize*d.slots)); err != nil {
+		return err
+	}
+	if err := cw.EndChunk(); err != nil {
+		return err
+	}
+	cw.ds64 = d
+	return nil
+}
+
+// record notes the size of a chunk ending inside the form. Only the data
+// chunk and those with a table slot in ids may exceed 32 bits.
+func (d *ds64Info) record(f *frame, size int64, ids []string) error {
+	switch {
+	case f == d.form:
+		return nil
+	case f.id == [4]byte{'d', 'a', 't', 'a'}:
+		d.dataSize = uint64(size)
+		d.large = d.large || size > maxSize32
+		return nil
+	case size <= maxSize32:
+		return nil
+	}
+	// a slot for every listed ID, more for IDs listed several times
+	slots := 0
+	for _, id := range ids {
+		if fourCC(id) == f.id {
+			slots++
+		}
+	}
+	for _, e := range d.table {
+		if e.ID == f.id {
+			slots--
+		}
+	}
+	if slots <= 0 {
+		return ErrChunkTooLarge
+	}
+	d.table = append(d.table, DS64Size{ID: f.id, Size: uint64(size)})
+	d.large = true
+	return nil
+}
+
+// finishDS64 converts the reserved JUNK chunk into a ds64 chunk and renames
+// the form when any size exceeded 32 bits. It reports whether the form was
+// converted.
+func (cw *Writer) finishDS64(form *frame, size int64) (bool, error) {
+	d := cw.ds64
+	cw.ds64 = nil
+	if !d.large && size <= maxSize32 {
+		return false, nil
+	}
+
+	// unused slots stay zero behind the table
+	ds := make([]byte, 8+ds64PayloadSize+ds64EntrySize*d.slots)
+	copy(ds[:4], "ds64")
+	binary.LittleEndian.PutUint32(ds[4:], uint32(len(ds)-8))
+	binary.LittleEndian.PutUint64(ds[8:], uint64(size))
+	binary.LittleEndian.PutUint64(ds[16:], d.dataSize)
+	binary.LittleEndian.PutUint64(ds[24:], d.sampleCount)
+	binary.LittleEndian.PutUint32(ds[32:], uint32(len(d.table)))
+	for i, e := range d.table {
+		off := 8 + ds64PayloadSize + ds64EntrySize*i
+		copy(ds[off:], e.ID[:])
+		binary.LittleEndian.PutUint64(ds[off+4:], e.Size)
+	}
+
+	if form.buf != nil {
+		copy(form.buf.Bytes()[d.junk:], ds)
+	} else if err := cw.writeAt(ds, d.junk); err != nil {
+		return false, err
+	}
+	form.id = fourCC("RF64")
+	if cw.format == BW64 {
+		form.id = fourCC("BW64")
+	}
+	return true, nil
+}
+
+// writeAt overwrites already written bytes of a seekable output and returns
+// to the current position.
+func (cw *Writer) writeAt(p []byte, off int64) error {
+	cur, err := cw.ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := cw.ws.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := cw.w.Write(p); err != nil {
+		return err
+	}
+	_, err = cw.ws.Seek(cur, io.SeekStart)
+	return err
+}
@@ -0,0 +1,270 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func writeBW64(t *testing.T, cw *Writer, data []byte) {
+	t.Helper()
+	if err := cw.BeginForm("WAVE"); err != nil {
+		t.Fatalf("BeginForm: %v", err)
+	}
+	ch, err := cw.BeginChunk("data")
+	if err != nil {
+		t.Fatalf("BeginChunk: %v", err)
+	}
+	ch.Write(data)
+	cw.SetSampleCount(uint64(len(data) / 2))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestWriter_BW64(t *testing.T) {
+	data := bytes.Repeat([]byte{1, 2, 3, 4}, 10)
+
+	t.Run("keeps JUNK for small files", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeBW64(t, NewWriter(&buf, BW64), data)
+		out := buf.Bytes()
+		if string(out[:4]) != "RIFF" {
+			t.Fatalf("expected RIFF, got %q", out[:4])
+		}
+		if string(out[12:16]) != "JUNK" {
+			t.Fatalf("expected JUNK, got %q", out[12:16])
+		}
+		if got := binary.LittleEndian.Uint32(out[16:]); got != ds64PayloadSize {
+			t.Fatalf("expected JUNK size %d, got %d", ds64PayloadSize, got)
+		}
+		if got := binary.LittleEndian.Uint32(out[4:]); int(got) != len(out)-8 {
+			t.Fatalf("expected RIFF size %d, got %d", len(out)-8, got)
+		}
+	})
+
+	check := func(t *testing.T, out []byte, form string) {
+		t.Helper()
+		if string(out[:4]) != form {
+			t.Fatalf("expected %s, got %q", form, out[:4])
+		}
+		if got := binary.LittleEndian.Uint32(out[4:]); got != 0xFFFFFFFF {
+			t.Fatalf("expected RIFF size -1, got %d", got)
+		}
+		if string(out[12:16]) != "ds64" {
+			t.Fatalf("expected ds64, got %q", out[12:16])
+		}
+		if got := binary.LittleEndian.Uint64(out[20:]); int(got) != len(out)-8 {
+			t.Fatalf("expected ds64 RIFF size %d, got %d", len(out)-8, got)
+		}
+		if got := binary.LittleEndian.Uint64(out[28:]); int(got) != len(data) {
+			t.Fatalf("expected ds64 data size %d, got %d", len(data), got)
+		}
+		if got := binary.LittleEndian.Uint64(out[36:]); int(got) != len(data)/2 {
+			t.Fatalf("expected ds64 sample count %d, got %d", len(data)/2, got)
+		}
+		dataHdr := 12 + 8 + ds64PayloadSize
+		if got := binary.LittleEndian.Uint32(out[dataHdr+4:]); got != 0xFFFFFFFF {
+			t.Fatalf("expected data size -1, got %d", got)
+		}
+	}
+
+	t.Run("converts to ds64 when sizes exceed 32 bits", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		var buf bytes.Buffer
+		writeBW64(t, NewWriter(&buf, BW64), data)
+		check(t, buf.Bytes(), "BW64")
+	})
+
+	t.Run("converts seekable output in place", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		var sb seekBuffer
+		writeBW64(t, NewWriter(&sb, RF64), data)
+		check(t, sb.buf, "RF64")
+	})
+
+	t.Run("rejects other large chunks", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RF64)
+		cw.BeginForm("WAVE")
+		ch, _ := cw.BeginChunk("iXML")
+		ch.Write(data)
+		if err := cw.EndChunk(); err != ErrChunkTooLarge {
+			t.Fatalf("expected ErrChunkTooLarge, got %v", err)
+		}
+	})
+}
+
+func TestDS64Table(t *testing.T) {
+	defer func(v int64) { maxSize32 = v }(maxSize32)
+	// room for the JUNK chunk reserving three table entries
+	maxSize32 = 64
+	big := bytes.Repeat([]byte("<x/>"), 20)
+
+	write := func(t *testing.T, table ...string) ([]byte, error) {
+		t.Helper()
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RF64)
+		cw.DS64Table = table
+		cw.BeginForm("WAVE")
+		for _, id := range []string{"iXML", "data", "axml"} {
+			ch, _ := cw.BeginChunk(id)
+			ch.Write(big)
+			if err := cw.EndChunk(); err != nil {
+				return nil, err
+			}
+		}
+		err := cw.Close()
+		return buf.Bytes(), err
+	}
+
+	t.Run("records listed chunks", func(t *testing.T) {
+		out, err := write(t, "axml", "iXML", "bext")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := binary.LittleEndian.Uint32(out[16:]); got != ds64PayloadSize+3*ds64EntrySize {
+			t.Fatalf("expected ds64 size %d, got %d", ds64PayloadSize+3*ds64EntrySize, got)
+		}
+		sc, err := NewScanner(bytes.NewReader(out))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []DS64Size{{ID: chunk.FourCC{'i', 'X', 'M', 'L'}, Size: 80}, {ID: chunk.FourCC{'a', 'x', 'm', 'l'}, Size: 80}}
+		if len(sc.DS64.Table) != 2 || sc.DS64.Table[0] != want[0] || sc.DS64.Table[1] != want[1] {
+			t.Fatalf("unexpected table %+v", sc.DS64.Table)
+		}
+		for sc.Next() {
+			if ch := sc.Chunk(); ch.Size != len(big) {
+				t.Fatalf("%s: expected size %d, got %d", ch.ID, len(big), ch.Size)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, entries, err := Index(bytes.NewReader(out))
+		if err != nil || len(entries) != 3 || entries[2].Size != int64(len(big)) {
+			t.Fatalf("unexpected index %+v, %v", entries, err)
+		}
+	})
+
+	t.Run("sizes chunks sharing an ID per occurrence", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RF64)
+		cw.DS64Table = []string{"iXML", "iXML"}
+		cw.BeginForm("WAVE")
+		for _, n := range []int{100, 80} {
+			ch, _ := cw.BeginChunk("iXML")
+			ch.Write(bytes.Repeat([]byte("x"), n))
+			if err := cw.EndChunk(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := cw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out := buf.Bytes()
+		sc, err := NewScanner(bytes.NewReader(out))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var sizes []int
+		for sc.Next() {
+			sizes = append(sizes, sc.Chunk().Size)
+		}
+		if err := sc.Err(); err != nil || len(sizes) != 2 || sizes[0] != 100 || sizes[1] != 80 {
+			t.Fatalf("unexpected sizes %v, %v", sizes, err)
+		}
+		_, entries, err := Index(bytes.NewReader(out))
+		if err != nil || len(entries) != 2 || entries[0].Size != 100 || entries[1].Size != 80 {
+			t.Fatalf("unexpected index %+v, %v", entries, err)
+		}
+		tree, err := ParseTree(bytes.NewReader(out), TreeOptions{})
+		// the tree lists the ds64 chunk first
+		if err != nil || len(tree.Root.Children) != 3 || tree.Root.Children[1].Size != 100 || tree.Root.Children[2].Size != 80 {
+			t.Fatalf("unexpected tree, %v", err)
+		}
+	})
+
+	t.Run("keeps the table when rewriting", func(t *testing.T) {
+		out, _ := write(t, "iXML", "axml")
+		var buf bytes.Buffer
+		if err := Remove(&buf, bytes.NewReader(out), MatchID("JUNK")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), out) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), out)
+		}
+	})
+
+	t.Run("keeps the tables of merged inputs", func(t *testing.T) {
+		ixml := func(n int) []byte {
+			var buf bytes.Buffer
+			cw := NewWriter(&buf, RF64)
+			cw.DS64Table = []string{"iXML"}
+			cw.BeginForm("WAVE")
+			ch, _ := cw.BeginChunk("iXML")
+			ch.Write(bytes.Repeat([]byte("x"), n))
+			cw.EndChunk()
+			if err := cw.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return buf.Bytes()
+		}
+		a, b := ixml(100), ixml(80)
+		for _, c := range []struct {
+			policy MergePolicy
+			sizes  []int64
+		}{
+			{KeepFirst, []int64{100}},
+			{KeepSecond, []int64{80}},
+			{KeepBoth, []int64{100, 80}},
+		} {
+			var buf bytes.Buffer
+			if err := Merge(&buf, bytes.NewReader(a), bytes.NewReader(b), c.policy); err != nil {
+				t.Fatalf("policy %d: unexpected error: %v", c.policy, err)
+			}
+			out := buf.Bytes()
+			// a slot per chunk kept
+			if got := binary.LittleEndian.Uint32(out[16:]); got != ds64PayloadSize+uint32(len(c.sizes))*ds64EntrySize {
+				t.Fatalf("policy %d: unexpected ds64 size %d", c.policy, got)
+			}
+			_, entries, err := Index(bytes.NewReader(out))
+			if err != nil || len(entries) != len(c.sizes) {
+				t.Fatalf("policy %d: unexpected index %+v, %v", c.policy, entries, err)
+			}
+			for i, e := range entries {
+				if e.Size != c.sizes[i] {
+					t.Fatalf("policy %d: unexpected index %+v", c.policy, entries)
+				}
+			}
+		}
+	})
+
+	t.Run("rejects chunks without a slot", func(t *testing.T) {
+		if _, err := write(t, "iXML"); err != ErrChunkTooLarge {
+			t.Fatalf("expected ErrChunkTooLarge, got %v", err)
+		}
+	})
+}
+
+func TestDescribeDS64(t *testing.T) {
+	payload := make([]byte, ds64PayloadSize)
+	binary.LittleEndian.PutUint64(payload, 5<<30)
+	binary.LittleEndian.PutUint64(payload[8:], 5<<30-64)
+	binary.LittleEndian.PutUint64(payload[16:], 1000)
+	r := &chunk.Reader{ID: [4]byte{'d', 's', '6', '4'}, Size: len(payload), R: bytes.NewReader(payload)}
+	want := "RIFF size 5368709120, data size 5368709056, 1000 samples"
+	if got := chunk.Describe(r); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
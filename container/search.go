@@ -0,0 +1,126 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/CWBudde/chunk"
+)
+
+// MaxTextChunk is the largest payload SearchText treats as text; bigger
+// chunks, such as audio data, are skipped without being read.
+const MaxTextChunk = 1 << 20
+
+// ErrEmptyPattern is returned by Search for an empty pattern.
+var ErrEmptyPattern = errors.New("container: empty search pattern")
+
+// SearchMatch is an occurrence of a pattern in a chunk payload.
+type SearchMatch struct {
+	// Path locates the chunk as in Diff, e.g. "LIST:INFO/INAM".
+	Path string `json:"path"`
+	// Offset is the absolute file offset of the first matching byte.
+	Offset int64 `json:"offset"`
+	// Text is the matched text for SearchText.
+	Text string `json:"text,omitempty"`
+}
+
+// Search returns every occurrence of pattern in the payloads of the data
+// chunks of the container in ra, in file order, such as a UMID embedded in
+// a bext chunk. Payloads are streamed through a pooled buffer, so files of
+// any size are searched in constant memory; only group headers are parsed
+// up front.
+func Search(ra io.ReaderAt, pattern []byte) ([]SearchMatch, error) {
+	if len(pattern) == 0 {
+		return nil, ErrEmptyPattern
+	}
+	t, err := ParseTree(ra, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var matches []SearchMatch
+	buf := chunk.GetBuffer()
+	defer chunk.PutBuffer(buf)
+	if len(buf) < 2*len(pattern) {
+		buf = make([]byte, 2*len(pattern))
+	}
+	for _, l := range leafPaths(t.Root, "") {
+		if l.node.Size < int64(len(pattern)) {
+			continue
+		}
+		found, err := searchPayload(l.node, pattern, buf)
+		for _, off := range found {
+			matches = append(matches, SearchMatch{Path: l.path, Offset: off})
+		}
+		if err != nil {
+			return matches, err
+		}
+	}
+	return matches, nil
+}
+
+// searchPayload returns the file offsets of pattern in the payload of n.
+// The last len(pattern)-1 bytes of every read are kept, so matches across
+// reads are found.
+func searchPayload(n *Node, pattern, buf []byte) ([]int64, error) {
+	var found []int64
+	r := n.Payload()
+	base := n.Offset + 8 // file offset of buf[0]
+	kept := 0
+	for {
+		k, err := io.ReadFull(r, buf[kept:])
+		end := kept + k
+		for i := 0; ; {
+			j := bytes.Index(buf[i:end], pattern)
+			if j < 0 {
+				break
+			}
+			found = append(found, base+int64(i+j))
+			i += j + 1
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return found, nil
+		}
+		if err != nil {
+			return found, err
+		}
+		kept = min(len(pattern)-1, end)
+		copy(buf, buf[end-kept:end])
+		base += int64(end - kept)
+	}
+}
+
+// SearchText returns every match of re in the text chunks of the container
+// in ra, in file order. A chunk is text if its payload is valid UTF-8, not
+// counting the NUL terminators many writers add, and at most MaxTextChunk
+// bytes, as for INFO tags and iXML.
+func SearchText(ra io.ReaderAt, re *regexp.Regexp) ([]SearchMatch, error) {
+	t, err := ParseTree(ra, TreeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var matches []SearchMatch
+	for _, l := range leafPaths(t.Root, "") {
+		if l.node.Size > MaxTextChunk {
+			continue
+		}
+		b, err := io.ReadAll(l.node.Payload())
+		if err != nil {
+			return matches, err
+		}
+		b = bytes.TrimRight(b, "\x00")
+		if !utf8.Valid(b) || bytes.IndexByte(b, 0) >= 0 {
+			continue
+		}
+		for _, loc := range re.FindAllIndex(b, -1) {
+			matches = append(matches, SearchMatch{
+				Path:   l.path,
+				Offset: l.node.Offset + 8 + int64(loc[0]),
+				Text:   string(b[loc[0]:loc[1]]),
+			})
+		}
+	}
+	return matches, nil
+}
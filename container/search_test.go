@@ -0,0 +1,78 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	src := bytes.NewReader(buildNested(t))
+
+	t.Run("finds bytes in payloads", func(t *testing.T) {
+		matches, err := Search(src, []byte("m"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []SearchMatch{{Path: "fmt ", Offset: 23}, {Path: "data", Offset: 60}}
+		if len(matches) != len(want) || matches[0] != want[0] || matches[1] != want[1] {
+			t.Fatalf("expected %v, got %v", want, matches)
+		}
+	})
+
+	t.Run("skips headers and pad bytes", func(t *testing.T) {
+		for _, p := range []string{"INFO", "INAM", "odd\x00"} {
+			matches, err := Search(src, []byte(p))
+			if err != nil || len(matches) != 0 {
+				t.Fatalf("%q: unexpected matches %v (%v)", p, matches, err)
+			}
+		}
+	})
+
+	t.Run("finds matches across reads", func(t *testing.T) {
+		tree, err := ParseTree(src, TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data := tree.Root.Children[2]
+		found, err := searchPayload(data, []byte("ampl"), make([]byte, 4))
+		if err != nil || len(found) != 1 || found[0] != 59 {
+			t.Fatalf("unexpected offsets %v (%v)", found, err)
+		}
+	})
+
+	t.Run("rejects empty patterns", func(t *testing.T) {
+		if _, err := Search(src, nil); !errors.Is(err, ErrEmptyPattern) {
+			t.Fatalf("expected ErrEmptyPattern, got %v", err)
+		}
+	})
+}
+
+func TestSearchText(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewWriter(&buf, RIFF)
+	cw.BeginForm("WAVE")
+	cw.BeginList("INFO")
+	ch, _ := cw.BeginChunk("ICMT")
+	ch.Write([]byte("take 12, take 13\x00"))
+	cw.EndChunk()
+	cw.EndChunk()
+	ch, _ = cw.BeginChunk("data")
+	ch.Write([]byte{0, 't', 'a', 'k', 'e', ' ', '1', '4'})
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	matches, err := SearchText(bytes.NewReader(buf.Bytes()), regexp.MustCompile(`take \d+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []SearchMatch{
+		{Path: "LIST:INFO/ICMT", Offset: 32, Text: "take 12"},
+		{Path: "LIST:INFO/ICMT", Offset: 41, Text: "take 13"},
+	}
+	if len(matches) != len(want) || matches[0] != want[0] || matches[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, matches)
+	}
+}
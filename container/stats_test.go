@@ -0,0 +1,57 @@
+package container
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("times walker handlers", func(t *testing.T) {
+		defer func(f func() time.Time) { timeNow = f }(timeNow)
+		now := time.Unix(0, 0)
+		timeNow = func() time.Time { return now }
+
+		src := buildWAV(t, "fmt ", "format", "iXML", "<xml/>", "data", "samples")
+		stats := &Stats{}
+		w := Walker{Stats: stats}
+		w.Handle("iXML", func(*chunk.Reader) error {
+			now = now.Add(2 * time.Second)
+			return nil
+		})
+		w.Handle("data", func(*chunk.Reader) error {
+			now = now.Add(time.Second)
+			return nil
+		})
+		if err := w.Walk(bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids := stats.IDs()
+		if len(ids) != 2 || ids[0].String() != "iXML" || ids[1].String() != "data" {
+			t.Fatalf("unexpected IDs %q", ids)
+		}
+		c := stats.Get(ids[1])
+		if c.Count != 1 || c.Bytes != 7 || c.Duration != time.Second || c.Throughput() != 7 {
+			t.Fatalf("unexpected data stats %+v", c)
+		}
+		if s := stats.String(); !strings.HasPrefix(s, "iXML: 1 chunks, 6 bytes in 2s") {
+			t.Fatalf("unexpected summary %q", s)
+		}
+	})
+
+	t.Run("sums up calls", func(t *testing.T) {
+		var s Stats
+		id := chunk.FourCC{'L', 'I', 'S', 'T'}
+		s.Record(id, 10, time.Millisecond)
+		s.Record(id, 30, 3*time.Millisecond)
+		if c := s.Get(id); c.Count != 2 || c.Bytes != 40 || c.Throughput() != 10000 {
+			t.Fatalf("unexpected stats %+v", c)
+		}
+		if c := s.Get(chunk.FourCC{'d', 'a', 't', 'a'}); c != (ChunkStats{}) || c.Throughput() != 0 {
+			t.Fatalf("expected no stats, got %+v", c)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrUnknownForm is returned by StripMetadata for form types whose
+// essential chunks are not known.
+var ErrUnknownForm = errors.New("container: essential chunks of the form type are not known")
+
+// essentialChunks lists the chunks a file of a form type cannot play
+// without, including the wavl list some WAVE files hold instead of data.
+var essentialChunks = map[string][]string{
+	"WAVE": {"fmt ", "fact", "data", "LIST:wavl"},
+	"AIFF": {"COMM", "SSND"},
+	"AIFC": {"FVER", "COMM", "SSND"},
+}
+
+// StripMetadata copies the container read from src to dst with only the
+// chunks of the outermost group a player needs, such as fmt and data for
+// WAVE files, and those listed in keep, to scrub media of metadata before
+// publishing. Groups are kept by ID or by ID and type, as in "LIST:INFO".
+// Fillers are dropped too, as they may hold stale data. Form types other
+// than WAVE, AIFF and AIFC fail with ErrUnknownForm.
+func StripMetadata(dst io.Writer, src io.Reader, keep ...string) error {
+	br := bufio.NewReader(src)
+	b, err := br.Peek(12)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	h, err := ReadHeader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	match, err := stripMatch(h, keep)
+	if err != nil {
+		return err
+	}
+	return Remove(dst, br, match)
+}
+
+// StripMetadataFile strips the container stored at path, see
+// StripMetadata. The edit runs as a transaction, so path is left untouched
+// on error.
+func StripMetadataFile(path string, keep ...string) error {
+	tx, err := Begin(path)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	h, err := ReadHeader(io.NewSectionReader(tx.src, 0, 12))
+	if err != nil {
+		return err
+	}
+	match, err := stripMatch(h, keep)
+	if err != nil {
+		return err
+	}
+	if err := tx.Remove(match); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// stripMatch returns the match for the chunks StripMetadata removes from a
+// container with header h.
+func stripMatch(h Header, keep []string) (Match, error) {
+	essential, ok := essentialChunks[h.Type.String()]
+	if !ok {
+		return nil, ErrUnknownForm
+	}
+	var ids []string
+	var lists []Match
+	for _, k := range append(essential[:len(essential):len(essential)], keep...) {
+		if id, typ, ok := strings.Cut(k, ":"); ok {
+			lists = append(lists, matchGroup(id, typ))
+		} else {
+			ids = append(ids, k)
+		}
+	}
+	kept := MatchID(ids...)
+	return func(ch *chunk.Reader) bool {
+		if kept(ch) {
+			return false
+		}
+		for _, m := range lists {
+			if m(ch) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// matchGroup matches groups with the given ID and type.
+func matchGroup(id, typ string) Match {
+	gid, gtyp := fourCC(id), fourCC(typ)
+	return func(ch *chunk.Reader) bool {
+		if ch.ID != gid {
+			return false
+		}
+		b, err := ch.Peek(4)
+		return err == nil && [4]byte(b) == gtyp
+	}
+}
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripMetadata(t *testing.T) {
+	src := buildWAV(t, "bext", "origin", "fmt ", "format", "LIST", "INFOINAM", "JUNK", "xx",
+		"LIST", "adtlnote", "iXML", "<x/>", "data", "samples")
+
+	t.Run("keeps essential chunks", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := StripMetadata(&out, bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("keeps allowed chunks and groups", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := StripMetadata(&out, bytes.NewReader(src), "iXML", "LIST:adtl"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "LIST", "adtlnote", "iXML", "<x/>", "data", "samples")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("keeps wavl lists", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "format", "LIST", "wavldata", "LIST", "INFOINAM")
+		var out bytes.Buffer
+		if err := StripMetadata(&out, bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := buildWAV(t, "fmt ", "format", "LIST", "wavldata")
+		if !bytes.Equal(out.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", out.Bytes(), want)
+		}
+	})
+
+	t.Run("strips files in place", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "take.wav")
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := StripMetadataFile(path, "bext"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if want := buildWAV(t, "bext", "origin", "fmt ", "format", "data", "samples"); !bytes.Equal(got, want) {
+			t.Fatalf("unexpected file\n got % x\nwant % x", got, want)
+		}
+	})
+
+	t.Run("rejects unknown forms", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RIFF)
+		cw.BeginForm("AVI ")
+		cw.Close()
+		if err := StripMetadata(&bytes.Buffer{}, bytes.NewReader(buf.Bytes())); err != ErrUnknownForm {
+			t.Fatalf("expected ErrUnknownForm, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package container
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Transform is a symmetric payload transform such as encryption or
+// obfuscation, applied per data chunk while the framing (IDs, sizes, groups)
+// stays readable by any RIFF or IFF reader.
+type Transform struct {
+	// Match selects the chunks to transform; nil selects every data chunk.
+	Match func(id chunk.FourCC) bool
+	// Encode wraps the stored payload on write. Close is called on EndChunk,
+	// before the chunk size is written.
+	Encode func(w io.Writer) io.WriteCloser
+	// Decode wraps the stored payload on read.
+	Decode func(r io.Reader) io.Reader
+}
+
+func (t *Transform) applies(id [4]byte) bool {
+	return t != nil && (t.Match == nil || t.Match(chunk.FourCC(id)))
+}
+
+// SetTransform applies t to the payload of every matching data chunk begun
+// afterwards; the chunk.Writer returned by BeginChunk then takes the plain
+// payload and the chunk size counts the encoded bytes. Pass nil to stop
+// transforming.
+func (cw *Writer) SetTransform(t *Transform) {
+	cw.transform = t
+}
+
+// startTransform routes the caller's writes for f through the encoder.
+func (cw *Writer) startTransform(f *frame) {
+	if f.group || cw.padding || cw.raw || !cw.transform.applies(f.id) || cw.transform.Encode == nil {
+		return
+	}
+	f.enc = cw.transform.Encode(f.ch)
+	f.plain = &chunk.Writer{ID: f.id, W: f.enc}
+}
+
+// Chunk returns the current chunk. When a Transform is set and matches the
+// chunk, its payload is read through Transform.Decode; Size still reports
+// the stored size, so read such chunks until io.EOF.
+func (s *Scanner) Chunk() *chunk.Reader {
+	ch := s.Scanner.Chunk()
+	if ch == nil || ch == s.decoded || !s.Transform.applies(ch.ID) || s.Transform.Decode == nil {
+		return ch
+	}
+	ch.R = s.Transform.Decode(ch.R)
+	s.decoded = ch
+	return ch
+}
@@ -0,0 +1,110 @@
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// xorWriter obfuscates bytes with a single byte key.
+type xorWriter struct {
+	w   io.Writer
+	key byte
+}
+
+func (x *xorWriter) Write(p []byte) (int, error) {
+	q := make([]byte, len(p))
+	for i, b := range p {
+		q[i] = b ^ x.key
+	}
+	return x.w.Write(q)
+}
+
+func (x *xorWriter) Close() error { return nil }
+
+type xorReader struct {
+	r   io.Reader
+	key byte
+}
+
+func (x *xorReader) Read(p []byte) (int, error) {
+	n, err := x.r.Read(p)
+	for i := range p[:n] {
+		p[i] ^= x.key
+	}
+	return n, err
+}
+
+var xorTransform = &Transform{
+	Match:  func(id chunk.FourCC) bool { return id.String() == "SECR" },
+	Encode: func(w io.Writer) io.WriteCloser { return &xorWriter{w, 0x5a} },
+	Decode: func(r io.Reader) io.Reader { return &xorReader{r, 0x5a} },
+}
+
+func writeTransformed(t *testing.T, out io.Writer) {
+	t.Helper()
+	cw := NewWriter(out, RIFF)
+	cw.SetTransform(xorTransform)
+	cw.BeginForm("GAME")
+	for _, id := range []string{"SECR", "open"} {
+		ch, err := cw.BeginChunk(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch.Write([]byte("abc"))
+		if err := cw.EndChunk(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	want := []byte{
+		'R', 'I', 'F', 'F', 28, 0, 0, 0, 'G', 'A', 'M', 'E',
+		'S', 'E', 'C', 'R', 3, 0, 0, 0, 'a' ^ 0x5a, 'b' ^ 0x5a, 'c' ^ 0x5a, 0,
+		'o', 'p', 'e', 'n', 3, 0, 0, 0, 'a', 'b', 'c', 0,
+	}
+
+	t.Run("encodes matching chunks while buffering", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeTransformed(t, &buf)
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+
+	t.Run("encodes matching chunks while seeking", func(t *testing.T) {
+		sb := &seekBuffer{}
+		writeTransformed(t, sb)
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("decodes matching chunks", func(t *testing.T) {
+		s, err := NewScanner(bytes.NewReader(want))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.Transform = xorTransform
+		for s.Next() {
+			// Chunk may be called repeatedly without decoding twice
+			s.Chunk()
+			got, err := io.ReadAll(s.Chunk())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != "abc" {
+				t.Fatalf("chunk %q: unexpected payload %q", s.Chunk().ID, got)
+			}
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/CWBudde/chunk"
+)
+
+var (
+	// ErrTreeBudget is returned by ParseTree when the container has more
+	// chunks than TreeOptions allow.
+	ErrTreeBudget = errors.New("container: chunk tree exceeds its budget")
+	// ErrTooDeep is returned by ParseTree for groups nested more than
+	// MaxDepth levels deep, counting the outermost one.
+	ErrTooDeep = errors.New("container: groups nested too deeply")
+)
+
+// MaxDepth is the deepest nesting of groups ParseTree follows. Real files
+// rarely go beyond a handful of levels; crafted ones could otherwise nest a
+// group every 12 bytes.
+const MaxDepth = 64
+
+// nodeCost estimates the memory held per node: the node itself and its
+// pointer in the parent's Children.
+const nodeCost = int64(unsafe.Sizeof(Node{})) + 8
+
+// Node is a chunk in the tree of a container. Groups such as LIST hold their
+// chunks in Children.
+type Node struct {
+	ID chunk.FourCC `json:"id"`
+	// Type is the form or list type of groups.
+	Type chunk.FourCC `json:"type,omitzero"`
+	// Offset is the absolute offset of the chunk header.
+	Offset int64 `json:"offset"`
+	// Size is the payload size, including the type of groups but excluding
+	// the header and pad byte.
+	Size     int64   `json:"size"`
+	Children []*Node `json:"chunks,omitempty"`
+
+	tree   *Tree
+	cached atomic.Pointer[[]byte]
+}
+
+// IsGroup reports whether n is a group chunk with a type and children.
+func (n *Node) IsGroup() bool {
+	return IsGroup(n.ID)
+}
+
+// End returns the offset right after the chunk including its pad byte.
+func (n *Node) End() int64 {
+	return n.Offset + 8 + n.Size + n.Size%2
+}
+
+// Payload returns a reader over the payload of n, read from the source of
+// the tree on demand. For groups it starts with the type. Payloads no larger
+// than TreeOptions.CacheSize are read once and then served from memory.
+func (n *Node) Payload() io.ReadSeeker {
+	t := n.tree
+	if b := n.cached.Load(); b != nil {
+		return bytes.NewReader(*b)
+	}
+	sr := io.NewSectionReader(t.ra, n.Offset+8, n.Size)
+	if n.Size > t.cacheSize {
+		return sr
+	}
+	b := make([]byte, n.Size)
+	if _, err := io.ReadFull(sr, b); err != nil {
+		// leave truncated payloads to the section reader
+		return sr
+	}
+	n.cached.Store(&b)
+	return bytes.NewReader(b)
+}
+
+// Tree is the structure of a container as read by ParseTree.
+type Tree struct {
+	Header Header `json:"header"`
+	// DS64 is set for RF64 and BW64 containers.
+	DS64 *DS64 `json:"ds64,omitempty"`
+	// Root is the outermost group.
+	Root *Node `json:"root"`
+	// Nodes is the number of nodes including Root.
+	Nodes int `json:"nodes"`
+
+	ra        io.ReaderAt
+	cacheSize int64
+	decoded   *decodeCache
+}
+
+// TreeOptions bounds the resources ParseTree may use on untrusted input. A
+// zero limit means no limit.
+type TreeOptions struct {
+	// MaxNodes is the largest number of chunks, groups included.
+	MaxNodes int
+	// MaxMemory is the largest estimated size of the tree in bytes.
+	MaxMemory int64
+	// MaxDeclared bounds the sum of the declared sizes of data chunks, which
+	// cannot exceed the input length for a well-formed container. Exceeding
+	// it fails with chunk.ErrDeclaredSize.
+	MaxDeclared int64
+	// CacheSize is the largest payload Node.Payload keeps in memory once
+	// read; zero disables caching.
+	CacheSize int64
+	// DecodeCache is the number of decoded chunks DecodeNode keeps, the least
+	// recently used being dropped first; zero disables the cache.
+	DecodeCache int
+}
+
+// ParseTree reads the chunk headers of the container in ra, recursing into
+// groups, without reading any payload. When the tree would exceed opts it
+// stops and returns what it has read so far along with ErrTreeBudget, or
+// chunk.ErrDeclaredSize for MaxDeclared;
+// callers can then fall back to streaming the container with NewScanner,
+// which needs no memory per chunk.
+func ParseTree(ra io.ReaderAt, opts TreeOptions) (*Tree, error) {
+	sr := io.NewSectionReader(ra, 0, math.MaxInt64)
+	h, err := ReadHeader(sr)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{Header: h, Nodes: 1, ra: ra, cacheSize: opts.CacheSize}
+	t.Root = &Node{ID: h.ID, Type: h.Type, Size: h.Size, tree: t}
+	if opts.DecodeCache > 0 {
+		t.decoded = newDecodeCache(opts.DecodeCache)
+	}
+	p := treeParser{ra: ra, order: h.Format.ByteOrder(), opts: opts, tree: t}
+	off := int64(12)
+	if h.Format == RF64 || h.Format == BW64 {
+		ds, n, err := readDS64(sr)
+		if err != nil {
+			return t, err
+		}
+		t.DS64 = ds
+		p.ds64 = ds.cursor()
+		if h.Size == math.MaxUint32 {
+			t.Root.Size = int64(ds.RIFFSize)
+		}
+		var size [4]byte
+		if _, err := ra.ReadAt(size[:], off+4); err != nil {
+			return t, io.ErrUnexpectedEOF
+		}
+		ds64 := &Node{ID: chunk.FourCC{'d', 's', '6', '4'}, Offset: off, Size: int64(binary.LittleEndian.Uint32(size[:]))}
+		if err := p.add(t.Root, ds64); err != nil {
+			return t, err
+		}
+		off += n
+	}
+	return t, p.walk(t.Root, off, 8+t.Root.Size, 1)
+}
+
+type treeParser struct {
+	ra    io.ReaderAt
+	order binary.ByteOrder
+	opts  TreeOptions
+	tree  *Tree
+	// ds64 hands out the sizes of the ds64 table in file order
+	ds64 *ds64Sizes
+
+	declared int64
+}
+
+// walk adds the chunks between off and end to parent, which is nested depth
+// levels deep. Chunks claiming to extend beyond their group are listed with
+// their declared size, but their children are only read up to end.
+func (p *treeParser) walk(parent *Node, off, end int64, depth int) error {
+	if depth > MaxDepth {
+		return ErrTooDeep
+	}
+	for off+8 <= end {
+		var hdr [12]byte
+		if _, err := p.ra.ReadAt(hdr[:8], off); err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		n := &Node{Offset: off, Size: int64(p.order.Uint32(hdr[4:]))}
+		copy(n.ID[:], hdr[:4])
+		if p.ds64 != nil && n.Size == math.MaxUint32 {
+			if size, ok := p.ds64.size(n.ID); ok {
+				n.Size = size
+			}
+		}
+		if err := p.add(parent, n); err != nil {
+			return err
+		}
+		if n.IsGroup() && n.Size >= 4 {
+			if _, err := p.ra.ReadAt(hdr[8:], off+8); err != nil {
+				return io.ErrUnexpectedEOF
+			}
+			copy(n.Type[:], hdr[8:])
+			if err := p.walk(n, off+12, min(off+8+n.Size, end), depth+1); err != nil {
+				return err
+			}
+		}
+		off = n.End()
+	}
+	return nil
+}
+
+// add appends n to parent unless the budget is used up.
+func (p *treeParser) add(parent, n *Node) error {
+	nodes := p.tree.Nodes + 1
+	if p.opts.MaxNodes > 0 && nodes > p.opts.MaxNodes ||
+		p.opts.MaxMemory > 0 && int64(nodes)*nodeCost > p.opts.MaxMemory {
+		return ErrTreeBudget
+	}
+	if !n.IsGroup() {
+		p.declared += n.Size
+		if p.opts.MaxDeclared > 0 && p.declared > p.opts.MaxDeclared {
+			return chunk.ErrDeclaredSize
+		}
+	}
+	n.tree = p.tree
+	parent.Children = append(parent.Children, n)
+	p.tree.Nodes = nodes
+	return nil
+}
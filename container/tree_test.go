@@ -0,0 +1,155 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestParseTree(t *testing.T) {
+	src := buildNested(t)
+
+	t.Run("recurses into groups", func(t *testing.T) {
+		tree, err := ParseTree(bytes.NewReader(src), TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		root := tree.Root
+		if root.ID.String() != "RIFF" || root.Type.String() != "WAVE" || root.End() != int64(len(src)) {
+			t.Fatalf("unexpected root %+v", root)
+		}
+		if tree.Nodes != 5 || len(root.Children) != 3 {
+			t.Fatalf("expected 5 nodes with 3 top-level chunks, got %d and %d", tree.Nodes, len(root.Children))
+		}
+		list := root.Children[1]
+		if !list.IsGroup() || list.Type.String() != "INFO" || list.Offset != 26 || len(list.Children) != 1 {
+			t.Fatalf("unexpected list %+v", list)
+		}
+		if c := list.Children[0]; c.ID.String() != "INAM" || c.Offset != 38 || c.Size != 3 || c.End() != 50 {
+			t.Fatalf("unexpected nested chunk %+v", c)
+		}
+		if d := root.Children[2]; d.ID.String() != "data" || d.Offset != 50 {
+			t.Fatalf("unexpected data chunk %+v", d)
+		}
+	})
+
+	t.Run("includes ds64 of RF64 containers", func(t *testing.T) {
+		defer func(v int64) { maxSize32 = v }(maxSize32)
+		maxSize32 = 32
+
+		var buf bytes.Buffer
+		writeBW64(t, NewWriter(&buf, BW64), make([]byte, 40))
+		tree, err := ParseTree(bytes.NewReader(buf.Bytes()), TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c := tree.Root.Children
+		if len(c) != 2 || c[0].ID.String() != "ds64" || c[0].Size != 28 || c[1].Size != 40 || c[1].Offset != c[0].End() {
+			t.Fatalf("unexpected chunks %+v %+v", c[0], c[1])
+		}
+	})
+
+	t.Run("stops at the node budget", func(t *testing.T) {
+		tree, err := ParseTree(bytes.NewReader(src), TreeOptions{MaxNodes: 3})
+		if err != ErrTreeBudget {
+			t.Fatalf("expected ErrTreeBudget, got %v", err)
+		}
+		if tree.Nodes != 3 || len(tree.Root.Children) != 2 || len(tree.Root.Children[1].Children) != 0 {
+			t.Fatalf("expected the partial tree, got %d nodes", tree.Nodes)
+		}
+	})
+
+	t.Run("stops at the memory budget", func(t *testing.T) {
+		if _, err := ParseTree(bytes.NewReader(src), TreeOptions{MaxMemory: 4 * nodeCost}); err != ErrTreeBudget {
+			t.Fatalf("expected ErrTreeBudget, got %v", err)
+		}
+		if _, err := ParseTree(bytes.NewReader(src), TreeOptions{MaxMemory: 5 * nodeCost}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("bounds the declared sizes", func(t *testing.T) {
+		// fmt, INAM and data declare 6+3+7 bytes
+		if _, err := ParseTree(bytes.NewReader(src), TreeOptions{MaxDeclared: 15}); err != chunk.ErrDeclaredSize {
+			t.Fatalf("expected ErrDeclaredSize, got %v", err)
+		}
+		if _, err := ParseTree(bytes.NewReader(src), TreeOptions{MaxDeclared: 16}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("limits the nesting depth", func(t *testing.T) {
+		var inner []byte
+		for range MaxDepth {
+			inner = append(binary.BigEndian.AppendUint32([]byte("LIST"), uint32(4+len(inner))), append([]byte("INFO"), inner...)...)
+		}
+		form := append(binary.BigEndian.AppendUint32([]byte("FORM"), uint32(4+len(inner))), append([]byte("AIFF"), inner...)...)
+		if _, err := ParseTree(bytes.NewReader(form), TreeOptions{}); err != ErrTooDeep {
+			t.Fatalf("expected ErrTooDeep, got %v", err)
+		}
+		if _, err := ParseTree(bytes.NewReader(form[12:]), TreeOptions{}); err != nil {
+			t.Fatalf("unexpected error one level up: %v", err)
+		}
+	})
+
+	t.Run("rejects ds64 sizes beyond int64", func(t *testing.T) {
+		ds := []byte("RF64\xff\xff\xff\xffWAVEds64\x1c\x00\x00\x00")
+		ds = binary.LittleEndian.AppendUint64(ds, 1<<63)
+		ds = append(ds, make([]byte, 20)...)
+		if _, err := ParseTree(bytes.NewReader(ds), TreeOptions{}); err != chunk.ErrSizeOverflow {
+			t.Fatalf("expected ErrSizeOverflow, got %v", err)
+		}
+	})
+}
+
+func TestNode_Payload(t *testing.T) {
+	src := buildNested(t)
+
+	t.Run("reads on demand", func(t *testing.T) {
+		tree, err := ParseTree(bytes.NewReader(src), TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		list := tree.Root.Children[1]
+		got, _ := io.ReadAll(list.Children[0].Payload())
+		if string(got) != "odd" {
+			t.Fatalf("expected %q, got %q", "odd", got)
+		}
+		// groups start with their type
+		p := list.Payload()
+		p.Seek(4, io.SeekStart)
+		if got, _ := io.ReadAll(p); !bytes.Equal(got, src[38:50]) {
+			t.Fatalf("unexpected list payload %q", got)
+		}
+	})
+
+	t.Run("caches small payloads", func(t *testing.T) {
+		ra := &countingReaderAt{r: bytes.NewReader(src)}
+		tree, err := ParseTree(ra, TreeOptions{CacheSize: 6})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fmtNode, data := tree.Root.Children[0], tree.Root.Children[2]
+		reads := ra.reads
+		for range 3 {
+			if got, _ := io.ReadAll(fmtNode.Payload()); string(got) != "format" {
+				t.Fatalf("unexpected payload %q", got)
+			}
+		}
+		if ra.reads != reads+1 {
+			t.Fatalf("expected a single read, got %d", ra.reads-reads)
+		}
+		reads = ra.reads
+		for range 2 {
+			if got, _ := io.ReadAll(data.Payload()); string(got) != "samples" {
+				t.Fatalf("unexpected payload %q", got)
+			}
+		}
+		if ra.reads == reads+1 {
+			t.Fatal("expected the larger payload to be read each time")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package container
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrTxDone is returned when a transaction is used after Commit or Rollback.
+var ErrTxDone = errors.New("container: transaction already committed or rolled back")
+
+// Tx collects edits to a container file and applies them in a single pass on
+// Commit. The output goes to a temporary file in the same directory that is
+// synced and renamed over the original, so a crash or error never leaves a
+// half-written file behind.
+//
+//	tx, err := container.Begin("take.wav")
+//	if err != nil {
+//		return err
+//	}
+//	defer tx.Rollback()
+//	tx.Remove(container.MatchID("JUNK"))
+//	tx.Insert("bext", bext, container.Before("fmt "))
+//	return tx.Commit()
+type Tx struct {
+	path  string
+	src   *os.File
+	tmp   *os.File
+	edits edits
+	done  bool
+}
+
+// Begin starts a transaction on the container stored at path. Replace and
+// the Before and After positions find NUL padded chunk IDs too, unless
+// StrictIDs is given.
+func Begin(path string, opts ...LookupOption) (*Tx, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ReadHeader(src); err != nil {
+		src.Close()
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+	return &Tx{path: path, src: src, tmp: tmp, edits: edits{strict: lookup(opts).strict}}, nil
+}
+
+// ReadAt reads from the original file, so edits can be based on its
+// content, e.g. through Index.
+func (tx *Tx) ReadAt(p []byte, off int64) (int, error) {
+	if tx.done {
+		return 0, ErrTxDone
+	}
+	return tx.src.ReadAt(p, off)
+}
+
+// Insert queues a new chunk with the payload read from r at pos.
+func (tx *Tx) Insert(id string, r io.Reader, pos Position) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if err := chunk.ValidateFourCC(id); err != nil {
+		return err
+	}
+	tx.edits.insert(id, r, pos)
+	return nil
+}
+
+// Remove queues the removal of every chunk selected by match.
+func (tx *Tx) Remove(match Match) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.edits.removes = append(tx.edits.removes, match)
+	return nil
+}
+
+// Replace queues replacing the payload of the first chunk with the given ID
+// by the content read from r.
+func (tx *Tx) Replace(id string, r io.Reader) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.edits.replaceWith(id, r)
+	return nil
+}
+
+// Commit applies the queued edits and atomically replaces the original file.
+// The transaction is rolled back if any step fails.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if _, err := tx.src.Seek(0, io.SeekStart); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.edits.apply(tx.tmp, tx.src); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if info, err := tx.src.Stat(); err == nil {
+		tx.tmp.Chmod(info.Mode().Perm())
+	}
+	if err := tx.tmp.Sync(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.tmp.Close(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.src.Close()
+	if err := os.Rename(tx.tmp.Name(), tx.path); err != nil {
+		os.Remove(tx.tmp.Name())
+		tx.done = true
+		return err
+	}
+	tx.done = true
+	return nil
+}
+
+// Rollback discards the transaction and its temporary file. It is safe to
+// call after Commit, which makes it convenient to defer.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+	tx.src.Close()
+	tx.tmp.Close()
+	return os.Remove(tx.tmp.Name())
+}
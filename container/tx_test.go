@@ -0,0 +1,116 @@
+package container
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTx(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "take.wav")
+		src := buildWAV(t, "fmt ", "format", "JUNK", "xx", "iXML", "<old/>", "data", "samples")
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("commit applies all edits in one pass", func(t *testing.T) {
+		path := setup(t)
+		tx, err := Begin(path)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		defer tx.Rollback()
+		tx.Remove(MatchID("JUNK"))
+		tx.Replace("iXML", strings.NewReader("<new/>"))
+		tx.Insert("bext", strings.NewReader("meta"), Before("fmt "))
+		tx.Insert("cue ", strings.NewReader("cues"), AtEnd)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		got, _ := os.ReadFile(path)
+		want := buildWAV(t, "bext", "meta", "fmt ", "format", "iXML", "<new/>", "data", "samples", "cue ", "cues")
+		if !bytes.Equal(got, want) {
+			t.Fatalf("unexpected file\n got % x\nwant % x", got, want)
+		}
+		if err := tx.Commit(); err != ErrTxDone {
+			t.Fatalf("expected ErrTxDone, got %v", err)
+		}
+	})
+
+	t.Run("rollback leaves the original", func(t *testing.T) {
+		path := setup(t)
+		orig, _ := os.ReadFile(path)
+		tx, err := Begin(path)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		tx.Remove(MatchID("data"))
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback: %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if !bytes.Equal(got, orig) {
+			t.Fatal("expected file to be unchanged")
+		}
+		entries, _ := os.ReadDir(filepath.Dir(path))
+		if len(entries) != 1 {
+			t.Fatalf("expected temporary file to be removed, got %d entries", len(entries))
+		}
+		if err := tx.Remove(MatchID("data")); err != ErrTxDone {
+			t.Fatalf("expected ErrTxDone, got %v", err)
+		}
+	})
+
+	t.Run("failed commit rolls back", func(t *testing.T) {
+		path := setup(t)
+		orig, _ := os.ReadFile(path)
+		tx, err := Begin(path)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		tx.Replace("bext", strings.NewReader("missing"))
+		if err := tx.Commit(); err != ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+		got, _ := os.ReadFile(path)
+		if !bytes.Equal(got, orig) {
+			t.Fatal("expected file to be unchanged")
+		}
+		entries, _ := os.ReadDir(filepath.Dir(path))
+		if len(entries) != 1 {
+			t.Fatalf("expected temporary file to be removed, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("reads the original", func(t *testing.T) {
+		path := setup(t)
+		tx, err := Begin(path)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		defer tx.Rollback()
+		_, entries, err := Index(tx)
+		if err != nil || len(entries) != 4 || entries[2].ID.String() != "iXML" {
+			t.Fatalf("unexpected index %v, %v", entries, err)
+		}
+		tx.Rollback()
+		if _, err := tx.ReadAt(make([]byte, 4), 0); err != ErrTxDone {
+			t.Fatalf("expected ErrTxDone, got %v", err)
+		}
+	})
+
+	t.Run("rejects non-containers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notes.txt")
+		os.WriteFile(path, []byte("just some text"), 0o644)
+		if _, err := Begin(path); err != ErrUnknownFormat {
+			t.Fatalf("expected ErrUnknownFormat, got %v", err)
+		}
+	})
+}
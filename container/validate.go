@@ -0,0 +1,231 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Check is the kind of problem reported in a Finding.
+type Check int
+
+// Checks run by Validate.
+const (
+	// CheckSize flags chunks running past their group or the end of the
+	// file, and containers whose size does not match the file.
+	CheckSize Check = iota
+	// CheckID flags chunk IDs that are not four printable characters, and
+	// unknown IDs resembling a known one (see chunk.Resembles), such as
+	// "Data" written for "data".
+	CheckID
+	// CheckPad flags pad bytes that are missing or not 0x00.
+	CheckPad
+	// CheckRequired flags required chunks missing from the outermost group.
+	CheckRequired
+	// CheckOrder flags chunks of the outermost group breaking an ordering
+	// rule of the format (see chunk.CheckOrder), such as "data" ahead of
+	// "fmt ".
+	CheckOrder
+	// CheckStructure flags headers the container cannot be parsed beyond,
+	// such as groups nested deeper than MaxDepth; the chunks read up to
+	// there are checked all the same.
+	CheckStructure
+	// CheckCRC flags chunks whose stored checksum does not match their
+	// content. RIFF and IFF carry none; png.Validate reports it for PNG.
+	CheckCRC
+)
+
+var checkNames = [...]string{"size", "id", "pad", "required", "order", "structure", "crc"}
+
+func (c Check) String() string {
+	if c >= 0 && int(c) < len(checkNames) {
+		return checkNames[c]
+	}
+	return "Check(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, so findings encode their
+// check by name.
+func (c Check) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Severity tells how serious a Finding is.
+type Severity int
+
+const (
+	// Error marks findings that break the structure or a requirement of
+	// the format, which readers may fail on.
+	Error Severity = iota
+	// Warning marks oddities most readers cope with, such as a non-zero
+	// pad byte or chunks out of the usual order.
+	Warning
+)
+
+var severityNames = [...]string{"error", "warning"}
+
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return "Severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, so findings encode their
+// severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Finding is a problem found by Validate.
+type Finding struct {
+	Check    Check    `json:"check"`
+	Severity Severity `json:"severity"`
+	// Offset is the offset of the chunk header and ID its ID; missing
+	// required chunks have Offset 0 and the ID that is missing.
+	Offset int64        `json:"offset"`
+	ID     chunk.FourCC `json:"id"`
+	// Path locates the chunk as in Diff, e.g. "LIST:INFO/INAM"; it is
+	// empty for the container itself.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s at %d: %s", f.ID, f.Offset, f.Message)
+}
+
+// ValidateOptions configures Validate.
+type ValidateOptions struct {
+	// Required lists chunk IDs the outermost group must hold, such as
+	// "fmt " and "data" for WAVE files.
+	Required []string
+	// Format is the name of the format, e.g. "wav", whose known chunks
+	// unknown IDs are compared with and whose ordering rules apply; empty
+	// means all formats in chunk.Catalog and chunk.OrderRules.
+	Format string
+}
+
+// Validate checks the structure of the container stored in the size bytes
+// of ra, without modifying it: chunk sizes against their group and the
+// file, chunk IDs, pad bytes, the order of the chunks and those listed in
+// opts.Required. It does not stop at the first problem: every one is
+// reported as a Finding, in file order followed by misplaced and missing
+// chunks, so a report shows all that is wrong with a file at once. The
+// error is only set if the container cannot be read at all. Repair fixes
+// most size and pad findings.
+func Validate(ra io.ReaderAt, size int64, opts ValidateOptions) ([]Finding, error) {
+	t, err := ParseTree(io.NewSectionReader(ra, 0, size), TreeOptions{})
+	if t == nil {
+		return nil, err
+	}
+	v := validator{ra: ra, size: size, format: opts.Format}
+	root := t.Root
+	switch end := 8 + root.Size; {
+	case end > size:
+		v.add(CheckSize, root, "", fmt.Sprintf("size %d runs past the end of the file at %d", root.Size, size))
+	case end+end%2 < size:
+		v.warn(CheckSize, root, "", fmt.Sprintf("%d bytes after the container", size-end-end%2))
+	}
+	// a truncated container is reported by its sizes
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		v.findings = append(v.findings, Finding{Check: CheckStructure, ID: root.ID, Message: "parsing stopped: " + err.Error()})
+	}
+	if err := v.group(root, "", min(8+root.Size, size)); err != nil {
+		return v.findings, err
+	}
+	ids := make([]chunk.FourCC, len(root.Children))
+	for i, n := range root.Children {
+		ids[i] = n.ID
+	}
+	names := childNames(root)
+	for _, o := range chunk.CheckOrder(opts.Format, ids) {
+		v.warn(CheckOrder, root.Children[o.Index], names[o.Index], o.Message)
+	}
+	for _, id := range opts.Required {
+		k := fourCC(id)
+		found := false
+		for _, n := range root.Children {
+			found = found || n.ID == k
+		}
+		if !found {
+			v.findings = append(v.findings, Finding{Check: CheckRequired, ID: k, Path: string(k[:]), Message: "required chunk missing"})
+		}
+	}
+	return v.findings, nil
+}
+
+type validator struct {
+	ra       io.ReaderAt
+	size     int64
+	format   string
+	findings []Finding
+}
+
+// add reports an error about n, found at path.
+func (v *validator) add(c Check, n *Node, path, msg string) {
+	v.findings = append(v.findings, Finding{Check: c, Offset: n.Offset, ID: n.ID, Path: path, Message: msg})
+}
+
+// warn reports a warning about n, found at path.
+func (v *validator) warn(c Check, n *Node, path, msg string) {
+	v.findings = append(v.findings, Finding{Check: c, Severity: Warning, Offset: n.Offset, ID: n.ID, Path: path, Message: msg})
+}
+
+// group checks the children of g, found at prefix, whose payload ends at
+// end or the end of the file, whichever comes first.
+func (v *validator) group(g *Node, prefix string, end int64) error {
+	for i, name := range childNames(g) {
+		n, path := g.Children[i], prefix+name
+		if !n.ID.Valid() {
+			v.add(CheckID, n, path, "ID is not printable")
+		} else if c, ok := chunk.Resembles(v.format, n.ID); ok {
+			v.warn(CheckID, n, path, fmt.Sprintf("unknown ID resembling %q (%s)", c.ID.String(), c.Name))
+		}
+		chunkEnd := n.Offset + 8 + n.Size
+		switch {
+		case chunkEnd > end && end == v.size:
+			v.add(CheckSize, n, path, fmt.Sprintf("size %d runs past the end of the file at %d", n.Size, end))
+		case chunkEnd > end:
+			v.add(CheckSize, n, path, fmt.Sprintf("size %d runs past the end of the %s at %d", n.Size, g.ID, end))
+		}
+		if n.IsGroup() && n.Size >= 4 {
+			if err := v.group(n, path+"/", min(chunkEnd, end)); err != nil {
+				return err
+			}
+		}
+		if n.Size%2 == 1 && chunkEnd <= end {
+			if err := v.pad(n, path, chunkEnd, end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pad checks the pad byte at off behind n, found at path, in a group
+// ending at end.
+func (v *validator) pad(n *Node, path string, off, end int64) error {
+	if off == end {
+		v.add(CheckPad, n, path, "missing pad byte")
+		return nil
+	}
+	var b [5]byte
+	k, err := v.ra.ReadAt(b[:], off)
+	if k == 0 {
+		return err
+	}
+	if b[0] == 0 {
+		return nil
+	}
+	// a chunk ID right after the payload means the writer left out the pad
+	if id := (chunk.FourCC)(b[:4]); k == len(b) && id.Valid() && !(chunk.FourCC)(b[1:]).Valid() {
+		v.add(CheckPad, n, path, "missing pad byte")
+	} else {
+		v.warn(CheckPad, n, path, fmt.Sprintf("pad byte is 0x%02x", b[0]))
+	}
+	return nil
+}
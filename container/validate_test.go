@@ -0,0 +1,166 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestValidate(t *testing.T) {
+	validate := func(t *testing.T, b []byte, required ...string) []string {
+		t.Helper()
+		findings, err := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{Required: required})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.Check.String()+": "+f.String())
+		}
+		return got
+	}
+	expect := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+	}
+
+	t.Run("well-formed", func(t *testing.T) {
+		expect(t, validate(t, buildNested(t), "fmt ", "data"))
+	})
+
+	t.Run("pad bytes", func(t *testing.T) {
+		b := buildNested(t)
+		b[49] = 'x' // after INAM "odd"
+		b = append(b, 0)
+		expect(t, validate(t, b),
+			"size: RIFF at 0: 1 bytes after the container",
+			"pad: INAM at 38: pad byte is 0x78")
+	})
+
+	t.Run("missing pad at the end", func(t *testing.T) {
+		b := buildNested(t)[:65]
+		b[4] = 57
+		expect(t, validate(t, b), "pad: data at 50: missing pad byte")
+	})
+
+	t.Run("missing pad between chunks", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.WriteString("RIFF\x15\x00\x00\x00WAVE")
+		buf.WriteString("odd \x01\x00\x00\x00x")
+		buf.WriteString("next\x00\x00\x00\x00")
+		expect(t, validate(t, buf.Bytes()), "pad: odd  at 12: missing pad byte")
+	})
+
+	t.Run("sizes", func(t *testing.T) {
+		b := buildNested(t)
+		b[30] = 40 // LIST size 16 -> 40
+		b = b[:60]
+		expect(t, validate(t, b),
+			"size: RIFF at 0: size 58 runs past the end of the file at 60",
+			"size: LIST at 26: size 40 runs past the end of the file at 60",
+			"size: data at 50: size 7 runs past the end of the file at 60")
+	})
+
+	t.Run("IDs", func(t *testing.T) {
+		b := buildNested(t)
+		copy(b[12:], "fm\x00 ")
+		expect(t, validate(t, b), "id: fm\x00  at 12: ID is not printable")
+	})
+
+	t.Run("IDs resembling known ones", func(t *testing.T) {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC{'d', 'a', 't', 'a'}, Format: "validate-test", Name: "samples"})
+		b := buildNested(t)
+		copy(b[50:], "DATA")
+		findings, err := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{Format: "validate-test"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(findings) != 1 || findings[0].String() != `DATA at 50: unknown ID resembling "data" (samples)` {
+			t.Fatalf("unexpected findings %q", findings)
+		}
+		if findings, _ := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{Format: "other"}); len(findings) != 0 {
+			t.Fatalf("expected no findings for another format, got %q", findings)
+		}
+	})
+
+	t.Run("chunk order", func(t *testing.T) {
+		chunk.RegisterOrder(chunk.OrderRule{Format: "order-test", ID: chunk.FourCC{'f', 'r', 's', 't'}, Before: chunk.FourCC{'s', 'c', 'n', 'd'}})
+		chunk.RegisterOrder(chunk.OrderRule{Format: "order-test", ID: chunk.FourCC{'h', 'e', 'a', 'd'}, First: true})
+		b := buildWAV(t, "scnd", "x", "head", "y", "frst", "z")
+		findings, err := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{Format: "order-test"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.Check.String()+": "+f.String())
+		}
+		expect(t, got,
+			"order: head at 22: must be the first chunk",
+			`order: frst at 32: must come before "scnd"`)
+	})
+
+	t.Run("required chunks", func(t *testing.T) {
+		expect(t, validate(t, buildNested(t), "fmt ", "bext"),
+			"required: bext at 0: required chunk missing")
+	})
+
+	t.Run("collects everything with severities and paths", func(t *testing.T) {
+		b := buildNested(t)
+		b[49] = 'x'             // pad after INAM
+		copy(b[50:], "da\x00a") // unprintable data ID
+		b = append(b, 0, 0)
+		findings, err := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{Required: []string{"fmt ", "bext"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"warning size  RIFF at 0: 2 bytes after the container",
+			"warning pad LIST:INFO/INAM INAM at 38: pad byte is 0x78",
+			"error id da\x00a da\x00a at 50: ID is not printable",
+			"error required bext bext at 0: required chunk missing",
+		}
+		if len(findings) != len(want) {
+			t.Fatalf("expected %q, got %q", want, findings)
+		}
+		for i, f := range findings {
+			if got := f.Severity.String() + " " + f.Check.String() + " " + f.Path + " " + f.String(); got != want[i] {
+				t.Fatalf("expected %q, got %q", want[i], got)
+			}
+		}
+	})
+
+	t.Run("goes on where parsing stopped", func(t *testing.T) {
+		inner := []byte("odd \x01\x00\x00\x00x\xff")
+		for range MaxDepth {
+			inner = append(binary.LittleEndian.AppendUint32([]byte("LIST"), uint32(4+len(inner))), append([]byte("INFO"), inner...)...)
+		}
+		b := append(binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(14+len(inner))), "WAVEodd \x01\x00\x00\x00x\xff"...)
+		b = append(b, inner...)
+		findings, err := Validate(bytes.NewReader(b), int64(len(b)), ValidateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(findings) != 2 || findings[0].Check != CheckStructure || findings[0].Message != "parsing stopped: "+ErrTooDeep.Error() ||
+			findings[1].Check != CheckPad || findings[1].Path != "odd " {
+			t.Fatalf("unexpected findings %q", findings)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := json.Marshal(Finding{Check: CheckPad, Offset: 38, ID: [4]byte{'I', 'N', 'A', 'M'}, Path: "LIST:INFO/INAM", Message: "missing pad byte"})
+		if err != nil || string(b) != `{"check":"pad","severity":"error","offset":38,"id":"INAM","path":"LIST:INFO/INAM","message":"missing pad byte"}` {
+			t.Fatalf("unexpected JSON %s (%v)", b, err)
+		}
+	})
+}
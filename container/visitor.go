@@ -0,0 +1,61 @@
+package container
+
+import (
+	"errors"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrSkip is returned by Visitor.Enter to skip the children of a group, or
+// the payload of a data chunk. Leave is still called for the node.
+var ErrSkip = errors.New("container: skip node")
+
+// Visitor is called for the nodes of a tree by Tree.Accept, so tools making
+// several passes over a tree, such as statistics, exporters or validators,
+// do not have to implement the traversal each time. Embed NopVisitor to
+// implement only some of the methods.
+type Visitor interface {
+	// Enter is called for every node before its children or payload.
+	Enter(n *Node) error
+	// VisitPayload is called between Enter and Leave of every data chunk
+	// with a reader over its payload, which is only read from the source
+	// of the tree as far as the visitor reads it.
+	VisitPayload(r *chunk.Reader) error
+	// Leave is called for every node after its children or payload.
+	Leave(n *Node) error
+}
+
+// NopVisitor is a Visitor doing nothing.
+type NopVisitor struct{}
+
+func (NopVisitor) Enter(*Node) error                { return nil }
+func (NopVisitor) VisitPayload(*chunk.Reader) error { return nil }
+func (NopVisitor) Leave(*Node) error                { return nil }
+
+// Accept walks the tree depth first in file order, starting at the root,
+// see Node.Accept.
+func (t *Tree) Accept(v Visitor) error {
+	return t.Root.Accept(v)
+}
+
+// Accept calls v for n and the nodes below it: Enter, then the children of
+// a group or VisitPayload for a data chunk, then Leave. An error returned by
+// v, other than ErrSkip from Enter, stops the walk and is returned.
+func (n *Node) Accept(v Visitor) error {
+	switch err := v.Enter(n); {
+	case err == ErrSkip:
+		return v.Leave(n)
+	case err != nil:
+		return err
+	}
+	if n.IsGroup() {
+		for _, c := range n.Children {
+			if err := c.Accept(v); err != nil {
+				return err
+			}
+		}
+	} else if err := v.VisitPayload(n.Chunk()); err != nil {
+		return err
+	}
+	return v.Leave(n)
+}
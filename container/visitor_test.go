@@ -0,0 +1,100 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// recorder is a Visitor logging every call.
+type recorder struct {
+	log  []string
+	skip string
+	fail string
+}
+
+func (r *recorder) Enter(n *Node) error {
+	r.log = append(r.log, "enter "+n.ID.String())
+	switch n.ID.String() {
+	case r.skip:
+		return ErrSkip
+	case r.fail:
+		return errors.New("fail")
+	}
+	return nil
+}
+
+func (r *recorder) VisitPayload(ch *chunk.Reader) error {
+	p, err := io.ReadAll(ch)
+	r.log = append(r.log, "payload "+string(p))
+	return err
+}
+
+func (r *recorder) Leave(n *Node) error {
+	r.log = append(r.log, "leave "+n.ID.String())
+	return nil
+}
+
+func TestAccept(t *testing.T) {
+	tree, err := ParseTree(bytes.NewReader(buildNested(t)), TreeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("walks depth first", func(t *testing.T) {
+		var r recorder
+		if err := tree.Accept(&r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "enter RIFF,enter fmt ,payload format,leave fmt ,enter LIST,enter INAM,payload odd,leave INAM,leave LIST," +
+			"enter data,payload samples,leave data,leave RIFF"
+		if got := strings.Join(r.log, ","); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("skips nodes", func(t *testing.T) {
+		r := recorder{skip: "LIST"}
+		if err := tree.Accept(&r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Join(r.log, ","); strings.Contains(got, "INAM") || !strings.Contains(got, "enter LIST,leave LIST,enter data") {
+			t.Fatalf("expected LIST to be skipped, got %s", got)
+		}
+	})
+
+	t.Run("stops at errors", func(t *testing.T) {
+		r := recorder{fail: "INAM"}
+		if err := tree.Accept(&r); err == nil || err.Error() != "fail" {
+			t.Fatalf("expected the visitor's error, got %v", err)
+		}
+		if last := r.log[len(r.log)-1]; last != "enter INAM" {
+			t.Fatalf("expected the walk to stop at INAM, got %q", r.log)
+		}
+	})
+
+	t.Run("visits subtrees with embedded NopVisitor", func(t *testing.T) {
+		v := &sizeVisitor{}
+		if err := tree.Root.Children[1].Accept(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(v.sizes) != 1 || v.sizes[0] != 3 {
+			t.Fatalf("expected the INAM size, got %v", v.sizes)
+		}
+	})
+}
+
+// sizeVisitor collects payload sizes, leaving the rest to NopVisitor.
+type sizeVisitor struct {
+	NopVisitor
+	sizes []int
+}
+
+func (v *sizeVisitor) VisitPayload(ch *chunk.Reader) error {
+	v.sizes = append(v.sizes, ch.Size)
+	return nil
+}
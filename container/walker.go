@@ -0,0 +1,193 @@
+package container
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"runtime/pprof"
+
+	"github.com/CWBudde/chunk"
+)
+
+// profileDo runs handlers under profiler labels; tests swap it to observe
+// the labels.
+var profileDo = pprof.Do
+
+// Handler processes a chunk found by a Walker. It may read as much of the
+// payload as it needs; the rest is skipped.
+type Handler func(ch *chunk.Reader) error
+
+// Walker dispatches the chunks of a container to handlers registered by ID.
+// Groups such as LIST without a handler of their own are descended into, so
+// a handler for INAM sees the INAM chunk inside LIST/INFO. The zero value is
+// ready to use.
+type Walker struct {
+	// Default, if set, handles the data chunks without a registered handler.
+	Default Handler
+	// Context is handed to the scanner, see chunk.Scanner, and to pprof.Do.
+	Context context.Context
+	// ProfileLabels runs every handler under pprof.Do with the label "chunk"
+	// set to the chunk ID, so CPU profiles of ingestion jobs attribute the
+	// time spent to the chunk decoders.
+	ProfileLabels bool
+	// Stats, if set, records the wall time of every handler call along with
+	// the payload size, per chunk ID.
+	Stats *Stats
+	// StrictIDs dispatches by the exact chunk ID. By default a chunk whose
+	// ID is padded with NULs, such as "fmt\x00", falls back to the handler
+	// for the space-padded ID, see chunk.FourCC.Normalize.
+	StrictIDs bool
+	// IgnoreCase falls back to a handler whose ID differs from the chunk
+	// ID only in the case of ASCII letters, for files of encoders that
+	// write "Data" for "data". Exact matches still win, and NUL padding is
+	// ignored even with StrictIDs.
+	IgnoreCase bool
+	// OnCaseMismatch, if set, is called for every chunk dispatched by
+	// IgnoreCase, with the chunk ID and the ID of the handler. Such chunks
+	// are reported to Warn as well, as chunk.WarnCase.
+	OnCaseMismatch CaseMismatch
+	// Warn, if set, is told about the oddities the scanners tolerate (see
+	// Scanner.Warn), with absolute offsets, about chunks dispatched by
+	// IgnoreCase and about data chunks without a handler of their own
+	// that are missing from chunk.Catalog. As the
+	// catalog is filled by the format packages, import the packages of the
+	// formats walked.
+	Warn func(chunk.Warning)
+	// Filter, if set, selects the chunks dispatched to handlers; the others
+	// are skipped. Groups without a handler are still descended into, so
+	// the filter sees their chunks.
+	Filter Filter
+
+	handlers map[[4]byte]Handler
+	ids      []chunk.FourCC // registration order, for IgnoreCase
+}
+
+// Handle registers h for the chunks with the given ID, replacing any handler
+// registered before. IDs shorter than four characters are padded with
+// spaces.
+func (w *Walker) Handle(id string, h Handler) {
+	if w.handlers == nil {
+		w.handlers = make(map[[4]byte]Handler)
+	}
+	k := fourCC(id)
+	if _, ok := w.handlers[k]; !ok {
+		w.ids = append(w.ids, k)
+	}
+	w.handlers[k] = h
+}
+
+// handler returns the handler for the chunk ID, found at the absolute
+// offset off, or nil.
+func (w *Walker) handler(id chunk.FourCC, off int64) Handler {
+	if h, ok := w.handlers[id]; ok {
+		return h
+	}
+	if !w.StrictIDs {
+		if h, ok := w.handlers[id.Normalize()]; ok {
+			return h
+		}
+	}
+	if !w.IgnoreCase {
+		return nil
+	}
+	for _, k := range w.ids {
+		if k.EqualFold(id) {
+			if w.OnCaseMismatch != nil {
+				w.OnCaseMismatch(id, k)
+			}
+			if w.Warn != nil {
+				w.Warn(chunk.Warning{Kind: chunk.WarnCase, Offset: off, ID: id, Message: fmt.Sprintf("handled as %q ignoring case", k.String())})
+			}
+			return w.handlers[k]
+		}
+	}
+	return nil
+}
+
+// chunkIter is implemented by chunk.Scanner and Scanner.
+type chunkIter interface {
+	Next() bool
+	Chunk() *chunk.Reader
+	Offset() int64
+	Err() error
+}
+
+// Walk reads the container from r and calls the handlers for its chunks in
+// file order. It stops at the first error of a handler and returns it.
+func (w *Walker) Walk(r io.Reader) error {
+	s, err := NewScanner(r)
+	if err != nil {
+		return err
+	}
+	s.Context = w.Context
+	s.Warn = w.Warn
+	return w.walk(s, s.Header.Format.ByteOrder(), 1, 0)
+}
+
+// walk dispatches the chunks of s, nested depth levels deep as in ParseTree.
+// base is added to the offsets of s to make them absolute.
+func (w *Walker) walk(s chunkIter, order binary.ByteOrder, depth int, base int64) error {
+	if depth > MaxDepth {
+		return ErrTooDeep
+	}
+	for s.Next() {
+		ch := s.Chunk()
+		h := w.handler(ch.ID, base+s.Offset())
+		if h == nil && IsGroup(ch.ID) && ch.Size >= 4 {
+			if _, err := ch.ReadFourCC(); err != nil {
+				return err
+			}
+			inner := chunk.NewScanner(ch, order)
+			inner.Context = w.Context
+			// inner offsets count from the group payload after its type
+			start := base + s.Offset() + 12
+			if w.Warn != nil {
+				inner.Warn = func(x chunk.Warning) {
+					x.Offset += start
+					w.Warn(x)
+				}
+			}
+			if err := w.walk(inner, order, depth+1, start); err != nil {
+				return err
+			}
+			continue
+		}
+		if w.Filter != nil && !w.Filter(ch, depth) {
+			continue
+		}
+		if h == nil {
+			if _, ok := chunk.LookupChunk("", ch.ID); !ok && w.Warn != nil {
+				w.Warn(chunk.Warning{Kind: chunk.WarnUnknown, Offset: base + s.Offset(), ID: ch.ID, Message: "unknown chunk"})
+			}
+			h = w.Default
+		}
+		if h == nil {
+			continue
+		}
+		if err := w.call(h, ch); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// call runs h on ch, under profiler labels and timed if requested.
+func (w *Walker) call(h Handler, ch *chunk.Reader) error {
+	if w.Stats != nil {
+		start := timeNow()
+		defer func() { w.Stats.Record(ch.ID, int64(ch.Size), timeNow().Sub(start)) }()
+	}
+	if !w.ProfileLabels {
+		return h(ch)
+	}
+	ctx := w.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var err error
+	profileDo(ctx, pprof.Labels("chunk", string(ch.ID[:])), func(context.Context) {
+		err = h(ch)
+	})
+	return err
+}
@@ -0,0 +1,172 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestWalker(t *testing.T) {
+	src := buildNested(t)
+
+	t.Run("dispatches by ID", func(t *testing.T) {
+		var got []string
+		var w Walker
+		w.Handle("INAM", func(ch *chunk.Reader) error {
+			b, err := io.ReadAll(ch)
+			got = append(got, "INAM="+string(b))
+			return err
+		})
+		w.Handle("data", func(ch *chunk.Reader) error {
+			got = append(got, "data")
+			return nil
+		})
+		w.Default = func(ch *chunk.Reader) error {
+			got = append(got, "default "+string(ch.ID[:]))
+			return nil
+		}
+		if err := w.Walk(bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"default fmt ", "INAM=odd", "data"}
+		if len(got) != len(want) {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		}
+	})
+
+	t.Run("hands groups with a handler over whole", func(t *testing.T) {
+		var w Walker
+		var calls int
+		w.Handle("LIST", func(ch *chunk.Reader) error {
+			calls++
+			typ, _ := ch.ReadFourCC()
+			if typ.String() != "INFO" {
+				t.Fatalf("unexpected list type %q", typ)
+			}
+			return nil
+		})
+		w.Handle("INAM", func(*chunk.Reader) error {
+			t.Fatal("INAM handled inside a handled LIST")
+			return nil
+		})
+		if err := w.Walk(bytes.NewReader(src)); err != nil || calls != 1 {
+			t.Fatalf("expected one LIST, got %d (%v)", calls, err)
+		}
+	})
+
+	t.Run("falls back to space-padded IDs", func(t *testing.T) {
+		src := buildWAV(t, "fmt_", "format", "data", "samples")
+		src[15] = 0 // fmt\x00, which Writer refuses to write
+		for _, strict := range []bool{false, true} {
+			calls := 0
+			w := Walker{StrictIDs: strict}
+			w.Handle("fmt", func(*chunk.Reader) error {
+				calls++
+				return nil
+			})
+			if err := w.Walk(bytes.NewReader(src)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := map[bool]int{false: 1, true: 0}[strict]; calls != want {
+				t.Fatalf("StrictIDs=%v: expected %d calls, got %d", strict, want, calls)
+			}
+		}
+	})
+
+	t.Run("ignores case if asked to", func(t *testing.T) {
+		src := buildWAV(t, "fmt ", "format", "Data", "samples", "data", "more")
+		var got, warned []string
+		var ws chunk.Warnings
+		w := Walker{IgnoreCase: true, Warn: ws.Add}
+		w.OnCaseMismatch = func(id, want chunk.FourCC) {
+			warned = append(warned, id.String()+"->"+want.String())
+		}
+		w.Handle("data", func(ch *chunk.Reader) error {
+			got = append(got, string(ch.ID[:]))
+			return nil
+		})
+		if err := w.Walk(bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[0] != "Data" || got[1] != "data" {
+			t.Fatalf("unexpected chunks %q", got)
+		}
+		if len(warned) != 1 || warned[0] != "Data->data" {
+			t.Fatalf("unexpected warnings %q", warned)
+		}
+		// fmt is reported as unknown, the wav catalog not being imported
+		if n := len(ws); n != 2 || ws[1].Kind != chunk.WarnCase || ws[1].Offset != 26 || ws[1].ID.String() != "Data" {
+			t.Fatalf("unexpected warnings %+v", ws)
+		}
+	})
+
+	t.Run("reports warnings", func(t *testing.T) {
+		b := bytes.Clone(src)
+		b[49] = 0xff                                         // pad after INAM
+		binary.LittleEndian.PutUint32(b[4:], uint32(len(b))) // 8 bytes of slack
+		var ws chunk.Warnings
+		w := Walker{Warn: ws.Add}
+		for _, id := range []string{"fmt ", "data"} {
+			w.Handle(id, func(*chunk.Reader) error { return nil })
+		}
+		if err := w.Walk(bytes.NewReader(b)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"unknown: INAM at 38: unknown chunk",
+			"pad: INAM at 49: pad byte is 0xff",
+			"size: RIFF at 66: container size runs 8 bytes past the last chunk",
+		}
+		if len(ws) != len(want) {
+			t.Fatalf("expected %q, got %q", want, ws)
+		}
+		for i, x := range ws {
+			if got := x.Kind.String() + ": " + x.String(); got != want[i] {
+				t.Fatalf("expected %q, got %q", want[i], got)
+			}
+		}
+	})
+
+	t.Run("stops at handler errors", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var w Walker
+		w.Handle("fmt ", func(*chunk.Reader) error { return errStop })
+		w.Handle("data", func(*chunk.Reader) error {
+			t.Fatal("walk went on after an error")
+			return nil
+		})
+		if err := w.Walk(bytes.NewReader(src)); err != errStop {
+			t.Fatalf("expected errStop, got %v", err)
+		}
+	})
+
+	t.Run("labels handlers for profiles", func(t *testing.T) {
+		defer func(f func(context.Context, pprof.LabelSet, func(context.Context))) { profileDo = f }(profileDo)
+		var labels []string
+		profileDo = func(ctx context.Context, l pprof.LabelSet, f func(context.Context)) {
+			pprof.Do(ctx, l, func(ctx context.Context) {
+				v, _ := pprof.Label(ctx, "chunk")
+				labels = append(labels, v)
+				f(ctx)
+			})
+		}
+		w := Walker{ProfileLabels: true, Default: func(*chunk.Reader) error { return nil }}
+		if err := w.Walk(bytes.NewReader(src)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(labels) != 3 || labels[0] != "fmt " || labels[1] != "INAM" || labels[2] != "data" {
+			t.Fatalf("unexpected labels %q", labels)
+		}
+	})
+}
@@ -0,0 +1,467 @@
+// Package container reads, writes and rewrites chunk based container formats
+// such as RIFF (WAV), RIFX, RF64/BW64 and IFF (AIFF) on top of the chunk
+// package.
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Format selects the framing rules of a container.
+type Format int
+
+const (
+	// IFF is the big-endian EA IFF 85 framing used by AIFF (FORM, LIST, CAT).
+	IFF Format = iota
+	// RIFF is the little-endian framing used by WAV and AVI.
+	RIFF
+	// RIFX is RIFF with big-endian sizes.
+	RIFX
+	// RF64 is RIFF with a JUNK chunk reserved up front that is converted to
+	// a ds64 chunk once the form or its data chunk exceed 32-bit sizes.
+	RF64
+	// BW64 is the ITU-R BS.2088 variant of RF64 using the BW64 form ID.
+	BW64
+)
+
+// ByteOrder returns the byte order used for size fields.
+func (f Format) ByteOrder() binary.ByteOrder {
+	switch f {
+	case RIFF, RF64, BW64:
+		return binary.LittleEndian
+	default:
+		return binary.BigEndian
+	}
+}
+
+var formatNames = [...]string{IFF: "IFF", RIFF: "RIFF", RIFX: "RIFX", RF64: "RF64", BW64: "BW64"}
+
+// String returns the name of the format, e.g. "RIFF".
+func (f Format) String() string {
+	if f >= 0 && int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Format) UnmarshalText(text []byte) error {
+	for i, name := range formatNames {
+		if name == string(text) {
+			*f = Format(i)
+			return nil
+		}
+	}
+	return ErrUnknownFormat
+}
+
+// IsGroup reports whether id names a chunk whose payload is a four character
+// type followed by nested chunks.
+func IsGroup(id [4]byte) bool {
+	switch string(id[:]) {
+	case "RIFF", "RIFX", "RF64", "BW64", "FORM", "LIST", "CAT ", "PROP":
+		return true
+	}
+	return false
+}
+
+// formID returns the ID of the outermost group chunk.
+func (f Format) formID() string {
+	switch f {
+	case RIFF, RF64, BW64:
+		return "RIFF"
+	case RIFX:
+		return "RIFX"
+	default:
+		return "FORM"
+	}
+}
+
+// maxSize32 is the largest size that fits a chunk header. It is a variable so
+// tests can exercise the 64-bit code paths without writing gigabytes.
+var maxSize32 int64 = math.MaxUint32
+
+var (
+	// ErrNoOpenChunk is returned when EndChunk is called without a matching
+	// begin.
+	ErrNoOpenChunk = errors.New("container: no open chunk")
+	// ErrChunkTooLarge is returned when a chunk does not fit a 32-bit size
+	// field.
+	ErrChunkTooLarge = errors.New("container: chunk exceeds 32-bit size")
+	// ErrInvalidNesting is returned when a chunk or group is opened inside a
+	// plain data chunk.
+	ErrInvalidNesting = errors.New("container: chunks can only be nested inside groups")
+)
+
+// frame is a chunk or group that has been begun but not ended yet.
+type frame struct {
+	id    [4]byte
+	group bool
+	// start is the absolute offset of the chunk header.
+	start int64
+	// buf holds the payload when the output cannot seek.
+	buf *bytes.Buffer
+	ch  *chunk.Writer
+	// hash receives the payload when chunks are hashed.
+	hash hash.Hash
+	// enc encodes the payload written to plain when a Transform applies.
+	enc   io.WriteCloser
+	plain *chunk.Writer
+	// pad replaces the zero pad bytes if it has the right length.
+	pad []byte
+}
+
+// Writer writes a container of chunks to an underlying stream. When the
+// stream implements io.WriteSeeker the chunk sizes are patched in place once
+// a chunk is ended, otherwise every open chunk is buffered in memory until
+// it is ended.
+type Writer struct {
+	// ValidateID checks every chunk, group and form type ID before it is
+	// written. It defaults to chunk.ValidateFourCC; set it to nil to write
+	// arbitrary IDs, which are then space padded or truncated to four bytes.
+	ValidateID func(id string) error
+	// DS64Table lists the IDs of chunks besides data that may exceed 32-bit
+	// sizes in RF64 and BW64 containers, once per chunk. A ds64 table entry
+	// is reserved for each when the form is begun, so it has to be set
+	// before BeginForm; larger chunks of other IDs fail with
+	// ErrChunkTooLarge.
+	DS64Table []string
+
+	w      io.Writer
+	ws     io.WriteSeeker
+	format Format
+	spec   chunk.HeaderSpec
+	stack  []*frame
+	ds64   *ds64Info
+
+	// written counts the bytes flushed to w when buffering
+	written int64
+	align   int
+	filler  string
+	// padding suppresses alignment while a filler chunk is written
+	padding bool
+	// raw skips hashing and transforms for reserved chunks
+	raw       bool
+	newHash   func() hash.Hash
+	report    func(Digest)
+	transform *Transform
+	// open is set while a chunk copied by copyOpen waits for its pad bytes
+	open bool
+}
+
+// NewWriter returns a Writer producing the given container format on w.
+func NewWriter(w io.Writer, format Format) *Writer {
+	cw := &Writer{
+		ValidateID: chunk.ValidateFourCC,
+		w:          w,
+		format:     format,
+		spec:       chunk.RIFFHeader,
+	}
+	if format.ByteOrder() == binary.BigEndian {
+		cw.spec = chunk.IFFHeader
+	}
+	if ws, ok := w.(io.WriteSeeker); ok {
+		if _, err := ws.Seek(0, io.SeekCurrent); err == nil {
+			cw.ws = ws
+		}
+	}
+	if cw.ws == nil {
+		cw.w = &countingWriter{w: w, n: &cw.written}
+	}
+	return cw
+}
+
+// NewSpecWriter returns a Writer producing chunks with a custom header
+// layout on w, e.g. for game or embedded containers with two byte IDs or
+// sizes that include the header. IDs shorter than four bytes need a
+// ValidateID that accepts them. Chunks and groups work as usual, while
+// BeginForm and the RF64 handling assume a RIFF or IFF layout.
+func NewSpecWriter(w io.Writer, spec chunk.HeaderSpec) (*Writer, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	format := RIFF
+	if spec.Order == binary.BigEndian {
+		format = IFF
+	}
+	cw := NewWriter(w, format)
+	cw.spec = spec
+	return cw, nil
+}
+
+// Format returns the container format being written.
+func (cw *Writer) Format() Format {
+	return cw.format
+}
+
+// BeginForm opens the outermost group of the container (FORM for IFF, RIFF or
+// RIFX otherwise) with the given form type, e.g. "AIFF" or "WAVE".
+func (cw *Writer) BeginForm(formType string) error {
+	if err := cw.BeginGroup(cw.format.formID(), formType); err != nil {
+		return err
+	}
+	if cw.format == RF64 || cw.format == BW64 {
+		return cw.reserveDS64()
+	}
+	return nil
+}
+
+// BeginList opens a LIST group with the given list type, e.g. "INFO".
+func (cw *Writer) BeginList(listType string) error {
+	return cw.BeginGroup("LIST", listType)
+}
+
+// BeginCat opens an IFF CAT group. The contents type may be four spaces when
+// the concatenated forms have mixed types.
+func (cw *Writer) BeginCat(contentsType string) error {
+	return cw.BeginGroup("CAT ", contentsType)
+}
+
+// BeginGroup opens a group chunk with the given ID whose payload starts with
+// a four character type. Chunks begun afterwards are nested inside the group
+// until it is ended with EndChunk.
+func (cw *Writer) BeginGroup(id, groupType string) error {
+	if err := cw.validate(id); err != nil {
+		return err
+	}
+	if err := cw.validate(groupType); err != nil {
+		return err
+	}
+	return cw.beginGroupID(fourCC(id), fourCC(groupType))
+}
+
+// beginGroupID opens a group without validating its ID and type.
+func (cw *Writer) beginGroupID(id, groupType [4]byte) error {
+	f, err := cw.beginID(id, true)
+	if err != nil {
+		return err
+	}
+	_, err = f.ch.Write(groupType[:])
+	return err
+}
+
+// BeginChunk opens a data chunk with the given ID and returns a chunk Writer
+// for its payload. The payload must be complete when EndChunk is called.
+func (cw *Writer) BeginChunk(id string) (*chunk.Writer, error) {
+	f, err := cw.begin(id, false)
+	if err != nil {
+		return nil, err
+	}
+	if f.plain != nil {
+		return f.plain, nil
+	}
+	return f.ch, nil
+}
+
+// EndChunk closes the innermost open chunk or group, writing its size and a
+// pad byte if the payload has an odd length.
+func (cw *Writer) EndChunk() error {
+	if len(cw.stack) == 0 {
+		return ErrNoOpenChunk
+	}
+	f := cw.stack[len(cw.stack)-1]
+	if f.enc != nil {
+		if err := f.enc.Close(); err != nil {
+			return err
+		}
+	}
+	cw.stack = cw.stack[:len(cw.stack)-1]
+
+	size := int64(f.ch.Size)
+	field := size
+	if cw.ds64 != nil {
+		if err := cw.ds64.record(f, size, cw.DS64Table); err != nil {
+			return err
+		}
+	}
+	if size > cw.maxSize() {
+		if cw.ds64 == nil {
+			return ErrChunkTooLarge
+		}
+		field = math.MaxUint32
+	}
+	if cw.ds64 != nil && cw.ds64.form == f {
+		converted, err := cw.finishDS64(f, size)
+		if err != nil {
+			return err
+		}
+		if converted {
+			field = math.MaxUint32
+		}
+	}
+	var err error
+	if f.buf != nil {
+		err = cw.flushFrame(f, field)
+	} else {
+		err = cw.patchFrame(f, field)
+	}
+	if err != nil {
+		return err
+	}
+	cw.reportHash(f)
+	return nil
+}
+
+// Close ends every chunk and group that is still open.
+func (cw *Writer) Close() error {
+	for len(cw.stack) > 0 {
+		if err := cw.EndChunk(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cw *Writer) begin(id string, group bool) (*frame, error) {
+	if err := cw.validate(id); err != nil {
+		return nil, err
+	}
+	return cw.beginID(fourCC(id), group)
+}
+
+// beginID opens a chunk without validating its ID.
+func (cw *Writer) beginID(id [4]byte, group bool) (*frame, error) {
+	if n := len(cw.stack); n > 0 && !cw.stack[n-1].group {
+		return nil, ErrInvalidNesting
+	}
+	if err := cw.alignNext(); err != nil {
+		return nil, err
+	}
+	start, err := cw.offset()
+	if err != nil {
+		return nil, err
+	}
+	f := &frame{id: id, group: group, start: start}
+	if cw.ws == nil {
+		f.buf = bytes.NewBuffer(chunk.GetBuffer()[:0])
+		f.ch = &chunk.Writer{ID: f.id, W: f.buf}
+	} else {
+		if err := cw.writeHeader(cw.w, f.id, 0); err != nil {
+			return nil, err
+		}
+		f.ch = &chunk.Writer{ID: f.id, W: cw.w}
+	}
+	cw.startHash(f)
+	cw.startTransform(f)
+	cw.stack = append(cw.stack, f)
+	return f, nil
+}
+
+// sink returns where the next chunk header has to be written.
+func (cw *Writer) sink() io.Writer {
+	if n := len(cw.stack); n > 0 {
+		return cw.stack[n-1].ch
+	}
+	return cw.w
+}
+
+func (cw *Writer) flushFrame(f *frame, size int64) error {
+	dst := cw.sink()
+	if err := cw.writeHeader(dst, f.id, size); err != nil {
+		return err
+	}
+	if _, err := f.buf.WriteTo(dst); err != nil {
+		return err
+	}
+	// the buffer is drained and unreachable once the frame is popped
+	chunk.PutBuffer(f.buf.Bytes())
+	f.buf = nil
+	return writePadding(dst, cw.spec.Padding(int64(f.ch.Size)), f.pad)
+}
+
+func (cw *Writer) patchFrame(f *frame, size int64) error {
+	if err := writePadding(cw.w, cw.spec.Padding(int64(f.ch.Size)), f.pad); err != nil {
+		return err
+	}
+	end, err := cw.ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := cw.ws.Seek(f.start, io.SeekStart); err != nil {
+		return err
+	}
+	if err := cw.writeHeader(cw.w, f.id, size); err != nil {
+		return err
+	}
+	if _, err := cw.ws.Seek(end, io.SeekStart); err != nil {
+		return err
+	}
+	// the enclosing group has to account for the header, payload and pad
+	if n := len(cw.stack); n > 0 {
+		cw.stack[n-1].ch.Size += int(end - f.start)
+	}
+	return nil
+}
+
+func (cw *Writer) writeHeader(dst io.Writer, id [4]byte, size int64) error {
+	hdr := make([]byte, cw.spec.HeaderSize())
+	cw.spec.PutHeader(hdr, id, size)
+	_, err := dst.Write(hdr)
+	return err
+}
+
+// headerSize returns the size of a chunk header as an offset.
+func (cw *Writer) headerSize() int64 {
+	return int64(cw.spec.HeaderSize())
+}
+
+// maxSize returns the largest payload a size field can hold.
+func (cw *Writer) maxSize() int64 {
+	if cw.spec.SizeSize == 4 && !cw.spec.SizeIncludesHeader {
+		return maxSize32
+	}
+	return cw.spec.MaxSize()
+}
+
+// writePadding writes n pad bytes, those of pad if it holds n and zeros
+// otherwise.
+func writePadding(dst io.Writer, n int64, pad []byte) error {
+	if n == 0 {
+		return nil
+	}
+	if int64(len(pad)) != n {
+		pad = make([]byte, n)
+	}
+	_, err := dst.Write(pad)
+	return err
+}
+
+func (cw *Writer) validate(id string) error {
+	if cw.ValidateID == nil {
+		return nil
+	}
+	return cw.ValidateID(id)
+}
+
+// fourCC converts a chunk ID to its on-disk form, padding short IDs with
+// spaces.
+func fourCC(id string) [4]byte {
+	b := [4]byte{' ', ' ', ' ', ' '}
+	copy(b[:], id)
+	return b
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
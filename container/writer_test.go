@@ -0,0 +1,301 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// seekBuffer is an in-memory io.WriteSeeker used to exercise the size
+// patching code path.
+type seekBuffer struct {
+	buf []byte
+	pos int
+}
+
+func (s *seekBuffer) Write(p []byte) (int, error) {
+	if end := s.pos + len(p); end > len(s.buf) {
+		s.buf = append(s.buf, make([]byte, end-len(s.buf))...)
+	}
+	n := copy(s.buf[s.pos:], p)
+	s.pos += n
+	return n, nil
+}
+
+func (s *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(s.pos) + offset
+	case io.SeekEnd:
+		abs = int64(len(s.buf)) + offset
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	s.pos = int(abs)
+	return abs, nil
+}
+
+func (s *seekBuffer) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(s.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.buf[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (s *seekBuffer) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(s.buf) {
+		s.buf = append(s.buf, make([]byte, end-len(s.buf))...)
+	}
+	return copy(s.buf[off:], p), nil
+}
+
+type recordingPool struct {
+	gets, puts int
+}
+
+func (p *recordingPool) Get() []byte {
+	p.gets++
+	return make([]byte, 0, 4)
+}
+
+func (p *recordingPool) Put(b []byte) {
+	p.puts++
+}
+
+func writeAIFFLike(t *testing.T, cw *Writer) {
+	t.Helper()
+	if err := cw.BeginForm("AIFF"); err != nil {
+		t.Fatalf("BeginForm: %v", err)
+	}
+	ch, err := cw.BeginChunk("COMM")
+	if err != nil {
+		t.Fatalf("BeginChunk: %v", err)
+	}
+	if err := ch.WriteBE(uint16(2)); err != nil {
+		t.Fatalf("WriteBE: %v", err)
+	}
+	if err := ch.WriteByte(0xAA); err != nil {
+		t.Fatalf("WriteByte: %v", err)
+	}
+	if err := cw.EndChunk(); err != nil {
+		t.Fatalf("EndChunk: %v", err)
+	}
+	if err := cw.BeginList("INFO"); err != nil {
+		t.Fatalf("BeginList: %v", err)
+	}
+	ch, err = cw.BeginChunk("NAME")
+	if err != nil {
+		t.Fatalf("BeginChunk: %v", err)
+	}
+	ch.Write([]byte("ab"))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+var wantAIFFLike = []byte{
+	'F', 'O', 'R', 'M', 0, 0, 0, 38,
+	'A', 'I', 'F', 'F',
+	'C', 'O', 'M', 'M', 0, 0, 0, 3, 0, 2, 0xAA, 0,
+	'L', 'I', 'S', 'T', 0, 0, 0, 14,
+	'I', 'N', 'F', 'O',
+	'N', 'A', 'M', 'E', 0, 0, 0, 2, 'a', 'b',
+}
+
+func TestWriter_IFF(t *testing.T) {
+	t.Run("buffers when output cannot seek", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeAIFFLike(t, NewWriter(&buf, IFF))
+		if !bytes.Equal(buf.Bytes(), wantAIFFLike) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), wantAIFFLike)
+		}
+	})
+
+	t.Run("returns chunk buffers to the pool", func(t *testing.T) {
+		p := &recordingPool{}
+		chunk.SetBufferPool(p)
+		defer chunk.SetBufferPool(nil)
+		var buf bytes.Buffer
+		writeAIFFLike(t, NewWriter(&buf, IFF))
+		if !bytes.Equal(buf.Bytes(), wantAIFFLike) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), wantAIFFLike)
+		}
+		// FORM, COMM, LIST and NAME
+		if p.gets != 4 || p.puts != 4 {
+			t.Fatalf("expected 4 gets and puts, got %d and %d", p.gets, p.puts)
+		}
+	})
+
+	t.Run("patches sizes when output can seek", func(t *testing.T) {
+		var sb seekBuffer
+		writeAIFFLike(t, NewWriter(&sb, IFF))
+		if !bytes.Equal(sb.buf, wantAIFFLike) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, wantAIFFLike)
+		}
+	})
+
+	t.Run("writes CAT groups", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, IFF)
+		if err := cw.BeginCat("    "); err != nil {
+			t.Fatalf("BeginCat: %v", err)
+		}
+		if err := cw.BeginForm("8SVX"); err != nil {
+			t.Fatalf("BeginForm: %v", err)
+		}
+		if err := cw.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		want := []byte{
+			'C', 'A', 'T', ' ', 0, 0, 0, 16, ' ', ' ', ' ', ' ',
+			'F', 'O', 'R', 'M', 0, 0, 0, 4, '8', 'S', 'V', 'X',
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+	})
+}
+
+func TestWriter_RIFF(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewWriter(&buf, RIFF)
+	if err := cw.BeginForm("WAVE"); err != nil {
+		t.Fatalf("BeginForm: %v", err)
+	}
+	ch, err := cw.BeginChunk("data")
+	if err != nil {
+		t.Fatalf("BeginChunk: %v", err)
+	}
+	ch.WriteLE(uint16(0x0102))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	want := []byte{
+		'R', 'I', 'F', 'F', 14, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'd', 'a', 't', 'a', 2, 0, 0, 0, 0x02, 0x01,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	t.Run("EndChunk without open chunk", func(t *testing.T) {
+		cw := NewWriter(io.Discard, IFF)
+		if err := cw.EndChunk(); err != ErrNoOpenChunk {
+			t.Fatalf("expected ErrNoOpenChunk, got %v", err)
+		}
+	})
+
+	t.Run("nesting inside a data chunk", func(t *testing.T) {
+		cw := NewWriter(io.Discard, IFF)
+		if _, err := cw.BeginChunk("SSND"); err != nil {
+			t.Fatalf("BeginChunk: %v", err)
+		}
+		if _, err := cw.BeginChunk("COMM"); err != ErrInvalidNesting {
+			t.Fatalf("expected ErrInvalidNesting, got %v", err)
+		}
+	})
+}
+
+func TestWriter_ValidateID(t *testing.T) {
+	t.Run("rejects invalid IDs by default", func(t *testing.T) {
+		cw := NewWriter(io.Discard, RIFF)
+		if err := cw.BeginForm("WAV"); err != chunk.ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC, got %v", err)
+		}
+		if _, err := cw.BeginChunk("fmt"); err != chunk.ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC, got %v", err)
+		}
+		if _, err := cw.BeginChunk("fm\x00t"); err != chunk.ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC, got %v", err)
+		}
+	})
+
+	t.Run("validation can be disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := NewWriter(&buf, RIFF)
+		cw.ValidateID = nil
+		if _, err := cw.BeginChunk("fmt"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cw.Close()
+		if string(buf.Bytes()[:4]) != "fmt " {
+			t.Fatalf("expected space padded ID, got %q", buf.Bytes()[:4])
+		}
+	})
+}
+
+func TestNewSpecWriter(t *testing.T) {
+	spec := chunk.HeaderSpec{IDSize: 2, SizeSize: 2, Order: binary.BigEndian, SizeIncludesHeader: true, Align: 2}
+	want := []byte{
+		'G', 'R', 0, 16, 'L', 'E', 'V', 'L',
+		'h', 'p', 0, 7, 1, 2, 3, 0,
+	}
+	write := func(t *testing.T, out io.Writer) {
+		t.Helper()
+		cw, err := NewSpecWriter(out, spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cw.ValidateID = nil
+		if err := cw.BeginGroup("GR", "LEVL"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch, err := cw.BeginChunk("hp")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch.Write([]byte{1, 2, 3})
+		if err := cw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("buffered", func(t *testing.T) {
+		var buf bytes.Buffer
+		write(t, &buf)
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+		s := chunk.NewSpecScanner(bytes.NewReader(buf.Bytes()), spec)
+		if !s.Next() || s.Chunk().Size != 12 {
+			t.Fatalf("unexpected chunk: %v", s.Err())
+		}
+	})
+
+	t.Run("seeking", func(t *testing.T) {
+		sb := &seekBuffer{}
+		write(t, sb)
+		if !bytes.Equal(sb.buf, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", sb.buf, want)
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		cw, _ := NewSpecWriter(&bytes.Buffer{}, chunk.HeaderSpec{IDSize: 4, SizeSize: 1, Order: binary.LittleEndian})
+		ch, _ := cw.BeginChunk("big ")
+		ch.Write(make([]byte, 256))
+		if err := cw.EndChunk(); err != ErrChunkTooLarge {
+			t.Fatalf("expected ErrChunkTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		if _, err := NewSpecWriter(&bytes.Buffer{}, chunk.HeaderSpec{IDSize: 4}); err != chunk.ErrInvalidHeaderSpec {
+			t.Fatalf("expected ErrInvalidHeaderSpec, got %v", err)
+		}
+	})
+}
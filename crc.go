@@ -0,0 +1,92 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// ErrChecksum is returned by Done when the stored checksum of a chunk does
+// not match its payload.
+var ErrChecksum = errors.New("chunk checksum mismatch")
+
+// CRC describes a CRC32 stored in the last four bytes of a chunk's payload.
+type CRC struct {
+	// Table selects the polynomial, crc32.IEEETable if nil; use
+	// crc32.MakeTable(crc32.Castagnoli) for CRC-32C.
+	Table *crc32.Table
+	// Order is the byte order of the stored value, big-endian if nil.
+	Order binary.ByteOrder
+	// IncludeID feeds the chunk ID to the checksum before the payload, as
+	// PNG does with the chunk type.
+	IncludeID bool
+}
+
+// VerifyCRC returns a Reader for the payload of ch without its trailing
+// four byte checksum. Everything read from it, including what Done skips,
+// is checksummed; Done then reads the stored value and returns ErrChecksum
+// if it differs, or io.ErrUnexpectedEOF if ch is shorter than four bytes.
+// Verification happens once; later calls of Done return the same result.
+func VerifyCRC(ch *Reader, c CRC) *Reader {
+	c = c.withDefaults()
+	h := c.newHash(ch.ID)
+	size := max(ch.Size-ch.Pos-4, 0)
+	v := &Reader{
+		ID:       ch.ID,
+		Size:     size,
+		R:        io.TeeReader(io.LimitReader(ch, int64(size)), h),
+		Context:  ch.Context,
+		Progress: ch.Progress,
+	}
+	var (
+		done bool
+		err  error
+	)
+	v.verify = func() error {
+		if !done {
+			done = true
+			err = checkCRC(ch, h, c.Order)
+		}
+		return err
+	}
+	return v
+}
+
+// withDefaults fills in the table and byte order left nil.
+func (c CRC) withDefaults() CRC {
+	if c.Table == nil {
+		c.Table = crc32.IEEETable
+	}
+	if c.Order == nil {
+		c.Order = binary.BigEndian
+	}
+	return c
+}
+
+// newHash returns a hash for a chunk with the given ID, which has already
+// been fed if IncludeID is set.
+func (c CRC) newHash(id [4]byte) hash.Hash32 {
+	h := crc32.New(c.Table)
+	if c.IncludeID {
+		h.Write(id[:])
+	}
+	return h
+}
+
+// checkCRC reads the stored checksum from the rest of ch and compares it
+// with h.
+func checkCRC(ch *Reader, h hash.Hash32, order binary.ByteOrder) error {
+	if ch.Size-ch.Pos != 4 {
+		return io.ErrUnexpectedEOF
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(ch, sum[:]); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	if order.Uint32(sum[:]) != h.Sum32() {
+		return ErrChecksum
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+func withCRC(payload []byte, c CRC, id string) []byte {
+	h := crc32.New(c.Table)
+	if c.IncludeID {
+		h.Write([]byte(id))
+	}
+	h.Write(payload)
+	order := c.Order
+	if order == nil {
+		order = binary.BigEndian
+	}
+	sum := make([]byte, 4)
+	order.PutUint32(sum, h.Sum32())
+	return append(append([]byte(nil), payload...), sum...)
+}
+
+func TestVerifyCRC(t *testing.T) {
+	castagnoli := crc32.MakeTable(crc32.Castagnoli)
+	for _, c := range []struct {
+		name string
+		crc  CRC
+	}{
+		{"IEEE", CRC{Table: crc32.IEEETable}},
+		{"Castagnoli little-endian", CRC{Table: castagnoli, Order: binary.LittleEndian}},
+		{"including the ID", CRC{Table: crc32.IEEETable, IncludeID: true}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			data := withCRC([]byte("payload"), c.crc, "tEXt")
+			ch := &Reader{ID: [4]byte{'t', 'E', 'X', 't'}, Size: len(data), R: bytes.NewReader(data)}
+			v := VerifyCRC(ch, c.crc)
+			if v.Size != 7 {
+				t.Fatalf("expected size 7, got %d", v.Size)
+			}
+			// read part of the payload, Done checksums the rest
+			if b, _ := v.ReadByte(); b != 'p' {
+				t.Fatalf("unexpected first byte %q", b)
+			}
+			if err := v.Done(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ch.IsFullyRead() {
+				t.Fatal("expected the checksum to be consumed")
+			}
+		})
+	}
+
+	t.Run("detects corruption", func(t *testing.T) {
+		data := withCRC([]byte("payload"), CRC{Table: crc32.IEEETable}, "")
+		data[2] ^= 1
+		v := VerifyCRC(&Reader{Size: len(data), R: bytes.NewReader(data)}, CRC{})
+		if _, err := io.ReadAll(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := v.Done(); err != ErrChecksum {
+			t.Fatalf("expected ErrChecksum, got %v", err)
+		}
+		if err := v.Done(); err != ErrChecksum {
+			t.Fatalf("expected the result to stick, got %v", err)
+		}
+	})
+
+	t.Run("reports chunks too short for a checksum", func(t *testing.T) {
+		v := VerifyCRC(&Reader{Size: 2, R: bytes.NewReader([]byte{1, 2})}, CRC{})
+		if err := v.Done(); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+}
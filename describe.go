@@ -0,0 +1,57 @@
+package chunk
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Describer returns a one-line summary of the chunk in r for people, such
+// as "PCM 48kHz 24-bit stereo" for the fmt chunk of a WAVE file. It should
+// read no more of the payload than it needs.
+type Describer func(r *Reader) (string, error)
+
+var describers struct {
+	sync.RWMutex
+	m map[FourCC]Describer
+}
+
+// RegisterDescriber sets the describer of chunks with the given ID,
+// replacing an earlier one. The format packages register describers for the
+// chunks they decode when imported; as chunks are told apart by ID only, a
+// describer must not assume more about the file than its ID implies. IDs
+// shorter than four characters are padded with spaces, and chunks whose ID
+// is padded with NULs instead find the describer too, see FourCC.Normalize.
+func RegisterDescriber(id string, d Describer) {
+	k := FourCC{' ', ' ', ' ', ' '}
+	copy(k[:], id)
+	describers.Lock()
+	defer describers.Unlock()
+	if describers.m == nil {
+		describers.m = make(map[FourCC]Describer)
+	}
+	describers.m[k] = d
+}
+
+// Describe returns the summary of r by the describer registered for its ID,
+// or "" if there is none or it fails to decode the payload. Unknown IDs
+// resembling a known one, see Resembles, are described as such. Tree dumps
+// and the chunk command show it next to the chunk.
+func Describe(r *Reader) string {
+	describers.RLock()
+	d, ok := describers.m[r.ID]
+	if !ok {
+		d = describers.m[FourCC(r.ID).Normalize()]
+	}
+	describers.RUnlock()
+	if d == nil {
+		if c, ok := Resembles("", FourCC(r.ID)); ok {
+			return fmt.Sprintf("unknown ID, resembles %q (%s)", c.ID.String(), c.Name)
+		}
+		return ""
+	}
+	s, err := d(r)
+	if err != nil {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package chunk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	RegisterDescriber("TST1", func(r *Reader) (string, error) {
+		b, err := r.Peek(2)
+		return "starts with " + string(b), err
+	})
+	RegisterDescriber("TST2", func(r *Reader) (string, error) {
+		return "", errors.New("corrupt")
+	})
+	reader := func(id string, payload string) *Reader {
+		return &Reader{ID: [4]byte([]byte(id)), Size: len(payload), R: bytes.NewReader([]byte(payload))}
+	}
+
+	t.Run("describes registered IDs", func(t *testing.T) {
+		if got := Describe(reader("TST1", "hello")); got != "starts with he" {
+			t.Fatalf("unexpected description %q", got)
+		}
+	})
+
+	t.Run("pads short IDs", func(t *testing.T) {
+		RegisterDescriber("TS", func(r *Reader) (string, error) { return "short", nil })
+		for _, id := range []string{"TS  ", "TS\x00\x00"} {
+			if got := Describe(reader(id, "")); got != "short" {
+				t.Fatalf("%q: unexpected description %q", id, got)
+			}
+		}
+	})
+
+	t.Run("flags IDs resembling known ones", func(t *testing.T) {
+		RegisterChunk(ChunkInfo{ID: FourCC{'t', 's', 't', '4'}, Format: "describe-test", Name: "fourth test"})
+		if got := Describe(reader("TST4", "")); got != `unknown ID, resembles "tst4" (fourth test)` {
+			t.Fatalf("unexpected description %q", got)
+		}
+	})
+
+	t.Run("unknown IDs and failed describers", func(t *testing.T) {
+		if got := Describe(reader("TST3", "hello")); got != "" {
+			t.Fatalf("unexpected description %q", got)
+		}
+		if got := Describe(reader("TST2", "hello")); got != "" {
+			t.Fatalf("unexpected description %q", got)
+		}
+	})
+}
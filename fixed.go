@@ -0,0 +1,85 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrFixedRange is returned when a value does not fit the fixed-point field
+// it is written to.
+var ErrFixedRange = errors.New("value out of range for fixed-point field")
+
+// Rational is an unsigned fraction as stored in EXIF RATIONAL fields, e.g.
+// exposure times and resolutions.
+type Rational struct {
+	Num, Den uint32
+}
+
+// Float64 returns the value of r, or NaN if the denominator is zero.
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return math.NaN()
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// String returns r as num/den.
+func (r Rational) String() string {
+	return fmt.Sprintf("%d/%d", r.Num, r.Den)
+}
+
+// ReadFixed16_16 reads a signed 16.16 fixed-point number, as used by the
+// MP4 and QuickTime movie and track headers for rates, sizes and matrices.
+func (ch *Reader) ReadFixed16_16(order binary.ByteOrder) (float64, error) {
+	v, err := Read[int32](ch, order)
+	return float64(v) / (1 << 16), err
+}
+
+// ReadFixed2_30 reads a signed 2.30 fixed-point number, as used by the
+// last column of MP4 and QuickTime transformation matrices.
+func (ch *Reader) ReadFixed2_30(order binary.ByteOrder) (float64, error) {
+	v, err := Read[int32](ch, order)
+	return float64(v) / (1 << 30), err
+}
+
+// ReadRational reads a numerator followed by a denominator, both unsigned
+// 32-bit integers.
+func (ch *Reader) ReadRational(order binary.ByteOrder) (Rational, error) {
+	var r Rational
+	var err error
+	if r.Num, err = Read[uint32](ch, order); err != nil {
+		return r, err
+	}
+	r.Den, err = Read[uint32](ch, order)
+	return r, err
+}
+
+// WriteFixed16_16 writes v as a signed 16.16 fixed-point number, rounded to
+// the nearest step.
+func (ch *Writer) WriteFixed16_16(v float64, order binary.ByteOrder) error {
+	return ch.writeFixed(v, 1<<16, order)
+}
+
+// WriteFixed2_30 writes v as a signed 2.30 fixed-point number, rounded to
+// the nearest step.
+func (ch *Writer) WriteFixed2_30(v float64, order binary.ByteOrder) error {
+	return ch.writeFixed(v, 1<<30, order)
+}
+
+// WriteRational writes the numerator and denominator of r.
+func (ch *Writer) WriteRational(r Rational, order binary.ByteOrder) error {
+	if err := Write(ch, r.Num, order); err != nil {
+		return err
+	}
+	return Write(ch, r.Den, order)
+}
+
+func (ch *Writer) writeFixed(v, one float64, order binary.ByteOrder) error {
+	f := math.Round(v * one)
+	if !(f >= math.MinInt32 && f <= math.MaxInt32) { // NaN fails as well
+		return ErrFixedRange
+	}
+	return Write(ch, int32(f), order)
+}
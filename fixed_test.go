@@ -0,0 +1,87 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestReader_ReadFixed(t *testing.T) {
+	t.Run("reads 16.16 in both byte orders", func(t *testing.T) {
+		be := reader([]byte{0x00, 0x01, 0x80, 0x00})
+		if v, err := be.ReadFixed16_16(binary.BigEndian); err != nil || v != 1.5 {
+			t.Fatalf("expected 1.5, got %v, %v", v, err)
+		}
+		le := reader([]byte{0x00, 0x00, 0xff, 0xff})
+		if v, err := le.ReadFixed16_16(binary.LittleEndian); err != nil || v != -1 {
+			t.Fatalf("expected -1, got %v, %v", v, err)
+		}
+	})
+
+	t.Run("reads 2.30", func(t *testing.T) {
+		r := reader([]byte{0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0})
+		if v, err := r.ReadFixed2_30(binary.BigEndian); err != nil || v != 1 {
+			t.Fatalf("expected 1, got %v, %v", v, err)
+		}
+		if v, err := r.ReadFixed2_30(binary.LittleEndian); err != nil || v != -0.5 {
+			t.Fatalf("expected -0.5, got %v, %v", v, err)
+		}
+	})
+
+	t.Run("reads rationals", func(t *testing.T) {
+		r := reader([]byte{0, 0, 0, 1, 0, 0, 0, 250, 72, 0, 0, 0, 1, 0, 0, 0})
+		if v, err := r.ReadRational(binary.BigEndian); err != nil || v != (Rational{1, 250}) || v.String() != "1/250" {
+			t.Fatalf("expected 1/250, got %v, %v", v, err)
+		}
+		if v, err := r.ReadRational(binary.LittleEndian); err != nil || v.Float64() != 72 {
+			t.Fatalf("expected 72/1, got %v, %v", v, err)
+		}
+		if !math.IsNaN((Rational{1, 0}).Float64()) {
+			t.Fatal("expected NaN for a zero denominator")
+		}
+	})
+
+	t.Run("reports short data", func(t *testing.T) {
+		if _, err := reader([]byte{0, 0, 0, 1, 0}).ReadRational(binary.BigEndian); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+}
+
+func TestWriter_WriteFixed(t *testing.T) {
+	t.Run("round-trips", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		w.WriteFixed16_16(-1.5, binary.BigEndian)
+		w.WriteFixed2_30(0.25, binary.LittleEndian)
+		w.WriteRational(Rational{3, 4}, binary.LittleEndian)
+		if w.Size != 16 {
+			t.Fatalf("expected Size=16, got %d", w.Size)
+		}
+		r := reader(buf.Bytes())
+		a, _ := r.ReadFixed16_16(binary.BigEndian)
+		b, _ := r.ReadFixed2_30(binary.LittleEndian)
+		c, _ := r.ReadRational(binary.LittleEndian)
+		if a != -1.5 || b != 0.25 || c != (Rational{3, 4}) {
+			t.Fatalf("unexpected values %v %v %v", a, b, c)
+		}
+	})
+
+	t.Run("rejects values out of range", func(t *testing.T) {
+		w := &Writer{W: &bytes.Buffer{}}
+		for _, v := range []float64{32768, math.NaN()} {
+			if err := w.WriteFixed16_16(v, binary.BigEndian); err != ErrFixedRange {
+				t.Fatalf("%v: expected ErrFixedRange, got %v", v, err)
+			}
+		}
+		if err := w.WriteFixed2_30(-2.5, binary.BigEndian); err != ErrFixedRange {
+			t.Fatalf("expected ErrFixedRange, got %v", err)
+		}
+	})
+}
+
+func reader(b []byte) *Reader {
+	return &Reader{Size: len(b), R: bytes.NewReader(b)}
+}
@@ -0,0 +1,104 @@
+package chunk
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrUnknownFormat is returned by DetectFormat when no registered format
+// recognizes a file.
+var ErrUnknownFormat = errors.New("unknown file format")
+
+// DetectSize is the number of leading bytes DetectFormat hands to
+// FormatModule.Detect; shorter files are passed whole.
+const DetectSize = 32
+
+// ChunkScanner reads the chunks of a file one after the other. Scanner and
+// the scanners of the format packages implement it.
+type ChunkScanner interface {
+	// Next advances to the next chunk, finishing the current one. It
+	// returns false at the end of the file or on error.
+	Next() bool
+	// Chunk returns the current chunk.
+	Chunk() *Reader
+	// Offset returns the offset of the current chunk header; format
+	// modules count it from the start of the file.
+	Offset() int64
+	// Err returns the first error encountered, or nil at a clean end.
+	Err() error
+}
+
+// FormatModule adds support for a file format built from chunks. The
+// format packages register theirs when imported, for RIFF/RF64 WAVE, AIFF,
+// PNG and ISO BMFF files; proprietary containers register their own with
+// RegisterFormat and are then picked up by DetectFormat and the chunk
+// command.
+type FormatModule interface {
+	// Name is a short lower-case name of the format, e.g. "wav".
+	Name() string
+	// Detect reports whether a file starting with header is in the format.
+	// header holds the first DetectSize bytes, or fewer for short files.
+	Detect(header []byte) bool
+	// NewScanner reads the file header from r and returns a scanner over
+	// the top-level chunks that follow it.
+	NewScanner(r io.Reader) (ChunkScanner, error)
+	// Describe returns a one-line description of a file starting with
+	// header, such as "RF64 WAVE audio".
+	Describe(header []byte) string
+}
+
+var formats struct {
+	sync.RWMutex
+	modules []FormatModule
+}
+
+// RegisterFormat adds m to the formats tried by DetectFormat. Formats are
+// tried in the order they were registered; registering a name twice
+// replaces the earlier module in place.
+func RegisterFormat(m FormatModule) {
+	formats.Lock()
+	defer formats.Unlock()
+	for i, old := range formats.modules {
+		if old.Name() == m.Name() {
+			formats.modules[i] = m
+			return
+		}
+	}
+	formats.modules = append(formats.modules, m)
+}
+
+// Formats returns the registered formats in the order DetectFormat tries
+// them.
+func Formats() []FormatModule {
+	formats.RLock()
+	defer formats.RUnlock()
+	return append([]FormatModule(nil), formats.modules...)
+}
+
+// LookupFormat returns the registered format with the given name, or nil.
+func LookupFormat(name string) FormatModule {
+	for _, m := range Formats() {
+		if m.Name() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// DetectFormat returns the first registered format recognizing the file in
+// ra, or ErrUnknownFormat.
+func DetectFormat(ra io.ReaderAt) (FormatModule, error) {
+	header := make([]byte, DetectSize)
+	n, err := ra.ReadAt(header, 0)
+	if n < len(header) && err != nil && err != io.EOF {
+		return nil, err
+	}
+	header = header[:n]
+	for _, m := range Formats() {
+		if m.Detect(header) {
+			return m, nil
+		}
+	}
+	return nil, ErrUnknownFormat
+}
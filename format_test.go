@@ -0,0 +1,69 @@
+package chunk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testModule recognizes files starting with its name.
+type testModule struct {
+	name string
+}
+
+func (m testModule) Name() string { return m.name }
+
+func (m testModule) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, []byte(m.name))
+}
+
+func (m testModule) NewScanner(r io.Reader) (ChunkScanner, error) {
+	if _, err := io.CopyN(io.Discard, r, int64(len(m.name))); err != nil {
+		return nil, err
+	}
+	return NewScanner(r, IFFHeader.Order), nil
+}
+
+func (m testModule) Describe([]byte) string { return m.name + " file" }
+
+func TestFormats(t *testing.T) {
+	var _ ChunkScanner = (*Scanner)(nil)
+	RegisterFormat(testModule{"TST1"})
+	RegisterFormat(testModule{"TST2"})
+
+	t.Run("detects registered formats", func(t *testing.T) {
+		file := append([]byte("TST2"), 'N', 'A', 'M', 'E', 0, 0, 0, 2, 'h', 'i')
+		m, err := DetectFormat(bytes.NewReader(file))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Name() != "TST2" {
+			t.Fatalf("expected TST2, got %s", m.Name())
+		}
+		s, err := m.NewScanner(bytes.NewReader(file))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !s.Next() || s.Chunk().ID != [4]byte{'N', 'A', 'M', 'E'} || s.Chunk().Size != 2 {
+			t.Fatalf("unexpected chunk %+v (%v)", s.Chunk(), s.Err())
+		}
+	})
+
+	t.Run("short and unknown files", func(t *testing.T) {
+		if _, err := DetectFormat(bytes.NewReader([]byte("TS"))); !errors.Is(err, ErrUnknownFormat) {
+			t.Fatalf("expected ErrUnknownFormat, got %v", err)
+		}
+	})
+
+	t.Run("registering a name again replaces the module", func(t *testing.T) {
+		n := len(Formats())
+		RegisterFormat(testModule{"TST1"})
+		if len(Formats()) != n {
+			t.Fatalf("expected %d formats, got %d", n, len(Formats()))
+		}
+		if LookupFormat("TST1") == nil || LookupFormat("none") != nil {
+			t.Fatal("unexpected LookupFormat result")
+		}
+	})
+}
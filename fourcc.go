@@ -0,0 +1,136 @@
+package chunk
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+)
+
+// FourCC is a four character code identifying a chunk or form type.
+type FourCC [4]byte
+
+// ErrInvalidFourCC is returned for IDs that are not exactly four printable
+// ASCII characters.
+var ErrInvalidFourCC = errors.New("chunk ID must be four printable ASCII characters")
+
+// ParseFourCC validates s with ValidateFourCC and converts it to a FourCC.
+func ParseFourCC(s string) (FourCC, error) {
+	var f FourCC
+	if err := ValidateFourCC(s); err != nil {
+		return f, err
+	}
+	copy(f[:], s)
+	return f, nil
+}
+
+// String returns the code as a string, including any padding.
+func (f FourCC) String() string {
+	return string(f[:])
+}
+
+// Valid reports whether all four bytes are printable ASCII.
+func (f FourCC) Valid() bool {
+	for _, c := range f {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize returns f with trailing NUL bytes replaced by spaces. The
+// specifications pad short IDs such as "fmt " with spaces, but some writers
+// pad them with NULs; both normalize to the same code.
+func (f FourCC) Normalize() FourCC {
+	for i := len(f) - 1; i >= 0 && f[i] == 0; i-- {
+		f[i] = ' '
+	}
+	return f
+}
+
+// Matches reports whether f and g are the same code after Normalize, so
+// "fmt " matches "fmt\x00".
+func (f FourCC) Matches(g FourCC) bool {
+	return f.Normalize() == g.Normalize()
+}
+
+// EqualFold is Matches ignoring the case of ASCII letters, so "Data"
+// matches "data".
+func (f FourCC) EqualFold(g FourCC) bool {
+	f, g = f.Normalize(), g.Normalize()
+	for i := range f {
+		if lower(f[i]) != lower(g[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// MarshalText implements encoding.TextMarshaler. Printable codes are encoded
+// as is, anything else as 0x followed by eight hex digits so the value
+// survives text formats such as JSON.
+func (f FourCC) MarshalText() ([]byte, error) {
+	if f.Valid() {
+		return []byte(f.String()), nil
+	}
+	return []byte("0x" + hex.EncodeToString(f[:])), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting both forms
+// produced by MarshalText.
+func (f *FourCC) UnmarshalText(text []byte) error {
+	s := string(text)
+	if len(s) == 10 && strings.HasPrefix(s, "0x") {
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return ErrInvalidFourCC
+		}
+		copy(f[:], b)
+		return nil
+	}
+	v, err := ParseFourCC(s)
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// ValidateFourCC checks that id is exactly four bytes of printable ASCII,
+// which is what every RIFF and IFF parser expects.
+func ValidateFourCC(id string) error {
+	if len(id) != 4 {
+		return ErrInvalidFourCC
+	}
+	var f FourCC
+	copy(f[:], id)
+	if !f.Valid() {
+		return ErrInvalidFourCC
+	}
+	return nil
+}
+
+// ReadFourCC reads a four character code
+func (ch *Reader) ReadFourCC() (FourCC, error) {
+	var f FourCC
+	_, err := io.ReadFull(ch, f[:])
+	return f, err
+}
+
+// WriteFourCC validates and writes a four character code, e.g. a form type
+// or a chunk ID referenced from the payload.
+func (ch *Writer) WriteFourCC(id string) error {
+	if err := ValidateFourCC(id); err != nil {
+		return err
+	}
+	_, err := ch.Write([]byte(id))
+	return err
+}
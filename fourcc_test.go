@@ -0,0 +1,125 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidateFourCC(t *testing.T) {
+	valid := []string{"fmt ", "data", "RIFF", "    ", "(c) "}
+	for _, id := range valid {
+		if err := ValidateFourCC(id); err != nil {
+			t.Fatalf("%q: unexpected error: %v", id, err)
+		}
+	}
+	invalid := []string{"", "fmt", "datas", "da\x00a", "d\xe4ta", "\tab "}
+	for _, id := range invalid {
+		if err := ValidateFourCC(id); err != ErrInvalidFourCC {
+			t.Fatalf("%q: expected ErrInvalidFourCC, got %v", id, err)
+		}
+	}
+}
+
+func TestParseFourCC(t *testing.T) {
+	f, err := ParseFourCC("WAVE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.String() != "WAVE" {
+		t.Fatalf("expected WAVE, got %q", f.String())
+	}
+	if _, err := ParseFourCC("WAV"); err == nil {
+		t.Fatal("expected error for short ID")
+	}
+}
+
+func TestWriter_WriteFourCC(t *testing.T) {
+	t.Run("writes valid code", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := w.WriteFourCC("adtl"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "adtl" || w.Size != 4 {
+			t.Fatalf("expected adtl, got %q", buf.String())
+		}
+	})
+
+	t.Run("rejects invalid code", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := w.WriteFourCC("ad"); err != ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected nothing written, got % x", buf.Bytes())
+		}
+	})
+}
+
+func TestReader_ReadFourCC(t *testing.T) {
+	r := &Reader{Size: 4, R: bytes.NewReader([]byte("INFO"))}
+	f, err := r.ReadFourCC()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f != (FourCC{'I', 'N', 'F', 'O'}) {
+		t.Fatalf("expected INFO, got %q", f.String())
+	}
+	if r.Pos != 4 {
+		t.Fatalf("expected Pos=4, got %d", r.Pos)
+	}
+}
+
+func TestFourCC_Text(t *testing.T) {
+	for _, f := range []FourCC{{'f', 'm', 't', ' '}, {'d', 0, 0, 0xFF}} {
+		text, err := f.MarshalText()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got FourCC
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("%q: unexpected error: %v", text, err)
+		}
+		if got != f {
+			t.Fatalf("expected % x, got % x", f[:], got[:])
+		}
+	}
+	if text, _ := (FourCC{0, 1, 2, 3}).MarshalText(); string(text) != "0x00010203" {
+		t.Fatalf("expected hex form, got %q", text)
+	}
+	var f FourCC
+	if err := f.UnmarshalText([]byte("0xzzzzzzzz")); err == nil {
+		t.Fatal("expected error for malformed text")
+	}
+}
+
+func TestFourCC_Normalize(t *testing.T) {
+	if got := (FourCC{'f', 'm', 't', 0}).Normalize(); got != (FourCC{'f', 'm', 't', ' '}) {
+		t.Fatalf("expected \"fmt \", got %q", got.String())
+	}
+	if got := (FourCC{'a', 0, 'b', 0}).Normalize(); got != (FourCC{'a', 0, 'b', ' '}) {
+		t.Fatalf("expected only trailing NULs replaced, got %q", got.String())
+	}
+	if !(FourCC{'f', 'm', 't', ' '}).Matches(FourCC{'f', 'm', 't', 0}) {
+		t.Fatal("expected \"fmt \" to match \"fmt\\x00\"")
+	}
+	if (FourCC{'f', 'm', 't', ' '}).Matches(FourCC{'f', 'm', 't', 'x'}) {
+		t.Fatal("expected \"fmt \" not to match \"fmtx\"")
+	}
+}
+
+func TestFourCC_EqualFold(t *testing.T) {
+	data := FourCC{'d', 'a', 't', 'a'}
+	for _, g := range []FourCC{{'D', 'a', 't', 'a'}, {'D', 'A', 'T', 'A'}, data} {
+		if !data.EqualFold(g) {
+			t.Fatalf("expected %q to match", g.String())
+		}
+	}
+	if !(FourCC{'F', 'M', 'T', 0}).EqualFold(FourCC{'f', 'm', 't', ' '}) {
+		t.Fatal("expected NUL padding to be ignored")
+	}
+	if (FourCC{'d', 'a', 't', 0xc1}).EqualFold(FourCC{'d', 'a', 't', 0xe1}) {
+		t.Fatal("expected only ASCII letters to fold")
+	}
+}
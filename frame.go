@@ -0,0 +1,128 @@
+package chunk
+
+import (
+	"bytes"
+	"io"
+)
+
+// FrameWriter sends chunks as self-delimiting messages over a stream such as
+// a socket or pipe: header, payload, an optional CRC32 and padding, as laid
+// out by its HeaderSpec. A FrameReader with the same spec and CRC reads them
+// back. It is not safe for concurrent use.
+type FrameWriter struct {
+	// CRC, if set, appends a checksum of the payload to every frame; it is
+	// counted in the size field.
+	CRC *CRC
+
+	w    io.Writer
+	spec HeaderSpec
+	buf  []byte
+}
+
+// NewFrameWriter returns a FrameWriter writing frames with the given header
+// layout to w.
+func NewFrameWriter(w io.Writer, spec HeaderSpec) (*FrameWriter, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &FrameWriter{w: w, spec: spec}, nil
+}
+
+// WriteFrame writes a frame with the given ID and payload in a single Write
+// call. Payloads too large for the size field fail with ErrSizeOverflow.
+func (fw *FrameWriter) WriteFrame(id FourCC, payload []byte) error {
+	size := int64(len(payload))
+	if fw.CRC != nil {
+		size += 4
+	}
+	if size > fw.spec.MaxSize() {
+		return ErrSizeOverflow
+	}
+	hs := fw.spec.HeaderSize()
+	b := append(fw.buf[:0], make([]byte, hs)...)
+	fw.spec.PutHeader(b, id, size)
+	b = append(b, payload...)
+	if fw.CRC != nil {
+		c := fw.CRC.withDefaults()
+		h := c.newHash(id)
+		h.Write(payload)
+		b = append(b, 0, 0, 0, 0)
+		c.Order.PutUint32(b[len(b)-4:], h.Sum32())
+	}
+	b = append(b, make([]byte, fw.spec.Padding(size))...)
+	if cap(b) <= DefaultBufferSize {
+		fw.buf = b
+	}
+	_, err := fw.w.Write(b)
+	return err
+}
+
+// FrameReader reads the frames sent by a FrameWriter.
+type FrameReader struct {
+	// CRC, if set, verifies the checksum at the end of every frame, see
+	// FrameWriter.CRC.
+	CRC *CRC
+	// MaxSize, if positive, is the largest payload accepted; larger frames
+	// fail with ErrDeclaredSize before anything is allocated for them.
+	MaxSize int64
+
+	r    io.Reader
+	spec HeaderSpec
+}
+
+// NewFrameReader returns a FrameReader reading frames with the given header
+// layout from r.
+func NewFrameReader(r io.Reader, spec HeaderSpec) (*FrameReader, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &FrameReader{r: r, spec: spec}, nil
+}
+
+// ReadFrame reads the next frame and returns its ID and payload. It returns
+// io.EOF when the stream ends between frames, io.ErrUnexpectedEOF when it
+// ends within one and ErrChecksum for frames failing the CRC check.
+func (fr *FrameReader) ReadFrame() (FourCC, []byte, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(fr.r, hdr[:fr.spec.HeaderSize()]); err != nil {
+		return FourCC{}, nil, err
+	}
+	id, size, err := fr.spec.ParseHeader(hdr[:])
+	if err != nil {
+		return id, nil, err
+	}
+	data := size
+	if fr.CRC != nil {
+		if size < 4 {
+			return id, nil, io.ErrUnexpectedEOF
+		}
+		data -= 4
+	}
+	if fr.MaxSize > 0 && data > fr.MaxSize {
+		return id, nil, ErrDeclaredSize
+	}
+	// grow with the data read, a crafted size must not allocate up front
+	var buf bytes.Buffer
+	total := size + fr.spec.Padding(size)
+	if total < size {
+		return id, nil, ErrSizeOverflow
+	}
+	buf.Grow(int(min(total, 64<<10)))
+	if n, err := io.CopyN(&buf, fr.r, total); n < total {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return id, nil, err
+	}
+	b := buf.Bytes()[:size]
+	if fr.CRC != nil {
+		c := fr.CRC.withDefaults()
+		h := c.newHash(id)
+		h.Write(b[:data])
+		if c.Order.Uint32(b[data:]) != h.Sum32() {
+			return id, nil, ErrChecksum
+		}
+		b = b[:data]
+	}
+	return id, b, nil
+}
@@ -0,0 +1,87 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestFrames(t *testing.T) {
+	t.Run("round-trips over a connection", func(t *testing.T) {
+		a, b := net.Pipe()
+		defer a.Close()
+		defer b.Close()
+		fw, _ := NewFrameWriter(a, RIFFHeader)
+		fw.CRC = &CRC{}
+		fr, _ := NewFrameReader(b, RIFFHeader)
+		fr.CRC = &CRC{}
+		go func() {
+			fw.WriteFrame(FourCC{'P', 'I', 'N', 'G'}, []byte("odd"))
+			fw.WriteFrame(FourCC{'D', 'A', 'T', 'A'}, nil)
+			a.Close()
+		}()
+		for _, want := range []struct{ id, payload string }{{"PING", "odd"}, {"DATA", ""}} {
+			id, payload, err := fr.ReadFrame()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if id.String() != want.id || string(payload) != want.payload {
+				t.Fatalf("expected %s %q, got %s %q", want.id, want.payload, id, payload)
+			}
+		}
+		if _, _, err := fr.ReadFrame(); err != io.EOF {
+			t.Fatalf("expected io.EOF, got %v", err)
+		}
+	})
+
+	t.Run("lays frames out by the spec", func(t *testing.T) {
+		spec := HeaderSpec{IDSize: 2, SizeSize: 2, Order: binary.BigEndian, SizeIncludesHeader: true}
+		var buf bytes.Buffer
+		fw, _ := NewFrameWriter(&buf, spec)
+		fw.WriteFrame(FourCC{'h', 'i'}, []byte("abc"))
+		if got := buf.String(); got != "hi\x00\x07abc" {
+			t.Fatalf("unexpected frame %q", got)
+		}
+		fr, _ := NewFrameReader(&buf, spec)
+		if id, p, err := fr.ReadFrame(); err != nil || id != (FourCC{'h', 'i'}) || string(p) != "abc" {
+			t.Fatalf("unexpected frame %q %q (%v)", id[:], p, err)
+		}
+	})
+
+	t.Run("detects corrupt frames", func(t *testing.T) {
+		var buf bytes.Buffer
+		fw, _ := NewFrameWriter(&buf, IFFHeader)
+		fw.CRC = &CRC{IncludeID: true}
+		fw.WriteFrame(FourCC{'M', 'S', 'G', ' '}, []byte("hello"))
+		buf.Bytes()[9] ^= 1
+		fr, _ := NewFrameReader(&buf, IFFHeader)
+		fr.CRC = &CRC{IncludeID: true}
+		if _, _, err := fr.ReadFrame(); err != ErrChecksum {
+			t.Fatalf("expected ErrChecksum, got %v", err)
+		}
+	})
+
+	t.Run("bounds untrusted sizes", func(t *testing.T) {
+		fr, _ := NewFrameReader(bytes.NewReader([]byte("BIG \xff\xff\xff\x7f")), IFFHeader)
+		fr.MaxSize = 1 << 20
+		if _, _, err := fr.ReadFrame(); err != ErrDeclaredSize {
+			t.Fatalf("expected ErrDeclaredSize, got %v", err)
+		}
+		fr, _ = NewFrameReader(bytes.NewReader([]byte("BIG \xff\xff\xff\x7fabc")), IFFHeader)
+		if _, _, err := fr.ReadFrame(); err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("rejects oversized payloads", func(t *testing.T) {
+		fw, _ := NewFrameWriter(io.Discard, HeaderSpec{IDSize: 4, SizeSize: 1, Order: binary.BigEndian})
+		if err := fw.WriteFrame(FourCC{'b', 'i', 'g', ' '}, make([]byte, 256)); err != ErrSizeOverflow {
+			t.Fatalf("expected ErrSizeOverflow, got %v", err)
+		}
+		if _, err := NewFrameWriter(io.Discard, HeaderSpec{}); err != ErrInvalidHeaderSpec {
+			t.Fatalf("expected ErrInvalidHeaderSpec, got %v", err)
+		}
+	})
+}
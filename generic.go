@@ -0,0 +1,111 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Read reads a value of type T in the given byte order. Fixed-size integers,
+// floats and bools are decoded without reflection or allocations; any other
+// type falls back to binary.Read.
+func Read[T any](r *Reader, order binary.ByteOrder) (T, error) {
+	var v T
+	if r == nil || r.R == nil {
+		return v, errors.New("nil Reader/reader pointer")
+	}
+	n := fixedSize(v)
+	if n == 0 {
+		err := binary.Read(r, order, &v)
+		return v, err
+	}
+	b := r.scratch[:n]
+	if _, err := io.ReadFull(r, b); err != nil {
+		return v, err
+	}
+	switch p := any(&v).(type) {
+	case *uint8:
+		*p = b[0]
+	case *int8:
+		*p = int8(b[0])
+	case *bool:
+		*p = b[0] != 0
+	case *uint16:
+		*p = order.Uint16(b)
+	case *int16:
+		*p = int16(order.Uint16(b))
+	case *uint32:
+		*p = order.Uint32(b)
+	case *int32:
+		*p = int32(order.Uint32(b))
+	case *float32:
+		*p = math.Float32frombits(order.Uint32(b))
+	case *uint64:
+		*p = order.Uint64(b)
+	case *int64:
+		*p = int64(order.Uint64(b))
+	case *float64:
+		*p = math.Float64frombits(order.Uint64(b))
+	}
+	return v, nil
+}
+
+// Write writes v in the given byte order. Fixed-size integers, floats and
+// bools are encoded without reflection or allocations; any other type falls
+// back to binary.Write.
+func Write[T any](w *Writer, v T, order binary.ByteOrder) error {
+	if w == nil || w.W == nil {
+		return errors.New("nil Writer/writer pointer")
+	}
+	n := fixedSize(v)
+	if n == 0 {
+		return w.writeWithByteOrder(v, order)
+	}
+	b := w.scratch[:n]
+	switch x := any(v).(type) {
+	case uint8:
+		b[0] = x
+	case int8:
+		b[0] = byte(x)
+	case bool:
+		b[0] = 0
+		if x {
+			b[0] = 1
+		}
+	case uint16:
+		order.PutUint16(b, x)
+	case int16:
+		order.PutUint16(b, uint16(x))
+	case uint32:
+		order.PutUint32(b, x)
+	case int32:
+		order.PutUint32(b, uint32(x))
+	case float32:
+		order.PutUint32(b, math.Float32bits(x))
+	case uint64:
+		order.PutUint64(b, x)
+	case int64:
+		order.PutUint64(b, uint64(x))
+	case float64:
+		order.PutUint64(b, math.Float64bits(x))
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// fixedSize returns the encoded size of the fast path types and 0 for
+// anything else.
+func fixedSize(v any) int {
+	switch v.(type) {
+	case uint8, int8, bool:
+		return 1
+	case uint16, int16:
+		return 2
+	case uint32, int32, float32:
+		return 4
+	case uint64, int64, float64:
+		return 8
+	}
+	return 0
+}
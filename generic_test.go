@@ -0,0 +1,116 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("encodes fixed-size types", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		Write(w, uint16(0x0102), binary.BigEndian)
+		Write(w, int32(-2), binary.LittleEndian)
+		Write(w, float32(1), binary.BigEndian)
+		Write(w, true, binary.LittleEndian)
+		want := []byte{
+			0x01, 0x02,
+			0xFE, 0xFF, 0xFF, 0xFF,
+			0x3F, 0x80, 0x00, 0x00,
+			0x01,
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("expected % x, got % x", want, buf.Bytes())
+		}
+		if w.Size != len(want) {
+			t.Fatalf("expected Size=%d, got %d", len(want), w.Size)
+		}
+	})
+
+	t.Run("falls back to binary.Write", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := Write(w, [2]uint16{1, 2}, binary.LittleEndian); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), []byte{1, 0, 2, 0}) {
+			t.Fatalf("unexpected output % x", buf.Bytes())
+		}
+	})
+
+	t.Run("does not allocate", func(t *testing.T) {
+		w := &Writer{W: io.Discard}
+		allocs := testing.AllocsPerRun(100, func() {
+			Write(w, uint32(0xDEADBEEF), binary.LittleEndian)
+			Write(w, math.Pi, binary.BigEndian)
+		})
+		if allocs != 0 {
+			t.Fatalf("expected no allocations, got %v", allocs)
+		}
+	})
+
+	t.Run("nil writer returns error", func(t *testing.T) {
+		if err := Write[uint8](nil, 1, binary.LittleEndian); err == nil {
+			t.Fatal("expected error for nil Writer pointer")
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("decodes what Write encoded", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		Write(w, int16(-300), binary.BigEndian)
+		Write(w, uint64(1<<40), binary.LittleEndian)
+		Write(w, -0.5, binary.LittleEndian)
+
+		r := &Reader{Size: buf.Len(), R: bytes.NewReader(buf.Bytes())}
+		if v, err := Read[int16](r, binary.BigEndian); err != nil || v != -300 {
+			t.Fatalf("int16: %v, %v", v, err)
+		}
+		if v, err := Read[uint64](r, binary.LittleEndian); err != nil || v != 1<<40 {
+			t.Fatalf("uint64: %v, %v", v, err)
+		}
+		if v, err := Read[float64](r, binary.LittleEndian); err != nil || v != -0.5 {
+			t.Fatalf("float64: %v, %v", v, err)
+		}
+		if r.Pos != buf.Len() {
+			t.Fatalf("expected Pos=%d, got %d", buf.Len(), r.Pos)
+		}
+	})
+
+	t.Run("falls back to binary.Read", func(t *testing.T) {
+		r := &Reader{Size: 4, R: bytes.NewReader([]byte{1, 0, 2, 0})}
+		v, err := Read[[2]uint16](r, binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v != [2]uint16{1, 2} {
+			t.Fatalf("expected [1 2], got %v", v)
+		}
+	})
+
+	t.Run("does not allocate", func(t *testing.T) {
+		data := make([]byte, 8)
+		br := bytes.NewReader(data)
+		r := &Reader{Size: len(data), R: br}
+		allocs := testing.AllocsPerRun(100, func() {
+			br.Reset(data)
+			Read[uint32](r, binary.LittleEndian)
+			Read[float32](r, binary.BigEndian)
+		})
+		if allocs != 0 {
+			t.Fatalf("expected no allocations, got %v", allocs)
+		}
+	})
+
+	t.Run("short data returns error", func(t *testing.T) {
+		r := &Reader{Size: 4, R: bytes.NewReader([]byte{1})}
+		if _, err := Read[uint32](r, binary.LittleEndian); err == nil {
+			t.Fatal("expected error for short data")
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var (
+	// ErrInvalidHeaderSpec is returned for header layouts that cannot be
+	// encoded.
+	ErrInvalidHeaderSpec = errors.New("invalid chunk header spec")
+	// ErrInvalidSize is returned for size fields smaller than the header
+	// they are supposed to include.
+	ErrInvalidSize = errors.New("chunk size smaller than its header")
+	// ErrSizeOverflow is returned for sizes that do not fit the offsets and
+	// lengths of the platform, e.g. 64-bit size fields above MaxInt64.
+	ErrSizeOverflow = errors.New("chunk size overflows")
+)
+
+// HeaderSpec describes the chunk header of a TLV style format: an ID
+// followed by a size field. RIFF and IFF are the specs below, custom game or
+// embedded containers often use shorter IDs or sizes that count the header.
+type HeaderSpec struct {
+	// IDSize is the number of ID bytes, 0 to 4. Shorter IDs occupy the
+	// first bytes of the Reader and Writer ID.
+	IDSize int
+	// SizeSize is the width of the size field: 1, 2, 4 or 8 bytes.
+	SizeSize int
+	Order    binary.ByteOrder
+	// SizeIncludesHeader is set when the size field counts the header as
+	// well as the payload.
+	SizeIncludesHeader bool
+	// Align pads every payload to a multiple of Align bytes; 2 for RIFF
+	// and IFF, 0 or 1 for no padding. Sizes never include the padding.
+	Align int
+}
+
+// Header specs of the common formats.
+var (
+	RIFFHeader = HeaderSpec{IDSize: 4, SizeSize: 4, Order: binary.LittleEndian, Align: 2}
+	IFFHeader  = HeaderSpec{IDSize: 4, SizeSize: 4, Order: binary.BigEndian, Align: 2}
+)
+
+// Validate reports whether the spec can be encoded.
+func (h HeaderSpec) Validate() error {
+	switch {
+	case h.IDSize < 0 || h.IDSize > 4, h.Order == nil, h.Align < 0:
+		return ErrInvalidHeaderSpec
+	}
+	switch h.SizeSize {
+	case 1, 2, 4, 8:
+		return nil
+	}
+	return ErrInvalidHeaderSpec
+}
+
+// HeaderSize returns the number of header bytes.
+func (h HeaderSpec) HeaderSize() int {
+	return h.IDSize + h.SizeSize
+}
+
+// MaxSize returns the largest payload size the size field can express.
+func (h HeaderSpec) MaxSize() int64 {
+	limit := int64(math.MaxInt64)
+	if h.SizeSize < 8 {
+		limit = int64(1)<<(8*h.SizeSize) - 1
+	}
+	if h.SizeIncludesHeader {
+		limit -= int64(h.HeaderSize())
+	}
+	return limit
+}
+
+// Padding returns the number of pad bytes following a payload of size
+// bytes.
+func (h HeaderSpec) Padding(size int64) int64 {
+	if h.Align <= 1 {
+		return 0
+	}
+	return (int64(h.Align) - size%int64(h.Align)) % int64(h.Align)
+}
+
+// PutHeader encodes the header of a chunk with a payload of size bytes into
+// b, which must hold HeaderSize bytes.
+func (h HeaderSpec) PutHeader(b []byte, id [4]byte, size int64) {
+	copy(b, id[:h.IDSize])
+	if h.SizeIncludesHeader {
+		size += int64(h.HeaderSize())
+	}
+	h.putSize(b[h.IDSize:], uint64(size))
+}
+
+// ParseHeader decodes a header from b and returns the ID and the payload
+// size.
+func (h HeaderSpec) ParseHeader(b []byte) (id [4]byte, size int64, err error) {
+	copy(id[:], b[:h.IDSize])
+	raw := h.rawSize(b)
+	if raw > math.MaxInt64 {
+		return id, 0, ErrSizeOverflow
+	}
+	size = int64(raw)
+	if h.SizeIncludesHeader {
+		if size < int64(h.HeaderSize()) {
+			return id, 0, ErrInvalidSize
+		}
+		size -= int64(h.HeaderSize())
+	}
+	return id, size, nil
+}
+
+// rawSize returns the size field of the header in b as stored.
+func (h HeaderSpec) rawSize(b []byte) uint64 {
+	b = b[h.IDSize:]
+	switch h.SizeSize {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(h.Order.Uint16(b))
+	case 4:
+		return uint64(h.Order.Uint32(b))
+	}
+	return h.Order.Uint64(b)
+}
+
+func (h HeaderSpec) putSize(b []byte, v uint64) {
+	switch h.SizeSize {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		h.Order.PutUint16(b, uint16(v))
+	case 4:
+		h.Order.PutUint32(b, uint32(v))
+	default:
+		h.Order.PutUint64(b, v)
+	}
+}
@@ -0,0 +1,66 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestHeaderSpec(t *testing.T) {
+	spec := HeaderSpec{IDSize: 2, SizeSize: 2, Order: binary.BigEndian, SizeIncludesHeader: true}
+	hdr := make([]byte, spec.HeaderSize())
+	spec.PutHeader(hdr, [4]byte{'T', 'X'}, 5)
+	if !bytes.Equal(hdr, []byte{'T', 'X', 0, 9}) {
+		t.Fatalf("unexpected header % x", hdr)
+	}
+	id, size, err := spec.ParseHeader(hdr)
+	if err != nil || id != [4]byte{'T', 'X'} || size != 5 {
+		t.Fatalf("unexpected header %q %d %v", id, size, err)
+	}
+	if spec.MaxSize() != 65535-4 {
+		t.Fatalf("unexpected max size %d", spec.MaxSize())
+	}
+	if _, _, err := spec.ParseHeader([]byte{'T', 'X', 0, 3}); err != ErrInvalidSize {
+		t.Fatalf("expected ErrInvalidSize, got %v", err)
+	}
+	if RIFFHeader.Padding(3) != 1 || RIFFHeader.Padding(4) != 0 || spec.Padding(3) != 0 {
+		t.Fatal("unexpected padding")
+	}
+	for _, bad := range []HeaderSpec{
+		{IDSize: 5, SizeSize: 4, Order: binary.LittleEndian},
+		{IDSize: 4, SizeSize: 3, Order: binary.LittleEndian},
+		{IDSize: 4, SizeSize: 4},
+	} {
+		if err := bad.Validate(); err != ErrInvalidHeaderSpec {
+			t.Fatalf("%+v: expected ErrInvalidHeaderSpec, got %v", bad, err)
+		}
+	}
+}
+
+func TestNewSpecScanner(t *testing.T) {
+	spec := HeaderSpec{IDSize: 1, SizeSize: 4, Order: binary.LittleEndian, Align: 4}
+	data := []byte{
+		'A', 3, 0, 0, 0, 'a', 'b', 'c', 0,
+		'B', 1, 0, 0, 0, 'd', // final padding omitted
+	}
+	s := NewSpecScanner(bytes.NewReader(data), spec)
+	var got []string
+	for s.Next() {
+		b := make([]byte, s.Chunk().Size)
+		s.Chunk().Read(b)
+		got = append(got, string(s.Chunk().ID[:1])+string(b))
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "Aabc" || got[1] != "Bd" {
+		t.Fatalf("unexpected chunks %q", got)
+	}
+
+	t.Run("invalid spec", func(t *testing.T) {
+		s := NewSpecScanner(bytes.NewReader(data), HeaderSpec{})
+		if s.Next() || s.Err() != ErrInvalidHeaderSpec {
+			t.Fatalf("expected ErrInvalidHeaderSpec, got %v", s.Err())
+		}
+	})
+}
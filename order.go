@@ -0,0 +1,92 @@
+package chunk
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// OrderRule constrains the position of a chunk in the outermost group of a
+// format. Rules only apply to files holding the chunk; that it is required
+// at all is checked separately.
+type OrderRule struct {
+	// Format is the name of the format the rule belongs to, e.g. "wav".
+	Format string
+	ID     FourCC
+	// Before, unless zero, is a chunk that must not appear ahead of the
+	// first chunk with ID, as "data" must not come before "fmt ".
+	Before FourCC
+	// First and Last require the chunk to open or close the group.
+	First, Last bool
+}
+
+// OrderViolation is a broken OrderRule reported by CheckOrder.
+type OrderViolation struct {
+	// Index is the position of the misplaced chunk among the checked IDs.
+	Index   int
+	Rule    OrderRule
+	Message string
+}
+
+var orderRules struct {
+	sync.RWMutex
+	rules []OrderRule
+}
+
+// RegisterOrder adds r to the ordering rules checked by CheckOrder. The
+// format packages register the rules of their formats when imported.
+func RegisterOrder(r OrderRule) {
+	orderRules.Lock()
+	defer orderRules.Unlock()
+	if !slices.Contains(orderRules.rules, r) {
+		orderRules.rules = append(orderRules.rules, r)
+	}
+}
+
+// OrderRules returns the ordering rules of the given format, or of all
+// formats if format is "", in the order they were registered.
+func OrderRules(format string) []OrderRule {
+	orderRules.RLock()
+	defer orderRules.RUnlock()
+	var out []OrderRule
+	for _, r := range orderRules.rules {
+		if format == "" || r.Format == format {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// CheckOrder checks ids, the chunk IDs of an outermost group in file order,
+// against the rules of format ("" meaning all formats) and returns the
+// violations sorted by index. Many hardware players reject files whose
+// chunks are out of order even though their parsers could cope.
+func CheckOrder(format string, ids []FourCC) []OrderViolation {
+	var out []OrderViolation
+	for _, r := range OrderRules(format) {
+		i := slices.Index(ids, r.ID)
+		if i < 0 {
+			continue
+		}
+		if r.First && i != 0 {
+			out = append(out, OrderViolation{i, r, "must be the first chunk"})
+		}
+		if k := lastIndex(ids, r.ID); r.Last && k != len(ids)-1 {
+			out = append(out, OrderViolation{k, r, "must be the last chunk"})
+		}
+		if r.Before != (FourCC{}) && slices.Contains(ids[:i], r.Before) {
+			out = append(out, OrderViolation{i, r, fmt.Sprintf("must come before %q", r.Before.String())})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b OrderViolation) int { return a.Index - b.Index })
+	return out
+}
+
+func lastIndex(ids []FourCC, id FourCC) int {
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,58 @@
+package chunk
+
+import "testing"
+
+func TestCheckOrder(t *testing.T) {
+	var (
+		head = FourCC{'h', 'e', 'a', 'd'}
+		body = FourCC{'b', 'o', 'd', 'y'}
+		tail = FourCC{'t', 'a', 'i', 'l'}
+		meta = FourCC{'m', 'e', 't', 'a'}
+	)
+	RegisterOrder(OrderRule{Format: "order-test", ID: head, First: true})
+	RegisterOrder(OrderRule{Format: "order-test", ID: tail, Last: true})
+	RegisterOrder(OrderRule{Format: "order-test", ID: meta, Before: body})
+	RegisterOrder(OrderRule{Format: "order-test", ID: meta, Before: body})
+
+	t.Run("lists the rules of a format once", func(t *testing.T) {
+		if got := OrderRules("order-test"); len(got) != 3 || got[0].ID != head {
+			t.Fatalf("unexpected rules %+v", got)
+		}
+		if got := OrderRules("other"); len(got) != 0 {
+			t.Fatalf("expected no rules, got %+v", got)
+		}
+	})
+
+	t.Run("accepts ordered and partial groups", func(t *testing.T) {
+		for _, ids := range [][]FourCC{
+			{head, meta, body, tail},
+			{body, tail},
+			{head, tail, tail},
+			nil,
+		} {
+			if got := CheckOrder("order-test", ids); len(got) != 0 {
+				t.Fatalf("%v: unexpected violations %+v", ids, got)
+			}
+		}
+	})
+
+	t.Run("reports misplaced chunks by index", func(t *testing.T) {
+		got := CheckOrder("order-test", []FourCC{body, tail, head, meta, body})
+		want := []struct {
+			index int
+			msg   string
+		}{
+			{1, "must be the last chunk"},
+			{2, "must be the first chunk"},
+			{3, `must come before "body"`},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d violations, got %+v", len(want), got)
+		}
+		for i, w := range want {
+			if got[i].Index != w.index || got[i].Message != w.msg {
+				t.Fatalf("violation %d: expected %d %q, got %+v", i, w.index, w.msg, got[i])
+			}
+		}
+	})
+}
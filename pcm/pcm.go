@@ -0,0 +1,277 @@
+// Package pcm converts between the raw bytes of audio data chunks and sample
+// slices. Every conversion allocates its result once and runs a loop
+// specialized for the sample width and byte order, which is much faster than
+// decoding samples one by one with binary.Read.
+package pcm
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrLayout is returned for layouts a conversion does not support.
+var ErrLayout = errors.New("pcm: unsupported sample layout")
+
+// Layout describes how samples are stored.
+type Layout struct {
+	// Width is the number of bytes per sample: 1 to 4 for integers, 4 or 8
+	// for floats.
+	Width int
+	Order binary.ByteOrder
+	// Float selects IEEE 754 samples.
+	Float bool
+	// Unsigned selects offset binary 8-bit samples as used by WAV.
+	Unsigned bool
+}
+
+// Validate reports whether l describes a supported layout.
+func (l Layout) Validate() error {
+	switch {
+	case l.Order != binary.LittleEndian && l.Order != binary.BigEndian:
+		return ErrLayout
+	case l.Float:
+		if l.Width != 4 && l.Width != 8 || l.Unsigned {
+			return ErrLayout
+		}
+	case l.Width < 1 || l.Width > 4, l.Unsigned && l.Width != 1:
+		return ErrLayout
+	}
+	return nil
+}
+
+// Count returns the number of complete samples in b.
+func (l Layout) Count(b []byte) int {
+	if l.Width <= 0 {
+		return 0
+	}
+	return len(b) / l.Width
+}
+
+// Int16s decodes integer samples of at most 16 bits. Values are kept as
+// stored, 8-bit samples are not scaled up.
+func Int16s(b []byte, l Layout) ([]int16, error) {
+	if err := l.Validate(); err != nil || l.Float || l.Width > 2 {
+		return nil, ErrLayout
+	}
+	out := make([]int16, l.Count(b))
+	decodeInts(out, b, l, 1)
+	return out, nil
+}
+
+// Int32s decodes integer samples of up to 32 bits, sign-extending the
+// stored values, e.g. 24-bit samples range from -8388608 to 8388607.
+func Int32s(b []byte, l Layout) ([]int32, error) {
+	if err := l.Validate(); err != nil || l.Float {
+		return nil, ErrLayout
+	}
+	out := make([]int32, l.Count(b))
+	decodeInts(out, b, l, 1)
+	return out, nil
+}
+
+// Float32s decodes samples as float32. Integer samples are normalized to
+// [-1, 1).
+func Float32s(b []byte, l Layout) ([]float32, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	out := make([]float32, l.Count(b))
+	if l.Float {
+		decodeFloats(out, b, l)
+	} else {
+		decodeInts(out, b, l, float32(1/fullScale(l.Width)))
+	}
+	return out, nil
+}
+
+// Float64s decodes samples as float64. Integer samples are normalized to
+// [-1, 1).
+func Float64s(b []byte, l Layout) ([]float64, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	out := make([]float64, l.Count(b))
+	if l.Float {
+		decodeFloats(out, b, l)
+	} else {
+		decodeInts(out, b, l, 1/fullScale(l.Width))
+	}
+	return out, nil
+}
+
+// AppendInt16s appends s encoded in the integer layout l to dst. Values are
+// written as they are and must fit the sample width.
+func AppendInt16s(dst []byte, s []int16, l Layout) ([]byte, error) {
+	return appendInts(dst, s, l)
+}
+
+// AppendInt32s appends s encoded in the integer layout l to dst. Values are
+// written as they are and must fit the sample width.
+func AppendInt32s(dst []byte, s []int32, l Layout) ([]byte, error) {
+	return appendInts(dst, s, l)
+}
+
+// AppendFloat32s appends s encoded in layout l to dst. For integer layouts
+// the samples are scaled from [-1, 1] and clipped.
+func AppendFloat32s(dst []byte, s []float32, l Layout) ([]byte, error) {
+	return appendFloats(dst, s, l)
+}
+
+// AppendFloat64s appends s encoded in layout l to dst. For integer layouts
+// the samples are scaled from [-1, 1] and clipped.
+func AppendFloat64s(dst []byte, s []float64, l Layout) ([]byte, error) {
+	return appendFloats(dst, s, l)
+}
+
+// fullScale returns the magnitude of the most negative integer sample.
+func fullScale(width int) float64 {
+	return float64(int64(1) << (8*width - 1))
+}
+
+func decodeInts[T int16 | int32 | float32 | float64](out []T, b []byte, l Layout, scale T) {
+	be := l.Order == binary.BigEndian
+	switch l.Width {
+	case 1:
+		if l.Unsigned {
+			for i := range out {
+				out[i] = T(int32(b[i])-128) * scale
+			}
+		} else {
+			for i := range out {
+				out[i] = T(int8(b[i])) * scale
+			}
+		}
+	case 2:
+		if be {
+			for i := range out {
+				out[i] = T(int16(uint16(b[2*i])<<8|uint16(b[2*i+1]))) * scale
+			}
+		} else {
+			for i := range out {
+				out[i] = T(int16(uint16(b[2*i])|uint16(b[2*i+1])<<8)) * scale
+			}
+		}
+	case 3:
+		if be {
+			for i := range out {
+				p := b[3*i : 3*i+3]
+				out[i] = T(int32(int8(p[0]))<<16|int32(p[1])<<8|int32(p[2])) * scale
+			}
+		} else {
+			for i := range out {
+				p := b[3*i : 3*i+3]
+				out[i] = T(int32(p[0])|int32(p[1])<<8|int32(int8(p[2]))<<16) * scale
+			}
+		}
+	case 4:
+		if be {
+			for i := range out {
+				out[i] = T(int32(binary.BigEndian.Uint32(b[4*i:]))) * scale
+			}
+		} else {
+			for i := range out {
+				out[i] = T(int32(binary.LittleEndian.Uint32(b[4*i:]))) * scale
+			}
+		}
+	}
+}
+
+func decodeFloats[T float32 | float64](out []T, b []byte, l Layout) {
+	if l.Width == 4 {
+		for i := range out {
+			out[i] = T(math.Float32frombits(l.Order.Uint32(b[4*i:])))
+		}
+		return
+	}
+	for i := range out {
+		out[i] = T(math.Float64frombits(l.Order.Uint64(b[8*i:])))
+	}
+}
+
+func appendInts[T int16 | int32](dst []byte, s []T, l Layout) ([]byte, error) {
+	if err := l.Validate(); err != nil || l.Float {
+		return dst, ErrLayout
+	}
+	n := len(dst)
+	dst = slices.Grow(dst, len(s)*l.Width)[:n+len(s)*l.Width]
+	encodeInts(dst[n:], s, l)
+	return dst, nil
+}
+
+func encodeInts[T int16 | int32](b []byte, s []T, l Layout) {
+	be := l.Order == binary.BigEndian
+	switch l.Width {
+	case 1:
+		var offset int32
+		if l.Unsigned {
+			offset = 128
+		}
+		for i, v := range s {
+			b[i] = byte(int32(v) + offset)
+		}
+	case 2:
+		if be {
+			for i, v := range s {
+				binary.BigEndian.PutUint16(b[2*i:], uint16(v))
+			}
+		} else {
+			for i, v := range s {
+				binary.LittleEndian.PutUint16(b[2*i:], uint16(v))
+			}
+		}
+	case 3:
+		if be {
+			for i, v := range s {
+				b[3*i], b[3*i+1], b[3*i+2] = byte(int32(v)>>16), byte(int32(v)>>8), byte(v)
+			}
+		} else {
+			for i, v := range s {
+				b[3*i], b[3*i+1], b[3*i+2] = byte(v), byte(int32(v)>>8), byte(int32(v)>>16)
+			}
+		}
+	case 4:
+		if be {
+			for i, v := range s {
+				binary.BigEndian.PutUint32(b[4*i:], uint32(v))
+			}
+		} else {
+			for i, v := range s {
+				binary.LittleEndian.PutUint32(b[4*i:], uint32(v))
+			}
+		}
+	}
+}
+
+func appendFloats[T float32 | float64](dst []byte, s []T, l Layout) ([]byte, error) {
+	if err := l.Validate(); err != nil {
+		return dst, err
+	}
+	n := len(dst)
+	dst = slices.Grow(dst, len(s)*l.Width)[:n+len(s)*l.Width]
+	b := dst[n:]
+	switch {
+	case l.Float && l.Width == 4:
+		for i, v := range s {
+			l.Order.PutUint32(b[4*i:], math.Float32bits(float32(v)))
+		}
+	case l.Float:
+		for i, v := range s {
+			l.Order.PutUint64(b[8*i:], math.Float64bits(float64(v)))
+		}
+	default:
+		// scale in blocks so integer layouts need no extra allocation
+		limit := fullScale(l.Width) - 1
+		var ints [256]int32
+		for len(s) > 0 {
+			m := min(len(s), len(ints))
+			for i, v := range s[:m] {
+				ints[i] = int32(math.Round(max(-1, min(1, float64(v))) * limit))
+			}
+			encodeInts(b, ints[:m], l)
+			s, b = s[m:], b[m*l.Width:]
+		}
+	}
+	return dst, nil
+}
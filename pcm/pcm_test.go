@@ -0,0 +1,142 @@
+package pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+var (
+	le16 = Layout{Width: 2, Order: binary.LittleEndian}
+	be24 = Layout{Width: 3, Order: binary.BigEndian}
+	le24 = Layout{Width: 3, Order: binary.LittleEndian}
+	u8   = Layout{Width: 1, Order: binary.LittleEndian, Unsigned: true}
+	f32  = Layout{Width: 4, Order: binary.LittleEndian, Float: true}
+)
+
+func TestInt32s(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		l    Layout
+		data []byte
+		want []int32
+	}{
+		{"16-bit LE", le16, []byte{0x01, 0x00, 0xff, 0xff, 0x00, 0x80}, []int32{1, -1, -32768}},
+		{"24-bit LE", le24, []byte{0xff, 0xff, 0x7f, 0x00, 0x00, 0x80}, []int32{8388607, -8388608}},
+		{"24-bit BE", be24, []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xfe}, []int32{8388607, -2}},
+		{"32-bit BE", Layout{Width: 4, Order: binary.BigEndian}, []byte{0x80, 0, 0, 0}, []int32{-1 << 31}},
+		{"unsigned 8-bit", u8, []byte{0, 128, 255}, []int32{-128, 0, 127}},
+		{"trailing bytes", le16, []byte{2, 0, 9}, []int32{2}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Int32s(c.data, c.l)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Fatalf("expected %v, got %v", c.want, got)
+				}
+			}
+			back, err := AppendInt32s(nil, got, c.l)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(back, c.data[:len(got)*c.l.Width]) {
+				t.Fatalf("round trip mismatch: % x", back)
+			}
+		})
+	}
+}
+
+func TestInt16s(t *testing.T) {
+	got, err := Int16s([]byte{0x00, 0x80, 0x34, 0x12}, le16)
+	if err != nil || got[0] != -32768 || got[1] != 0x1234 {
+		t.Fatalf("unexpected samples %v %v", got, err)
+	}
+	if _, err := Int16s(nil, le24); err != ErrLayout {
+		t.Fatalf("expected ErrLayout for 24-bit samples, got %v", err)
+	}
+	b, _ := AppendInt16s([]byte{0xaa}, []int16{-2}, le16)
+	if !bytes.Equal(b, []byte{0xaa, 0xfe, 0xff}) {
+		t.Fatalf("unexpected output % x", b)
+	}
+}
+
+func TestFloats(t *testing.T) {
+	t.Run("normalizes integers", func(t *testing.T) {
+		got, err := Float64s([]byte{0x00, 0x80, 0x00, 0x40}, le16)
+		if err != nil || got[0] != -1 || got[1] != 0.5 {
+			t.Fatalf("unexpected samples %v %v", got, err)
+		}
+		f, _ := Float32s([]byte{0, 128}, u8)
+		if f[0] != -1 || f[1] != 0 {
+			t.Fatalf("unexpected samples %v", f)
+		}
+	})
+
+	t.Run("IEEE float round trip", func(t *testing.T) {
+		in := []float32{0, 0.5, -0.25, 1}
+		b, err := AppendFloat32s(nil, in, f32)
+		if err != nil || len(b) != 16 {
+			t.Fatalf("unexpected output % x %v", b, err)
+		}
+		got, _ := Float32s(b, f32)
+		for i := range in {
+			if got[i] != in[i] {
+				t.Fatalf("expected %v, got %v", in, got)
+			}
+		}
+		d, _ := Float64s(b, f32)
+		if d[1] != 0.5 {
+			t.Fatalf("unexpected samples %v", d)
+		}
+	})
+
+	t.Run("scales and clips into integer layouts", func(t *testing.T) {
+		in := make([]float64, 300)
+		in[0], in[1], in[299] = 1, -2, 0.5
+		b, err := AppendFloat64s(nil, in, le24)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := Int32s(b, le24)
+		if len(got) != 300 || got[0] != 8388607 || got[1] != -8388607 || got[299] != 4194304 {
+			t.Fatalf("unexpected samples %v %v %v", got[0], got[1], got[299])
+		}
+	})
+}
+
+func TestLayout_Validate(t *testing.T) {
+	for _, l := range []Layout{
+		{Width: 3, Order: binary.LittleEndian, Float: true},
+		{Width: 5, Order: binary.LittleEndian},
+		{Width: 2, Order: binary.LittleEndian, Unsigned: true},
+		{Width: 2},
+	} {
+		if err := l.Validate(); err != ErrLayout {
+			t.Fatalf("%+v: expected ErrLayout, got %v", l, err)
+		}
+	}
+}
+
+func BenchmarkInt32s(b *testing.B) {
+	data := make([]byte, 3*48000)
+	for b.Loop() {
+		Int32s(data, le24)
+	}
+}
+
+func BenchmarkBinaryRead(b *testing.B) {
+	data := make([]byte, 2*48000)
+	for b.Loop() {
+		r := bytes.NewReader(data)
+		out := make([]int16, 48000)
+		for i := range out {
+			binary.Read(r, binary.LittleEndian, &out[i])
+		}
+	}
+}
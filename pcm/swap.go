@@ -0,0 +1,83 @@
+package pcm
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// SwapBytes16 reverses the byte order of the 16-bit samples in b in place.
+// A trailing odd byte is left alone.
+func SwapBytes16(b []byte) {
+	i := 0
+	// eight bytes at a time: swap the bytes of every 16-bit lane
+	for ; i+8 <= len(b); i += 8 {
+		x := binary.LittleEndian.Uint64(b[i:])
+		x = x>>8&0x00FF00FF00FF00FF | x&0x00FF00FF00FF00FF<<8
+		binary.LittleEndian.PutUint64(b[i:], x)
+	}
+	for ; i+2 <= len(b); i += 2 {
+		b[i], b[i+1] = b[i+1], b[i]
+	}
+}
+
+// SwapBytes24 reverses the byte order of the packed 24-bit samples in b in
+// place. Trailing bytes of an incomplete sample are left alone.
+func SwapBytes24(b []byte) {
+	i := 0
+	for ; i+12 <= len(b); i += 12 {
+		p := b[i : i+12 : i+12]
+		p[0], p[2] = p[2], p[0]
+		p[3], p[5] = p[5], p[3]
+		p[6], p[8] = p[8], p[6]
+		p[9], p[11] = p[11], p[9]
+	}
+	for ; i+3 <= len(b); i += 3 {
+		b[i], b[i+2] = b[i+2], b[i]
+	}
+}
+
+// SwapBytes32 reverses the byte order of the 32-bit samples in b in place.
+// Trailing bytes of an incomplete sample are left alone.
+func SwapBytes32(b []byte) {
+	i := 0
+	for ; i+8 <= len(b); i += 8 {
+		x := bits.ReverseBytes64(binary.LittleEndian.Uint64(b[i:]))
+		binary.LittleEndian.PutUint64(b[i:], bits.RotateLeft64(x, 32))
+	}
+	if i+4 <= len(b) {
+		binary.LittleEndian.PutUint32(b[i:], bits.ReverseBytes32(binary.LittleEndian.Uint32(b[i:])))
+	}
+}
+
+// swapBytes64 reverses the byte order of the 64-bit samples in b in place.
+func swapBytes64(b []byte) {
+	for i := 0; i+8 <= len(b); i += 8 {
+		binary.LittleEndian.PutUint64(b[i:], bits.ReverseBytes64(binary.LittleEndian.Uint64(b[i:])))
+	}
+}
+
+// Reorder converts the samples in b, stored in layout l, to the byte order
+// order in place, e.g. to hand big-endian AIFF or RIFX data to code that
+// expects little-endian samples.
+func Reorder(b []byte, l Layout, order binary.ByteOrder) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if order != binary.LittleEndian && order != binary.BigEndian {
+		return ErrLayout
+	}
+	if order == l.Order {
+		return nil
+	}
+	switch l.Width {
+	case 2:
+		SwapBytes16(b)
+	case 3:
+		SwapBytes24(b)
+	case 4:
+		SwapBytes32(b)
+	case 8:
+		swapBytes64(b)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// naiveSwap reverses every width bytes of b.
+func naiveSwap(b []byte, width int) []byte {
+	out := slices.Clone(b)
+	for i := 0; i+width <= len(out); i += width {
+		slices.Reverse(out[i : i+width])
+	}
+	return out
+}
+
+func TestSwapBytes(t *testing.T) {
+	src := make([]byte, 61)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, c := range []struct {
+		width int
+		swap  func([]byte)
+	}{
+		{2, SwapBytes16},
+		{3, SwapBytes24},
+		{4, SwapBytes32},
+		{8, swapBytes64},
+	} {
+		// every length exercises both the word loop and the tail
+		for n := range len(src) {
+			got := slices.Clone(src[:n])
+			c.swap(got)
+			if want := naiveSwap(src[:n], c.width); !bytes.Equal(got, want) {
+				t.Fatalf("width %d, %d bytes: got %v, want %v", c.width, n, got, want)
+			}
+		}
+	}
+}
+
+func TestReorder(t *testing.T) {
+	t.Run("converts big-endian samples", func(t *testing.T) {
+		b := []byte{0x12, 0x34, 0x56, 0xAB, 0xCD, 0xEF}
+		if err := Reorder(b, be24, binary.LittleEndian); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := Int32s(b, le24)
+		if got[0] != 0x123456 || got[1] != -0x543211 {
+			t.Fatalf("unexpected samples %x", got)
+		}
+	})
+
+	t.Run("keeps matching orders", func(t *testing.T) {
+		b := []byte{1, 2}
+		if err := Reorder(b, le16, binary.LittleEndian); err != nil || b[0] != 1 {
+			t.Fatalf("expected no change, got %v (%v)", b, err)
+		}
+	})
+
+	t.Run("rejects bad layouts", func(t *testing.T) {
+		if err := Reorder(nil, Layout{Width: 5, Order: binary.BigEndian}, binary.LittleEndian); err != ErrLayout {
+			t.Fatalf("expected ErrLayout, got %v", err)
+		}
+	})
+}
+
+func BenchmarkSwapBytes16(b *testing.B) {
+	data := make([]byte, 2*48000)
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		SwapBytes16(data)
+	}
+}
+
+func BenchmarkSwapBytes24(b *testing.B) {
+	data := make([]byte, 3*48000)
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		SwapBytes24(data)
+	}
+}
@@ -0,0 +1,53 @@
+package png
+
+import "github.com/CWBudde/chunk"
+
+// specPNG is the specification of the chunks in catalog.
+const specPNG = "PNG (Third Edition)"
+
+// catalog lists the chunks of PNG files registered with
+// chunk.RegisterChunk. The case of PNG chunk IDs carries their properties,
+// so "iTXt" and "ITXT" are different chunks.
+var catalog = []struct{ id, name string }{
+	{"IHDR", "image header"},
+	{"PLTE", "palette"},
+	{"IDAT", "image data"},
+	{"IEND", "image trailer"},
+	{"acTL", "animation control"},
+	{"bKGD", "background color"},
+	{"cHRM", "chromaticities"},
+	{"cICP", "coding-independent code points"},
+	{"eXIf", "Exif data"},
+	{"fcTL", "frame control"},
+	{"fdAT", "frame data"},
+	{"gAMA", "gamma"},
+	{"hIST", "palette histogram"},
+	{"iCCP", "ICC profile"},
+	{"iTXt", "international text"},
+	{"pHYs", "pixel dimensions"},
+	{"sBIT", "significant bits"},
+	{"sPLT", "suggested palette"},
+	{"sRGB", "sRGB rendering intent"},
+	{"tEXt", "text"},
+	{"tIME", "last modification time"},
+	{"tRNS", "transparency"},
+	{"zTXt", "compressed text"},
+}
+
+// order lists the ordering rules of PNG files registered with
+// chunk.RegisterOrder.
+var order = []chunk.OrderRule{
+	{ID: chunk.FourCC([]byte("IHDR")), First: true},
+	{ID: chunk.FourCC([]byte("IEND")), Last: true},
+	{ID: chunk.FourCC([]byte("PLTE")), Before: chunk.FourCC([]byte("IDAT"))},
+}
+
+func init() {
+	for _, c := range catalog {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte(c.id)), Format: "png", Name: c.name, Spec: specPNG})
+	}
+	for _, r := range order {
+		r.Format = "png"
+		chunk.RegisterOrder(r)
+	}
+}
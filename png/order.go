@@ -0,0 +1,26 @@
+package png
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+)
+
+// CheckOrder reads the PNG stream r and checks its chunks against the
+// ordering rules of PNG: IHDR first, IEND last and PLTE ahead of the image
+// data. The Index of a violation counts the chunks after the signature.
+// Checksums are verified along the way.
+func CheckOrder(r io.Reader) ([]chunk.OrderViolation, error) {
+	s, err := NewScanner(r)
+	if err != nil {
+		return nil, err
+	}
+	var ids []chunk.FourCC
+	for s.Next() {
+		ids = append(ids, s.Chunk().ID)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return chunk.CheckOrder("png", ids), nil
+}
@@ -0,0 +1,46 @@
+package png
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCheckOrder(t *testing.T) {
+	build := func(t *testing.T, types ...string) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		WriteSignature(&buf)
+		for _, typ := range types {
+			if err := WriteChunk(&buf, typ, []byte("x")); err != nil {
+				t.Fatalf("WriteChunk: %v", err)
+			}
+		}
+		return buf.Bytes()
+	}
+
+	t.Run("accepts a well-ordered stream", func(t *testing.T) {
+		got, err := CheckOrder(bytes.NewReader(build(t, "IHDR", "PLTE", "IDAT", "IDAT", "IEND")))
+		if err != nil || len(got) != 0 {
+			t.Fatalf("unexpected violations %+v (%v)", got, err)
+		}
+	})
+
+	t.Run("reports misplaced chunks", func(t *testing.T) {
+		got, err := CheckOrder(bytes.NewReader(build(t, "tEXt", "IHDR", "IDAT", "PLTE", "IEND", "tEXt")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 3 || got[0].Index != 1 || got[1].Index != 3 || got[2].Index != 4 {
+			t.Fatalf("unexpected violations %+v", got)
+		}
+		if got[1].Message != `must come before "IDAT"` || got[2].Message != "must be the last chunk" {
+			t.Fatalf("unexpected messages %+v", got)
+		}
+	})
+
+	t.Run("rejects other streams", func(t *testing.T) {
+		if _, err := CheckOrder(bytes.NewReader([]byte("RIFF"))); err != ErrSignature {
+			t.Fatalf("expected ErrSignature, got %v", err)
+		}
+	})
+}
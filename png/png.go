@@ -0,0 +1,106 @@
+// Package png reads and writes PNG chunks: a big-endian length, the chunk
+// type, the payload and a CRC32 over type and payload.
+package png
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// Signature is the eight byte header every PNG stream starts with.
+var Signature = [8]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+var (
+	// ErrInvalidType is returned for chunk types that are not four ASCII
+	// letters.
+	ErrInvalidType = errors.New("png: chunk type must be four ASCII letters")
+	// ErrLengthMismatch is returned when a streamed chunk payload does not
+	// match the length announced in its header.
+	ErrLengthMismatch = errors.New("png: payload length does not match header")
+	// ErrTooLarge is returned for payloads exceeding the 2^31-1 byte limit.
+	ErrTooLarge = errors.New("png: chunk payload too large")
+)
+
+// WriteSignature writes the PNG signature.
+func WriteSignature(w io.Writer) error {
+	_, err := w.Write(Signature[:])
+	return err
+}
+
+// WriteChunk writes a complete chunk of the given type, e.g. "tEXt", and
+// appends the CRC32 of type and payload.
+func WriteChunk(w io.Writer, typ string, payload []byte) error {
+	cw, err := NewChunkWriter(w, typ, len(payload))
+	if err != nil {
+		return err
+	}
+	if _, err := cw.Write(payload); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// ChunkWriter streams the payload of a single chunk whose length is known up
+// front. The embedded chunk Writer provides the usual convenience methods;
+// Close appends the CRC.
+type ChunkWriter struct {
+	*chunk.Writer
+	length int
+	out    io.Writer
+	crc    hash.Hash32
+}
+
+// NewChunkWriter writes the header of a chunk with the given type and payload
+// length and returns a ChunkWriter for the payload.
+func NewChunkWriter(w io.Writer, typ string, length int) (*ChunkWriter, error) {
+	if !validType(typ) {
+		return nil, ErrInvalidType
+	}
+	if length < 0 || length > math.MaxInt32 {
+		return nil, ErrTooLarge
+	}
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[:4], uint32(length))
+	copy(hdr[4:], typ)
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(hdr[4:])
+	cw := &ChunkWriter{
+		Writer: &chunk.Writer{W: io.MultiWriter(w, crc)},
+		length: length,
+		out:    w,
+		crc:    crc,
+	}
+	copy(cw.ID[:], typ)
+	return cw, nil
+}
+
+// Close verifies the payload length and writes the CRC.
+func (cw *ChunkWriter) Close() error {
+	if cw.Size != cw.length {
+		return ErrLengthMismatch
+	}
+	return binary.Write(cw.out, binary.BigEndian, cw.crc.Sum32())
+}
+
+func validType(typ string) bool {
+	if len(typ) != 4 {
+		return false
+	}
+	for i := 0; i < 4; i++ {
+		c := typ[i] | 0x20 // fold to lower case
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
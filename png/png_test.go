@@ -0,0 +1,81 @@
+package png
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+func TestWriteChunk(t *testing.T) {
+	t.Run("writes length, type, payload and CRC", func(t *testing.T) {
+		var buf bytes.Buffer
+		payload := []byte("Comment\x00hello")
+		if err := WriteChunk(&buf, "tEXt", payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out := buf.Bytes()
+		if len(out) != 12+len(payload) {
+			t.Fatalf("expected %d bytes, got %d", 12+len(payload), len(out))
+		}
+		if got := binary.BigEndian.Uint32(out); int(got) != len(payload) {
+			t.Fatalf("expected length %d, got %d", len(payload), got)
+		}
+		if string(out[4:8]) != "tEXt" {
+			t.Fatalf("expected tEXt, got %q", out[4:8])
+		}
+		want := crc32.ChecksumIEEE(out[4 : 8+len(payload)])
+		if got := binary.BigEndian.Uint32(out[8+len(payload):]); got != want {
+			t.Fatalf("expected CRC 0x%08x, got 0x%08x", want, got)
+		}
+	})
+
+	t.Run("IEND has the well known CRC", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteChunk(&buf, "IEND", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []byte{0, 0, 0, 0, 'I', 'E', 'N', 'D', 0xAE, 0x42, 0x60, 0x82}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("expected % x, got % x", want, buf.Bytes())
+		}
+	})
+
+	t.Run("rejects invalid types", func(t *testing.T) {
+		for _, typ := range []string{"", "abc", "ab1d", "abcde"} {
+			if err := WriteChunk(&bytes.Buffer{}, typ, nil); err != ErrInvalidType {
+				t.Fatalf("%q: expected ErrInvalidType, got %v", typ, err)
+			}
+		}
+	})
+}
+
+func TestChunkWriter(t *testing.T) {
+	t.Run("streams payload", func(t *testing.T) {
+		var streamed, whole bytes.Buffer
+		cw, err := NewChunkWriter(&streamed, "abCd", 6)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cw.WriteBE(uint16(0x0102))
+		cw.Write([]byte("xyzw"))
+		if err := cw.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		WriteChunk(&whole, "abCd", []byte{1, 2, 'x', 'y', 'z', 'w'})
+		if !bytes.Equal(streamed.Bytes(), whole.Bytes()) {
+			t.Fatalf("expected % x, got % x", whole.Bytes(), streamed.Bytes())
+		}
+	})
+
+	t.Run("detects short payload", func(t *testing.T) {
+		cw, err := NewChunkWriter(&bytes.Buffer{}, "zTXt", 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cw.Write([]byte{1})
+		if err := cw.Close(); err != ErrLengthMismatch {
+			t.Fatalf("expected ErrLengthMismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package png
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrSignature is returned by NewScanner for streams that do not start with
+// the PNG signature.
+var ErrSignature = errors.New("png: missing PNG signature")
+
+func init() {
+	chunk.RegisterFormat(module{})
+}
+
+// Scanner reads the chunks of a PNG stream. Payloads are checksummed as
+// they are read or skipped; Next fails with chunk.ErrChecksum when the CRC
+// of the previous chunk does not match.
+type Scanner struct {
+	r    io.Reader
+	cur  *chunk.Reader
+	off  int64
+	next int64
+	err  error
+	// badCRC, if set, is called when the chunk read last fails the CRC
+	// check, and Next carries on; Validate uses it to report them all.
+	badCRC func()
+}
+
+// NewScanner reads the signature from r and returns a Scanner positioned
+// before the first chunk.
+func NewScanner(r io.Reader) (*Scanner, error) {
+	var sig [8]byte
+	if _, err := io.ReadFull(r, sig[:]); err != nil || sig != Signature {
+		return nil, ErrSignature
+	}
+	return &Scanner{r: r, next: int64(len(sig))}, nil
+}
+
+// Next advances to the next chunk, verifying the current one. It returns
+// false at the end of the stream or on error.
+func (s *Scanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	if s.cur != nil {
+		err := s.cur.Done()
+		s.cur = nil
+		if err == chunk.ErrChecksum && s.badCRC != nil {
+			s.badCRC()
+			err = nil
+		}
+		if err != nil {
+			s.setErr(err)
+			return false
+		}
+	}
+	var hdr [8]byte
+	if n, err := io.ReadFull(s.r, hdr[:]); err != nil {
+		if n > 0 || err != io.EOF {
+			s.setErr(err)
+		}
+		return false
+	}
+	length := int64(binary.BigEndian.Uint32(hdr[:4]))
+	if length > math.MaxInt32 {
+		s.err = ErrTooLarge
+		return false
+	}
+	// the CRC is read as the last four bytes of the raw chunk
+	raw := &chunk.Reader{ID: [4]byte(hdr[4:]), Size: int(length) + 4, R: io.LimitReader(s.r, length+4)}
+	s.cur = chunk.VerifyCRC(raw, chunk.CRC{IncludeID: true})
+	s.off = s.next
+	s.next += 12 + length
+	return true
+}
+
+// Chunk returns the current chunk, without its CRC.
+func (s *Scanner) Chunk() *chunk.Reader {
+	return s.cur
+}
+
+// Offset returns the offset of the current chunk header from the start of
+// the stream, counting the signature.
+func (s *Scanner) Offset() int64 {
+	return s.off
+}
+
+// Err returns the first error encountered, or nil at a clean end of stream.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) setErr(err error) {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	s.err = err
+}
+
+// module is the chunk.FormatModule of PNG streams, registered when the
+// package is imported.
+type module struct{}
+
+func (module) Name() string { return "png" }
+
+func (module) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, Signature[:])
+}
+
+func (module) NewScanner(r io.Reader) (chunk.ChunkScanner, error) {
+	s, err := NewScanner(r)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (module) Describe([]byte) string { return "PNG image" }
@@ -0,0 +1,77 @@
+package png
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// testPNG returns a PNG stream with a tEXt and an IEND chunk.
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	WriteSignature(&buf)
+	if err := WriteChunk(&buf, "tEXt", []byte("Comment\x00hi")); err != nil {
+		t.Fatalf("WriteChunk: %v", err)
+	}
+	if err := WriteChunk(&buf, "IEND", nil); err != nil {
+		t.Fatalf("WriteChunk: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("reads chunks and verifies CRCs", func(t *testing.T) {
+		s, err := NewScanner(bytes.NewReader(testPNG(t)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !s.Next() || string(s.Chunk().ID[:]) != "tEXt" || s.Offset() != 8 {
+			t.Fatalf("unexpected first chunk (%v)", s.Err())
+		}
+		payload, err := io.ReadAll(s.Chunk())
+		if err != nil || string(payload) != "Comment\x00hi" {
+			t.Fatalf("unexpected payload %q (%v)", payload, err)
+		}
+		if !s.Next() || string(s.Chunk().ID[:]) != "IEND" || s.Offset() != 30 {
+			t.Fatalf("unexpected second chunk (%v)", s.Err())
+		}
+		if s.Next() || s.Err() != nil {
+			t.Fatalf("expected a clean end, got %v", s.Err())
+		}
+	})
+
+	t.Run("reports CRC mismatches", func(t *testing.T) {
+		b := testPNG(t)
+		b[20] ^= 1
+		s, _ := NewScanner(bytes.NewReader(b))
+		for s.Next() {
+		}
+		if !errors.Is(s.Err(), chunk.ErrChecksum) {
+			t.Fatalf("expected ErrChecksum, got %v", s.Err())
+		}
+	})
+
+	t.Run("truncated and foreign streams", func(t *testing.T) {
+		b := testPNG(t)
+		s, _ := NewScanner(bytes.NewReader(b[:len(b)-2]))
+		for s.Next() {
+		}
+		if !errors.Is(s.Err(), io.ErrUnexpectedEOF) {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+		if _, err := NewScanner(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00"))); !errors.Is(err, ErrSignature) {
+			t.Fatalf("expected ErrSignature, got %v", err)
+		}
+	})
+
+	t.Run("is a registered format", func(t *testing.T) {
+		m, err := chunk.DetectFormat(bytes.NewReader(testPNG(t)))
+		if err != nil || m.Name() != "png" {
+			t.Fatalf("unexpected format %v (%v)", m, err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package png
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// Validate reads the PNG stream r and reports what is wrong with it as
+// container.Finding values, as container.Validate does for RIFF and IFF:
+// chunks failing the CRC check, invalid or resembling chunk types, chunks
+// running past the end of the stream, the ordering rules of CheckOrder and
+// missing IHDR or IEND chunks. The path of a finding is the chunk type,
+// numbered from the second one on as in "IDAT[1]". The error is only set if
+// r is not a PNG stream or cannot be read.
+func Validate(r io.Reader) ([]container.Finding, error) {
+	cr := &countReader{r: r}
+	s, err := NewScanner(cr)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		findings []container.Finding
+		chunks   []container.Finding // offset, ID and path of every chunk
+		seen     = map[chunk.FourCC]int{}
+	)
+	s.badCRC = func() {
+		n := chunks[len(chunks)-1]
+		n.Check, n.Message = container.CheckCRC, "CRC mismatch"
+		findings = append(findings, n)
+	}
+	for s.Next() {
+		id := chunk.FourCC(s.Chunk().ID)
+		path := id.String()
+		if k := seen[id]; k > 0 {
+			path += "[" + strconv.Itoa(k) + "]"
+		}
+		seen[id]++
+		n := container.Finding{Offset: s.Offset(), ID: id, Path: path}
+		chunks = append(chunks, n)
+		if !id.Valid() {
+			n.Check, n.Message = container.CheckID, "type is not printable"
+			findings = append(findings, n)
+		} else if c, ok := chunk.Resembles("png", id); ok {
+			n.Check, n.Severity = container.CheckID, container.Warning
+			n.Message = fmt.Sprintf("unknown type resembling %q (%s)", c.ID.String(), c.Name)
+			findings = append(findings, n)
+		}
+	}
+	switch err := s.Err(); {
+	case errors.Is(err, io.ErrUnexpectedEOF) && len(chunks) > 0 && cr.n < s.next:
+		n := chunks[len(chunks)-1]
+		n.Check, n.Message = container.CheckSize, fmt.Sprintf("chunk runs past the end of the stream at %d", cr.n)
+		findings = append(findings, n)
+	case errors.Is(err, io.ErrUnexpectedEOF), err == ErrTooLarge:
+		findings = append(findings, container.Finding{Check: container.CheckStructure, Offset: s.next, Message: "parsing stopped: " + err.Error()})
+	case err != nil:
+		return findings, err
+	}
+
+	ids := make([]chunk.FourCC, len(chunks))
+	for i, n := range chunks {
+		ids[i] = n.ID
+	}
+	for _, o := range chunk.CheckOrder("png", ids) {
+		n := chunks[o.Index]
+		n.Check, n.Severity, n.Message = container.CheckOrder, container.Warning, o.Message
+		findings = append(findings, n)
+	}
+	for _, id := range []string{"IHDR", "IEND"} {
+		if seen[chunk.FourCC([]byte(id))] == 0 {
+			findings = append(findings, container.Finding{Check: container.CheckRequired, ID: chunk.FourCC([]byte(id)), Path: id, Message: "required chunk missing"})
+		}
+	}
+	return findings, nil
+}
+
+// countReader counts the bytes read through it.
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,72 @@
+package png
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestValidate(t *testing.T) {
+	build := func(t *testing.T, types ...string) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		WriteSignature(&buf)
+		for _, typ := range types {
+			if err := WriteChunk(&buf, typ, []byte("x")); err != nil {
+				t.Fatalf("WriteChunk: %v", err)
+			}
+		}
+		return buf.Bytes()
+	}
+
+	t.Run("accepts a valid stream", func(t *testing.T) {
+		got, err := Validate(bytes.NewReader(build(t, "IHDR", "IDAT", "IDAT", "IEND")))
+		if err != nil || len(got) != 0 {
+			t.Fatalf("unexpected findings %+v (%v)", got, err)
+		}
+	})
+
+	t.Run("reports every CRC mismatch", func(t *testing.T) {
+		b := build(t, "IHDR", "IDAT", "IDAT", "IEND")
+		b[8+13+8] ^= 1   // payload of the first IDAT
+		b[8+13*2+8] ^= 1 // payload of the second
+		got, err := Validate(bytes.NewReader(b))
+		if err != nil || len(got) != 2 {
+			t.Fatalf("unexpected findings %+v (%v)", got, err)
+		}
+		if got[0].Check != container.CheckCRC || got[0].Offset != 21 || got[1].Path != "IDAT[1]" || got[1].Offset != 34 {
+			t.Fatalf("unexpected findings %+v", got)
+		}
+	})
+
+	t.Run("reports order and missing chunks", func(t *testing.T) {
+		got, err := Validate(bytes.NewReader(build(t, "IDAT", "IHDR")))
+		if err != nil || len(got) != 2 {
+			t.Fatalf("unexpected findings %+v (%v)", got, err)
+		}
+		if got[0].Check != container.CheckOrder || got[0].Severity != container.Warning || got[0].Path != "IHDR" {
+			t.Fatalf("unexpected order finding %+v", got[0])
+		}
+		if got[1].Check != container.CheckRequired || got[1].Path != "IEND" {
+			t.Fatalf("unexpected required finding %+v", got[1])
+		}
+	})
+
+	t.Run("reports truncated chunks", func(t *testing.T) {
+		b := build(t, "IHDR", "IEND")
+		got, err := Validate(bytes.NewReader(b[:len(b)-2]))
+		if err != nil || len(got) != 1 {
+			t.Fatalf("unexpected findings %+v (%v)", got, err)
+		}
+		if got[0].Check != container.CheckSize || got[0].Path != "IEND" || got[0].Severity != container.Error {
+			t.Fatalf("unexpected finding %+v", got[0])
+		}
+	})
+
+	t.Run("rejects other streams", func(t *testing.T) {
+		if _, err := Validate(bytes.NewReader([]byte("RIFF"))); err != ErrSignature {
+			t.Fatalf("expected ErrSignature, got %v", err)
+		}
+	})
+}
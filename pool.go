@@ -0,0 +1,180 @@
+package chunk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBufferSize is the length of the buffers handed out by the default
+// pool.
+const DefaultBufferSize = 32 << 10
+
+// BufferPool supplies the scratch buffers used to skip and copy payloads
+// (Done, Jump, WriteTo, ReadFrom, the scanner and container.CopyChunk) and the
+// chunk buffers of container writers without a seekable output. Get may
+// return a buffer of any length; callers use its full capacity. Put hands a
+// buffer back once nothing references it any more, possibly with a different
+// capacity than it was handed out with.
+type BufferPool interface {
+	Get() []byte
+	Put([]byte)
+}
+
+// NewBufferPool returns a BufferPool backed by a sync.Pool that hands out
+// buffers of size bytes and drops smaller ones put back.
+func NewBufferPool(size int) BufferPool {
+	return &syncPool{size: size}
+}
+
+type syncPool struct {
+	size int
+	p    sync.Pool
+}
+
+func (p *syncPool) Get() []byte {
+	if b, ok := p.p.Get().(*[]byte); ok {
+		return *b
+	}
+	return make([]byte, p.size)
+}
+
+func (p *syncPool) Put(b []byte) {
+	if cap(b) < p.size {
+		return
+	}
+	b = b[:cap(b)]
+	p.p.Put(&b)
+}
+
+var (
+	defaultPool = NewBufferPool(DefaultBufferSize)
+	bufferPool  atomic.Pointer[BufferPool]
+)
+
+// SetBufferPool replaces the pool used by this package and its
+// sub-packages, e.g. to share buffers with the rest of a server. A nil pool
+// restores the default. It is safe to call concurrently with copies, but
+// buffers are put back into the pool current at that time, so buffers in
+// use during the swap end up in the new pool, which must take (or drop)
+// buffers it did not hand out.
+func SetBufferPool(p BufferPool) {
+	if p == nil {
+		bufferPool.Store(nil)
+		return
+	}
+	bufferPool.Store(&p)
+}
+
+func currentPool() BufferPool {
+	if p := bufferPool.Load(); p != nil {
+		return *p
+	}
+	return defaultPool
+}
+
+// GetBuffer returns a buffer from the current pool, extended to its full
+// capacity and never empty.
+func GetBuffer() []byte {
+	b := currentPool().Get()
+	if cap(b) == 0 {
+		return make([]byte, DefaultBufferSize)
+	}
+	return b[:cap(b)]
+}
+
+// PutBuffer returns a buffer obtained from GetBuffer to the current pool.
+func PutBuffer(b []byte) {
+	if cap(b) > 0 {
+		currentPool().Put(b)
+	}
+}
+
+// discard reads and drops up to n bytes from r in slices of a pooled buffer.
+// Like io.CopyN it returns io.EOF when r ends early. A non-nil ctx is checked
+// before every slice and progress, if set, is called after every slice with
+// the bytes skipped so far.
+func discard(ctx context.Context, r io.Reader, n int64, progress func(skipped, total int64)) (int64, error) {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	var done int64
+	for done < n {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return done, err
+			}
+		}
+		m, err := r.Read(buf[:min(int64(len(buf)), n-done)])
+		done += int64(m)
+		if m > 0 && progress != nil {
+			progress(done, n)
+		}
+		if err != nil {
+			if err == io.EOF && done == n {
+				err = nil
+			}
+			return done, err
+		}
+	}
+	return done, nil
+}
+
+// WriteTo copies the chunk to w until its reader returns io.EOF, through a
+// pooled buffer. It implements io.WriterTo, so io.Copy from a chunk does not
+// allocate.
+func (ch *Reader) WriteTo(w io.Writer) (int64, error) {
+	if ch == nil || ch.R == nil {
+		return 0, errors.New("nil Reader/reader pointer")
+	}
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	var done int64
+	for {
+		n, err := ch.Read(buf)
+		if n > 0 {
+			m, werr := w.Write(buf[:n])
+			done += int64(m)
+			if werr != nil {
+				return done, werr
+			}
+			if m < n {
+				return done, io.ErrShortWrite
+			}
+		}
+		if err == io.EOF {
+			return done, nil
+		}
+		if err != nil {
+			return done, err
+		}
+	}
+}
+
+// ReadFrom copies r into the payload until EOF through a pooled buffer. It
+// implements io.ReaderFrom.
+func (ch *Writer) ReadFrom(r io.Reader) (int64, error) {
+	if ch == nil || ch.W == nil {
+		return 0, errors.New("nil Writer/writer pointer")
+	}
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	var done int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			m, werr := ch.Write(buf[:n])
+			done += int64(m)
+			if werr != nil {
+				return done, werr
+			}
+		}
+		if err == io.EOF {
+			return done, nil
+		}
+		if err != nil {
+			return done, err
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package chunk
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// countingPool hands out small buffers so copies take several rounds.
+type countingPool struct {
+	gets, puts int
+}
+
+func (p *countingPool) Get() []byte {
+	p.gets++
+	return make([]byte, 3)
+}
+
+func (p *countingPool) Put(b []byte) {
+	p.puts++
+}
+
+func usePool(t *testing.T, p BufferPool) {
+	t.Helper()
+	SetBufferPool(p)
+	t.Cleanup(func() { SetBufferPool(nil) })
+}
+
+func TestBufferPool(t *testing.T) {
+	t.Run("default pool recycles buffers", func(t *testing.T) {
+		p := NewBufferPool(16)
+		b := p.Get()
+		if len(b) != 16 {
+			t.Fatalf("expected 16 byte buffer, got %d", len(b))
+		}
+		p.Put(b[:0])
+		if b := p.Get(); len(b) != 16 {
+			t.Fatalf("expected buffer at full capacity, got %d", len(b))
+		}
+		p.Put(make([]byte, 4)) // too small, dropped
+	})
+
+	t.Run("copies use the configured pool", func(t *testing.T) {
+		p := &countingPool{}
+		usePool(t, p)
+
+		ch := &Reader{Size: 10, R: strings.NewReader("0123456789")}
+		if err := ch.Jump(4); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out bytes.Buffer
+		n, err := io.Copy(&out, ch)
+		if err != nil || n != 6 || out.String() != "456789" {
+			t.Fatalf("unexpected copy %d %q %v", n, out.String(), err)
+		}
+
+		var dst bytes.Buffer
+		w := &Writer{W: &dst}
+		if n, err := io.Copy(w, struct{ io.Reader }{strings.NewReader("payload")}); err != nil || n != 7 {
+			t.Fatalf("unexpected copy %d %v", n, err)
+		}
+		if w.Size != 7 || dst.String() != "payload" {
+			t.Fatalf("unexpected payload %q (size %d)", dst.String(), w.Size)
+		}
+		if p.gets != 3 || p.puts != 3 {
+			t.Fatalf("expected 3 gets and puts, got %d and %d", p.gets, p.puts)
+		}
+	})
+
+	t.Run("jump reports short payloads", func(t *testing.T) {
+		usePool(t, &countingPool{})
+		ch := &Reader{Size: 10, R: strings.NewReader("01234")}
+		if err := ch.Jump(8); err != io.EOF {
+			t.Fatalf("expected io.EOF, got %v", err)
+		}
+		if ch.Pos != 5 {
+			t.Fatalf("expected Pos=5, got %d", ch.Pos)
+		}
+	})
+}
+
+func BenchmarkReader_Done(b *testing.B) {
+	data := make([]byte, 1<<20)
+	b.ReportAllocs()
+	for b.Loop() {
+		ch := &Reader{Size: len(data), R: bytes.NewReader(data)}
+		ch.Done()
+	}
+}
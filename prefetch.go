@@ -0,0 +1,109 @@
+package chunk
+
+import (
+	"io"
+	"sync"
+)
+
+// Prefetcher reads ahead of its consumer in a background goroutine. Put
+// between a slow source and a Scanner, the next chunk header and the start
+// of its payload are already on their way while the current chunk is being
+// processed, which hides the latency of network and object storage
+// streams:
+//
+//	p := chunk.NewPrefetcher(resp.Body, 1<<20)
+//	defer p.Close()
+//	s := chunk.NewScanner(p, binary.LittleEndian)
+type Prefetcher struct {
+	src io.Reader
+
+	mu     sync.Mutex
+	cond   sync.Cond
+	buf    []byte // ring buffer of prefetched bytes
+	r, n   int    // start and length of the buffered bytes
+	err    error  // error of the source, returned once buf is drained
+	closed bool
+}
+
+// NewPrefetcher returns a Prefetcher that keeps up to ahead bytes of r
+// buffered, DefaultBufferSize if ahead is not positive.
+func NewPrefetcher(r io.Reader, ahead int) *Prefetcher {
+	if ahead <= 0 {
+		ahead = DefaultBufferSize
+	}
+	p := &Prefetcher{src: r, buf: make([]byte, ahead)}
+	p.cond.L = &p.mu
+	go p.fill()
+	return p
+}
+
+// fill reads from the source into the free part of the ring until the
+// source fails or the Prefetcher is closed. Only the region outside [r, r+n)
+// is written, so the source is read without holding the lock.
+func (p *Prefetcher) fill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		for p.n == len(p.buf) && !p.closed {
+			p.cond.Wait()
+		}
+		if p.closed {
+			return
+		}
+		w := (p.r + p.n) % len(p.buf)
+		free := min(len(p.buf)-p.n, len(p.buf)-w)
+		p.mu.Unlock()
+		m, err := p.src.Read(p.buf[w : w+free])
+		p.mu.Lock()
+		p.n += m
+		if err != nil {
+			p.err = err
+		}
+		p.cond.Broadcast()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Read implements io.Reader. Errors of the source, including io.EOF, are
+// returned once everything read before them has been consumed.
+func (p *Prefetcher) Read(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.n == 0 && p.err == nil && !p.closed {
+		p.cond.Wait()
+	}
+	switch {
+	case p.closed:
+		return 0, io.ErrClosedPipe
+	case p.n == 0:
+		return 0, p.err
+	}
+	k := copy(b, p.buf[p.r:min(p.r+p.n, len(p.buf))])
+	p.r = (p.r + k) % len(p.buf)
+	p.n -= k
+	p.cond.Broadcast()
+	return k, nil
+}
+
+// Buffered returns the number of bytes read ahead and not yet consumed.
+func (p *Prefetcher) Buffered() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.n
+}
+
+// Close stops prefetching; later reads return io.ErrClosedPipe. A read of
+// the source already in progress is not interrupted, and the source is not
+// closed.
+func (p *Prefetcher) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,93 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// trickleReader hands out at most 16 bytes per read and counts them.
+type trickleReader struct {
+	r    io.Reader
+	read atomic.Int64
+}
+
+func (t *trickleReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p[:min(len(p), 16)])
+	t.read.Add(int64(n))
+	return n, err
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrefetcher(t *testing.T) {
+	t.Run("reads ahead up to the limit", func(t *testing.T) {
+		data := make([]byte, 1000)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		src := &trickleReader{r: bytes.NewReader(data)}
+		p := NewPrefetcher(src, 64)
+		defer p.Close()
+
+		waitFor(t, func() bool { return p.Buffered() == 64 })
+		buf := make([]byte, 40)
+		if n, _ := io.ReadFull(p, buf); n != 40 || !bytes.Equal(buf, data[:40]) {
+			t.Fatalf("unexpected data % x", buf[:n])
+		}
+		waitFor(t, func() bool { return src.read.Load() == 104 })
+		time.Sleep(5 * time.Millisecond)
+		if got := src.read.Load(); got != 104 {
+			t.Fatalf("expected the source to stay 64 bytes ahead, read %d", got)
+		}
+
+		rest, err := io.ReadAll(p)
+		if err != nil || !bytes.Equal(rest, data[40:]) {
+			t.Fatalf("unexpected rest (%d bytes): %v", len(rest), err)
+		}
+	})
+
+	t.Run("feeds a scanner", func(t *testing.T) {
+		p := NewPrefetcher(&trickleReader{r: bytes.NewReader(scannerTestData)}, 8)
+		defer p.Close()
+		s := NewScanner(p, binary.LittleEndian)
+		var ids []string
+		for s.Next() {
+			ids = append(ids, string(s.Chunk().ID[:]))
+		}
+		if s.Err() != nil || len(ids) != 3 {
+			t.Fatalf("unexpected chunks %q: %v", ids, s.Err())
+		}
+	})
+
+	t.Run("passes errors after the data", func(t *testing.T) {
+		boom := errors.New("boom")
+		p := NewPrefetcher(io.MultiReader(bytes.NewReader([]byte("abc")), iotest.ErrReader(boom)), 0)
+		defer p.Close()
+		b, err := io.ReadAll(p)
+		if string(b) != "abc" || err != boom {
+			t.Fatalf("expected abc and boom, got %q and %v", b, err)
+		}
+	})
+
+	t.Run("fails reads after Close", func(t *testing.T) {
+		p := NewPrefetcher(bytes.NewReader([]byte("abc")), 0)
+		p.Close()
+		if _, err := p.Read(make([]byte, 1)); err != io.ErrClosedPipe {
+			t.Fatalf("expected io.ErrClosedPipe, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package chunk
+
+import (
+	"io"
+	"time"
+)
+
+// timeNow and timeSleep are replaced by tests.
+var (
+	timeNow   = time.Now
+	timeSleep = time.Sleep
+)
+
+// rateLimited throttles reads to rate bytes per second on average, measured
+// from the first read.
+type rateLimited struct {
+	r     io.Reader
+	rate  int64
+	start time.Time
+	total int64
+}
+
+// WithRateLimit returns a reader that passes reads through to r at no more
+// than bytesPerSec bytes per second, so background jobs can walk large
+// archives without saturating shared bandwidth. Reads are cut to a tenth of
+// a second's worth of data to keep the stream smooth. Wrap the source of a
+// Scanner, or the R of a single Reader, with it; a non-positive rate
+// returns r unchanged.
+func WithRateLimit(r io.Reader, bytesPerSec int64) io.Reader {
+	if bytesPerSec <= 0 {
+		return r
+	}
+	return &rateLimited{r: r, rate: bytesPerSec}
+}
+
+func (l *rateLimited) Read(p []byte) (int, error) {
+	if l.start.IsZero() {
+		l.start = timeNow()
+	}
+	if limit := max(l.rate/10, 1); int64(len(p)) > limit {
+		p = p[:limit]
+	}
+	n, err := l.r.Read(p)
+	l.total += int64(n)
+	due := l.start.Add(time.Duration(float64(l.total) / float64(l.rate) * float64(time.Second)))
+	if wait := due.Sub(timeNow()); wait > 0 {
+		timeSleep(wait)
+	}
+	return n, err
+}
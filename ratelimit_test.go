@@ -0,0 +1,65 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when the code under test sleeps.
+func fakeClock(t *testing.T) *time.Duration {
+	t.Helper()
+	var slept time.Duration
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return start.Add(slept) }
+	timeSleep = func(d time.Duration) { slept += d }
+	t.Cleanup(func() { timeNow, timeSleep = time.Now, time.Sleep })
+	return &slept
+}
+
+func TestWithRateLimit(t *testing.T) {
+	t.Run("spreads reads over time", func(t *testing.T) {
+		slept := fakeClock(t)
+		r := WithRateLimit(bytes.NewReader(make([]byte, 5000)), 1000)
+		var sizes []int
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				sizes = append(sizes, n)
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+		if len(sizes) != 50 || sizes[0] != 100 {
+			t.Fatalf("expected 50 reads of 100 bytes, got %v", sizes)
+		}
+		if *slept != 5*time.Second {
+			t.Fatalf("expected 5s of throttling, got %v", *slept)
+		}
+	})
+
+	t.Run("throttles a scanner", func(t *testing.T) {
+		slept := fakeClock(t)
+		s := NewScanner(WithRateLimit(bytes.NewReader(scannerTestData), 10), binary.LittleEndian)
+		for s.Next() {
+		}
+		if s.Err() != nil {
+			t.Fatalf("unexpected error: %v", s.Err())
+		}
+		// 31 bytes at 10 bytes per second
+		if *slept < 3*time.Second || *slept > 3200*time.Millisecond {
+			t.Fatalf("expected about 3.1s of throttling, got %v", *slept)
+		}
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		src := bytes.NewReader(nil)
+		if WithRateLimit(src, 0) != io.Reader(src) {
+			t.Fatal("expected the reader to be returned unchanged")
+		}
+	})
+}
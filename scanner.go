@@ -0,0 +1,340 @@
+package chunk
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	// ErrTooManyChunks is returned by Scanner.Next once MaxChunks chunks
+	// have been read.
+	ErrTooManyChunks = errors.New("too many chunks")
+	// ErrDeclaredSize is returned by Scanner.Next when the declared chunk
+	// sizes add up to more than MaxDeclared.
+	ErrDeclaredSize = errors.New("declared chunk sizes exceed the limit")
+	// ErrPadding is returned by ZeroPad for pad bytes other than 0x00.
+	ErrPadding = errors.New("chunk pad byte is not zero")
+	// ErrNotClosed is returned by Scanner.Next with RequireClose when the
+	// previous chunk was not closed.
+	ErrNotClosed = errors.New("previous chunk was not closed")
+)
+
+// Scanner reads consecutive chunks from a stream. Each chunk is an ID, a
+// 32-bit size in the scanner's byte order and the payload, followed by a pad
+// byte when the size is odd; other layouts are read with NewSpecScanner. It
+// is used the same way as bufio.Scanner:
+//
+//	s := chunk.NewScanner(r, binary.LittleEndian)
+//	for s.Next() {
+//		ch := s.Chunk()
+//		// ...
+//	}
+//	if err := s.Err(); err != nil {
+//		// ...
+//	}
+//
+// The previous chunk is finished (see Reader.Done) when Next is called, so
+// handlers may stop reading a chunk at any point. With RequireClose they
+// have to close every chunk instead, typically with defer ch.Close().
+type Scanner struct {
+	// Sizes64 maps chunk IDs to their real sizes for chunks whose header
+	// size is 0xFFFFFFFF, as announced by a RF64/BW64 ds64 chunk.
+	Sizes64 map[FourCC]int64
+	// Size64, if set, is used instead of Sizes64. It is called once per
+	// chunk whose header size is 0xFFFFFFFF, in stream order, so tables
+	// listing an ID once per chunk can hand out a size per occurrence.
+	Size64 func(id FourCC) (int64, bool)
+	// Context and Progress are handed to every chunk and used when Next
+	// skips the unread rest of the previous one, see Reader.
+	Context  context.Context
+	Progress func(skipped, total int64)
+	// MaxChunks, if positive, is the number of chunks after which Next fails
+	// with ErrTooManyChunks.
+	MaxChunks int
+	// MaxDeclared, if positive, bounds the sum of the declared payload sizes.
+	// Services accepting untrusted uploads set it to a small multiple of the
+	// upload size, so crafted headers fail fast with ErrDeclaredSize.
+	MaxDeclared int64
+	// ValidateID, if set, is called with the ID of every chunk; Next fails
+	// with its error. chunk.ValidateFourCC rejects IDs with NULs or other
+	// non-printable bytes, which usually means the stream is corrupt.
+	ValidateID func(id string) error
+	// ValidatePad, if set, is called with the pad bytes following every
+	// padded chunk, the chunk ID and the offset of the pad relative to
+	// where the scanner started reading; Next fails with its error. ZeroPad
+	// rejects anything but 0x00, which some decoders misparse and which may
+	// hide data; a function collecting the offsets reports every offender.
+	ValidatePad func(id FourCC, off int64, pad []byte) error
+	// Warn, if set, is called for oddities Next tolerates: pad bytes other
+	// than 0x00 when ValidatePad accepts them, and a pad byte missing at
+	// the end of the stream. Offsets count like those of ValidatePad.
+	Warn func(Warning)
+	// RequireClose makes Next fail with ErrNotClosed unless the previous
+	// chunk was closed, so handlers forgetting a chunk show up. StrictClose
+	// is handed to every chunk, see Reader.
+	RequireClose bool
+	StrictClose  bool
+
+	r    io.Reader
+	spec HeaderSpec
+	hdr  [12]byte
+	cur  *Reader
+	lr   *io.LimitedReader
+	sec  *io.SectionReader
+	off  int64
+	next int64
+	err  error
+
+	chunks   int
+	declared int64
+}
+
+// NewScanner returns a Scanner reading chunks from r with sizes in the given
+// byte order.
+func NewScanner(r io.Reader, order binary.ByteOrder) *Scanner {
+	spec := RIFFHeader
+	spec.Order = order
+	return &Scanner{r: r, spec: spec}
+}
+
+// NewSpecScanner returns a Scanner reading chunks with the given header
+// layout from r. An invalid spec is reported by Err.
+func NewSpecScanner(r io.Reader, spec HeaderSpec) *Scanner {
+	s := &Scanner{r: r, spec: spec}
+	s.err = spec.Validate()
+	return s
+}
+
+// NewScannerAt returns a Scanner reading chunks with the given header layout
+// from the n bytes of ra starting at off. Each chunk is handed out as an
+// io.SectionReader of its payload: nothing is drained between chunks and
+// Readers stay valid, independent of each other, after Next moved on.
+// Offsets are relative to off.
+func NewScannerAt(ra io.ReaderAt, off, n int64, spec HeaderSpec) *Scanner {
+	s := NewSpecScanner(nil, spec)
+	s.sec = io.NewSectionReader(ra, off, n)
+	return s
+}
+
+// Next advances to the next chunk, finishing the current one. It returns
+// false at the end of the stream or on error.
+func (s *Scanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	if s.cur != nil {
+		if s.RequireClose && !s.cur.closed {
+			s.err = ErrNotClosed
+			return false
+		}
+		if !s.finish() {
+			return false
+		}
+	}
+
+	hdr := s.hdr[:s.spec.HeaderSize()]
+	n, err := s.readHeader(hdr)
+	if err != nil {
+		if err != io.EOF || n != 0 {
+			s.setErr(err)
+		}
+		return false
+	}
+	s.off = s.next
+	id, size, err := s.spec.ParseHeader(hdr)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	if s.spec.SizeSize == 4 && s.spec.rawSize(hdr) == math.MaxUint32 {
+		lookup := func(id FourCC) (int64, bool) {
+			size, ok := s.Sizes64[id]
+			return size, ok
+		}
+		if s.Size64 != nil {
+			lookup = s.Size64
+		}
+		if size64, ok := lookup(FourCC(id)); ok {
+			size = size64
+		}
+	}
+	// offsets and Reader.Size have to hold the size and its pad byte
+	if size < 0 || size > math.MaxInt-1 || size > math.MaxInt64-1-s.off-int64(len(hdr)) {
+		s.err = ErrSizeOverflow
+		return false
+	}
+	if s.ValidateID != nil {
+		if err := s.ValidateID(string(id[:s.spec.IDSize])); err != nil {
+			s.err = err
+			return false
+		}
+	}
+	s.chunks++
+	s.declared += size
+	switch {
+	case s.MaxChunks > 0 && s.chunks > s.MaxChunks:
+		s.err = ErrTooManyChunks
+		return false
+	case s.MaxDeclared > 0 && s.declared > s.MaxDeclared:
+		s.err = ErrDeclaredSize
+		return false
+	}
+	ch := &Reader{ID: id, Size: int(size), Context: s.Context, Progress: s.Progress, StrictClose: s.StrictClose}
+	if s.sec != nil {
+		ch.R = io.NewSectionReader(s.sec, s.off+int64(len(hdr)), size)
+	} else {
+		s.lr = &io.LimitedReader{R: s.r, N: size}
+		ch.R = s.lr
+	}
+	s.cur = ch
+	s.next = s.off + int64(len(hdr)) + size
+	return true
+}
+
+// Chunk returns the current chunk.
+func (s *Scanner) Chunk() *Reader {
+	return s.cur
+}
+
+// Offset returns the offset of the current chunk header relative to where
+// the scanner started reading.
+func (s *Scanner) Offset() int64 {
+	return s.off
+}
+
+// Err returns the first error encountered, or nil at a clean end of stream.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// readHeader reads the next header from the stream, or from the section at
+// the offset following the previous chunk.
+func (s *Scanner) readHeader(hdr []byte) (int, error) {
+	if s.sec == nil {
+		return io.ReadFull(s.r, hdr)
+	}
+	n, err := s.sec.ReadAt(hdr, s.next)
+	if err == io.EOF && n > 0 && n < len(hdr) {
+		err = io.ErrUnexpectedEOF
+	}
+	if n == len(hdr) {
+		err = nil
+	}
+	return n, err
+}
+
+// finish drains the current chunk and its padding. Missing padding at the
+// very end of the stream is tolerated, as many writers omit it.
+func (s *Scanner) finish() bool {
+	ch := s.cur
+	s.cur = nil
+	if s.sec != nil {
+		// nothing to drain, but a truncated payload is still an error
+		if ch.Size > 0 {
+			var last [1]byte
+			if _, err := s.sec.ReadAt(last[:], s.next-1); err != nil {
+				s.setErr(err)
+				return false
+			}
+		}
+		pad := s.spec.Padding(int64(ch.Size))
+		if pad > 0 && (s.ValidatePad != nil || s.Warn != nil) {
+			b := make([]byte, pad)
+			// a pad missing at the end is tolerated as below
+			if n, _ := s.sec.ReadAt(b, s.next); n < len(b) {
+				s.warn(WarnPad, ch.ID, s.next, "missing pad byte at the end")
+			} else if !s.checkPad(ch.ID, b) {
+				return false
+			}
+		}
+		s.next += pad
+		return true
+	}
+	// drain through the limit rather than Done so reads that bypassed the
+	// Reader (and its Pos) are accounted for as well
+	lr := s.lr
+	if _, err := discard(s.Context, lr, lr.N, s.Progress); err != nil && err != io.EOF {
+		s.setErr(err)
+		return false
+	}
+	if lr.N > 0 {
+		s.setErr(io.ErrUnexpectedEOF)
+		return false
+	}
+	pad := s.spec.Padding(int64(ch.Size))
+	if pad == 0 {
+		return true
+	}
+	if s.ValidatePad == nil && s.Warn == nil {
+		if _, err := io.CopyN(io.Discard, s.r, pad); err != nil {
+			if err != io.EOF {
+				s.setErr(err)
+			}
+			return false
+		}
+		s.next += pad
+		return true
+	}
+	b := make([]byte, pad)
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			s.setErr(err)
+		} else {
+			s.warn(WarnPad, ch.ID, s.next, "missing pad byte at the end")
+		}
+		return false
+	}
+	if !s.checkPad(ch.ID, b) {
+		return false
+	}
+	s.next += pad
+	return true
+}
+
+// checkPad hands the pad bytes at s.next to ValidatePad and warns about
+// those it accepts that are not zero. It returns false if they are
+// rejected.
+func (s *Scanner) checkPad(id [4]byte, pad []byte) bool {
+	if s.ValidatePad != nil {
+		if err := s.ValidatePad(FourCC(id), s.next, pad); err != nil {
+			s.err = err
+			return false
+		}
+	}
+	for _, c := range pad {
+		if c != 0 {
+			s.warn(WarnPad, id, s.next, fmt.Sprintf("pad byte is 0x%02x", c))
+			break
+		}
+	}
+	return true
+}
+
+// warn reports a warning to Warn, if set.
+func (s *Scanner) warn(kind WarningKind, id [4]byte, off int64, msg string) {
+	if s.Warn != nil {
+		s.Warn(Warning{Kind: kind, Offset: off, ID: FourCC(id), Message: msg})
+	}
+}
+
+// ZeroPad is a Scanner.ValidatePad function returning ErrPadding unless all
+// pad bytes are 0x00.
+func ZeroPad(id FourCC, off int64, pad []byte) error {
+	for _, b := range pad {
+		if b != 0 {
+			return ErrPadding
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) setErr(err error) {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	s.err = err
+}
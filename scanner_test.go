@@ -0,0 +1,350 @@
+package chunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+var scannerTestData = []byte{
+	'f', 'm', 't', ' ', 3, 0, 0, 0, 1, 2, 3, 0,
+	'd', 'a', 't', 'a', 2, 0, 0, 0, 4, 5,
+	'o', 'd', 'd', ' ', 1, 0, 0, 0, 6, // final pad byte omitted
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("iterates chunks and skips pad bytes", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+
+		var ids []string
+		var offsets []int64
+		for s.Next() {
+			ch := s.Chunk()
+			ids = append(ids, string(ch.ID[:]))
+			offsets = append(offsets, s.Offset())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 3 || ids[0] != "fmt " || ids[1] != "data" || ids[2] != "odd " {
+			t.Fatalf("unexpected chunks %q", ids)
+		}
+		if offsets[0] != 0 || offsets[1] != 12 || offsets[2] != 22 {
+			t.Fatalf("unexpected offsets %v", offsets)
+		}
+	})
+
+	t.Run("stops skipping when the context is canceled", func(t *testing.T) {
+		data := make([]byte, 8+100000)
+		copy(data, "data")
+		binary.LittleEndian.PutUint32(data[4:], 100000)
+		ctx, cancel := context.WithCancel(context.Background())
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		s.Context = ctx
+		var calls int
+		s.Progress = func(skipped, total int64) {
+			calls++
+			if total != 100000 {
+				t.Fatalf("unexpected total %d", total)
+			}
+			cancel()
+		}
+		if !s.Next() {
+			t.Fatalf("expected a chunk, got %v", s.Err())
+		}
+		if s.Next() {
+			t.Fatal("expected no further chunk")
+		}
+		if !errors.Is(s.Err(), context.Canceled) || calls != 1 {
+			t.Fatalf("expected cancellation after one slice, got %v after %d", s.Err(), calls)
+		}
+	})
+
+	t.Run("limits the chunk count", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		s.MaxChunks = 2
+		n := 0
+		for s.Next() {
+			n++
+		}
+		if n != 2 || s.Err() != ErrTooManyChunks {
+			t.Fatalf("expected 2 chunks and ErrTooManyChunks, got %d and %v", n, s.Err())
+		}
+	})
+
+	t.Run("limits the declared sizes", func(t *testing.T) {
+		// a single header claiming 4 GiB
+		data := []byte{'d', 'a', 't', 'a', 0xff, 0xff, 0xff, 0xff, 0}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		s.MaxDeclared = 10 * int64(len(data))
+		if s.Next() || s.Err() != ErrDeclaredSize {
+			t.Fatalf("expected ErrDeclaredSize, got %v", s.Err())
+		}
+
+		s = NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		s.MaxDeclared = 6
+		for s.Next() {
+		}
+		if s.Err() != nil {
+			t.Fatalf("unexpected error: %v", s.Err())
+		}
+	})
+
+	t.Run("rejects sizes that overflow offsets", func(t *testing.T) {
+		spec := HeaderSpec{IDSize: 1, SizeSize: 8, Order: binary.BigEndian}
+		s := NewSpecScanner(bytes.NewReader([]byte{'x', 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}), spec)
+		if s.Next() || s.Err() != ErrSizeOverflow {
+			t.Fatalf("expected ErrSizeOverflow, got %v", s.Err())
+		}
+	})
+
+	t.Run("validates IDs", func(t *testing.T) {
+		data := []byte{'d', 'a', 't', 'a', 0, 0, 0, 0, 'a', 0, 'b', 'c', 0, 0, 0, 0}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		s.ValidateID = ValidateFourCC
+		if !s.Next() || s.Next() || s.Err() != ErrInvalidFourCC {
+			t.Fatalf("expected ErrInvalidFourCC for the second chunk, got %v", s.Err())
+		}
+	})
+
+	t.Run("validates pad bytes", func(t *testing.T) {
+		data := []byte{
+			'f', 'm', 't', ' ', 1, 0, 0, 0, 1, 0,
+			'd', 'a', 't', 'a', 1, 0, 0, 0, 2, 'x',
+			'o', 'd', 'd', ' ', 1, 0, 0, 0, 3, // final pad byte omitted
+		}
+		for _, s := range []*Scanner{
+			NewScanner(bytes.NewReader(data), binary.LittleEndian),
+			NewScannerAt(bytes.NewReader(data), 0, int64(len(data)), RIFFHeader),
+		} {
+			var offenders []int64
+			s.ValidatePad = func(id FourCC, off int64, pad []byte) error {
+				if err := ZeroPad(id, off, pad); err != nil {
+					offenders = append(offenders, off)
+				}
+				return nil
+			}
+			for s.Next() {
+			}
+			if s.Err() != nil || len(offenders) != 1 || offenders[0] != 19 {
+				t.Fatalf("expected an offender at 19, got %v (%v)", offenders, s.Err())
+			}
+		}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		s.ValidatePad = ZeroPad
+		if !s.Next() || !s.Next() || s.Next() || s.Err() != ErrPadding {
+			t.Fatalf("expected ErrPadding after data, got %v", s.Err())
+		}
+	})
+
+	t.Run("limits reads to the chunk", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		if !s.Next() {
+			t.Fatalf("expected a chunk, got %v", s.Err())
+		}
+		buf, err := io.ReadAll(s.Chunk())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf, []byte{1, 2, 3}) {
+			t.Fatalf("expected 01 02 03, got % x", buf)
+		}
+		if !s.Chunk().IsFullyRead() {
+			t.Fatal("expected chunk to be fully read")
+		}
+	})
+
+	t.Run("finishes partially read chunks", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		s.Next()
+		s.Chunk().ReadByte()
+		if !s.Next() {
+			t.Fatalf("expected a second chunk, got %v", s.Err())
+		}
+		b, err := s.Chunk().ReadByte()
+		if err != nil || b != 4 {
+			t.Fatalf("expected 0x04, got 0x%02x, %v", b, err)
+		}
+	})
+
+	t.Run("requires closed chunks", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		s.RequireClose = true
+		if !s.Next() {
+			t.Fatalf("expected chunk: %v", s.Err())
+		}
+		s.Chunk().Close()
+		if !s.Next() {
+			t.Fatalf("expected chunk: %v", s.Err())
+		}
+		if s.Next() || s.Err() != ErrNotClosed {
+			t.Fatalf("expected ErrNotClosed, got %v", s.Err())
+		}
+	})
+
+	t.Run("hands strict closing to chunks", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(scannerTestData), binary.LittleEndian)
+		s.StrictClose = true
+		var errs []error
+		for s.Next() {
+			ch := s.Chunk()
+			if ch.ID == [4]byte{'d', 'a', 't', 'a'} {
+				io.ReadAll(ch)
+			}
+			errs = append(errs, ch.Close())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(errs) != 3 || errs[0] != ErrUnread || errs[1] != nil || errs[2] != ErrUnread {
+			t.Fatalf("unexpected close errors %v", errs)
+		}
+	})
+
+	t.Run("reads big-endian sizes", func(t *testing.T) {
+		data := []byte{'C', 'O', 'M', 'M', 0, 0, 0, 2, 9, 9}
+		s := NewScanner(bytes.NewReader(data), binary.BigEndian)
+		if !s.Next() || s.Chunk().Size != 2 {
+			t.Fatalf("expected a 2 byte chunk, got %v", s.Err())
+		}
+	})
+
+	t.Run("applies 64-bit sizes", func(t *testing.T) {
+		data := []byte{'d', 'a', 't', 'a', 0xFF, 0xFF, 0xFF, 0xFF, 1, 2, 3, 4}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		s.Sizes64 = map[FourCC]int64{{'d', 'a', 't', 'a'}: 4}
+		if !s.Next() || s.Chunk().Size != 4 {
+			t.Fatalf("expected a 4 byte chunk, got %v", s.Err())
+		}
+		if s.Next() {
+			t.Fatal("expected end of stream")
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("asks Size64 per chunk", func(t *testing.T) {
+		data := []byte{'d', 'a', 't', 'a', 0xFF, 0xFF, 0xFF, 0xFF, 1, 2, 'd', 'a', 't', 'a', 0xFF, 0xFF, 0xFF, 0xFF, 3}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		sizes := []int64{2, 1}
+		s.Size64 = func(FourCC) (int64, bool) {
+			n := sizes[0]
+			sizes = sizes[1:]
+			return n, true
+		}
+		var got []int
+		for s.Next() {
+			got = append(got, s.Chunk().Size)
+		}
+		if err := s.Err(); err != nil || len(got) != 2 || got[0] != 2 || got[1] != 1 {
+			t.Fatalf("unexpected sizes %v, %v", got, err)
+		}
+	})
+
+	t.Run("reports truncated header", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader([]byte{'d', 'a', 't'}), binary.LittleEndian)
+		if s.Next() {
+			t.Fatal("expected no chunk")
+		}
+		if s.Err() != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+	})
+
+	t.Run("reports truncated payload", func(t *testing.T) {
+		data := []byte{'d', 'a', 't', 'a', 10, 0, 0, 0, 1, 2}
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		if !s.Next() {
+			t.Fatalf("expected a chunk, got %v", s.Err())
+		}
+		if s.Next() {
+			t.Fatal("expected no second chunk")
+		}
+		if s.Err() != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+	})
+}
+
+func TestNewScannerAt(t *testing.T) {
+	// prefix and suffix are outside the section
+	src := append(append([]byte("junk"), scannerTestData...), "more"...)
+
+	t.Run("hands out independent section readers", func(t *testing.T) {
+		s := NewScannerAt(bytes.NewReader(src), 4, int64(len(scannerTestData)), RIFFHeader)
+		var chunks []*Reader
+		var offsets []int64
+		for s.Next() {
+			chunks = append(chunks, s.Chunk())
+			offsets = append(offsets, s.Offset())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(chunks) != 3 || offsets[1] != 12 || offsets[2] != 22 {
+			t.Fatalf("unexpected chunks at %v", offsets)
+		}
+		// read them backwards, long after the scanner moved on
+		for i, want := range []string{"\x06", "\x04\x05", "\x01\x02\x03"} {
+			got, err := io.ReadAll(chunks[2-i])
+			if err != nil || string(got) != want {
+				t.Fatalf("chunk %d: expected % x, got % x (%v)", 2-i, want, got, err)
+			}
+		}
+	})
+
+	t.Run("reports truncated payloads", func(t *testing.T) {
+		s := NewScannerAt(bytes.NewReader(scannerTestData), 0, 10, RIFFHeader)
+		if !s.Next() {
+			t.Fatalf("expected a chunk, got %v", s.Err())
+		}
+		if s.Next() || s.Err() != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+	})
+
+	t.Run("reports truncated headers", func(t *testing.T) {
+		s := NewScannerAt(bytes.NewReader(scannerTestData), 0, 16, RIFFHeader)
+		s.Next()
+		if s.Next() || s.Err() != io.ErrUnexpectedEOF {
+			t.Fatalf("expected io.ErrUnexpectedEOF, got %v", s.Err())
+		}
+	})
+}
+
+func FuzzScanner(f *testing.F) {
+	f.Add(scannerTestData, uint8(0))
+	f.Add([]byte{'d', 'a', 't', 'a', 0xff, 0xff, 0xff, 0xff}, uint8(1))
+	f.Add([]byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint8(2))
+	specs := []HeaderSpec{
+		RIFFHeader,
+		IFFHeader,
+		{IDSize: 1, SizeSize: 8, Order: binary.BigEndian, SizeIncludesHeader: true},
+		{IDSize: 2, SizeSize: 2, Order: binary.LittleEndian, SizeIncludesHeader: true, Align: 4},
+	}
+	f.Fuzz(func(t *testing.T, data []byte, which uint8) {
+		spec := specs[int(which)%len(specs)]
+		s := NewSpecScanner(bytes.NewReader(data), spec)
+		if which&0x80 != 0 {
+			s = NewScannerAt(bytes.NewReader(data), 0, int64(len(data)), spec)
+		}
+		s.Sizes64 = map[FourCC]int64{{'d', 'a', 't', 'a'}: 1 << 40}
+		n := 0
+		for s.Next() {
+			ch := s.Chunk()
+			if ch.Size < 0 || s.Offset() < 0 {
+				t.Fatalf("negative size %d or offset %d", ch.Size, s.Offset())
+			}
+			if _, err := ch.Peek(16); err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+				t.Fatalf("unexpected Peek error: %v", err)
+			}
+			if n++; n > len(data) {
+				t.Fatalf("%d chunks from %d bytes", n, len(data))
+			}
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SniffSize is the number of payload bytes Sniff peeks at.
+const SniffSize = 512
+
+// ContentType is the kind of payload found by Sniff.
+type ContentType int
+
+// Content types told apart by Sniff.
+const (
+	// Binary is anything not recognized as one of the other types.
+	Binary ContentType = iota
+	// Text is UTF-8 text without control characters other than tabs and
+	// line breaks, as in INFO tags.
+	Text
+	// XML is UTF-8 text starting with a tag, as in iXML and axml chunks.
+	XML
+	// JSON is UTF-8 text starting with an object or array.
+	JSON
+	// PNG is a PNG image, such as embedded cover art.
+	PNG
+	// JPEG is a JPEG image.
+	JPEG
+	// ID3 is an ID3v2 tag.
+	ID3
+)
+
+var contentTypeNames = [...]string{"binary", "text", "xml", "json", "png", "jpeg", "id3"}
+
+func (c ContentType) String() string {
+	if c >= 0 && int(c) < len(contentTypeNames) {
+		return contentTypeNames[c]
+	}
+	return "ContentType(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c ContentType) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// IsText reports whether payloads of the type can be shown as text.
+func (c ContentType) IsText() bool {
+	return c == Text || c == XML || c == JSON
+}
+
+// Sniff classifies the payload of r by its first SniffSize bytes, which it
+// peeks at (see Reader.Peek) without consuming them. Text types allow the
+// NUL terminators many writers add. Payloads that cannot be read, and empty
+// ones, are Binary.
+func Sniff(r *Reader) ContentType {
+	b, err := r.Peek(SniffSize)
+	if err != nil || len(b) == 0 {
+		return Binary
+	}
+	complete := r.Size-r.Pos <= len(b)
+	switch {
+	case bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG
+	case bytes.HasPrefix(b, []byte{0xff, 0xd8, 0xff}):
+		return JPEG
+	case len(b) >= 10 && bytes.HasPrefix(b, []byte("ID3")) && b[3] < 0xff && b[4] < 0xff:
+		return ID3
+	}
+	if complete {
+		b = bytes.TrimRight(b, "\x00")
+	} else {
+		// drop a character cut off by the end of the peeked bytes
+		for i := 0; i < utf8.UTFMax-1 && len(b) > 0 && !utf8.Valid(b); i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	if len(b) == 0 || !utf8.Valid(b) || bytes.ContainsFunc(b, isControl) {
+		return Binary
+	}
+	s := bytes.TrimLeft(bytes.TrimPrefix(b, []byte("\ufeff")), " \t\r\n")
+	switch {
+	case len(s) > 1 && s[0] == '<' && (s[1] == '?' || s[1] == '!' || isNameStart(s[1])):
+		return XML
+	case len(s) > 0 && (s[0] == '{' || s[0] == '['):
+		if !complete || json.Valid(s) {
+			return JSON
+		}
+	}
+	return Text
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 && r != '\t' && r != '\n' && r != '\r' || r == 0x7f
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':'
+}
@@ -0,0 +1,59 @@
+package chunk
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		payload string
+		want    ContentType
+	}{
+		{"text", "Take 1\x00", Text},
+		{"XML", "\ufeff\n<?xml version=\"1.0\"?><BWFXML/>", XML},
+		{"XML without declaration", "<ebuCoreMain></ebuCoreMain>", XML},
+		{"JSON", ` {"take": 1}` + "\x00", JSON},
+		{"malformed JSON", `{"take": 1`, Text},
+		{"PNG", "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR", PNG},
+		{"JPEG", "\xff\xd8\xff\xe0\x00\x10JFIF", JPEG},
+		{"ID3", "ID3\x04\x00\x00\x00\x00\x00\x0a", ID3},
+		{"binary", "\x01\x00\x02\x00", Binary},
+		{"invalid UTF-8", "caf\xe9", Binary},
+		{"empty", "", Binary},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := reader([]byte(tc.payload))
+			if got := Sniff(r); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			if b, _ := io.ReadAll(r); string(b) != tc.payload {
+				t.Fatalf("expected the payload to be left unread, got %q", b)
+			}
+		})
+	}
+
+	t.Run("large payloads", func(t *testing.T) {
+		// é is cut in half by the end of the peeked bytes
+		text := strings.Repeat("a", SniffSize-1) + "é"
+		if got := Sniff(reader([]byte(text))); got != Text {
+			t.Fatalf("expected text, got %v", got)
+		}
+		doc := `{"notes": "` + strings.Repeat("x", SniffSize) + `"}`
+		if got := Sniff(reader([]byte(doc))); got != JSON {
+			t.Fatalf("expected json, got %v", got)
+		}
+	})
+
+	t.Run("names", func(t *testing.T) {
+		if XML.String() != "xml" || ContentType(9).String() != "ContentType(9)" || !JSON.IsText() || PNG.IsText() {
+			t.Fatal("unexpected names or IsText")
+		}
+		if b, _ := ID3.MarshalText(); !bytes.Equal(b, []byte("id3")) {
+			t.Fatalf("unexpected text %q", b)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrStringTooLong is returned when a string does not fit the field it is
+// written to.
+var ErrStringTooLong = errors.New("string too long for field")
+
+// ReadString reads a fixed-width text field of width bytes. The value ends at
+// the first NUL byte and trailing spaces are removed, so both NUL and space
+// padded fields decode to the bare string.
+func (ch *Reader) ReadString(width int) (string, error) {
+	buf := make([]byte, width)
+	if _, err := io.ReadFull(ch, buf); err != nil {
+		return "", err
+	}
+	return trimField(string(buf)), nil
+}
+
+// ReadCString reads a NUL terminated string. The terminator is consumed but
+// not returned.
+func (ch *Reader) ReadCString() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := ch.ReadByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		if b == 0 {
+			return sb.String(), nil
+		}
+		sb.WriteByte(b)
+	}
+}
+
+// ReadPString reads a Pascal style string as used by IFF/AIFF: a count byte
+// followed by the text and a pad byte if the total length is odd.
+func (ch *Reader) ReadPString() (string, error) {
+	n, err := ch.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	size := int(n)
+	if size%2 == 0 {
+		size++ // pad byte
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(ch, buf); err != nil {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+// ReadUTF16String reads a fixed-width UTF-16 text field of width bytes in the
+// given byte order. The value ends at the first NUL code unit.
+func (ch *Reader) ReadUTF16String(width int, order binary.ByteOrder) (string, error) {
+	buf := make([]byte, width)
+	if _, err := io.ReadFull(ch, buf); err != nil {
+		return "", err
+	}
+	units := make([]uint16, 0, width/2)
+	for i := 0; i+1 < len(buf); i += 2 {
+		u := order.Uint16(buf[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// ReadUTF16CString reads a UTF-16 string terminated by a NUL code unit.
+func (ch *Reader) ReadUTF16CString(order binary.ByteOrder) (string, error) {
+	var units []uint16
+	var buf [2]byte
+	for {
+		if _, err := io.ReadFull(ch, buf[:]); err != nil {
+			return string(utf16.Decode(units)), err
+		}
+		u := order.Uint16(buf[:])
+		if u == 0 {
+			return string(utf16.Decode(units)), nil
+		}
+		units = append(units, u)
+	}
+}
+
+// WriteString writes s as a fixed-width field of width bytes, filling the
+// remainder with pad (usually 0 or ' ').
+func (ch *Writer) WriteString(s string, width int, pad byte) error {
+	if len(s) > width {
+		return ErrStringTooLong
+	}
+	buf := make([]byte, width)
+	n := copy(buf, s)
+	for i := n; i < width; i++ {
+		buf[i] = pad
+	}
+	_, err := ch.Write(buf)
+	return err
+}
+
+// WriteCString writes s followed by a NUL terminator.
+func (ch *Writer) WriteCString(s string) error {
+	buf := make([]byte, len(s)+1)
+	copy(buf, s)
+	_, err := ch.Write(buf)
+	return err
+}
+
+// WritePString writes s as a Pascal style string: a count byte, the text and
+// a pad byte if the total length is odd.
+func (ch *Writer) WritePString(s string) error {
+	if len(s) > 255 {
+		return ErrStringTooLong
+	}
+	size := 1 + len(s)
+	if size%2 == 1 {
+		size++
+	}
+	buf := make([]byte, size)
+	buf[0] = byte(len(s))
+	copy(buf[1:], s)
+	_, err := ch.Write(buf)
+	return err
+}
+
+// WriteUTF16String writes s as a fixed-width UTF-16 field of width bytes in
+// the given byte order, padding with NUL code units.
+func (ch *Writer) WriteUTF16String(s string, width int, order binary.ByteOrder) error {
+	units := utf16.Encode([]rune(s))
+	if len(units)*2 > width {
+		return ErrStringTooLong
+	}
+	buf := make([]byte, width)
+	for i, u := range units {
+		order.PutUint16(buf[2*i:], u)
+	}
+	_, err := ch.Write(buf)
+	return err
+}
+
+// WriteUTF16CString writes s in UTF-16 followed by a NUL code unit.
+func (ch *Writer) WriteUTF16CString(s string, order binary.ByteOrder) error {
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, 2*len(units)+2)
+	for i, u := range units {
+		order.PutUint16(buf[2*i:], u)
+	}
+	_, err := ch.Write(buf)
+	return err
+}
+
+// trimField cuts a fixed-width text field at the first NUL and removes
+// trailing space padding.
+func trimField(s string) string {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " ")
+}
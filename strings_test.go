@@ -0,0 +1,94 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriter_WriteString(t *testing.T) {
+	t.Run("pads with NULs", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := w.WriteString("ab", 4, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), []byte{'a', 'b', 0, 0}) {
+			t.Fatalf("unexpected output % x", buf.Bytes())
+		}
+		if w.Size != 4 {
+			t.Fatalf("expected Size=4, got %d", w.Size)
+		}
+	})
+
+	t.Run("pads with spaces", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		w.WriteString("ab", 4, ' ')
+		if buf.String() != "ab  " {
+			t.Fatalf("expected 'ab  ', got %q", buf.String())
+		}
+	})
+
+	t.Run("rejects strings wider than the field", func(t *testing.T) {
+		w := &Writer{W: &bytes.Buffer{}}
+		if err := w.WriteString("abcde", 4, 0); err != ErrStringTooLong {
+			t.Fatalf("expected ErrStringTooLong, got %v", err)
+		}
+	})
+}
+
+func TestWriter_WritePString(t *testing.T) {
+	t.Run("pads to even length", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		w.WritePString("abc")
+		if !bytes.Equal(buf.Bytes(), []byte{3, 'a', 'b', 'c'}) {
+			t.Fatalf("unexpected output % x", buf.Bytes())
+		}
+
+		buf.Reset()
+		w.WritePString("ab")
+		if !bytes.Equal(buf.Bytes(), []byte{2, 'a', 'b', 0}) {
+			t.Fatalf("unexpected output % x", buf.Bytes())
+		}
+	})
+
+	t.Run("rejects strings longer than 255 bytes", func(t *testing.T) {
+		w := &Writer{W: &bytes.Buffer{}}
+		if err := w.WritePString(string(make([]byte, 256))); err != ErrStringTooLong {
+			t.Fatalf("expected ErrStringTooLong, got %v", err)
+		}
+	})
+}
+
+func TestStrings_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	w.WriteString("fixed", 8, ' ')
+	w.WriteCString("c-string")
+	w.WritePString("pascal")
+	w.WriteUTF16String("grüß", 12, binary.LittleEndian)
+	w.WriteUTF16CString("€uro", binary.BigEndian)
+
+	r := &Reader{Size: buf.Len(), R: bytes.NewReader(buf.Bytes())}
+
+	if s, err := r.ReadString(8); err != nil || s != "fixed" {
+		t.Fatalf("ReadString: %q, %v", s, err)
+	}
+	if s, err := r.ReadCString(); err != nil || s != "c-string" {
+		t.Fatalf("ReadCString: %q, %v", s, err)
+	}
+	if s, err := r.ReadPString(); err != nil || s != "pascal" {
+		t.Fatalf("ReadPString: %q, %v", s, err)
+	}
+	if s, err := r.ReadUTF16String(12, binary.LittleEndian); err != nil || s != "grüß" {
+		t.Fatalf("ReadUTF16String: %q, %v", s, err)
+	}
+	if s, err := r.ReadUTF16CString(binary.BigEndian); err != nil || s != "€uro" {
+		t.Fatalf("ReadUTF16CString: %q, %v", s, err)
+	}
+	if !r.IsFullyRead() {
+		t.Fatalf("expected fully read, Pos=%d Size=%d", r.Pos, r.Size)
+	}
+}
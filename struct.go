@@ -0,0 +1,222 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrNotStruct is returned when ReadStruct or WriteStruct are passed anything
+// other than a struct (or a pointer to one for ReadStruct).
+var ErrNotStruct = errors.New("value is not a struct")
+
+// fieldTag is the parsed form of a `chunk:"..."` struct tag. Options are
+// comma separated:
+//
+//	"-"        ignore the field
+//	le, be     byte order of the field; on a struct field it becomes the
+//	           default for all nested fields
+//	skip=N     N unused bytes precede the field (zero on write)
+//	pad=N      N unused bytes follow the field (zero on write)
+//	string=N   fixed-width, NUL padded string of N bytes
+//	cstring    NUL terminated string
+//	pstring    count-prefixed, even padded string
+type fieldTag struct {
+	ignore  bool
+	order   binary.ByteOrder
+	skip    int
+	pad     int
+	width   int
+	cstring bool
+	pstring bool
+}
+
+func parseTag(tag string, order binary.ByteOrder) (fieldTag, error) {
+	ft := fieldTag{order: order}
+	if tag == "" {
+		return ft, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		key, val, hasVal := strings.Cut(strings.TrimSpace(opt), "=")
+		var n int
+		if hasVal {
+			var err error
+			if n, err = strconv.Atoi(val); err != nil || n < 0 {
+				return ft, fmt.Errorf("invalid chunk tag option %q", opt)
+			}
+		}
+		switch key {
+		case "-":
+			ft.ignore = true
+		case "le":
+			ft.order = binary.LittleEndian
+		case "be":
+			ft.order = binary.BigEndian
+		case "skip":
+			ft.skip = n
+		case "pad":
+			ft.pad = n
+		case "string":
+			ft.width = n
+		case "cstring":
+			ft.cstring = true
+		case "pstring":
+			ft.pstring = true
+		default:
+			return ft, fmt.Errorf("unknown chunk tag option %q", opt)
+		}
+	}
+	return ft, nil
+}
+
+// ReadStruct reads the fields of the struct dst points to in declaration
+// order. Fields are little-endian unless tagged otherwise with the `chunk:`
+// tag options le, be, skip=N, pad=N, string=N, cstring, pstring or "-".
+// Unexported fields are ignored and blank (_) fields skip their size.
+//
+//	type CommonChunk struct {
+//		Channels uint16 `chunk:"be"`
+//		Name     string `chunk:"string=32"`
+//		Format   uint32 `chunk:"le,skip=2"`
+//	}
+func (ch *Reader) ReadStruct(dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	return ch.readStruct(v.Elem(), binary.LittleEndian)
+}
+
+func (ch *Reader) readStruct(v reflect.Value, order binary.ByteOrder) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft, err := parseTag(sf.Tag.Get("chunk"), order)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		if ft.ignore {
+			continue
+		}
+		if sf.Name == "_" {
+			ft.skip += int(sf.Type.Size())
+		} else if !sf.IsExported() {
+			continue
+		}
+		if err := ch.Jump(ft.skip); err != nil {
+			return err
+		}
+		if sf.Name != "_" {
+			if err := ch.readField(v.Field(i), sf, ft); err != nil {
+				return err
+			}
+		}
+		if err := ch.Jump(ft.pad); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ch *Reader) readField(fv reflect.Value, sf reflect.StructField, ft fieldTag) error {
+	switch fv.Kind() {
+	case reflect.String:
+		var s string
+		var err error
+		switch {
+		case ft.cstring:
+			s, err = ch.ReadCString()
+		case ft.pstring:
+			s, err = ch.ReadPString()
+		case ft.width > 0:
+			s, err = ch.ReadString(ft.width)
+		default:
+			return fmt.Errorf("field %s: string fields need a string=N, cstring or pstring tag", sf.Name)
+		}
+		fv.SetString(s)
+		return err
+	case reflect.Struct:
+		return ch.readStruct(fv, ft.order)
+	}
+	if binary.Size(fv.Interface()) < 0 {
+		return fmt.Errorf("field %s: unsupported type %s", sf.Name, sf.Type)
+	}
+	return binary.Read(ch, ft.order, fv.Addr().Interface())
+}
+
+// WriteStruct writes the fields of src (a struct or a pointer to one) in
+// declaration order, honoring the same `chunk:` tags as Reader.ReadStruct so
+// a single annotated struct describes both directions.
+func (ch *Writer) WriteStruct(src any) error {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	return ch.writeStruct(v, binary.LittleEndian)
+}
+
+func (ch *Writer) writeStruct(v reflect.Value, order binary.ByteOrder) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft, err := parseTag(sf.Tag.Get("chunk"), order)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		if ft.ignore {
+			continue
+		}
+		if sf.Name == "_" {
+			ft.skip += int(sf.Type.Size())
+		} else if !sf.IsExported() {
+			continue
+		}
+		if err := ch.writeZeros(ft.skip); err != nil {
+			return err
+		}
+		if sf.Name != "_" {
+			if err := ch.writeField(v.Field(i), sf, ft); err != nil {
+				return err
+			}
+		}
+		if err := ch.writeZeros(ft.pad); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ch *Writer) writeField(fv reflect.Value, sf reflect.StructField, ft fieldTag) error {
+	switch fv.Kind() {
+	case reflect.String:
+		switch {
+		case ft.cstring:
+			return ch.WriteCString(fv.String())
+		case ft.pstring:
+			return ch.WritePString(fv.String())
+		case ft.width > 0:
+			return ch.WriteString(fv.String(), ft.width, 0)
+		}
+		return fmt.Errorf("field %s: string fields need a string=N, cstring or pstring tag", sf.Name)
+	case reflect.Struct:
+		return ch.writeStruct(fv, ft.order)
+	}
+	if binary.Size(fv.Interface()) < 0 {
+		return fmt.Errorf("field %s: unsupported type %s", sf.Name, sf.Type)
+	}
+	return ch.writeWithByteOrder(fv.Interface(), ft.order)
+}
+
+func (ch *Writer) writeZeros(n int) error {
+	if n <= 0 {
+		return nil
+	}
+	_, err := ch.Write(make([]byte, n))
+	return err
+}
@@ -0,0 +1,107 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testHeader struct {
+	Channels   uint16 `chunk:"be"`
+	SampleRate uint32
+	Name       string `chunk:"string=6"`
+	Flags      byte   `chunk:"skip=2,pad=1"`
+	Inner      struct {
+		A uint16
+		B [2]uint16
+	} `chunk:"be"`
+	Comment  string `chunk:"cstring"`
+	Title    string `chunk:"pstring"`
+	_        [2]byte
+	Ignored  int `chunk:"-"`
+	internal int
+}
+
+var testHeaderBytes = []byte{
+	0x00, 0x02, // Channels (be)
+	0x80, 0xBB, 0x00, 0x00, // SampleRate 48000 (le)
+	'm', 'o', 'n', 'o', 0, 0, // Name
+	0, 0, 0x07, 0, // skip, Flags, pad
+	0x00, 0x01, 0x00, 0x02, 0x00, 0x03, // Inner (be)
+	'h', 'i', 0, // Comment
+	3, 'a', 'b', 'c', // Title
+	0, 0, // blank field
+}
+
+func TestWriter_WriteStruct(t *testing.T) {
+	t.Run("honors tags", func(t *testing.T) {
+		h := testHeader{Channels: 2, SampleRate: 48000, Name: "mono", Flags: 7,
+			Comment: "hi", Title: "abc", Ignored: 5, internal: 6}
+		h.Inner.A = 1
+		h.Inner.B = [2]uint16{2, 3}
+
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := w.WriteStruct(&h); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), testHeaderBytes) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), testHeaderBytes)
+		}
+		if w.Size != len(testHeaderBytes) {
+			t.Fatalf("expected Size=%d, got %d", len(testHeaderBytes), w.Size)
+		}
+	})
+
+	t.Run("rejects non-struct values", func(t *testing.T) {
+		w := &Writer{W: &bytes.Buffer{}}
+		if err := w.WriteStruct(42); err != ErrNotStruct {
+			t.Fatalf("expected ErrNotStruct, got %v", err)
+		}
+	})
+
+	t.Run("rejects untagged strings", func(t *testing.T) {
+		w := &Writer{W: &bytes.Buffer{}}
+		if err := w.WriteStruct(struct{ S string }{"x"}); err == nil {
+			t.Fatal("expected error for untagged string field")
+		}
+	})
+}
+
+func TestReader_ReadStruct(t *testing.T) {
+	t.Run("honors tags", func(t *testing.T) {
+		r := &Reader{Size: len(testHeaderBytes), R: bytes.NewReader(testHeaderBytes)}
+		var h testHeader
+		if err := r.ReadStruct(&h); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if h.Channels != 2 || h.SampleRate != 48000 || h.Name != "mono" || h.Flags != 7 {
+			t.Fatalf("unexpected header %+v", h)
+		}
+		if h.Inner.A != 1 || h.Inner.B != [2]uint16{2, 3} {
+			t.Fatalf("unexpected nested struct %+v", h.Inner)
+		}
+		if h.Comment != "hi" || h.Title != "abc" {
+			t.Fatalf("unexpected strings %q %q", h.Comment, h.Title)
+		}
+		if r.Pos != len(testHeaderBytes) {
+			t.Fatalf("expected Pos=%d, got %d", len(testHeaderBytes), r.Pos)
+		}
+	})
+
+	t.Run("rejects non-pointer values", func(t *testing.T) {
+		r := &Reader{Size: 2, R: bytes.NewReader([]byte{0, 0})}
+		if err := r.ReadStruct(testHeader{}); err != ErrNotStruct {
+			t.Fatalf("expected ErrNotStruct, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown tag options", func(t *testing.T) {
+		r := &Reader{Size: 2, R: bytes.NewReader([]byte{0, 0})}
+		var v struct {
+			A uint16 `chunk:"middle"`
+		}
+		if err := r.ReadStruct(&v); err == nil {
+			t.Fatal("expected error for unknown tag option")
+		}
+	})
+}
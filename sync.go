@@ -0,0 +1,76 @@
+package chunk
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SyncReader wraps a Reader for pipelines where one goroutine reads the
+// payload while others poll how far it got. Reads and skips are serialized
+// by a mutex; Pos and Remaining return the position after the last of them
+// without taking it, so a progress poll never waits for a read blocked on
+// I/O. The wrapped Reader must not be used directly meanwhile.
+type SyncReader struct {
+	mu  sync.Mutex
+	ch  *Reader
+	pos atomic.Int64
+}
+
+// NewSyncReader returns a SyncReader reading from ch.
+func NewSyncReader(ch *Reader) *SyncReader {
+	s := &SyncReader{ch: ch}
+	s.pos.Store(int64(ch.Pos))
+	return s
+}
+
+// Read implements the io.Reader interface.
+func (s *SyncReader) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.sync()
+	return s.ch.Read(p)
+}
+
+// Do calls fn with the wrapped Reader while holding the lock, for its other
+// methods such as ReadLE or ReadStruct, and publishes the new position.
+func (s *SyncReader) Do(fn func(ch *Reader) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.sync()
+	return fn(s.ch)
+}
+
+// Jump skips ahead like Reader.Jump.
+func (s *SyncReader) Jump(bytesAhead int) error {
+	return s.Do(func(ch *Reader) error { return ch.Jump(bytesAhead) })
+}
+
+// Done finishes the Reader like Reader.Done.
+func (s *SyncReader) Done() error {
+	return s.Do((*Reader).Done)
+}
+
+// Close finishes the Reader like Reader.Close.
+func (s *SyncReader) Close() error {
+	return s.Do((*Reader).Close)
+}
+
+// Pos returns the number of payload bytes consumed so far.
+func (s *SyncReader) Pos() int {
+	return int(s.pos.Load())
+}
+
+// Size returns the payload size of the wrapped Reader.
+func (s *SyncReader) Size() int {
+	return s.ch.Size
+}
+
+// Remaining returns the number of payload bytes not consumed yet.
+func (s *SyncReader) Remaining() int {
+	return max(s.ch.Size-s.Pos(), 0)
+}
+
+// sync publishes the position of the wrapped Reader; the lock is held.
+func (s *SyncReader) sync() {
+	s.pos.Store(int64(s.ch.Pos))
+}
@@ -0,0 +1,58 @@
+package chunk
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSyncReader(t *testing.T) {
+	t.Run("reports progress while another goroutine reads", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		s := NewSyncReader(&Reader{Size: 8, R: pr})
+		done := make(chan error)
+		go func() {
+			_, err := io.ReadAll(io.LimitReader(s, 8))
+			done <- err
+		}()
+		if s.Pos() != 0 || s.Remaining() != 8 {
+			t.Fatalf("unexpected start %d/%d", s.Pos(), s.Remaining())
+		}
+		pw.Write([]byte("abc"))
+		// the reader is blocked on the pipe now, which must not block polls
+		for s.Pos() != 3 {
+		}
+		if s.Remaining() != 5 {
+			t.Fatalf("expected 5 bytes remaining, got %d", s.Remaining())
+		}
+		pw.Write([]byte("defgh"))
+		pw.Close()
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.Pos() != 8 || s.Remaining() != 0 || s.Size() != 8 {
+			t.Fatalf("unexpected end %d/%d", s.Pos(), s.Remaining())
+		}
+	})
+
+	t.Run("serializes other methods", func(t *testing.T) {
+		s := NewSyncReader(reader([]byte{1, 0, 2, 0, 0, 0, 9, 9}))
+		var v uint16
+		if err := s.Do(func(ch *Reader) error { return ch.ReadLE(&v) }); err != nil || v != 1 || s.Pos() != 2 {
+			t.Fatalf("unexpected value %d at %d (%v)", v, s.Pos(), err)
+		}
+		if err := s.Jump(4); err != nil || s.Pos() != 6 {
+			t.Fatalf("unexpected position %d after Jump (%v)", s.Pos(), err)
+		}
+		if err := s.Close(); err != nil || s.Remaining() != 0 {
+			t.Fatalf("expected Close to drain the rest, %d remaining (%v)", s.Remaining(), err)
+		}
+	})
+
+	t.Run("starts at the position of the Reader", func(t *testing.T) {
+		ch := reader(make([]byte, 4))
+		ch.ReadByte()
+		if s := NewSyncReader(ch); s.Pos() != 1 || s.Remaining() != 3 {
+			t.Fatalf("unexpected position %d/%d", s.Pos(), s.Remaining())
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package chunk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// WarningKind is the kind of oddity reported in a Warning.
+type WarningKind int
+
+// Kinds of warnings.
+const (
+	// WarnPad flags pad bytes other than 0x00, and a pad byte missing at
+	// the end of the stream.
+	WarnPad WarningKind = iota
+	// WarnUnknown flags chunks missing from the catalog of known chunks.
+	WarnUnknown
+	// WarnSize flags sizes that do not quite match the data, such as a
+	// container size running past its last chunk.
+	WarnSize
+	// WarnCase flags chunk IDs matched only by ignoring the case of ASCII
+	// letters, such as "Data" taken for "data".
+	WarnCase
+)
+
+var warningKindNames = [...]string{"pad", "unknown", "size", "case"}
+
+func (k WarningKind) String() string {
+	if k >= 0 && int(k) < len(warningKindNames) {
+		return warningKindNames[k]
+	}
+	return "WarningKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, so warnings encode their
+// kind by name.
+func (k WarningKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Warning is a recoverable oddity met while parsing. Unlike an error it
+// does not stop the parser, so callers can surface it and carry on.
+type Warning struct {
+	Kind WarningKind `json:"kind"`
+	// Offset is the offset the warning refers to, counted like the offsets
+	// of the reporting scanner, and ID the ID of the chunk concerned.
+	Offset  int64  `json:"offset"`
+	ID      FourCC `json:"id"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s at %d: %s", w.ID, w.Offset, w.Message)
+}
+
+// Warnings collects warnings; its Add method can be set as Scanner.Warn.
+type Warnings []Warning
+
+// Add appends w.
+func (ws *Warnings) Add(w Warning) {
+	*ws = append(*ws, w)
+}
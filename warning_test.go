@@ -0,0 +1,63 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	data := []byte{
+		'f', 'm', 't', ' ', 1, 0, 0, 0, 1, 0,
+		'd', 'a', 't', 'a', 1, 0, 0, 0, 2, 'x',
+		'o', 'd', 'd', ' ', 1, 0, 0, 0, 3, // final pad byte omitted
+	}
+	want := []string{
+		"data at 19: pad byte is 0x78",
+		"odd  at 29: missing pad byte at the end",
+	}
+
+	t.Run("reports tolerated pad bytes", func(t *testing.T) {
+		for _, s := range []*Scanner{
+			NewScanner(bytes.NewReader(data), binary.LittleEndian),
+			NewScannerAt(bytes.NewReader(data), 0, int64(len(data)), RIFFHeader),
+		} {
+			var ws Warnings
+			s.Warn = ws.Add
+			n := 0
+			for ; s.Next(); n++ {
+			}
+			if s.Err() != nil || n != 3 || len(ws) != len(want) {
+				t.Fatalf("expected 3 chunks and %d warnings, got %d %q (%v)", len(want), n, ws, s.Err())
+			}
+			for i, w := range ws {
+				if w.Kind != WarnPad || w.String() != want[i] {
+					t.Fatalf("expected %q, got %v %q", want[i], w.Kind, w.String())
+				}
+			}
+		}
+	})
+
+	t.Run("leaves rejected pad bytes to the error", func(t *testing.T) {
+		s := NewScanner(bytes.NewReader(data), binary.LittleEndian)
+		var ws Warnings
+		s.Warn = ws.Add
+		s.ValidatePad = ZeroPad
+		for s.Next() {
+		}
+		if s.Err() != ErrPadding || len(ws) != 0 {
+			t.Fatalf("expected ErrPadding and no warnings, got %v %q", s.Err(), ws)
+		}
+	})
+
+	t.Run("names kinds", func(t *testing.T) {
+		b, err := json.Marshal(Warning{Kind: WarnSize, Offset: 12, ID: FourCC{'R', 'I', 'F', 'F'}, Message: "m"})
+		if err != nil || string(b) != `{"kind":"size","offset":12,"id":"RIFF","message":"m"}` {
+			t.Fatalf("unexpected JSON %s (%v)", b, err)
+		}
+		if got := WarningKind(7).String(); got != "WarningKind(7)" {
+			t.Fatalf("unexpected name %q", got)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// ErrNotAdtlList is returned by DecodeLabels for LIST chunks of another
+// type.
+var ErrNotAdtlList = errors.New("wav: not a LIST/adtl chunk")
+
+// Label names a cue point, as stored in a labl chunk of a LIST/adtl group.
+type Label struct {
+	CueID uint32
+	Text  string
+}
+
+// EncodeLabels writes a LIST/adtl group with one labl chunk per label, in
+// the given order.
+func EncodeLabels(cw *container.Writer, labels []Label) error {
+	if err := cw.BeginList("adtl"); err != nil {
+		return err
+	}
+	for _, l := range labels {
+		ch, err := cw.BeginChunk("labl")
+		if err != nil {
+			return err
+		}
+		if err := chunk.Write(ch, l.CueID, binary.LittleEndian); err != nil {
+			return err
+		}
+		if err := ch.WriteCString(l.Text); err != nil {
+			return err
+		}
+		if err := cw.EndChunk(); err != nil {
+			return err
+		}
+	}
+	return cw.EndChunk()
+}
+
+// DecodeLabels reads the labl chunks of a LIST chunk whose payload starts
+// with the adtl list type. Notes and other chunks of the list are skipped.
+// The texts are decoded as AutoCharset unless an option says otherwise.
+func DecodeLabels(r *chunk.Reader, opts ...DecodeOption) ([]Label, error) {
+	cfg := newDecodeConfig(opts)
+	typ, err := r.ReadFourCC()
+	if err != nil {
+		return nil, err
+	}
+	if typ.String() != "adtl" {
+		return nil, ErrNotAdtlList
+	}
+	var labels []Label
+	s := chunk.NewScanner(io.LimitReader(r, int64(max(r.Size-r.Pos, 0))), binary.LittleEndian)
+	for s.Next() {
+		ch := s.Chunk()
+		if string(ch.ID[:]) != "labl" {
+			continue
+		}
+		id, err := chunk.Read[uint32](ch, binary.LittleEndian)
+		if err != nil {
+			return labels, err
+		}
+		text, err := io.ReadAll(ch)
+		if err != nil {
+			return labels, err
+		}
+		// some writers leave out the terminator
+		text, _, _ = bytes.Cut(text, []byte{0})
+		labels = append(labels, Label{CueID: id, Text: cfg.charset.Decode(text)})
+	}
+	return labels, s.Err()
+}
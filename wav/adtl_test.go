@@ -0,0 +1,56 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestEncodeLabels(t *testing.T) {
+	var buf bytes.Buffer
+	cw := container.NewWriter(&buf, container.RIFF)
+	cw.BeginForm("WAVE")
+	in := []Label{{CueID: 1, Text: "Intro"}, {CueID: 7, Text: "Hit"}}
+	if err := EncodeLabels(cw, in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{
+		'R', 'I', 'F', 'F', 50, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'L', 'I', 'S', 'T', 38, 0, 0, 0, 'a', 'd', 't', 'l',
+		'l', 'a', 'b', 'l', 10, 0, 0, 0, 1, 0, 0, 0, 'I', 'n', 't', 'r', 'o', 0,
+		'l', 'a', 'b', 'l', 8, 0, 0, 0, 7, 0, 0, 0, 'H', 'i', 't', 0,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+	}
+
+	s, err := container.NewScanner(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Next()
+	got, err := DecodeLabels(s.Chunk())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != in[0] || got[1] != in[1] {
+		t.Fatalf("unexpected labels %+v", got)
+	}
+
+	t.Run("rejects other lists", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := container.NewWriter(&buf, container.RIFF)
+		cw.BeginForm("WAVE")
+		EncodeInfo(cw, []InfoTag{{ID: [4]byte{'I', 'N', 'A', 'M'}, Value: "x"}})
+		cw.Close()
+		s, _ := container.NewScanner(bytes.NewReader(buf.Bytes()))
+		s.Next()
+		if _, err := DecodeLabels(s.Chunk()); err != ErrNotAdtlList {
+			t.Fatalf("expected ErrNotAdtlList, got %v", err)
+		}
+	})
+}
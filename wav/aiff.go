@@ -0,0 +1,271 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/aiff"
+	"github.com/CWBudde/chunk/container"
+	"github.com/CWBudde/chunk/pcm"
+)
+
+// ErrTranscode is returned by ToAIFF and FromAIFF for files they cannot
+// convert.
+var ErrTranscode = errors.New("wav: only integer PCM files in RIFF, RF64, BW64 or AIFF can be transcoded")
+
+// ToAIFF writes the WAVE file in src as an AIFF file: fmt becomes COMM, the
+// samples are swapped to big-endian, or to signed for 8 bits, and cue
+// points become markers named by their adtl labels. The samples are
+// streamed, so files of any size convert in constant memory. warn, if set,
+// is called with the ID of every other chunk, which is dropped.
+func ToAIFF(dst io.Writer, src io.ReaderAt, warn func(id chunk.FourCC)) error {
+	h, entries, err := container.Index(src)
+	if err != nil {
+		return err
+	}
+	if h.Type.String() != "WAVE" || h.Format == container.RIFX || h.Format == container.IFF {
+		return ErrTranscode
+	}
+	var fmtEntry, data *container.Entry
+	var points []CuePoint
+	labels := make(map[uint32]string)
+	for i, e := range entries {
+		switch e.ID.String() {
+		case "fmt ":
+			fmtEntry = &entries[i]
+		case "data":
+			data = &entries[i]
+		case "fact":
+		case "cue ":
+			if points, err = DecodeCue(section(src, e)); err != nil {
+				return err
+			}
+		case "LIST":
+			list, err := DecodeLabels(section(src, e))
+			if err == ErrNotAdtlList {
+				warnChunk(warn, e.ID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			for _, l := range list {
+				labels[l.CueID] = l.Text
+			}
+		default:
+			if !container.IsFiller(e.ID) {
+				warnChunk(warn, e.ID)
+			}
+		}
+	}
+	if fmtEntry == nil || data == nil {
+		return container.ErrChunkNotFound
+	}
+	f, err := DecodeFmt(section(src, *fmtEntry))
+	if err != nil {
+		return err
+	}
+	l := f.Layout()
+	if f.Tag() != FormatPCM || l.Validate() != nil || l.Width != int(f.BitsPerSample+7)/8 {
+		return ErrTranscode
+	}
+	if len(points) > math.MaxInt16 {
+		return fmt.Errorf("wav: %d cue points do not fit a MARK chunk", len(points))
+	}
+
+	aw, err := aiff.NewWriter(dst, aiff.Common{
+		Channels:   int16(f.Channels),
+		SampleSize: int16(f.BitsPerSample),
+		SampleRate: float64(f.SampleRate),
+	})
+	if err != nil {
+		return err
+	}
+	// marker IDs are numbered anew, cue IDs may not fit 16 bits
+	markers := make([]aiff.Marker, len(points))
+	for i, p := range points {
+		name := labels[p.ID]
+		markers[i] = aiff.Marker{ID: int16(i + 1), Position: p.SampleOffset, Name: name[:min(len(name), 255)]}
+	}
+	aw.SetMarkers(markers)
+	if err := transcodeSamples(writerFunc(aw.WriteRaw), section(src, *data), l, binary.BigEndian); err != nil {
+		return err
+	}
+	return aw.Close()
+}
+
+// FromAIFF writes the AIFF file in src as a WAVE file in format, RIFF, RF64
+// or BW64: COMM becomes fmt, the samples are swapped to little-endian, or to
+// unsigned for 8 bits, and markers become cue points with their names in a
+// LIST/adtl group. warn, if set, is called with the ID of every other
+// chunk, which is dropped.
+func FromAIFF(dst io.Writer, src io.ReaderAt, format container.Format, warn func(id chunk.FourCC)) error {
+	h, entries, err := container.Index(src)
+	if err != nil {
+		return err
+	}
+	if h.Type.String() != "AIFF" || format == container.RIFX || format == container.IFF {
+		return ErrTranscode
+	}
+	var comm, ssnd *container.Entry
+	var markers []aiff.Marker
+	for i, e := range entries {
+		switch e.ID.String() {
+		case "COMM":
+			comm = &entries[i]
+		case "SSND":
+			ssnd = &entries[i]
+		case "MARK":
+			if markers, err = aiff.DecodeMark(section(src, e)); err != nil {
+				return err
+			}
+		default:
+			if !container.IsFiller(e.ID) {
+				warnChunk(warn, e.ID)
+			}
+		}
+	}
+	if comm == nil || ssnd == nil {
+		return container.ErrChunkNotFound
+	}
+	c, err := aiff.DecodeComm(section(src, *comm))
+	if err != nil {
+		return err
+	}
+	width := (int(c.SampleSize) + 7) / 8
+	if c.Channels <= 0 || width < 1 || width > 4 || ssnd.Size < 8 {
+		return ErrTranscode
+	}
+	var off [4]byte
+	if _, err := src.ReadAt(off[:], ssnd.Offset+8); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	start := 8 + int64(binary.BigEndian.Uint32(off[:]))
+	if start > ssnd.Size {
+		return aiff.ErrShortChunk
+	}
+
+	f := WaveFormat{
+		FormatTag:     FormatPCM,
+		Channels:      uint16(c.Channels),
+		SampleRate:    uint32(c.SampleRate),
+		BitsPerSample: uint16(8 * width),
+	}
+	if int(c.SampleSize) != 8*width {
+		f.ValidBits = uint16(c.SampleSize)
+	}
+	cw := container.NewWriter(dst, format)
+	if err := cw.BeginForm("WAVE"); err != nil {
+		return err
+	}
+	ch, err := cw.BeginChunk("fmt ")
+	if err != nil {
+		return err
+	}
+	if err := EncodeFmt(ch, f); err != nil {
+		return err
+	}
+	if err := cw.EndChunk(); err != nil {
+		return err
+	}
+	if ch, err = cw.BeginChunk("data"); err != nil {
+		return err
+	}
+	samples := io.NewSectionReader(src, ssnd.Offset+8+start, ssnd.Size-start)
+	l := pcm.Layout{Width: width, Order: binary.BigEndian}
+	if err := transcodeSamples(ch, samples, l, binary.LittleEndian); err != nil {
+		return err
+	}
+	cw.SetSampleCount(uint64(ch.Size / (width * int(c.Channels))))
+	if err := cw.EndChunk(); err != nil {
+		return err
+	}
+	if err := writeMarkers(cw, markers); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// writeMarkers writes AIFF markers as a cue chunk and their names as a
+// LIST/adtl group.
+func writeMarkers(cw *container.Writer, markers []aiff.Marker) error {
+	if len(markers) == 0 {
+		return nil
+	}
+	points := make([]CuePoint, len(markers))
+	var labels []Label
+	for i, m := range markers {
+		points[i] = CuePoint{ID: uint32(m.ID), SampleOffset: m.Position}
+		if m.Name != "" {
+			labels = append(labels, Label{CueID: uint32(m.ID), Text: m.Name})
+		}
+	}
+	ch, err := cw.BeginChunk("cue ")
+	if err != nil {
+		return err
+	}
+	if err := EncodeCue(ch, points); err != nil {
+		return err
+	}
+	if err := cw.EndChunk(); err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return EncodeLabels(cw, labels)
+}
+
+// transcodeSamples copies the samples read from src, stored in layout l, to
+// dst in the given byte order. 8-bit samples switch between the unsigned
+// WAV and the signed AIFF encoding instead.
+func transcodeSamples(dst io.Writer, src io.Reader, l pcm.Layout, order binary.ByteOrder) error {
+	buf := chunk.GetBuffer()
+	defer chunk.PutBuffer(buf)
+	buf = buf[:len(buf)-len(buf)%l.Width]
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			b := buf[:n]
+			if l.Width == 1 {
+				for i := range b {
+					b[i] ^= 0x80
+				}
+			} else if err := pcm.Reorder(b, l, order); err != nil {
+				return err
+			}
+			if _, err := dst.Write(b); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// section returns a reader over the payload of e.
+func section(ra io.ReaderAt, e container.Entry) *chunk.Reader {
+	return &chunk.Reader{ID: e.ID, Size: int(e.Size), R: io.NewSectionReader(ra, e.Offset+8, e.Size)}
+}
+
+func warnChunk(warn func(id chunk.FourCC), id chunk.FourCC) {
+	if warn != nil {
+		warn(id)
+	}
+}
+
+// writerFunc adapts a write method such as aiff.Writer.WriteRaw to
+// io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
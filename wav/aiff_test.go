@@ -0,0 +1,98 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/aiff"
+	"github.com/CWBudde/chunk/chunktest"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestTranscodeAIFF(t *testing.T) {
+	fmt24 := encode(t, func(w *chunk.Writer) error {
+		return EncodeFmt(w, WaveFormat{FormatTag: FormatPCM, Channels: 1, SampleRate: 48000, BitsPerSample: 24})
+	})
+	cue := encode(t, func(w *chunk.Writer) error {
+		return EncodeCue(w, []CuePoint{{ID: 100000, SampleOffset: 1}, {ID: 7, SampleOffset: 0}})
+	})
+	src := chunktest.RIFF("WAVE").
+		Chunk("fmt ", fmt24).
+		Chunk("data", []byte{1, 2, 3, 4, 5, 6}).
+		Chunk("cue ", cue).
+		List("adtl").Chunk("labl", []byte{0xa0, 0x86, 0x01, 0, 'H', 'i', 't', 0}).End().
+		Chunk("PEAK", "x").
+		Bytes()
+
+	var warned []string
+	var a bytes.Buffer
+	if err := ToAIFF(&a, bytes.NewReader(src), func(id chunk.FourCC) { warned = append(warned, id.String()) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warned) != 1 || warned[0] != "PEAK" {
+		t.Fatalf("unexpected warnings %q", warned)
+	}
+	_, entries, err := container.Index(bytes.NewReader(a.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byID := make(map[string]*chunk.Reader)
+	for _, e := range entries {
+		byID[e.ID.String()] = section(bytes.NewReader(a.Bytes()), e)
+	}
+	c, err := aiff.DecodeComm(byID["COMM"])
+	if err != nil || c.SampleFrames != 2 || c.SampleSize != 24 || c.SampleRate != 48000 {
+		t.Fatalf("unexpected COMM %+v, %v", c, err)
+	}
+	markers, err := aiff.DecodeMark(byID["MARK"])
+	if err != nil || len(markers) != 2 || markers[0] != (aiff.Marker{ID: 1}) || markers[1] != (aiff.Marker{ID: 2, Position: 1, Name: "Hit"}) {
+		t.Fatalf("unexpected markers %+v, %v", markers, err)
+	}
+	ssnd := make([]byte, byID["SSND"].Size)
+	byID["SSND"].Read(ssnd)
+	if !bytes.Equal(ssnd, []byte{0, 0, 0, 0, 0, 0, 0, 0, 3, 2, 1, 6, 5, 4}) {
+		t.Fatalf("unexpected SSND % x", ssnd)
+	}
+
+	t.Run("and back", func(t *testing.T) {
+		var w bytes.Buffer
+		if err := FromAIFF(&w, bytes.NewReader(a.Bytes()), container.RIFF, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tree, err := container.ParseTree(bytes.NewReader(w.Bytes()), container.TreeOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ch := tree.Root.Children
+		if len(ch) != 4 || ch[0].ID.String() != "fmt " || ch[1].ID.String() != "data" || ch[2].ID.String() != "cue " || ch[3].Type.String() != "adtl" {
+			t.Fatalf("unexpected chunks %v", ch)
+		}
+		var data bytes.Buffer
+		container.Extract(bytes.NewReader(w.Bytes()), "data", 0, &data)
+		if !bytes.Equal(data.Bytes(), []byte{1, 2, 3, 4, 5, 6}) {
+			t.Fatalf("unexpected samples % x", data.Bytes())
+		}
+		points, err := DecodeCue(ch[2].Chunk())
+		if err != nil || len(points) != 2 || points[1] != (CuePoint{ID: 2, Position: 1, DataChunkID: chunk.FourCC{'d', 'a', 't', 'a'}, SampleOffset: 1}) {
+			t.Fatalf("unexpected cue points %+v, %v", points, err)
+		}
+		labels, err := DecodeLabels(ch[3].Chunk())
+		if err != nil || len(labels) != 1 || labels[0] != (Label{CueID: 2, Text: "Hit"}) {
+			t.Fatalf("unexpected labels %+v, %v", labels, err)
+		}
+	})
+
+	t.Run("rejects other samples", func(t *testing.T) {
+		float := encode(t, func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatIEEEFloat, Channels: 1, SampleRate: 48000, BitsPerSample: 32})
+		})
+		src := chunktest.RIFF("WAVE").Chunk("fmt ", float).Chunk("data", make([]byte, 4)).Reader()
+		if err := ToAIFF(&bytes.Buffer{}, src, nil); err != ErrTranscode {
+			t.Fatalf("expected ErrTranscode, got %v", err)
+		}
+		if err := FromAIFF(&bytes.Buffer{}, src, container.RIFF, nil); err != ErrTranscode {
+			t.Fatalf("expected ErrTranscode, got %v", err)
+		}
+	})
+}
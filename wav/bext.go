@@ -0,0 +1,187 @@
+package wav
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/CWBudde/chunk"
+)
+
+// bextFixedSize is the size of the bext chunk before the coding history.
+const bextFixedSize = 602
+
+// Bext is the content of a Broadcast Wave Format bext chunk (EBU Tech 3285).
+type Bext struct {
+	Description         string `json:"description"`
+	Originator          string `json:"originator"`
+	OriginatorReference string `json:"originatorReference"`
+	// OriginationDate is formatted yyyy-mm-dd, OriginationTime hh:mm:ss;
+	// see SetOrigination.
+	OriginationDate string `json:"originationDate"`
+	OriginationTime string `json:"originationTime"`
+	// TimeReference is the position of the first sample in samples since
+	// midnight; see TimeReference.
+	TimeReference uint64 `json:"timeReference"`
+	// Version selects the layout: 1 adds the UMID, 2 the loudness values.
+	// Fields of newer versions are written as zero.
+	Version uint16 `json:"version"`
+	UMID    UMID   `json:"-"`
+	// Loudness values in 0.01 LU/LUFS/dBTP, written for version 2; 0x7fff
+	// marks one not measured. See Loudness and SetLoudness.
+	LoudnessValue        int16 `json:"loudnessValue,omitempty"`
+	LoudnessRange        int16 `json:"loudnessRange,omitempty"`
+	MaxTruePeakLevel     int16 `json:"maxTruePeakLevel,omitempty"`
+	MaxMomentaryLoudness int16 `json:"maxMomentaryLoudness,omitempty"`
+	MaxShortTermLoudness int16 `json:"maxShortTermLoudness,omitempty"`
+	// CodingHistory holds one line per processing step, without the CR/LF
+	// terminators.
+	CodingHistory []string `json:"codingHistory,omitempty"`
+}
+
+// bextFixed is the on-disk layout of the fixed part of a bext chunk.
+type bextFixed struct {
+	Description          string `chunk:"string=256"`
+	Originator           string `chunk:"string=32"`
+	OriginatorReference  string `chunk:"string=32"`
+	OriginationDate      string `chunk:"string=10"`
+	OriginationTime      string `chunk:"string=8"`
+	TimeReference        uint64
+	Version              uint16
+	UMID                 [64]byte
+	LoudnessValue        int16
+	LoudnessRange        int16
+	MaxTruePeakLevel     int16
+	MaxMomentaryLoudness int16
+	MaxShortTermLoudness int16
+	_                    [180]byte
+}
+
+// bextJSON adds the UMID as hex to the JSON form of a bext chunk.
+type bextJSON struct {
+	*jsonBext
+	UMID string `json:"umid,omitempty"`
+}
+
+// jsonBext is Bext without its JSON methods.
+type jsonBext Bext
+
+// MarshalJSON implements json.Marshaler. The UMID is encoded as hex, see
+// UMID.String, and left out when it is zero.
+func (b Bext) MarshalJSON() ([]byte, error) {
+	v := bextJSON{jsonBext: (*jsonBext)(&b), UMID: b.UMID.String()}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the form written by
+// MarshalJSON. UMIDs are not validated, so those of broken files survive a
+// round trip.
+func (b *Bext) UnmarshalJSON(data []byte) error {
+	v := bextJSON{jsonBext: (*jsonBext)(b)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.UMID = UMID{}
+	if v.UMID == "" {
+		return nil
+	}
+	umid, err := hex.DecodeString(v.UMID)
+	if err != nil || len(umid) > len(b.UMID) {
+		return fmt.Errorf("wav: bad bext UMID %q", v.UMID)
+	}
+	copy(b.UMID[:], umid)
+	return nil
+}
+
+// TimeReference converts an offset since midnight into the sample count
+// stored in Bext.TimeReference.
+func TimeReference(d time.Duration, sampleRate uint32) uint64 {
+	sec, frac := d/time.Second, d%time.Second
+	return uint64(sec)*uint64(sampleRate) + uint64(frac)*uint64(sampleRate)/uint64(time.Second)
+}
+
+// SetOrigination sets the origination date and time fields from t.
+func (b *Bext) SetOrigination(t time.Time) {
+	b.OriginationDate = t.Format("2006-01-02")
+	b.OriginationTime = t.Format("15:04:05")
+}
+
+// EncodeBext writes a bext chunk. Text fields are NUL padded to their fixed
+// widths and ErrStringTooLong is returned for values that do not fit; each
+// coding history line is terminated with CR/LF. The UMID and loudness
+// values are written as stored, so a chunk read by DecodeBext survives a
+// rewrite even if they are invalid; SetUMID and SetLoudness check new ones.
+func EncodeBext(w *chunk.Writer, b Bext) error {
+	if b.Version < 1 {
+		b.UMID = UMID{}
+	}
+	if b.Version < 2 {
+		b.LoudnessValue, b.LoudnessRange, b.MaxTruePeakLevel = 0, 0, 0
+		b.MaxMomentaryLoudness, b.MaxShortTermLoudness = 0, 0
+	}
+	fixed := bextFixed{
+		Description:          b.Description,
+		Originator:           b.Originator,
+		OriginatorReference:  b.OriginatorReference,
+		OriginationDate:      b.OriginationDate,
+		OriginationTime:      b.OriginationTime,
+		TimeReference:        b.TimeReference,
+		Version:              b.Version,
+		UMID:                 b.UMID,
+		LoudnessValue:        b.LoudnessValue,
+		LoudnessRange:        b.LoudnessRange,
+		MaxTruePeakLevel:     b.MaxTruePeakLevel,
+		MaxMomentaryLoudness: b.MaxMomentaryLoudness,
+		MaxShortTermLoudness: b.MaxShortTermLoudness,
+	}
+	if err := w.WriteStruct(&fixed); err != nil {
+		return err
+	}
+	for _, line := range b.CodingHistory {
+		if _, err := io.WriteString(w, line+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBext reads a bext chunk. The UMID is kept as stored, whether valid
+// or not.
+func DecodeBext(r *chunk.Reader) (Bext, error) {
+	if r.Size < bextFixedSize {
+		return Bext{}, ErrShortChunk
+	}
+	var fixed bextFixed
+	if err := r.ReadStruct(&fixed); err != nil {
+		return Bext{}, err
+	}
+	b := Bext{
+		Description:          fixed.Description,
+		Originator:           fixed.Originator,
+		OriginatorReference:  fixed.OriginatorReference,
+		OriginationDate:      fixed.OriginationDate,
+		OriginationTime:      fixed.OriginationTime,
+		TimeReference:        fixed.TimeReference,
+		Version:              fixed.Version,
+		UMID:                 fixed.UMID,
+		LoudnessValue:        fixed.LoudnessValue,
+		LoudnessRange:        fixed.LoudnessRange,
+		MaxTruePeakLevel:     fixed.MaxTruePeakLevel,
+		MaxMomentaryLoudness: fixed.MaxMomentaryLoudness,
+		MaxShortTermLoudness: fixed.MaxShortTermLoudness,
+	}
+	history, err := readBytes(r, int64(r.Size-r.Pos))
+	if err != nil {
+		return b, err
+	}
+	text := strings.TrimRight(string(history), "\x00")
+	for line := range strings.SplitSeq(text, "\n") {
+		if line = strings.TrimSuffix(line, "\r"); line != "" {
+			b.CodingHistory = append(b.CodingHistory, line)
+		}
+	}
+	return b, nil
+}
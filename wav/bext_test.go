@@ -0,0 +1,101 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodeBext(t *testing.T) {
+	b := Bext{
+		Description:   "Scene 12 take 3",
+		Originator:    "recorder",
+		TimeReference: TimeReference(10*time.Hour+500*time.Millisecond, 48000),
+		Version:       2,
+		LoudnessValue: -2300,
+		CodingHistory: []string{"A=PCM,F=48000,W=24,M=stereo", "A=PCM,F=48000,W=16,T=dither"},
+	}
+	b.SetOrigination(time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC))
+
+	got := encode(t, func(w *chunk.Writer) error { return EncodeBext(w, b) })
+	history := "A=PCM,F=48000,W=24,M=stereo\r\nA=PCM,F=48000,W=16,T=dither\r\n"
+	if len(got) != bextFixedSize+len(history) {
+		t.Fatalf("expected %d bytes, got %d", bextFixedSize+len(history), len(got))
+	}
+	if string(got[320:338]) != "2024-03-0914:05:00" {
+		t.Fatalf("unexpected origination %q", got[320:338])
+	}
+	if string(got[bextFixedSize:]) != history {
+		t.Fatalf("unexpected coding history %q", got[bextFixedSize:])
+	}
+
+	d, err := DecodeBext(reader(got))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Description != b.Description || d.TimeReference != 36000*48000+24000 ||
+		d.LoudnessValue != -2300 || len(d.CodingHistory) != 2 || d.CodingHistory[1] != b.CodingHistory[1] {
+		t.Fatalf("unexpected bext %+v", d)
+	}
+
+	t.Run("version 1 drops loudness", func(t *testing.T) {
+		v1 := b
+		v1.Version = 1
+		d, err := DecodeBext(reader(encode(t, func(w *chunk.Writer) error { return EncodeBext(w, v1) })))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Version != 1 || d.LoudnessValue != 0 {
+			t.Fatalf("unexpected bext %+v", d)
+		}
+	})
+
+	t.Run("rejects long fields", func(t *testing.T) {
+		long := Bext{Originator: string(bytes.Repeat([]byte{'x'}, 33))}
+		if err := EncodeBext(&chunk.Writer{W: &bytes.Buffer{}}, long); err != chunk.ErrStringTooLong {
+			t.Fatalf("expected ErrStringTooLong, got %v", err)
+		}
+	})
+
+	t.Run("writes stored UMIDs as they are", func(t *testing.T) {
+		v1 := Bext{Version: 1}
+		v1.UMID[0], v1.UMID[63] = 0x06, 0xff // not a SMPTE UMID
+		d, err := DecodeBext(reader(encode(t, func(w *chunk.Writer) error { return EncodeBext(w, v1) })))
+		if err != nil || d.UMID != v1.UMID {
+			t.Fatalf("unexpected UMID %s, %v", d.UMID, err)
+		}
+	})
+
+	t.Run("validates new UMIDs", func(t *testing.T) {
+		var b Bext
+		if err := b.SetUMID(UMID{0x06}); err != ErrUMID || !b.UMID.IsZero() {
+			t.Fatalf("expected ErrUMID, got %v", err)
+		}
+		u, _ := ParseUMID("060a2b340101010501010d2013000000a1b2c3d4e5f60718293a4b5c6d7e8f90")
+		if err := b.SetUMID(u); err != nil || b.UMID != u || b.Version != 1 {
+			t.Fatalf("unexpected bext %+v (%v)", b, err)
+		}
+	})
+
+	t.Run("JSON encodes the UMID as hex", func(t *testing.T) {
+		var v Bext
+		v.UMID[0], v.UMID[63] = 0x06, 0xff
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Contains(data, []byte(`"umid":"06`)) || !bytes.Contains(data, []byte(`ff"`)) {
+			t.Fatalf("unexpected JSON %s", data)
+		}
+		var back Bext
+		if err := json.Unmarshal(data, &back); err != nil || back.UMID != v.UMID {
+			t.Fatalf("round trip failed: %v", err)
+		}
+		if data, _ := json.Marshal(Bext{}); bytes.Contains(data, []byte("umid")) {
+			t.Fatalf("zero UMID not left out: %s", data)
+		}
+	})
+}
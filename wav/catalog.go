@@ -0,0 +1,50 @@
+package wav
+
+import "github.com/CWBudde/chunk"
+
+// Specifications of the chunks in catalog.
+const (
+	specRIFF = "Multimedia Programming Interface and Data Specifications 1.0"
+	specRF64 = "EBU Tech 3306"
+	specBW64 = "ITU-R BS.2088"
+	specBext = "EBU Tech 3285"
+)
+
+// catalog lists the chunks of WAVE files registered with chunk.RegisterChunk.
+var catalog = []struct{ id, name, spec string }{
+	{"RIFF", "RIFF form", specRIFF},
+	{"RF64", "RF64 form", specRF64},
+	{"BW64", "BW64 form", specBW64},
+	{"LIST", "list", specRIFF},
+	{"JUNK", "filler", specRIFF},
+	{"junk", "filler", ""},
+	{"PAD ", "filler", ""},
+	{"fmt ", "wave format", specRIFF},
+	{"data", "sample data", specRIFF},
+	{"fact", "sample count", specRIFF},
+	{"cue ", "cue points", specRIFF},
+	{"plst", "playlist", specRIFF},
+	{"labl", "cue label", specRIFF},
+	{"note", "cue comment", specRIFF},
+	{"ltxt", "labeled text", specRIFF},
+	{"slnt", "silence", specRIFF},
+	{"smpl", "sampler settings", specRIFF},
+	{"inst", "instrument settings", specRIFF},
+	{"bext", "broadcast audio extension", specBext},
+	{"iXML", "iXML production metadata", "iXML specification"},
+	{"axml", "XML metadata", "EBU Tech 3285 supplement 5"},
+	{"ds64", "64-bit sizes", specRF64},
+	{"id3 ", "ID3 tag", "ID3v2"},
+	{"ID3 ", "ID3 tag", "ID3v2"},
+}
+
+func init() {
+	for _, c := range catalog {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte(c.id)), Format: "wav", Name: c.name, Spec: c.spec})
+	}
+	for _, id := range infoIDs {
+		chunk.RegisterChunk(chunk.ChunkInfo{ID: chunk.FourCC([]byte(id)), Format: "wav", Name: "INFO text", Spec: specRIFF})
+	}
+	// players reading the format on the way to the samples need it first
+	chunk.RegisterOrder(chunk.OrderRule{Format: "wav", ID: chunk.FourCC([]byte("fmt ")), Before: chunk.FourCC([]byte("data"))})
+}
@@ -0,0 +1,99 @@
+package wav
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ErrUnknownCharset is returned by Charset.UnmarshalText for names other
+// than those of the Charset constants.
+var ErrUnknownCharset = errors.New("wav: unknown charset")
+
+// Charset selects how the text of INFO and adtl chunks is decoded. The
+// specifications leave it open, and recorders from before Unicode wrote
+// their code page, so accented characters would come out garbled as UTF-8.
+type Charset int
+
+const (
+	// AutoCharset decodes UTF-8 and falls back to Windows-1252 for text
+	// that is not valid UTF-8.
+	AutoCharset Charset = iota
+	// UTF8 decodes UTF-8, keeping invalid bytes as they are.
+	UTF8
+	// Latin1 decodes ISO 8859-1.
+	Latin1
+	// Windows1252 decodes Windows-1252, the superset of ISO 8859-1 that
+	// Windows recorders wrote.
+	Windows1252
+)
+
+var charsetNames = [...]string{AutoCharset: "auto", UTF8: "utf-8", Latin1: "latin-1", Windows1252: "windows-1252"}
+
+// String returns the name of the charset, e.g. "windows-1252".
+func (c Charset) String() string {
+	if c >= 0 && int(c) < len(charsetNames) {
+		return charsetNames[c]
+	}
+	return "Charset(" + strconv.Itoa(int(c)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Charset) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Charset) UnmarshalText(text []byte) error {
+	for i, name := range charsetNames {
+		if name == string(text) {
+			*c = Charset(i)
+			return nil
+		}
+	}
+	return ErrUnknownCharset
+}
+
+// Decode returns b decoded in the charset as a UTF-8 string.
+func (c Charset) Decode(b []byte) string {
+	switch {
+	case c == UTF8, c == AutoCharset && utf8.Valid(b):
+		return string(b)
+	}
+	r := make([]rune, len(b))
+	for i, x := range b {
+		r[i] = rune(x)
+		if c != Latin1 && x >= 0x80 && x < 0xa0 {
+			r[i] = windows1252[x-0x80]
+		}
+	}
+	return string(r)
+}
+
+// windows1252 maps the bytes 0x80 to 0x9f, where Windows-1252 differs from
+// ISO 8859-1. The five unassigned ones stay control characters.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// DecodeOption configures the text decoding of DecodeInfo, DecodeLabels and
+// ReadMetadata.
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	charset Charset
+}
+
+// WithCharset decodes text in c instead of AutoCharset.
+func WithCharset(c Charset) DecodeOption {
+	return func(cfg *decodeConfig) { cfg.charset = c }
+}
+
+func newDecodeConfig(opts []DecodeOption) decodeConfig {
+	var cfg decodeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
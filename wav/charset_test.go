@@ -0,0 +1,62 @@
+package wav
+
+import (
+	"testing"
+
+	"github.com/CWBudde/chunk/chunktest"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestCharset(t *testing.T) {
+	t.Run("decodes", func(t *testing.T) {
+		latin := []byte("Caf\xe9 \x93Cr\xe8me\x94")
+		for _, c := range []struct {
+			charset Charset
+			in      []byte
+			want    string
+		}{
+			{AutoCharset, []byte("Café"), "Café"},
+			{AutoCharset, latin, "Café “Crème”"},
+			{Windows1252, latin, "Café “Crème”"},
+			{Latin1, latin, "Café \u0093Crème\u0094"},
+			{UTF8, latin, string(latin)},
+			{Windows1252, []byte{0x81, 0x80}, "\u0081€"},
+		} {
+			if got := c.charset.Decode(c.in); got != c.want {
+				t.Fatalf("%v: expected %q, got %q", c.charset, c.want, got)
+			}
+		}
+	})
+
+	t.Run("marshals names", func(t *testing.T) {
+		var c Charset
+		if err := c.UnmarshalText([]byte("latin-1")); err != nil || c != Latin1 {
+			t.Fatalf("expected Latin1, got %v, %v", c, err)
+		}
+		if err := c.UnmarshalText([]byte("ebcdic")); err != ErrUnknownCharset {
+			t.Fatalf("expected ErrUnknownCharset, got %v", err)
+		}
+		if Charset(9).String() != "Charset(9)" || Windows1252.String() != "windows-1252" {
+			t.Fatalf("unexpected names %s, %s", Charset(9), Windows1252)
+		}
+	})
+
+	t.Run("applies to decoders", func(t *testing.T) {
+		f := chunktest.RIFF("WAVE").
+			List("INFO").Chunk("IART", "Bj\xf6rk\x00").End().
+			List("adtl").Chunk("labl", uint32(1), "D\xe9but\x00").End().
+			Reader()
+		m, err := ReadMetadata(f)
+		if err != nil || m.Info["IART"] != "Björk" {
+			t.Fatalf("unexpected metadata %+v, %v", m, err)
+		}
+		if m, _ := ReadMetadata(f, WithCharset(UTF8)); m.Info["IART"] != "Bj\xf6rk" {
+			t.Fatalf("expected raw bytes, got %q", m.Info["IART"])
+		}
+		_, entries, _ := container.Index(f)
+		labels, err := DecodeLabels(section(f, entries[1]), WithCharset(Latin1))
+		if err != nil || len(labels) != 1 || labels[0].Text != "Début" {
+			t.Fatalf("unexpected labels %+v, %v", labels, err)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+	"github.com/CWBudde/chunk/pcm"
+)
+
+// ErrConvertFormat is returned by ConvertOrder for containers other than
+// RIFF and RIFX.
+var ErrConvertFormat = errors.New("wav: only RIFF and RIFX can be converted")
+
+// ConvertOrder copies the WAVE file read from src to dst with the framing of
+// to, RIFF (little-endian) or RIFX (big-endian). The fields of fmt and fact
+// and the samples of data are swapped; LIST/INFO, iXML, axml and filler
+// chunks hold no multi-byte numbers and are copied as they are. Other chunks
+// are copied unchanged as well, but warn, if set, is called with their ID
+// since their payload may still be in the old byte order. If src already
+// has the byte order of to, every payload is copied as it is and warn is
+// not called.
+func ConvertOrder(dst io.Writer, src io.Reader, to container.Format, warn func(id chunk.FourCC)) error {
+	s, err := container.NewScanner(src)
+	if err != nil {
+		return err
+	}
+	from := s.Header.Format
+	if from != container.RIFF && from != container.RIFX || to != container.RIFF && to != container.RIFX {
+		return ErrConvertFormat
+	}
+	c := converter{
+		cw:   container.NewWriter(dst, to),
+		from: from.ByteOrder(),
+		to:   to.ByteOrder(),
+		warn: warn,
+	}
+	c.cw.ValidateID = nil
+	if err := c.cw.BeginForm(string(s.Header.Type[:])); err != nil {
+		return err
+	}
+	if err := c.convert(s, ""); err != nil {
+		return err
+	}
+	return c.cw.Close()
+}
+
+// chunkIter is implemented by chunk.Scanner and container.Scanner.
+type chunkIter interface {
+	Next() bool
+	Chunk() *chunk.Reader
+	Err() error
+}
+
+type converter struct {
+	cw       *container.Writer
+	from, to binary.ByteOrder
+	warn     func(id chunk.FourCC)
+	layout   pcm.Layout
+}
+
+// convert copies the chunks of s, which are inside a list of listType.
+func (c *converter) convert(s chunkIter, listType string) error {
+	for s.Next() {
+		ch := s.Chunk()
+		var err error
+		switch id := string(ch.ID[:]); {
+		case container.IsGroup(ch.ID) && ch.Size >= 4:
+			err = c.group(ch)
+		case id == "fmt ":
+			err = c.format(ch)
+		case id == "fact" && c.from != c.to:
+			err = c.swapped(ch, pcm.SwapBytes32)
+		case id == "data" && c.layout.Width > 0:
+			err = c.data(ch)
+		case listType == "INFO", id == "iXML", id == "axml", container.IsFiller(ch.ID):
+			err = container.CopyChunk(c.cw, ch)
+		default:
+			if c.warn != nil && c.from != c.to {
+				c.warn(ch.ID)
+			}
+			err = container.CopyChunk(c.cw, ch)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+func (c *converter) group(ch *chunk.Reader) error {
+	typ, err := ch.ReadFourCC()
+	if err != nil {
+		return err
+	}
+	if err := c.cw.BeginGroup(string(ch.ID[:]), string(typ[:])); err != nil {
+		return err
+	}
+	if err := c.convert(chunk.NewScanner(ch, c.from), string(typ[:])); err != nil {
+		return err
+	}
+	return c.cw.EndChunk()
+}
+
+// format swaps the fields of a fmt chunk and remembers the sample layout of
+// the data chunk; unsupported layouts leave data to be copied with a warning.
+func (c *converter) format(ch *chunk.Reader) error {
+	b, err := readBytes(ch, int64(ch.Size))
+	if err != nil {
+		return err
+	}
+	if len(b) < 16 {
+		return ErrShortChunk
+	}
+	out := bytes.Clone(b)
+	if c.from != c.to {
+		swapFmt(out)
+	}
+	le := b
+	if c.from != binary.LittleEndian {
+		le = bytes.Clone(b)
+		swapFmt(le)
+	}
+	f, err := DecodeFmt(&chunk.Reader{ID: ch.ID, Size: len(le), R: bytes.NewReader(le)})
+	if err != nil {
+		return err
+	}
+	c.layout = f.Layout()
+	c.layout.Order = c.from
+	if c.layout.Validate() != nil {
+		c.layout = pcm.Layout{}
+	}
+	return c.write(ch.ID, out)
+}
+
+// swapFmt swaps the numbers of a fmt payload in place: the common 16 bytes,
+// cbSize and the WAVE_FORMAT_EXTENSIBLE fields including the numeric parts
+// of the SubFormat GUID. Format specific extra bytes are left alone.
+func swapFmt(b []byte) {
+	pcm.SwapBytes16(b[0:4])
+	pcm.SwapBytes32(b[4:12])
+	pcm.SwapBytes16(b[12:16])
+	if len(b) >= 18 {
+		pcm.SwapBytes16(b[16:18])
+	}
+	if len(b) >= 40 {
+		pcm.SwapBytes16(b[18:20])
+		pcm.SwapBytes32(b[20:28])
+		pcm.SwapBytes16(b[28:32])
+	}
+}
+
+// swapped writes ch with its payload passed through swap.
+func (c *converter) swapped(ch *chunk.Reader, swap func([]byte)) error {
+	b, err := readBytes(ch, int64(ch.Size))
+	if err != nil {
+		return err
+	}
+	swap(b)
+	return c.write(ch.ID, b)
+}
+
+// data streams the samples of ch in the new byte order.
+func (c *converter) data(ch *chunk.Reader) error {
+	w, err := c.cw.BeginChunk(string(ch.ID[:]))
+	if err != nil {
+		return err
+	}
+	buf := chunk.GetBuffer()
+	defer chunk.PutBuffer(buf)
+	buf = buf[:len(buf)-len(buf)%c.layout.Width]
+	for !ch.IsFullyRead() {
+		n, err := io.ReadFull(ch, buf[:min(len(buf), ch.Size-ch.Pos)])
+		if n > 0 {
+			if err := pcm.Reorder(buf[:n], c.layout, c.to); err != nil {
+				return err
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return c.cw.EndChunk()
+}
+
+func (c *converter) write(id [4]byte, payload []byte) error {
+	w, err := c.cw.BeginChunk(string(id[:]))
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	return c.cw.EndChunk()
+}
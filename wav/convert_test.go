@@ -0,0 +1,101 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestConvertOrder(t *testing.T) {
+	var src bytes.Buffer
+	cw := container.NewWriter(&src, container.RIFF)
+	cw.BeginForm("WAVE")
+	ch, _ := cw.BeginChunk("fmt ")
+	EncodeFmt(ch, WaveFormat{FormatTag: FormatPCM, Channels: 1, SampleRate: 8000, BitsPerSample: 24})
+	cw.EndChunk()
+	cw.BeginList("INFO")
+	ch, _ = cw.BeginChunk("INAM")
+	ch.Write([]byte("odd"))
+	cw.EndChunk()
+	cw.EndChunk()
+	ch, _ = cw.BeginChunk("cue ")
+	ch.Write([]byte{1, 0, 0, 0})
+	cw.EndChunk()
+	ch, _ = cw.BeginChunk("data")
+	ch.Write([]byte{0x56, 0x34, 0x12, 0xEF, 0xCD, 0xAB})
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var rifx bytes.Buffer
+	var warned []string
+	warn := func(id chunk.FourCC) { warned = append(warned, id.String()) }
+	if err := ConvertOrder(&rifx, bytes.NewReader(src.Bytes()), container.RIFX, warn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warned) != 1 || warned[0] != "cue " {
+		t.Fatalf("expected a warning for cue, got %q", warned)
+	}
+
+	t.Run("swaps framing, fields and samples", func(t *testing.T) {
+		s, err := container.NewScanner(bytes.NewReader(rifx.Bytes()))
+		if err != nil || s.Header.Format != container.RIFX {
+			t.Fatalf("expected RIFX, got %v (%v)", s.Header.Format, err)
+		}
+		payloads := map[string][]byte{}
+		for s.Next() {
+			b, _ := readBytes(s.Chunk(), int64(s.Chunk().Size))
+			payloads[string(s.Chunk().ID[:])] = b
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := payloads["fmt "]; !bytes.Equal(got[:8], []byte{0, 1, 0, 1, 0, 0, 0x1f, 0x40}) {
+			t.Fatalf("unexpected fmt % x", got)
+		}
+		if got := payloads["data"]; !bytes.Equal(got, []byte{0x12, 0x34, 0x56, 0xAB, 0xCD, 0xEF}) {
+			t.Fatalf("unexpected samples % x", got)
+		}
+		if got := payloads["LIST"]; !bytes.Equal(got, []byte("INFOINAM\x00\x00\x00\x03odd\x00")) {
+			t.Fatalf("unexpected list %q", got)
+		}
+	})
+
+	t.Run("converts back", func(t *testing.T) {
+		var back bytes.Buffer
+		if err := ConvertOrder(&back, bytes.NewReader(rifx.Bytes()), container.RIFF, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(back.Bytes(), src.Bytes()) {
+			t.Fatalf("round trip differs\n got % x\nwant % x", back.Bytes(), src.Bytes())
+		}
+	})
+
+	t.Run("copies files already in the target order", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			in   []byte
+			to   container.Format
+		}{
+			{"RIFF", src.Bytes(), container.RIFF},
+			{"RIFX", rifx.Bytes(), container.RIFX},
+		} {
+			var out bytes.Buffer
+			warned = nil
+			if err := ConvertOrder(&out, bytes.NewReader(tc.in), tc.to, warn); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			if !bytes.Equal(out.Bytes(), tc.in) || len(warned) != 0 {
+				t.Fatalf("%s: output differs (warned %q)\n got % x\nwant % x", tc.name, warned, out.Bytes(), tc.in)
+			}
+		}
+	})
+
+	t.Run("rejects other formats", func(t *testing.T) {
+		if err := ConvertOrder(&bytes.Buffer{}, bytes.NewReader(src.Bytes()), container.IFF, nil); err != ErrConvertFormat {
+			t.Fatalf("expected ErrConvertFormat, got %v", err)
+		}
+	})
+}
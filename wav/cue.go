@@ -0,0 +1,93 @@
+package wav
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"slices"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrDuplicateCueID is returned by EncodeCue when two cue points share an
+// ID.
+var ErrDuplicateCueID = errors.New("wav: duplicate cue point ID")
+
+// CuePoint is a marker of a cue chunk.
+type CuePoint struct {
+	ID uint32 `json:"id"`
+	// Position is the play order position; EncodeCue uses SampleOffset
+	// when it is zero.
+	Position uint32 `json:"position"`
+	// DataChunkID is the chunk the marker points into; EncodeCue uses
+	// "data" when it is zero.
+	DataChunkID  chunk.FourCC `json:"dataChunkID"`
+	ChunkStart   uint32       `json:"chunkStart"`
+	BlockStart   uint32       `json:"blockStart"`
+	SampleOffset uint32       `json:"sampleOffset"`
+}
+
+// EncodeCue writes a cue chunk with the points ordered by sample offset.
+// IDs are kept as given, so labels in an adtl list stay attached; points
+// without an ID get the next unused one.
+func EncodeCue(w *chunk.Writer, points []CuePoint) error {
+	points = slices.Clone(points)
+	slices.SortStableFunc(points, func(a, b CuePoint) int {
+		return cmp.Compare(a.SampleOffset, b.SampleOffset)
+	})
+	seen := make(map[uint32]bool, len(points))
+	var next uint32
+	for _, p := range points {
+		if p.ID == 0 {
+			continue
+		}
+		if seen[p.ID] {
+			return ErrDuplicateCueID
+		}
+		seen[p.ID] = true
+		next = max(next, p.ID)
+	}
+	for i := range points {
+		p := &points[i]
+		if p.ID == 0 {
+			next++
+			p.ID = next
+		}
+		if p.Position == 0 {
+			p.Position = p.SampleOffset
+		}
+		if p.DataChunkID == (chunk.FourCC{}) {
+			p.DataChunkID = chunk.FourCC{'d', 'a', 't', 'a'}
+		}
+	}
+	if err := chunk.Write(w, uint32(len(points)), binary.LittleEndian); err != nil {
+		return err
+	}
+	for i := range points {
+		if err := w.WriteStruct(&points[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeCue reads the points of a cue chunk in stored order.
+func DecodeCue(r *chunk.Reader) ([]CuePoint, error) {
+	n, err := chunk.Read[uint32](r, binary.LittleEndian)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n)*24 > int64(r.Size-r.Pos) {
+		return nil, ErrShortChunk
+	}
+	// grow with the data read, the count may be crafted
+	points := make([]CuePoint, 0, min(n, 1024))
+	for range n {
+		var p CuePoint
+		if err := r.ReadStruct(&p); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
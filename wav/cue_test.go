@@ -0,0 +1,50 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodeCue(t *testing.T) {
+	in := []CuePoint{
+		{ID: 7, SampleOffset: 96000},
+		{SampleOffset: 48000},
+		{ID: 2, SampleOffset: 0},
+	}
+	got := encode(t, func(w *chunk.Writer) error { return EncodeCue(w, in) })
+	if len(got) != 4+3*24 {
+		t.Fatalf("expected %d bytes, got %d", 4+3*24, len(got))
+	}
+	points, err := DecodeCue(reader(got))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantIDs := []uint32{2, 8, 7}
+	for i, p := range points {
+		if p.ID != wantIDs[i] {
+			t.Fatalf("point %d: expected ID %d, got %d", i, wantIDs[i], p.ID)
+		}
+		if p.DataChunkID.String() != "data" || p.Position != p.SampleOffset {
+			t.Fatalf("point %d: unexpected defaults %+v", i, p)
+		}
+	}
+	if in[1].ID != 0 {
+		t.Fatal("EncodeCue modified its input")
+	}
+
+	t.Run("rejects duplicate IDs", func(t *testing.T) {
+		dup := []CuePoint{{ID: 1}, {ID: 1, SampleOffset: 5}}
+		err := EncodeCue(&chunk.Writer{W: &bytes.Buffer{}}, dup)
+		if err != ErrDuplicateCueID {
+			t.Fatalf("expected ErrDuplicateCueID, got %v", err)
+		}
+	})
+
+	t.Run("rejects truncated chunks", func(t *testing.T) {
+		if _, err := DecodeCue(reader([]byte{2, 0, 0, 0})); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package wav
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrDate is returned by ParseDate for text it does not recognize as a date.
+var ErrDate = errors.New("wav: unrecognized date")
+
+// ParseDate parses the dates found in bext and INFO ICRD fields, which tools
+// write in many ways: year, month and day separated by '-', '/', '.', ':',
+// '_' or spaces, or run together as yyyymmdd, optionally followed by the
+// time as hh:mm or hh:mm:ss. Partial dates, just the year or year and
+// month, give the start of that period. RFC 3339 times keep their zone;
+// the others are taken to be in loc, nil meaning UTC.
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(strings.TrimRight(s, "\x00"))
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if strings.Trim(s, "0123456789-/.:_ T") != "" || len(fields) == 0 {
+		return time.Time{}, ErrDate
+	}
+	if f := fields[0]; len(f) == 8 {
+		fields = append([]string{f[:4], f[4:6], f[6:]}, fields[1:]...)
+	}
+	v := []int{0, 1, 1, 0, 0, 0}
+	switch len(fields) {
+	case 1, 2, 3, 5, 6:
+	default:
+		return time.Time{}, ErrDate
+	}
+	for i, f := range fields {
+		if i == 0 && len(f) != 4 || i > 0 && len(f) > 2 {
+			return time.Time{}, ErrDate
+		}
+		v[i], _ = strconv.Atoi(f)
+	}
+	t := time.Date(v[0], time.Month(v[1]), v[2], v[3], v[4], v[5], 0, loc)
+	// time.Date normalizes, e.g. February 30 to March 2
+	if t.Month() != time.Month(v[1]) || t.Day() != v[2] || t.Hour() != v[3] || t.Minute() != v[4] || t.Second() != v[5] {
+		return time.Time{}, ErrDate
+	}
+	return t, nil
+}
+
+// Origination parses the origination date and time fields, see ParseDate.
+// A missing time means midnight.
+func (b Bext) Origination(loc *time.Location) (time.Time, error) {
+	s := b.OriginationDate
+	if b.OriginationTime != "" {
+		s += " " + b.OriginationTime
+	}
+	return ParseDate(s, loc)
+}
+
+// DateTag returns an ICRD (creation date) tag for t, written as yyyy-mm-dd
+// as the RIFF specification recommends.
+func DateTag(t time.Time) InfoTag {
+	return InfoTag{ID: chunk.FourCC{'I', 'C', 'R', 'D'}, Value: t.Format("2006-01-02")}
+}
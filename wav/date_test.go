@@ -0,0 +1,59 @@
+package wav
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Run("accepts common notations", func(t *testing.T) {
+		for s, want := range map[string]time.Time{
+			"2024-03-09":          time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+			"2024/03/09":          time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+			"2024:3:9":            time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+			"20240309\x00":        time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+			"2024-03":             time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			"2024":                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			"2024-03-09 14:05":    time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC),
+			"2024-03-09T14:05:30": time.Date(2024, 3, 9, 14, 5, 30, 0, time.UTC),
+			"2024.03.09 14-05-30": time.Date(2024, 3, 9, 14, 5, 30, 0, time.UTC),
+		} {
+			got, err := ParseDate(s, nil)
+			if err != nil || !got.Equal(want) {
+				t.Fatalf("%q: expected %v, got %v, %v", s, want, got, err)
+			}
+		}
+	})
+
+	t.Run("honors zones", func(t *testing.T) {
+		got, err := ParseDate("2024-03-09T14:05:00+01:00", nil)
+		if err != nil || !got.Equal(time.Date(2024, 3, 9, 13, 5, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected time %v, %v", got, err)
+		}
+		loc := time.FixedZone("CET", 3600)
+		if got, _ := ParseDate("2024-03-09 14:05", loc); got.Location() != loc || got.Hour() != 14 {
+			t.Fatalf("unexpected time %v", got)
+		}
+	})
+
+	t.Run("rejects other text", func(t *testing.T) {
+		for _, s := range []string{"", "March 2024", "24-03-09", "2024-02-30", "2024-03-09 14", "2024-03-09 25:00", "2024-003-09"} {
+			if _, err := ParseDate(s, nil); err != ErrDate {
+				t.Fatalf("%q: expected ErrDate, got %v", s, err)
+			}
+		}
+	})
+
+	t.Run("round-trips bext and INFO fields", func(t *testing.T) {
+		want := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+		var b Bext
+		b.SetOrigination(want)
+		if got, err := b.Origination(nil); err != nil || !got.Equal(want) {
+			t.Fatalf("expected %v, got %v, %v", want, got, err)
+		}
+		tag := DateTag(want)
+		if tag.ID.String() != "ICRD" || tag.Value != "2024-03-09" {
+			t.Fatalf("unexpected tag %+v", tag)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/CWBudde/chunk"
+)
+
+// infoIDs are the LIST/INFO text chunks described by their value.
+var infoIDs = []string{
+	"IARL", "IART", "ICMS", "ICMT", "ICOP", "ICRD", "IENG", "IGNR", "IKEY",
+	"IMED", "INAM", "IPRD", "ISBJ", "ISFT", "ISRC", "ISRF", "ITCH", "ITRK",
+}
+
+// maxDescribedText is the number of bytes of a text chunk shown by its
+// description.
+const maxDescribedText = 60
+
+func init() {
+	chunk.RegisterDescriber("fmt ", describeFmt)
+	chunk.RegisterDescriber("bext", describeBext)
+	chunk.RegisterDescriber("cue ", describeCue)
+	chunk.RegisterDescriber("smpl", describeSmpl)
+	chunk.RegisterDescriber("plst", describePlst)
+	chunk.RegisterDescriber("iXML", describeIXML)
+	for _, id := range infoIDs {
+		chunk.RegisterDescriber(id, describeText)
+	}
+}
+
+// describeFmt summarizes a fmt chunk as "PCM 48kHz 24-bit stereo".
+func describeFmt(r *chunk.Reader) (string, error) {
+	f, err := DecodeFmt(r)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	switch tag := f.Tag(); tag {
+	case FormatPCM:
+		parts = append(parts, "PCM")
+	case FormatIEEEFloat:
+		parts = append(parts, "float")
+	case FormatALaw:
+		parts = append(parts, "A-law")
+	case FormatMULaw:
+		parts = append(parts, "µ-law")
+	case FormatExtensible:
+		parts = append(parts, "extensible")
+	default:
+		parts = append(parts, fmt.Sprintf("format 0x%04x", tag))
+	}
+	parts = append(parts, strconv.FormatFloat(float64(f.SampleRate)/1000, 'f', -1, 64)+"kHz")
+	if bits := f.ValidBits; bits != 0 || f.BitsPerSample != 0 {
+		if bits == 0 {
+			bits = f.BitsPerSample
+		}
+		parts = append(parts, fmt.Sprintf("%d-bit", bits))
+	}
+	return strings.Join(append(parts, channelCount(int(f.Channels))), " "), nil
+}
+
+func channelCount(n int) string {
+	switch n {
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	}
+	return fmt.Sprintf("%d channels", n)
+}
+
+// describeBext summarizes a bext chunk by its description, originator and
+// origination time.
+func describeBext(r *chunk.Reader) (string, error) {
+	b, err := DecodeBext(r)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	if b.Description != "" {
+		parts = append(parts, strconv.Quote(b.Description))
+	}
+	if b.Originator != "" {
+		parts = append(parts, "by "+b.Originator)
+	}
+	if at := strings.TrimSpace(b.OriginationDate + " " + b.OriginationTime); at != "" {
+		parts = append(parts, at)
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("version %d", b.Version), nil
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// describeCue counts the points of a cue chunk without decoding them.
+func describeCue(r *chunk.Reader) (string, error) {
+	n, err := chunk.Read[uint32](r, binary.LittleEndian)
+	if err != nil {
+		return "", err
+	}
+	if n == 1 {
+		return "1 cue point", nil
+	}
+	return fmt.Sprintf("%d cue points", n), nil
+}
+
+// describeSmpl summarizes a smpl chunk by its unity note and loop count.
+func describeSmpl(r *chunk.Reader) (string, error) {
+	s, err := DecodeSmpl(r)
+	if err != nil {
+		return "", err
+	}
+	loops := fmt.Sprintf("%d loops", len(s.Loops))
+	if len(s.Loops) == 1 {
+		loops = "1 loop"
+	}
+	return fmt.Sprintf("MIDI note %d, %s", s.MIDIUnityNote, loops), nil
+}
+
+// describePlst summarizes a plst chunk by its segment count.
+func describePlst(r *chunk.Reader) (string, error) {
+	n, err := chunk.Read[uint32](r, binary.LittleEndian)
+	if err != nil {
+		return "", err
+	}
+	if n == 1 {
+		return "1 segment", nil
+	}
+	return fmt.Sprintf("%d segments", n), nil
+}
+
+// describeIXML summarizes an iXML chunk by project, scene and take.
+func describeIXML(r *chunk.Reader) (string, error) {
+	x, err := DecodeIXML(r)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, f := range []struct{ name, value string }{
+		{"project", x.Project}, {"scene", x.Scene}, {"take", x.Take},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.name+" "+f.value)
+		}
+	}
+	if len(parts) == 0 {
+		return strings.TrimSpace("iXML " + x.Version), nil
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// describeText quotes the value of a LIST/INFO text chunk, cut at
+// maxDescribedText bytes.
+func describeText(r *chunk.Reader) (string, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxDescribedText+1))
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimRight(string(b), "\x00")
+	if len(s) > maxDescribedText {
+		return strconv.Quote(s[:maxDescribedText]) + "...", nil
+	}
+	return strconv.Quote(s), nil
+}
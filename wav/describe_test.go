@@ -0,0 +1,68 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// describeEncoded encodes a chunk with enc and describes it.
+func describeEncoded(t *testing.T, id string, enc func(w *chunk.Writer) error) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := enc(&chunk.Writer{W: &buf}); err != nil {
+		t.Fatalf("encode %s: %v", id, err)
+	}
+	return chunk.Describe(&chunk.Reader{ID: [4]byte([]byte(id)), Size: buf.Len(), R: &buf})
+}
+
+func TestDescribe(t *testing.T) {
+	for _, tc := range []struct {
+		name, id string
+		enc      func(w *chunk.Writer) error
+		want     string
+	}{
+		{"PCM fmt", "fmt ", func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatPCM, Channels: 2, SampleRate: 48000, BitsPerSample: 24})
+		}, "PCM 48kHz 24-bit stereo"},
+		{"extensible fmt", "fmt ", func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatIEEEFloat, Channels: 6, SampleRate: 44100, BitsPerSample: 32, ValidBits: 32})
+		}, "float 44.1kHz 32-bit 6 channels"},
+		{"bext", "bext", func(w *chunk.Writer) error {
+			return EncodeBext(w, Bext{Description: "Take 1", Originator: "Recorder", OriginationDate: "2026-10-14", OriginationTime: "10:00:00"})
+		}, `"Take 1" by Recorder 2026-10-14 10:00:00`},
+		{"cue", "cue ", func(w *chunk.Writer) error {
+			return EncodeCue(w, []CuePoint{{ID: 1}, {ID: 2}})
+		}, "2 cue points"},
+		{"smpl", "smpl", func(w *chunk.Writer) error {
+			return EncodeSmpl(w, Sampler{MIDIUnityNote: 60, Loops: []SampleLoop{{End: 100}}})
+		}, "MIDI note 60, 1 loop"},
+		{"plst", "plst", func(w *chunk.Writer) error {
+			return EncodePlst(w, []PlaylistSegment{{CuePointID: 1, Length: 10, Loops: 2}}, nil)
+		}, "1 segment"},
+		{"iXML", "iXML", func(w *chunk.Writer) error {
+			return EncodeIXML(w, IXML{Project: "Film", Scene: "12A", Take: "3"})
+		}, "project Film, scene 12A, take 3"},
+		{"INFO text", "INAM", func(w *chunk.Writer) error {
+			_, err := w.Write([]byte("Take 1\x00"))
+			return err
+		}, `"Take 1"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := describeEncoded(t, tc.id, tc.enc); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("long texts are cut", func(t *testing.T) {
+		got := describeEncoded(t, "ICMT", func(w *chunk.Writer) error {
+			_, err := w.Write(bytes.Repeat([]byte("a"), 100))
+			return err
+		})
+		if want := `"` + string(bytes.Repeat([]byte("a"), maxDescribedText)) + `"...`; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package wav
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/pcm"
+)
+
+// guidSuffix is the common tail of the KSDATAFORMAT_SUBTYPE GUIDs; the first
+// two bytes hold the format tag.
+var guidSuffix = [14]byte{0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+
+// WaveFormat is the content of a fmt chunk.
+type WaveFormat struct {
+	// FormatTag is the tag as stored; FormatExtensible when the extension
+	// is present, see Tag for the effective format.
+	FormatTag     uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+
+	// ValidBits, ChannelMask and SubFormat make up WAVE_FORMAT_EXTENSIBLE.
+	ValidBits   uint16
+	ChannelMask uint32
+	SubFormat   [16]byte
+
+	// Extra holds format specific bytes following cbSize of other formats,
+	// e.g. the ADPCM coefficients.
+	Extra []byte
+}
+
+// fmtBase is the 16 byte layout shared by all fmt chunks.
+type fmtBase struct {
+	FormatTag     uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// fmtExtensible follows cbSize in WAVE_FORMAT_EXTENSIBLE chunks.
+type fmtExtensible struct {
+	ValidBits   uint16
+	ChannelMask uint32
+	SubFormat   [16]byte
+}
+
+// SubFormatGUID returns the KSDATAFORMAT_SUBTYPE GUID of a format tag, e.g.
+// FormatPCM or FormatIEEEFloat.
+func SubFormatGUID(tag uint16) [16]byte {
+	var g [16]byte
+	binary.LittleEndian.PutUint16(g[:], tag)
+	copy(g[2:], guidSuffix[:])
+	return g
+}
+
+// Tag returns the effective format tag, taken from SubFormat for the
+// extensible form.
+func (f WaveFormat) Tag() uint16 {
+	if f.FormatTag == FormatExtensible && [14]byte(f.SubFormat[2:]) == guidSuffix {
+		return binary.LittleEndian.Uint16(f.SubFormat[:])
+	}
+	return f.FormatTag
+}
+
+// Extensible reports whether f needs WAVE_FORMAT_EXTENSIBLE: more than two
+// channels, valid bits that differ from the container size, or an explicit
+// FormatExtensible tag.
+func (f WaveFormat) Extensible() bool {
+	return f.FormatTag == FormatExtensible || f.Channels > 2 ||
+		(f.ValidBits != 0 && f.ValidBits != f.BitsPerSample)
+}
+
+// Layout returns the sample layout of f for the pcm package: little-endian,
+// unsigned at 8 bits and IEEE float for FormatIEEEFloat. The width is taken
+// from BlockAlign, so padded containers such as 20 bits in 3 bytes are
+// described correctly.
+func (f WaveFormat) Layout() pcm.Layout {
+	l := pcm.Layout{Order: binary.LittleEndian, Float: f.Tag() == FormatIEEEFloat}
+	if f.Channels > 0 {
+		l.Width = int(f.BlockAlign / f.Channels)
+	}
+	l.Unsigned = l.Width == 1 && !l.Float
+	return l
+}
+
+// DecodeFmt reads a fmt chunk.
+func DecodeFmt(r *chunk.Reader) (WaveFormat, error) {
+	if r.Size < 16 {
+		return WaveFormat{}, ErrShortChunk
+	}
+	var base fmtBase
+	if err := r.ReadStruct(&base); err != nil {
+		return WaveFormat{}, err
+	}
+	f := WaveFormat{
+		FormatTag:     base.FormatTag,
+		Channels:      base.Channels,
+		SampleRate:    base.SampleRate,
+		ByteRate:      base.ByteRate,
+		BlockAlign:    base.BlockAlign,
+		BitsPerSample: base.BitsPerSample,
+	}
+	if r.Size < 18 {
+		return f, nil
+	}
+	cbSize, err := chunk.Read[uint16](r, binary.LittleEndian)
+	if err != nil {
+		return f, err
+	}
+	cb := min(int(cbSize), r.Size-r.Pos)
+	if f.FormatTag == FormatExtensible && cb >= 22 {
+		var ext fmtExtensible
+		if err := r.ReadStruct(&ext); err != nil {
+			return f, err
+		}
+		f.ValidBits, f.ChannelMask, f.SubFormat = ext.ValidBits, ext.ChannelMask, ext.SubFormat
+		return f, nil
+	}
+	if cb > 0 {
+		f.Extra = make([]byte, cb)
+		if _, err := io.ReadFull(r, f.Extra); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// EncodeFmt writes a fmt chunk. BlockAlign and ByteRate are derived from the
+// other fields when zero. The extensible form is written when f.Extensible
+// reports so; its SubFormat defaults to the GUID of FormatTag and ValidBits
+// to BitsPerSample. Plain PCM is written as the 16 byte PCMWAVEFORMAT, other
+// formats carry a cbSize field.
+func EncodeFmt(w *chunk.Writer, f WaveFormat) error {
+	if f.BlockAlign == 0 {
+		f.BlockAlign = f.Channels * ((f.BitsPerSample + 7) / 8)
+	}
+	if f.ByteRate == 0 {
+		f.ByteRate = f.SampleRate * uint32(f.BlockAlign)
+	}
+	ext := f.Extensible()
+	if ext {
+		if f.SubFormat == ([16]byte{}) {
+			f.SubFormat = SubFormatGUID(f.FormatTag)
+		}
+		if f.ValidBits == 0 {
+			f.ValidBits = f.BitsPerSample
+		}
+		f.FormatTag = FormatExtensible
+	}
+	base := fmtBase{f.FormatTag, f.Channels, f.SampleRate, f.ByteRate, f.BlockAlign, f.BitsPerSample}
+	if err := w.WriteStruct(base); err != nil {
+		return err
+	}
+	switch {
+	case ext:
+		if err := chunk.Write(w, uint16(22), binary.LittleEndian); err != nil {
+			return err
+		}
+		return w.WriteStruct(fmtExtensible{f.ValidBits, f.ChannelMask, f.SubFormat})
+	case f.FormatTag == FormatPCM && len(f.Extra) == 0:
+		return nil
+	}
+	if err := chunk.Write(w, uint16(len(f.Extra)), binary.LittleEndian); err != nil {
+		return err
+	}
+	_, err := w.Write(f.Extra)
+	return err
+}
@@ -0,0 +1,116 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/pcm"
+)
+
+func encode(t *testing.T, fn func(w *chunk.Writer) error) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := fn(&chunk.Writer{W: &buf}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func reader(b []byte) *chunk.Reader {
+	return &chunk.Reader{Size: len(b), R: bytes.NewReader(b)}
+}
+
+func TestEncodeFmt(t *testing.T) {
+	t.Run("PCM", func(t *testing.T) {
+		got := encode(t, func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatPCM, Channels: 2, SampleRate: 44100, BitsPerSample: 16})
+		})
+		want := []byte{
+			1, 0, 2, 0, 0x44, 0xac, 0, 0, 0x10, 0xb1, 0x02, 0, 4, 0, 16, 0,
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", got, want)
+		}
+	})
+
+	t.Run("float carries cbSize", func(t *testing.T) {
+		got := encode(t, func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatIEEEFloat, Channels: 1, SampleRate: 48000, BitsPerSample: 32})
+		})
+		if len(got) != 18 || got[16] != 0 || got[17] != 0 {
+			t.Fatalf("unexpected output % x", got)
+		}
+	})
+
+	t.Run("extensible for more than two channels", func(t *testing.T) {
+		got := encode(t, func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: FormatPCM, Channels: 6, SampleRate: 48000,
+				BitsPerSample: 24, ChannelMask: 0x3f})
+		})
+		if len(got) != 40 {
+			t.Fatalf("expected 40 bytes, got %d", len(got))
+		}
+		f, err := DecodeFmt(reader(got))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.FormatTag != FormatExtensible || f.Tag() != FormatPCM || f.ValidBits != 24 ||
+			f.ChannelMask != 0x3f || f.BlockAlign != 18 || f.ByteRate != 48000*18 {
+			t.Fatalf("unexpected format %+v", f)
+		}
+	})
+
+	t.Run("extensible for differing valid bits", func(t *testing.T) {
+		in := WaveFormat{FormatTag: FormatPCM, Channels: 2, SampleRate: 96000, BitsPerSample: 32, ValidBits: 24}
+		if !in.Extensible() {
+			t.Fatal("expected extensible format")
+		}
+		f, err := DecodeFmt(reader(encode(t, func(w *chunk.Writer) error { return EncodeFmt(w, in) })))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.ValidBits != 24 || f.SubFormat != SubFormatGUID(FormatPCM) {
+			t.Fatalf("unexpected format %+v", f)
+		}
+	})
+}
+
+func TestDecodeFmt(t *testing.T) {
+	t.Run("keeps extra bytes", func(t *testing.T) {
+		in := WaveFormat{FormatTag: 0x0002, Channels: 1, SampleRate: 22050, BlockAlign: 256,
+			ByteRate: 11155, BitsPerSample: 4, Extra: []byte{0xf4, 0x01}}
+		f, err := DecodeFmt(reader(encode(t, func(w *chunk.Writer) error { return EncodeFmt(w, in) })))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(f.Extra, in.Extra) || f.ByteRate != 11155 {
+			t.Fatalf("unexpected format %+v", f)
+		}
+	})
+
+	t.Run("rejects short chunks", func(t *testing.T) {
+		if _, err := DecodeFmt(reader(make([]byte, 14))); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
+
+func TestWaveFormat_Layout(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		f    WaveFormat
+		want pcm.Layout
+	}{
+		{"8-bit", WaveFormat{FormatTag: FormatPCM, Channels: 1, BlockAlign: 1}, pcm.Layout{Width: 1, Order: binary.LittleEndian, Unsigned: true}},
+		{"20 in 24 bits", WaveFormat{FormatTag: FormatExtensible, Channels: 2, BlockAlign: 6, SubFormat: SubFormatGUID(FormatPCM)}, pcm.Layout{Width: 3, Order: binary.LittleEndian}},
+		{"float", WaveFormat{FormatTag: FormatIEEEFloat, Channels: 2, BlockAlign: 8}, pcm.Layout{Width: 4, Order: binary.LittleEndian, Float: true}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.f.Layout(); got != c.want {
+				t.Fatalf("expected %+v, got %+v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package wav
+
+import (
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+func init() {
+	chunk.RegisterFormat(module{})
+}
+
+// module is the chunk.FormatModule of WAVE files in RIFF, RIFX, RF64 and
+// BW64 containers, registered when the package is imported.
+type module struct{}
+
+func (module) Name() string { return "wav" }
+
+func (module) Detect(header []byte) bool {
+	if len(header) < 12 || string(header[8:12]) != "WAVE" {
+		return false
+	}
+	switch string(header[:4]) {
+	case "RIFF", "RIFX", "RF64", "BW64":
+		return true
+	}
+	return false
+}
+
+func (module) NewScanner(r io.Reader) (chunk.ChunkScanner, error) {
+	s, err := container.NewScanner(r)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m module) Describe(header []byte) string {
+	if !m.Detect(header) {
+		return "WAVE audio"
+	}
+	return string(header[:4]) + " WAVE audio"
+}
@@ -0,0 +1,44 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestFormatModule(t *testing.T) {
+	// a small RF64 file is written as RIFF with a JUNK chunk reserving the
+	// ds64 space
+	var buf bytes.Buffer
+	cw := container.NewWriter(&buf, container.RF64)
+	cw.BeginForm("WAVE")
+	ch, _ := cw.BeginChunk("data")
+	ch.Write([]byte("samples"))
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	m, err := chunk.DetectFormat(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name() != "wav" || m.Describe(buf.Bytes()) != "RIFF WAVE audio" {
+		t.Fatalf("unexpected module %s: %s", m.Name(), m.Describe(buf.Bytes()))
+	}
+	s, err := m.NewScanner(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for s.Next() {
+		ids = append(ids, string(s.Chunk().ID[:]))
+	}
+	if s.Err() != nil || len(ids) != 2 || ids[0] != "JUNK" || ids[1] != "data" {
+		t.Fatalf("unexpected chunks %q (%v)", ids, s.Err())
+	}
+	if m.Detect([]byte("RIFF\x00\x00\x00\x00AVI ")) {
+		t.Fatal("AVI detected as WAVE")
+	}
+}
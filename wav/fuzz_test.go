@@ -0,0 +1,30 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+// FuzzDecode feeds every decoder a payload whose declared size may be far
+// larger than the data, as in a crafted or truncated file.
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte("\x01\x00\x02\x00\x44\xac\x00\x00\x10\xb1\x02\x00\x04\x00\x10\x00"), uint32(16))
+	f.Add(make([]byte, 40), uint32(0xffffffff))
+	f.Add([]byte("\x01\x00\x00\x00\xff\xff\xff\xff"), uint32(8))
+	f.Add([]byte("INFOINAM\x03\x00\x00\x00abc\x00"), uint32(16))
+	f.Fuzz(func(t *testing.T, data []byte, size uint32) {
+		decoders := []func(*chunk.Reader) error{
+			func(r *chunk.Reader) error { _, err := DecodeFmt(r); return err },
+			func(r *chunk.Reader) error { _, err := DecodeBext(r); return err },
+			func(r *chunk.Reader) error { _, err := DecodeCue(r); return err },
+			func(r *chunk.Reader) error { _, err := DecodeSmpl(r); return err },
+			func(r *chunk.Reader) error { _, err := DecodeInfo(r); return err },
+			func(r *chunk.Reader) error { _, err := DecodeIXML(r); return err },
+		}
+		for _, decode := range decoders {
+			decode(&chunk.Reader{Size: int(size), R: bytes.NewReader(data)})
+		}
+	})
+}
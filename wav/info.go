@@ -0,0 +1,87 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// ErrNotInfoList is returned by DecodeInfo for LIST chunks of another type.
+var ErrNotInfoList = errors.New("wav: not a LIST/INFO chunk")
+
+// InfoTag is a text entry of a LIST/INFO chunk, e.g. INAM (title), IART
+// (artist) or ICMT (comment).
+type InfoTag struct {
+	ID    chunk.FourCC
+	Value string
+}
+
+// EncodeInfo writes a LIST/INFO group with one NUL terminated sub-chunk per
+// tag, in the given order. Tags with empty values are skipped; pad bytes are
+// added by the container writer.
+func EncodeInfo(cw *container.Writer, tags []InfoTag) error {
+	if err := cw.BeginList("INFO"); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if tag.Value == "" {
+			continue
+		}
+		ch, err := cw.BeginChunk(tag.ID.String())
+		if err != nil {
+			return err
+		}
+		if err := ch.WriteCString(tag.Value); err != nil {
+			return err
+		}
+		if err := cw.EndChunk(); err != nil {
+			return err
+		}
+	}
+	return cw.EndChunk()
+}
+
+// EncodeInfoMap is EncodeInfo for a map of IDs to values, written in sorted
+// ID order so the output is deterministic.
+func EncodeInfoMap(cw *container.Writer, tags map[string]string) error {
+	list := make([]InfoTag, 0, len(tags))
+	for _, id := range slices.Sorted(maps.Keys(tags)) {
+		fcc, err := chunk.ParseFourCC(id)
+		if err != nil {
+			return err
+		}
+		list = append(list, InfoTag{ID: fcc, Value: tags[id]})
+	}
+	return EncodeInfo(cw, list)
+}
+
+// DecodeInfo reads the tags of a LIST chunk whose payload starts with the
+// INFO list type. The values are decoded as AutoCharset unless an option
+// says otherwise.
+func DecodeInfo(r *chunk.Reader, opts ...DecodeOption) ([]InfoTag, error) {
+	cfg := newDecodeConfig(opts)
+	typ, err := r.ReadFourCC()
+	if err != nil {
+		return nil, err
+	}
+	if typ.String() != "INFO" {
+		return nil, ErrNotInfoList
+	}
+	var tags []InfoTag
+	s := chunk.NewScanner(io.LimitReader(r, int64(max(r.Size-r.Pos, 0))), binary.LittleEndian)
+	for s.Next() {
+		ch := s.Chunk()
+		b, err := io.ReadAll(ch)
+		if err != nil {
+			return tags, err
+		}
+		tags = append(tags, InfoTag{ID: chunk.FourCC(ch.ID), Value: cfg.charset.Decode(bytes.TrimRight(b, "\x00"))})
+	}
+	return tags, s.Err()
+}
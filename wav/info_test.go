@@ -0,0 +1,60 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestEncodeInfo(t *testing.T) {
+	var buf bytes.Buffer
+	cw := container.NewWriter(&buf, container.RIFF)
+	cw.BeginForm("WAVE")
+	tags := map[string]string{"INAM": "Take", "IART": "Band", "ICMT": ""}
+	if err := EncodeInfoMap(cw, tags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []byte{
+		'R', 'I', 'F', 'F', 44, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'L', 'I', 'S', 'T', 32, 0, 0, 0, 'I', 'N', 'F', 'O',
+		'I', 'A', 'R', 'T', 5, 0, 0, 0, 'B', 'a', 'n', 'd', 0, 0,
+		'I', 'N', 'A', 'M', 5, 0, 0, 0, 'T', 'a', 'k', 'e', 0, 0,
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+	}
+
+	s, err := container.NewScanner(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.Next() {
+		t.Fatalf("expected LIST chunk: %v", s.Err())
+	}
+	got, err := DecodeInfo(s.Chunk())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID.String() != "IART" || got[0].Value != "Band" || got[1].Value != "Take" {
+		t.Fatalf("unexpected tags %+v", got)
+	}
+
+	t.Run("rejects other lists", func(t *testing.T) {
+		if _, err := DecodeInfo(reader([]byte("adtl"))); err != ErrNotInfoList {
+			t.Fatalf("expected ErrNotInfoList, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid IDs", func(t *testing.T) {
+		cw := container.NewWriter(&bytes.Buffer{}, container.RIFF)
+		cw.BeginForm("WAVE")
+		if err := EncodeInfoMap(cw, map[string]string{"TOOLONG": "x"}); err == nil {
+			t.Fatal("expected error for invalid ID")
+		}
+	})
+}
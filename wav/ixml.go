@@ -0,0 +1,119 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrInvalidUTF8 is returned by EncodeIXMLRaw for input that is not valid
+// UTF-8.
+var ErrInvalidUTF8 = errors.New("wav: iXML must be UTF-8")
+
+// IXML is the commonly used subset of an iXML chunk, the location sound
+// metadata written by field recorders.
+type IXML struct {
+	XMLName   xml.Name    `xml:"BWFXML" json:"-"`
+	Version   string      `xml:"IXML_VERSION,omitempty" json:"version,omitempty"`
+	Project   string      `xml:"PROJECT,omitempty" json:"project,omitempty"`
+	Scene     string      `xml:"SCENE,omitempty" json:"scene,omitempty"`
+	Take      string      `xml:"TAKE,omitempty" json:"take,omitempty"`
+	Tape      string      `xml:"TAPE,omitempty" json:"tape,omitempty"`
+	Circled   bool        `xml:"CIRCLED,omitempty" json:"circled,omitempty"`
+	Note      string      `xml:"NOTE,omitempty" json:"note,omitempty"`
+	FileUID   string      `xml:"FILE_UID,omitempty" json:"fileUID,omitempty"`
+	UBits     string      `xml:"UBITS,omitempty" json:"ubits,omitempty"`
+	Speed     *IXMLSpeed  `xml:"SPEED,omitempty" json:"speed,omitempty"`
+	TrackList *IXMLTracks `xml:"TRACK_LIST,omitempty" json:"trackList,omitempty"`
+}
+
+// IXMLSpeed is the SPEED element of an iXML chunk.
+type IXMLSpeed struct {
+	Note                            string `xml:"NOTE,omitempty" json:"note,omitempty"`
+	MasterSpeed                     string `xml:"MASTER_SPEED,omitempty" json:"masterSpeed,omitempty"`
+	CurrentSpeed                    string `xml:"CURRENT_SPEED,omitempty" json:"currentSpeed,omitempty"`
+	TimecodeRate                    string `xml:"TIMECODE_RATE,omitempty" json:"timecodeRate,omitempty"`
+	TimecodeFlag                    string `xml:"TIMECODE_FLAG,omitempty" json:"timecodeFlag,omitempty"`
+	FileSampleRate                  uint32 `xml:"FILE_SAMPLE_RATE,omitempty" json:"fileSampleRate,omitempty"`
+	AudioBitDepth                   uint16 `xml:"AUDIO_BIT_DEPTH,omitempty" json:"audioBitDepth,omitempty"`
+	TimestampSamplesSinceMidnightLo uint32 `xml:"TIMESTAMP_SAMPLES_SINCE_MIDNIGHT_LO,omitempty" json:"timestampSamplesSinceMidnightLo,omitempty"`
+	TimestampSamplesSinceMidnightHi uint32 `xml:"TIMESTAMP_SAMPLES_SINCE_MIDNIGHT_HI,omitempty" json:"timestampSamplesSinceMidnightHi,omitempty"`
+}
+
+// IXMLTracks is the TRACK_LIST element of an iXML chunk.
+type IXMLTracks struct {
+	Count  int         `xml:"TRACK_COUNT" json:"count"`
+	Tracks []IXMLTrack `xml:"TRACK" json:"tracks"`
+}
+
+// IXMLTrack describes a single channel of the recording.
+type IXMLTrack struct {
+	ChannelIndex    int    `xml:"CHANNEL_INDEX" json:"channelIndex"`
+	InterleaveIndex int    `xml:"INTERLEAVE_INDEX" json:"interleaveIndex"`
+	Name            string `xml:"NAME,omitempty" json:"name,omitempty"`
+	Function        string `xml:"FUNCTION,omitempty" json:"function,omitempty"`
+}
+
+// EncodeIXML writes an iXML chunk from x as indented UTF-8 XML. The track
+// count is taken from the track list.
+func EncodeIXML(w *chunk.Writer, x IXML) error {
+	if x.Version == "" {
+		x.Version = "1.61"
+	}
+	if x.TrackList != nil {
+		tl := *x.TrackList
+		tl.Count = len(tl.Tracks)
+		x.TrackList = &tl
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "\t")
+	if err := enc.Encode(x); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	return EncodeIXMLRaw(w, buf.Bytes())
+}
+
+// EncodeIXMLRaw writes an iXML chunk from a complete XML document, e.g. one
+// read with DecodeIXMLRaw and edited. A trailing newline is appended when
+// needed so the payload has an even length, which some recorders require.
+func EncodeIXMLRaw(w *chunk.Writer, doc []byte) error {
+	if !utf8.Valid(doc) {
+		return ErrInvalidUTF8
+	}
+	if _, err := w.Write(doc); err != nil {
+		return err
+	}
+	if len(doc)%2 == 1 {
+		return w.WriteByte('\n')
+	}
+	return nil
+}
+
+// DecodeIXMLRaw reads the XML document of an iXML chunk, without the NUL or
+// whitespace padding some writers append.
+func DecodeIXMLRaw(r *chunk.Reader) ([]byte, error) {
+	doc, err := io.ReadAll(io.LimitReader(r, int64(max(r.Size-r.Pos, 0))))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(doc, "\x00 \t\r\n"), nil
+}
+
+// DecodeIXML reads an iXML chunk into the typed subset. Unknown elements are
+// ignored; use DecodeIXMLRaw to keep them.
+func DecodeIXML(r *chunk.Reader) (IXML, error) {
+	var x IXML
+	doc, err := DecodeIXMLRaw(r)
+	if err != nil {
+		return x, err
+	}
+	err = xml.Unmarshal(doc, &x)
+	return x, err
+}
@@ -0,0 +1,64 @@
+package wav
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodeIXML(t *testing.T) {
+	in := IXML{
+		Project: "Feature",
+		Scene:   "12A",
+		Take:    "3",
+		Circled: true,
+		Speed:   &IXMLSpeed{FileSampleRate: 48000, AudioBitDepth: 24},
+		TrackList: &IXMLTracks{Tracks: []IXMLTrack{
+			{ChannelIndex: 1, InterleaveIndex: 1, Name: "Boom"},
+			{ChannelIndex: 2, InterleaveIndex: 2, Name: "Lav"},
+		}},
+	}
+	got := encode(t, func(w *chunk.Writer) error { return EncodeIXML(w, in) })
+	if len(got)%2 != 0 {
+		t.Fatalf("expected even payload, got %d bytes", len(got))
+	}
+	if !strings.Contains(string(got), "<TRACK_COUNT>2</TRACK_COUNT>") {
+		t.Fatalf("missing track count in\n%s", got)
+	}
+
+	x, err := DecodeIXML(reader(got))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if x.Scene != "12A" || !x.Circled || x.Version != "1.61" || x.Speed.FileSampleRate != 48000 ||
+		x.TrackList.Count != 2 || x.TrackList.Tracks[1].Name != "Lav" {
+		t.Fatalf("unexpected iXML %+v", x)
+	}
+	if in.TrackList.Count != 0 {
+		t.Fatal("EncodeIXML modified its input")
+	}
+
+	t.Run("raw documents are padded", func(t *testing.T) {
+		doc := []byte("<BWFXML><NOTE>é!</NOTE></BWFXML>")
+		got := encode(t, func(w *chunk.Writer) error { return EncodeIXMLRaw(w, doc) })
+		if len(got) != len(doc)+1 || got[len(doc)] != '\n' {
+			t.Fatalf("unexpected output %q", got)
+		}
+		back, err := DecodeIXMLRaw(reader(append(got, 0, 0)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(back, doc) {
+			t.Fatalf("unexpected document %q", back)
+		}
+	})
+
+	t.Run("rejects invalid UTF-8", func(t *testing.T) {
+		err := EncodeIXMLRaw(&chunk.Writer{W: &bytes.Buffer{}}, []byte{'<', 0xff, '>'})
+		if err != ErrInvalidUTF8 {
+			t.Fatalf("expected ErrInvalidUTF8, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package wav
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrLoudness is returned by LoudnessInfo.Validate for values outside the
+// plausible ranges.
+var ErrLoudness = errors.New("wav: implausible loudness value")
+
+// loudnessUnset marks a loudness field of a bext chunk that was not
+// measured, as EBU Tech 3285 specifies.
+const loudnessUnset = 0x7fff
+
+// LoudnessInfo holds the EBU R128 loudness values of a bext chunk of
+// version 2. NaN marks a value that was not measured; the JSON form of a
+// bext chunk keeps the stored integers instead.
+type LoudnessInfo struct {
+	// Integrated is the integrated loudness in LUFS.
+	Integrated float64
+	// Range is the loudness range in LU.
+	Range float64
+	// TruePeak is the maximum true peak level in dBTP.
+	TruePeak float64
+	// MaxMomentary and MaxShortTerm are the highest momentary (400 ms) and
+	// short-term (3 s) loudness in LUFS.
+	MaxMomentary float64
+	MaxShortTerm float64
+}
+
+// Validate checks that the values are in plausible ranges: loudness
+// between -100 and 10 LUFS, the range between 0 and 100 LU and the true
+// peak between -100 and 20 dBTP. Unmeasured values pass.
+func (l LoudnessInfo) Validate() error {
+	for _, v := range []struct{ v, lo, hi float64 }{
+		{l.Integrated, -100, 10},
+		{l.Range, 0, 100},
+		{l.TruePeak, -100, 20},
+		{l.MaxMomentary, -100, 10},
+		{l.MaxShortTerm, -100, 10},
+	} {
+		if !math.IsNaN(v.v) && (v.v < v.lo || v.v > v.hi) {
+			return ErrLoudness
+		}
+	}
+	return nil
+}
+
+// Loudness returns the loudness values of b, and false if b is older than
+// version 2 and has none.
+func (b Bext) Loudness() (LoudnessInfo, bool) {
+	if b.Version < 2 {
+		return LoudnessInfo{}, false
+	}
+	return LoudnessInfo{
+		Integrated:   fromCentis(b.LoudnessValue),
+		Range:        fromCentis(b.LoudnessRange),
+		TruePeak:     fromCentis(b.MaxTruePeakLevel),
+		MaxMomentary: fromCentis(b.MaxMomentaryLoudness),
+		MaxShortTerm: fromCentis(b.MaxShortTermLoudness),
+	}, true
+}
+
+// SetLoudness validates l and stores it in b, rounded to the hundredths
+// the chunk keeps, raising b.Version to 2 if it is lower.
+func (b *Bext) SetLoudness(l LoudnessInfo) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	b.LoudnessValue = toCentis(l.Integrated)
+	b.LoudnessRange = toCentis(l.Range)
+	b.MaxTruePeakLevel = toCentis(l.TruePeak)
+	b.MaxMomentaryLoudness = toCentis(l.MaxMomentary)
+	b.MaxShortTermLoudness = toCentis(l.MaxShortTerm)
+	b.Version = max(b.Version, 2)
+	return nil
+}
+
+func fromCentis(v int16) float64 {
+	if v == loudnessUnset {
+		return math.NaN()
+	}
+	return float64(v) / 100
+}
+
+func toCentis(v float64) int16 {
+	if math.IsNaN(v) {
+		return loudnessUnset
+	}
+	return int16(math.Round(v * 100))
+}
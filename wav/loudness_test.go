@@ -0,0 +1,58 @@
+package wav
+
+import (
+	"math"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestBext_Loudness(t *testing.T) {
+	t.Run("round-trips through the chunk", func(t *testing.T) {
+		var b Bext
+		l := LoudnessInfo{Integrated: -23.004, Range: 7.5, TruePeak: -1, MaxMomentary: -15.25, MaxShortTerm: math.NaN()}
+		if err := b.SetLoudness(l); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.Version != 2 || b.LoudnessValue != -2300 || b.MaxShortTermLoudness != 0x7fff {
+			t.Fatalf("unexpected fields %+v", b)
+		}
+		d, err := DecodeBext(reader(encode(t, func(w *chunk.Writer) error { return EncodeBext(w, b) })))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := d.Loudness()
+		if !ok || got.Integrated != -23 || got.Range != 7.5 || got.TruePeak != -1 || got.MaxMomentary != -15.25 ||
+			!math.IsNaN(got.MaxShortTerm) {
+			t.Fatalf("unexpected loudness %+v, %v", got, ok)
+		}
+	})
+
+	t.Run("older versions have none", func(t *testing.T) {
+		if _, ok := (Bext{Version: 1, LoudnessValue: -2300}).Loudness(); ok {
+			t.Fatal("expected no loudness for version 1")
+		}
+	})
+
+	t.Run("rejects implausible values", func(t *testing.T) {
+		for _, l := range []LoudnessInfo{
+			{Integrated: 12},
+			{Range: -1},
+			{TruePeak: 30},
+			{MaxShortTerm: -150},
+		} {
+			var b Bext
+			if err := b.SetLoudness(l); err != ErrLoudness {
+				t.Fatalf("%+v: expected ErrLoudness, got %v", l, err)
+			}
+		}
+	})
+
+	t.Run("writes stored values as they are", func(t *testing.T) {
+		b := Bext{Version: 2, LoudnessValue: 3000}
+		d, err := DecodeBext(reader(encode(t, func(w *chunk.Writer) error { return EncodeBext(w, b) })))
+		if err != nil || d.LoudnessValue != 3000 {
+			t.Fatalf("unexpected loudness %d, %v", d.LoudnessValue, err)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package wav
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// Metadata is the metadata of a file decoded from its chunks, for
+// ingestion into asset databases. Fields of chunks the file lacks are left
+// empty and out of its JSON form.
+type Metadata struct {
+	// Info holds the LIST/INFO tags by ID, e.g. INAM or IART.
+	Info    map[string]string `json:"info,omitempty"`
+	Bext    *Bext             `json:"bext,omitempty"`
+	IXML    *IXML             `json:"ixml,omitempty"`
+	Cue     []CuePoint        `json:"cue,omitempty"`
+	Sampler *Sampler          `json:"smpl,omitempty"`
+	// Playlist holds the segments of a plst chunk, referencing Cue.
+	Playlist []PlaylistSegment `json:"plst,omitempty"`
+	// ID3 holds the text frames of an embedded ID3v2 tag by frame ID; see
+	// container.ID3Text.
+	ID3 map[string]string `json:"id3,omitempty"`
+}
+
+// ReadMetadata decodes the metadata chunks of the container in ra: the
+// LIST/INFO tags, bext, iXML, cue, smpl, plst and ID3 chunks at the top
+// level.
+// Other chunks, the audio data included, are not read. Tags of several
+// INFO lists are merged, the last one winning; of the other chunks the
+// first one counts. opts select the charset of the INFO tags.
+func ReadMetadata(ra io.ReaderAt, opts ...DecodeOption) (Metadata, error) {
+	var m Metadata
+	t, err := container.ParseTree(ra, container.TreeOptions{})
+	if err != nil {
+		return m, err
+	}
+	for _, n := range t.Root.Children {
+		if err := m.decode(n, opts); err != nil {
+			return m, fmt.Errorf("wav: %s at %d: %w", n.ID, n.Offset, err)
+		}
+	}
+	return m, nil
+}
+
+// decode adds the metadata of the chunk n.
+func (m *Metadata) decode(n *container.Node, opts []DecodeOption) error {
+	r := n.Chunk()
+	switch id := n.ID.String(); {
+	case id == "LIST" && n.Type.String() == "INFO":
+		tags, err := DecodeInfo(r, opts...)
+		if err != nil {
+			return err
+		}
+		if m.Info == nil {
+			m.Info = make(map[string]string)
+		}
+		for _, tag := range tags {
+			m.Info[tag.ID.String()] = tag.Value
+		}
+	case id == "bext" && m.Bext == nil:
+		b, err := DecodeBext(r)
+		if err != nil {
+			return err
+		}
+		m.Bext = &b
+	case id == "iXML" && m.IXML == nil:
+		x, err := DecodeIXML(r)
+		if err != nil {
+			return err
+		}
+		m.IXML = &x
+	case id == "cue " && m.Cue == nil:
+		points, err := DecodeCue(r)
+		if err != nil {
+			return err
+		}
+		m.Cue = points
+	case id == "smpl" && m.Sampler == nil:
+		s, err := DecodeSmpl(r)
+		if err != nil {
+			return err
+		}
+		m.Sampler = &s
+	case id == "plst" && m.Playlist == nil:
+		segments, err := DecodePlst(r)
+		if err != nil {
+			return err
+		}
+		m.Playlist = segments
+	case container.IsID3(n.ID) && m.ID3 == nil:
+		tag, err := io.ReadAll(n.Payload())
+		if err != nil {
+			return err
+		}
+		if m.ID3, err = container.ID3Text(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyMetadataFile writes m to the file stored at path, see ApplyMetadata.
+// The edit runs as a transaction, so path is left untouched on error.
+func ApplyMetadataFile(path string, m Metadata) error {
+	tx, err := container.Begin(path)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := ApplyMetadata(tx, m); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApplyMetadata queues the edits on tx that write m to its WAVE file, the
+// inverse of ReadMetadata, so tags can be applied in bulk from JSON. The
+// Info tags are merged into the file's ones, an empty value removing a tag,
+// and the INFO lists are replaced by a single one in place of the first.
+// Bext, iXML, cue, smpl and plst chunks set in m replace the first chunk of
+// their ID and drop the others; missing ones are added, bext in front of fmt
+// and the others at the end. Fields left empty keep the chunks of the file,
+// and ID3 tags, being the business of tag libraries, are never written.
+// Playlist segments are checked against Cue if that is set too.
+func ApplyMetadata(tx *container.Tx, m Metadata) error {
+	h, entries, err := container.Index(tx)
+	if err != nil {
+		return err
+	}
+	if h.Type.String() != "WAVE" {
+		return ErrNotWAVE
+	}
+	index := make(map[string]int)
+	var info map[string]string
+	infoAt := -1
+	for i, e := range entries {
+		id := e.ID.String()
+		if _, ok := index[id]; !ok {
+			index[id] = i
+		}
+		if id != "LIST" || m.Info == nil {
+			continue
+		}
+		tags, err := DecodeInfo(section(tx, e))
+		if err == ErrNotInfoList {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("wav: %s at %d: %w", e.ID, e.Offset, err)
+		}
+		if infoAt < 0 {
+			infoAt, info = i, make(map[string]string)
+		}
+		for _, tag := range tags {
+			info[tag.ID.String()] = tag.Value
+		}
+	}
+
+	if m.Info != nil {
+		if info == nil {
+			info = make(map[string]string)
+		}
+		maps.Copy(info, m.Info)
+		maps.DeleteFunc(info, func(_, v string) bool { return v == "" })
+		tx.Remove(container.MatchList("INFO"))
+		if len(info) > 0 {
+			list, err := encodeInfoList(h.Format, info)
+			if err != nil {
+				return err
+			}
+			pos := container.AtEnd
+			if infoAt >= 0 {
+				pos = container.AtIndex(infoAt)
+			}
+			tx.Insert("LIST", bytes.NewReader(list), pos)
+		}
+	}
+
+	chunks := []struct {
+		id     string
+		set    bool
+		encode func(w *chunk.Writer) error
+	}{
+		{"bext", m.Bext != nil, func(w *chunk.Writer) error { return EncodeBext(w, *m.Bext) }},
+		{"iXML", m.IXML != nil, func(w *chunk.Writer) error { return EncodeIXML(w, *m.IXML) }},
+		{"cue ", len(m.Cue) > 0, func(w *chunk.Writer) error { return EncodeCue(w, m.Cue) }},
+		{"smpl", m.Sampler != nil, func(w *chunk.Writer) error { return EncodeSmpl(w, *m.Sampler) }},
+		{"plst", len(m.Playlist) > 0, func(w *chunk.Writer) error { return EncodePlst(w, m.Playlist, m.Cue) }},
+	}
+	for _, c := range chunks {
+		if !c.set {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := c.encode(&chunk.Writer{W: &buf}); err != nil {
+			return fmt.Errorf("wav: %s: %w", c.id, err)
+		}
+		if _, ok := index[c.id]; ok {
+			tx.Replace(c.id, &buf)
+			tx.Remove(container.MatchID(c.id))
+			continue
+		}
+		pos := container.AtEnd
+		if i, ok := index["fmt "]; ok && c.id == "bext" {
+			pos = container.AtIndex(i)
+		}
+		tx.Insert(c.id, &buf, pos)
+	}
+	return nil
+}
+
+// encodeInfoList returns the payload of a LIST/INFO chunk holding tags, in
+// the byte order of format.
+func encodeInfoList(format container.Format, tags map[string]string) ([]byte, error) {
+	if format != container.RIFX {
+		format = container.RIFF
+	}
+	var buf bytes.Buffer
+	cw := container.NewWriter(&buf, format)
+	if err := cw.BeginForm("WAVE"); err != nil {
+		return nil, err
+	}
+	if err := EncodeInfoMap(cw, tags); err != nil {
+		return nil, err
+	}
+	if err := cw.Close(); err != nil {
+		return nil, err
+	}
+	// leave out the form and list headers
+	return buf.Bytes()[20:], nil
+}
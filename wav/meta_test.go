@@ -0,0 +1,167 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/chunktest"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestReadMetadata(t *testing.T) {
+	bext := encode(t, func(w *chunk.Writer) error {
+		return EncodeBext(w, Bext{Description: "Take 1", Originator: "Recorder"})
+	})
+	ixml := encode(t, func(w *chunk.Writer) error { return EncodeIXML(w, IXML{Project: "Film", Scene: "12A"}) })
+	cue := encode(t, func(w *chunk.Writer) error { return EncodeCue(w, []CuePoint{{ID: 1, SampleOffset: 480}}) })
+	smpl := encode(t, func(w *chunk.Writer) error { return EncodeSmpl(w, Sampler{MIDIUnityNote: 60}) })
+	plst := encode(t, func(w *chunk.Writer) error {
+		return EncodePlst(w, []PlaylistSegment{{CuePointID: 1, Length: 960, Loops: 2}}, nil)
+	})
+	id3 := []byte("ID3\x03\x00\x00\x00\x00\x00\x10TIT2\x00\x00\x00\x06\x00\x00\x00Title")
+	f := chunktest.RIFF("WAVE").
+		Chunk("fmt ", make([]byte, 16)).
+		Chunk("bext", bext).
+		List("INFO").Chunk("INAM", "Song\x00").Chunk("IART", "Band\x00").End().
+		Chunk("iXML", ixml).
+		Chunk("cue ", cue).
+		Chunk("smpl", smpl).
+		Chunk("plst", plst).
+		Chunk("id3 ", id3).
+		Chunk("data", make([]byte, 8)).
+		Reader()
+
+	m, err := ReadMetadata(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Info["INAM"] != "Song" || m.Info["IART"] != "Band" || m.Bext == nil || m.Bext.Originator != "Recorder" ||
+		m.IXML == nil || m.IXML.Scene != "12A" || len(m.Cue) != 1 || m.Cue[0].SampleOffset != 480 ||
+		m.Sampler == nil || m.Sampler.MIDIUnityNote != 60 || len(m.Playlist) != 1 || m.Playlist[0].Loops != 2 ||
+		m.ID3["TIT2"] != "Title" {
+		t.Fatalf("unexpected metadata %+v", m)
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{`"INAM":"Song"`, `"originator":"Recorder"`, `"scene":"12A"`, `"sampleOffset":480`, `"midiUnityNote":60`, `"plst":[{"cuePointID":1`, `"TIT2":"Title"`} {
+			if !strings.Contains(string(data), want) {
+				t.Fatalf("%s missing in %s", want, data)
+			}
+		}
+		if data, _ := json.Marshal(Metadata{}); string(data) != "{}" {
+			t.Fatalf("expected empty object, got %s", data)
+		}
+	})
+
+	t.Run("reports broken chunks", func(t *testing.T) {
+		f := chunktest.RIFF("WAVE").Chunk("bext", "short").Reader()
+		if _, err := ReadMetadata(f); err == nil || !strings.Contains(err.Error(), "bext at 12") {
+			t.Fatalf("expected bext error, got %v", err)
+		}
+	})
+}
+
+func TestApplyMetadataFile(t *testing.T) {
+	ixml := encode(t, func(w *chunk.Writer) error { return EncodeIXML(w, IXML{Scene: "12A"}) })
+	setup := func(t *testing.T, b *chunktest.Builder) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "take.wav")
+		if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("writes metadata from JSON", func(t *testing.T) {
+		path := setup(t, chunktest.RIFF("WAVE").
+			Chunk("fmt ", make([]byte, 16)).
+			List("INFO").Chunk("INAM", "Song\x00").Chunk("IART", "Band\x00").End().
+			Chunk("iXML", ixml).
+			Chunk("cue ", "stale").
+			Chunk("data", make([]byte, 8)).
+			Chunk("cue ", "older"))
+		var m Metadata
+		doc := `{"info":{"INAM":"Take 2","IART":"","ICMT":"Remix"},"bext":{"originator":"Tagger"},"cue":[{"id":1,"sampleOffset":96}]}`
+		if err := json.Unmarshal([]byte(doc), &m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ApplyMetadataFile(path, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		f, _ := os.ReadFile(path)
+		_, entries, err := container.Index(bytes.NewReader(f))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []string
+		for _, e := range entries {
+			ids = append(ids, e.ID.String())
+		}
+		if got := strings.Join(ids, ","); got != "bext,fmt ,LIST,iXML,cue ,data" {
+			t.Fatalf("unexpected chunks %s", got)
+		}
+		got, err := ReadMetadata(bytes.NewReader(f))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got.Info) != 2 || got.Info["INAM"] != "Take 2" || got.Info["ICMT"] != "Remix" ||
+			got.Bext == nil || got.Bext.Originator != "Tagger" || got.IXML == nil || got.IXML.Scene != "12A" ||
+			len(got.Cue) != 1 || got.Cue[0].SampleOffset != 96 {
+			t.Fatalf("unexpected metadata %+v", got)
+		}
+	})
+
+	t.Run("adds missing chunks", func(t *testing.T) {
+		path := setup(t, chunktest.RIFF("WAVE").Chunk("fmt ", make([]byte, 16)).Chunk("data", make([]byte, 8)))
+		m := Metadata{Info: map[string]string{"INAM": "Song"}, Sampler: &Sampler{MIDIUnityNote: 60}}
+		if err := ApplyMetadataFile(path, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f, _ := os.ReadFile(path)
+		got, err := ReadMetadata(bytes.NewReader(f))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Info["INAM"] != "Song" || got.Sampler == nil || got.Sampler.MIDIUnityNote != 60 || got.Bext != nil {
+			t.Fatalf("unexpected metadata %+v", got)
+		}
+	})
+
+	t.Run("checks playlists against cue points", func(t *testing.T) {
+		path := setup(t, chunktest.RIFF("WAVE").Chunk("fmt ", make([]byte, 16)).Chunk("data", make([]byte, 8)))
+		m := Metadata{Cue: []CuePoint{{ID: 1}}, Playlist: []PlaylistSegment{{CuePointID: 2, Length: 4}}}
+		if err := ApplyMetadataFile(path, m); !errors.Is(err, ErrUnknownCuePoint) {
+			t.Fatalf("expected ErrUnknownCuePoint, got %v", err)
+		}
+		m.Playlist[0].CuePointID = 1
+		if err := ApplyMetadataFile(path, m); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f, _ := os.ReadFile(path)
+		if got, err := ReadMetadata(bytes.NewReader(f)); err != nil || len(got.Playlist) != 1 || got.Playlist[0].Length != 4 {
+			t.Fatalf("unexpected metadata %+v, %v", got, err)
+		}
+	})
+
+	t.Run("rejects other forms", func(t *testing.T) {
+		path := setup(t, chunktest.FORM("AIFF").Chunk("COMM", make([]byte, 18)))
+		orig, _ := os.ReadFile(path)
+		if err := ApplyMetadataFile(path, Metadata{Info: map[string]string{"INAM": "x"}}); err != ErrNotWAVE {
+			t.Fatalf("expected ErrNotWAVE, got %v", err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, orig) {
+			t.Fatal("expected file to be unchanged")
+		}
+	})
+}
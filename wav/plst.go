@@ -0,0 +1,68 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/CWBudde/chunk"
+)
+
+// ErrUnknownCuePoint is returned by EncodePlst for segments referencing a
+// cue point that is not given.
+var ErrUnknownCuePoint = errors.New("wav: playlist segment references an unknown cue point")
+
+// PlaylistSegment is an entry of a plst chunk: the section of the sample
+// data starting at a cue point, played Loops times in a row.
+type PlaylistSegment struct {
+	// CuePointID is the ID of the cue point the segment starts at.
+	CuePointID uint32 `json:"cuePointID"`
+	// Length is the length of the segment in samples.
+	Length uint32 `json:"length"`
+	Loops  uint32 `json:"loops"`
+}
+
+// EncodePlst writes a plst chunk with the segments in play order. If
+// points is not nil, every segment has to start at one of them.
+func EncodePlst(w *chunk.Writer, segments []PlaylistSegment, points []CuePoint) error {
+	if points != nil {
+		ids := make(map[uint32]bool, len(points))
+		for _, p := range points {
+			ids[p.ID] = true
+		}
+		for _, s := range segments {
+			if !ids[s.CuePointID] {
+				return ErrUnknownCuePoint
+			}
+		}
+	}
+	if err := chunk.Write(w, uint32(len(segments)), binary.LittleEndian); err != nil {
+		return err
+	}
+	for i := range segments {
+		if err := w.WriteStruct(&segments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodePlst reads the segments of a plst chunk in play order.
+func DecodePlst(r *chunk.Reader) ([]PlaylistSegment, error) {
+	n, err := chunk.Read[uint32](r, binary.LittleEndian)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n)*12 > int64(r.Size-r.Pos) {
+		return nil, ErrShortChunk
+	}
+	// grow with the data read, the count may be crafted
+	segments := make([]PlaylistSegment, 0, min(n, 1024))
+	for range n {
+		var s PlaylistSegment
+		if err := r.ReadStruct(&s); err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+	return segments, nil
+}
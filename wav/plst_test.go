@@ -0,0 +1,48 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodePlst(t *testing.T) {
+	in := []PlaylistSegment{
+		{CuePointID: 2, Length: 48000, Loops: 1},
+		{CuePointID: 1, Length: 1000, Loops: 4},
+	}
+	points := []CuePoint{{ID: 1}, {ID: 2, SampleOffset: 48000}}
+	got := encode(t, func(w *chunk.Writer) error { return EncodePlst(w, in, points) })
+	want := []byte{
+		2, 0, 0, 0,
+		2, 0, 0, 0, 0x80, 0xbb, 0, 0, 1, 0, 0, 0,
+		1, 0, 0, 0, 0xe8, 0x03, 0, 0, 4, 0, 0, 0,
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("unexpected payload\n got % x\nwant % x", got, want)
+	}
+	segments, err := DecodePlst(reader(got))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 2 || segments[0] != in[0] || segments[1] != in[1] {
+		t.Fatalf("expected %+v, got %+v", in, segments)
+	}
+
+	t.Run("checks cue point references", func(t *testing.T) {
+		err := EncodePlst(&chunk.Writer{W: &bytes.Buffer{}}, in, points[:1])
+		if err != ErrUnknownCuePoint {
+			t.Fatalf("expected ErrUnknownCuePoint, got %v", err)
+		}
+		if err := EncodePlst(&chunk.Writer{W: &bytes.Buffer{}}, in, nil); err != nil {
+			t.Fatalf("expected no check without points, got %v", err)
+		}
+	})
+
+	t.Run("rejects truncated chunks", func(t *testing.T) {
+		if _, err := DecodePlst(reader([]byte{1, 0, 0, 0, 1, 0})); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
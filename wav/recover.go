@@ -0,0 +1,209 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+var (
+	// ErrNotWAVE is returned by Recover and NewSampleReader for IFF files,
+	// and by ApplyMetadata for forms other than WAVE.
+	ErrNotWAVE = errors.New("wav: not a RIFF, RIFX, RF64 or BW64 file")
+	// ErrNoData is returned by Recover for files without a data chunk, and
+	// by NewSampleReader for those without a wavl list either.
+	ErrNoData = errors.New("wav: no data chunk")
+)
+
+// RecoverOptions configures Recover.
+type RecoverOptions struct {
+	// AddFact appends a fact chunk with the recovered frame count if the
+	// file has none, which some players need for non-PCM formats.
+	AddFact bool
+}
+
+// Recovery describes what Recover did.
+type Recovery struct {
+	// DataSize is the size of the recovered data chunk and Frames the number
+	// of sample frames in it, 0 if the file has no fmt chunk.
+	DataSize, Frames int64
+	// Fixes lists the sizes that changed. For RF64 and BW64 these are the
+	// sizes of the RIFF and data chunk stored in ds64.
+	Fixes     []container.Correction
+	FactAdded bool
+}
+
+// recoverNode is a top-level chunk found by Recover.
+type recoverNode struct {
+	id        chunk.FourCC
+	off, size int64
+}
+
+// Recover makes a truncated recording of size bytes playable in place, such
+// as a WAV or BW64 file whose recorder stopped before patching its headers.
+// The chunks are walked up to the end of the file, or zeroed space, whatever
+// the RIFF size says. A data chunk running past the end, or left with a size
+// of 0 with samples after it, is clamped to the bytes that are really there
+// and a pad byte appended; other chunks cut off are dropped. Then the RIFF
+// size and for RF64/BW64 the ds64 sizes and sample count are rewritten. f is
+// anything opened read-write, such as *os.File.
+func Recover(f container.ReadWriterAt, size int64, opts RecoverOptions) (Recovery, error) {
+	var rec Recovery
+	h, err := container.ReadHeader(io.NewSectionReader(f, 0, size))
+	if err != nil {
+		return rec, err
+	}
+	if h.Format == container.IFF {
+		return rec, ErrNotWAVE
+	}
+	order := h.Format.ByteOrder()
+	rf64 := h.Format == container.RF64 || h.Format == container.BW64
+	off := int64(12)
+	riffSize := h.Size
+	var ds [24]byte
+	if rf64 {
+		var hdr [8]byte
+		if _, err := f.ReadAt(hdr[:], off); err != nil || string(hdr[:4]) != "ds64" {
+			return rec, container.ErrMissingDS64
+		}
+		if _, err := f.ReadAt(ds[:], off+8); err != nil {
+			return rec, container.ErrMissingDS64
+		}
+		riffSize = int64(binary.LittleEndian.Uint64(ds[0:]))
+		n := int64(binary.LittleEndian.Uint32(hdr[4:]))
+		off += 8 + n + n%2
+	}
+
+	var format, data, fact *recoverNode
+	end := off
+	for off+8 <= size {
+		var hdr [8]byte
+		if _, err := f.ReadAt(hdr[:], off); err != nil {
+			return rec, err
+		}
+		n := &recoverNode{off: off, size: int64(order.Uint32(hdr[4:]))}
+		copy(n.id[:], hdr[:4])
+		if !n.id.Valid() {
+			// zeroed space preallocated by the recorder
+			break
+		}
+		declared := n.size
+		switch string(n.id[:]) {
+		case "fmt ":
+			format = n
+		case "fact":
+			fact = n
+		case "data":
+			data = n
+			if rf64 && n.size == math.MaxUint32 {
+				declared = int64(binary.LittleEndian.Uint64(ds[8:]))
+				n.size = declared
+			}
+			if n.size == 0 && !chunkAt(f, off+8, size) {
+				// never patched: the samples run to the end of the file
+				n.size = size
+			}
+		}
+		if avail := size - off - 8; n == data && n.size > avail {
+			rec.Fixes = append(rec.Fixes, container.Correction{Offset: off, ID: n.id, OldSize: declared, NewSize: avail})
+			n.size = avail
+			end = size
+			if avail%2 == 1 {
+				if _, err := f.WriteAt([]byte{0}, end); err != nil {
+					return rec, err
+				}
+				end++
+			}
+			break
+		}
+		// chunks cut off by the end of the file are dropped
+		if off+8+n.size > size {
+			break
+		}
+		off += 8 + n.size + n.size%2
+		end = off
+	}
+	if data == nil {
+		return rec, ErrNoData
+	}
+	rec.DataSize = data.size
+
+	if format != nil {
+		r := &chunk.Reader{ID: format.id, Size: int(format.size), R: io.NewSectionReader(f, format.off+8, format.size)}
+		if wf, err := DecodeFmt(r); err == nil && wf.BlockAlign > 0 {
+			rec.Frames = data.size / int64(wf.BlockAlign)
+		}
+	}
+	if rec.Frames > 0 {
+		frames := uint32(min(rec.Frames, math.MaxUint32))
+		if rf64 {
+			// the count is in ds64
+			frames = math.MaxUint32
+		}
+		switch {
+		case fact != nil && fact.size >= 4:
+			if err := putUint32(f, order, fact.off+8, frames); err != nil {
+				return rec, err
+			}
+		case fact == nil && opts.AddFact:
+			hdr := []byte{'f', 'a', 'c', 't', 0, 0, 0, 0, 0, 0, 0, 0}
+			order.PutUint32(hdr[4:], 4)
+			order.PutUint32(hdr[8:], frames)
+			if _, err := f.WriteAt(hdr, end); err != nil {
+				return rec, err
+			}
+			end += int64(len(hdr))
+			rec.FactAdded = true
+		}
+	}
+
+	if riff := end - 8; riff != riffSize {
+		rec.Fixes = append(rec.Fixes, container.Correction{ID: h.ID, OldSize: riffSize, NewSize: riff})
+	}
+	if !rf64 {
+		if end-8 > math.MaxUint32 {
+			return rec, container.ErrChunkTooLarge
+		}
+		if err := putUint32(f, order, 4, uint32(end-8)); err != nil {
+			return rec, err
+		}
+		return rec, putUint32(f, order, data.off+4, uint32(data.size))
+	}
+	binary.LittleEndian.PutUint64(ds[0:], uint64(end-8))
+	binary.LittleEndian.PutUint64(ds[8:], uint64(data.size))
+	if rec.Frames > 0 {
+		binary.LittleEndian.PutUint64(ds[16:], uint64(rec.Frames))
+	}
+	// the ds64 payload follows its header at offset 12
+	if _, err := f.WriteAt(ds[:], 20); err != nil {
+		return rec, err
+	}
+	if err := putUint32(f, order, 4, math.MaxUint32); err != nil {
+		return rec, err
+	}
+	return rec, putUint32(f, order, data.off+4, math.MaxUint32)
+}
+
+// chunkAt reports whether off is the end of the file or a chunk header with
+// a printable ID starts there.
+func chunkAt(f io.ReaderAt, off, size int64) bool {
+	if off >= size {
+		return true
+	}
+	var id chunk.FourCC
+	if _, err := f.ReadAt(id[:], off); err != nil {
+		return false
+	}
+	return id.Valid()
+}
+
+func putUint32(f io.WriterAt, order binary.ByteOrder, off int64, v uint32) error {
+	var b [4]byte
+	order.PutUint32(b[:], v)
+	_, err := f.WriteAt(b[:], off)
+	return err
+}
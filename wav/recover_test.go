@@ -0,0 +1,146 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recoverFile writes b to a temp file, runs Recover and returns the result
+// and the new file contents.
+func recoverFile(t *testing.T, b []byte, opts RecoverOptions) (Recovery, []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rec.wav")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer f.Close()
+	rec, err := Recover(f, int64(len(b)), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Close()
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return rec, out
+}
+
+func pcmFmt() []byte {
+	b := make([]byte, 24)
+	copy(b, "fmt ")
+	binary.LittleEndian.PutUint32(b[4:], 16)
+	binary.LittleEndian.PutUint16(b[8:], FormatPCM)
+	binary.LittleEndian.PutUint16(b[10:], 1)
+	binary.LittleEndian.PutUint32(b[12:], 8000)
+	binary.LittleEndian.PutUint32(b[16:], 16000)
+	binary.LittleEndian.PutUint16(b[20:], 2)
+	binary.LittleEndian.PutUint16(b[22:], 16)
+	return b
+}
+
+func chunkBytes(id string, size uint32, payload []byte) []byte {
+	b := append([]byte(id), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[4:], size)
+	return append(b, payload...)
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("clamps truncated data", func(t *testing.T) {
+		b := append([]byte("RIFF\x00\x00\x00\x00WAVE"), pcmFmt()...)
+		b = append(b, chunkBytes("data", 1000, []byte{1, 2, 3, 4, 5})...)
+		rec, out := recoverFile(t, b, RecoverOptions{AddFact: true})
+		if rec.DataSize != 5 || rec.Frames != 2 || !rec.FactAdded {
+			t.Fatalf("unexpected recovery %+v", rec)
+		}
+		var got []string
+		for _, c := range rec.Fixes {
+			got = append(got, c.String())
+		}
+		want := []string{"data at 36: size 1000 -> 5", "RIFF at 0: size 0 -> 54"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		wantFile := append([]byte("RIFF\x36\x00\x00\x00WAVE"), pcmFmt()...)
+		wantFile = append(wantFile, chunkBytes("data", 5, []byte{1, 2, 3, 4, 5, 0})...)
+		wantFile = append(wantFile, chunkBytes("fact", 4, []byte{2, 0, 0, 0})...)
+		if !bytes.Equal(out, wantFile) {
+			t.Fatalf("unexpected file\n got % x\nwant % x", out, wantFile)
+		}
+	})
+
+	t.Run("data never patched", func(t *testing.T) {
+		b := append([]byte("RIFF\x00\x00\x00\x00WAVE"), pcmFmt()...)
+		b = append(b, chunkBytes("data", 0, []byte{1, 2, 3, 4})...)
+		rec, out := recoverFile(t, b, RecoverOptions{})
+		if rec.DataSize != 4 || rec.Frames != 2 || rec.FactAdded {
+			t.Fatalf("unexpected recovery %+v", rec)
+		}
+		if binary.LittleEndian.Uint32(out[40:]) != 4 || binary.LittleEndian.Uint32(out[4:]) != 40 {
+			t.Fatalf("unexpected sizes % x", out[:48])
+		}
+	})
+
+	t.Run("keeps chunks after data", func(t *testing.T) {
+		list := chunkBytes("LIST", 12, []byte("INFOINAM\x00\x00\x00\x00"))
+		b := append([]byte("RIFF\x04\x00\x00\x00WAVE"), pcmFmt()...)
+		b = append(b, chunkBytes("data", 3, []byte{1, 2, 3, 0})...)
+		b = append(b, list...)
+		rec, out := recoverFile(t, b, RecoverOptions{})
+		if rec.DataSize != 3 || len(rec.Fixes) != 1 || rec.Fixes[0].NewSize != int64(len(b)-8) {
+			t.Fatalf("unexpected recovery %+v", rec)
+		}
+		if !bytes.Equal(out[8:], b[8:]) {
+			t.Fatalf("chunks changed\n got % x\nwant % x", out, b)
+		}
+	})
+
+	t.Run("drops a cut off chunk", func(t *testing.T) {
+		b := append([]byte("RIFF\x00\x00\x00\x00WAVE"), pcmFmt()...)
+		b = append(b, chunkBytes("data", 2, []byte{1, 2})...)
+		b = append(b, chunkBytes("LIST", 100, []byte("INFO"))...)
+		rec, out := recoverFile(t, b, RecoverOptions{})
+		if len(rec.Fixes) != 1 || binary.LittleEndian.Uint32(out[4:]) != 38 {
+			t.Fatalf("unexpected recovery %+v", rec)
+		}
+	})
+
+	t.Run("updates ds64", func(t *testing.T) {
+		b := []byte("BW64\xff\xff\xff\xffWAVE")
+		b = append(b, chunkBytes("ds64", 28, make([]byte, 28))...)
+		b = append(b, pcmFmt()...)
+		b = append(b, chunkBytes("data", math.MaxUint32, []byte{1, 2, 3, 4, 5, 6})...)
+		rec, out := recoverFile(t, b, RecoverOptions{})
+		if rec.DataSize != 6 || rec.Frames != 3 || len(rec.Fixes) != 2 {
+			t.Fatalf("unexpected recovery %+v", rec)
+		}
+		ds := out[20:]
+		if riff, data, frames := binary.LittleEndian.Uint64(ds), binary.LittleEndian.Uint64(ds[8:]), binary.LittleEndian.Uint64(ds[16:]); riff != uint64(len(b)-8) || data != 6 || frames != 3 {
+			t.Fatalf("unexpected ds64 %d %d %d", riff, data, frames)
+		}
+		if binary.LittleEndian.Uint32(out[4:]) != math.MaxUint32 || binary.LittleEndian.Uint32(out[76:]) != math.MaxUint32 {
+			t.Fatalf("expected 32-bit sizes of -1, got % x", out[:80])
+		}
+	})
+
+	t.Run("requires data", func(t *testing.T) {
+		b := append([]byte("RIFF\x18\x00\x00\x00WAVE"), pcmFmt()...)
+		f, err := os.CreateTemp(t.TempDir(), "*.wav")
+		if err != nil {
+			t.Fatalf("CreateTemp: %v", err)
+		}
+		defer f.Close()
+		f.Write(b)
+		if _, err := Recover(f, int64(len(b)), RecoverOptions{}); err != ErrNoData {
+			t.Fatalf("expected ErrNoData, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package wav
+
+import "github.com/CWBudde/chunk"
+
+// Loop types of a smpl loop.
+const (
+	LoopForward  uint32 = 0
+	LoopPingPong uint32 = 1
+	LoopBackward uint32 = 2
+)
+
+// Sampler is the content of a smpl chunk describing a sampler instrument.
+type Sampler struct {
+	Manufacturer uint32 `json:"manufacturer"`
+	Product      uint32 `json:"product"`
+	// SamplePeriod is the duration of one sample in nanoseconds.
+	SamplePeriod uint32 `json:"samplePeriod"`
+	// MIDIUnityNote is the note played at the original pitch (60 is middle
+	// C); MIDIPitchFraction fine tunes it upwards in 1/2^32 semitones.
+	MIDIUnityNote     uint32       `json:"midiUnityNote"`
+	MIDIPitchFraction uint32       `json:"midiPitchFraction"`
+	SMPTEFormat       uint32       `json:"smpteFormat"`
+	SMPTEOffset       uint32       `json:"smpteOffset"`
+	Loops             []SampleLoop `json:"loops"`
+	// SamplerData holds manufacturer specific bytes after the loop table.
+	SamplerData []byte `json:"samplerData,omitempty"`
+}
+
+// SampleLoop is an entry of the smpl loop table. Start and End are sample
+// frame offsets; End is inclusive.
+type SampleLoop struct {
+	CuePointID uint32 `json:"cuePointID"`
+	Type       uint32 `json:"type"`
+	Start      uint32 `json:"start"`
+	End        uint32 `json:"end"`
+	Fraction   uint32 `json:"fraction"`
+	// PlayCount is the number of repetitions, zero for an endless loop.
+	PlayCount uint32 `json:"playCount"`
+}
+
+// smplHeader is the fixed part of a smpl chunk.
+type smplHeader struct {
+	Manufacturer      uint32
+	Product           uint32
+	SamplePeriod      uint32
+	MIDIUnityNote     uint32
+	MIDIPitchFraction uint32
+	SMPTEFormat       uint32
+	SMPTEOffset       uint32
+	NumSampleLoops    uint32
+	SamplerDataSize   uint32
+}
+
+// EncodeSmpl writes a smpl chunk including its loop table and sampler data.
+func EncodeSmpl(w *chunk.Writer, s Sampler) error {
+	h := smplHeader{
+		Manufacturer:      s.Manufacturer,
+		Product:           s.Product,
+		SamplePeriod:      s.SamplePeriod,
+		MIDIUnityNote:     s.MIDIUnityNote,
+		MIDIPitchFraction: s.MIDIPitchFraction,
+		SMPTEFormat:       s.SMPTEFormat,
+		SMPTEOffset:       s.SMPTEOffset,
+		NumSampleLoops:    uint32(len(s.Loops)),
+		SamplerDataSize:   uint32(len(s.SamplerData)),
+	}
+	if err := w.WriteStruct(&h); err != nil {
+		return err
+	}
+	for i := range s.Loops {
+		if err := w.WriteStruct(&s.Loops[i]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(s.SamplerData)
+	return err
+}
+
+// DecodeSmpl reads a smpl chunk.
+func DecodeSmpl(r *chunk.Reader) (Sampler, error) {
+	if r.Size < 36 {
+		return Sampler{}, ErrShortChunk
+	}
+	var h smplHeader
+	if err := r.ReadStruct(&h); err != nil {
+		return Sampler{}, err
+	}
+	s := Sampler{
+		Manufacturer:      h.Manufacturer,
+		Product:           h.Product,
+		SamplePeriod:      h.SamplePeriod,
+		MIDIUnityNote:     h.MIDIUnityNote,
+		MIDIPitchFraction: h.MIDIPitchFraction,
+		SMPTEFormat:       h.SMPTEFormat,
+		SMPTEOffset:       h.SMPTEOffset,
+	}
+	if int64(h.NumSampleLoops)*24+int64(h.SamplerDataSize) > int64(r.Size-r.Pos) {
+		return s, ErrShortChunk
+	}
+	// grow with the data read, the counts may be crafted
+	s.Loops = make([]SampleLoop, 0, min(h.NumSampleLoops, 1024))
+	for range h.NumSampleLoops {
+		var l SampleLoop
+		if err := r.ReadStruct(&l); err != nil {
+			return s, err
+		}
+		s.Loops = append(s.Loops, l)
+	}
+	if h.SamplerDataSize > 0 {
+		data, err := readBytes(r, int64(h.SamplerDataSize))
+		if err != nil {
+			return s, err
+		}
+		s.SamplerData = data
+	}
+	return s, nil
+}
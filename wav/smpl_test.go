@@ -0,0 +1,43 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+)
+
+func TestEncodeSmpl(t *testing.T) {
+	in := Sampler{
+		SamplePeriod:      20833,
+		MIDIUnityNote:     69,
+		MIDIPitchFraction: 0x80000000,
+		Loops: []SampleLoop{
+			{CuePointID: 1, Type: LoopForward, Start: 100, End: 9999},
+			{CuePointID: 2, Type: LoopPingPong, Start: 10000, End: 20000, PlayCount: 3},
+		},
+		SamplerData: []byte{1, 2, 3},
+	}
+	got := encode(t, func(w *chunk.Writer) error { return EncodeSmpl(w, in) })
+	if len(got) != 36+2*24+3 {
+		t.Fatalf("expected %d bytes, got %d", 36+2*24+3, len(got))
+	}
+	if got[12] != 69 || got[28] != 2 || got[32] != 3 {
+		t.Fatalf("unexpected header % x", got[:36])
+	}
+
+	s, err := DecodeSmpl(reader(got))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.MIDIUnityNote != 69 || s.MIDIPitchFraction != 0x80000000 || len(s.Loops) != 2 ||
+		s.Loops[1] != in.Loops[1] || !bytes.Equal(s.SamplerData, in.SamplerData) {
+		t.Fatalf("unexpected sampler %+v", s)
+	}
+
+	t.Run("rejects truncated loop tables", func(t *testing.T) {
+		if _, err := DecodeSmpl(reader(got[:60])); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
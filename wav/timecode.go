@@ -0,0 +1,204 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrFrameRate is returned for frame rates without frames, and for
+	// drop-frame counting at rates other than 29.97 and 59.94.
+	ErrFrameRate = errors.New("wav: invalid timecode frame rate")
+	// ErrTimecode is returned for timecodes with fields out of range, such
+	// as frames dropped in drop-frame counting.
+	ErrTimecode = errors.New("wav: invalid SMPTE timecode")
+)
+
+// FrameRate is the video frame rate a timecode counts in, as a fraction of
+// frames per second, e.g. 30000/1001 for NTSC.
+type FrameRate struct {
+	Num, Den uint32
+	// DropFrame skips the frame numbers 0 and 1 (0 to 3 at 59.94) at the
+	// start of every minute but each tenth, so 29.97 timecode keeps up with
+	// the clock.
+	DropFrame bool
+}
+
+// Common frame rates.
+var (
+	FPS23976  = FrameRate{Num: 24000, Den: 1001}
+	FPS24     = FrameRate{Num: 24, Den: 1}
+	FPS25     = FrameRate{Num: 25, Den: 1}
+	FPS2997   = FrameRate{Num: 30000, Den: 1001}
+	FPS2997DF = FrameRate{Num: 30000, Den: 1001, DropFrame: true}
+	FPS30     = FrameRate{Num: 30, Den: 1}
+	FPS50     = FrameRate{Num: 50, Den: 1}
+	FPS5994   = FrameRate{Num: 60000, Den: 1001}
+	FPS5994DF = FrameRate{Num: 60000, Den: 1001, DropFrame: true}
+	FPS60     = FrameRate{Num: 60, Den: 1}
+)
+
+// String returns the rate in frames per second, e.g. "29.97 DF".
+func (r FrameRate) String() string {
+	if r.Den == 0 {
+		return fmt.Sprintf("%d/0", r.Num)
+	}
+	s := fmt.Sprintf("%.3f", float64(r.Num)/float64(r.Den))
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	s = strings.TrimSuffix(s, ".")
+	if r.DropFrame {
+		s += " DF"
+	}
+	return s
+}
+
+// nominal returns the frames one timecode second counts and those dropped
+// per minute.
+func (r FrameRate) nominal() (fps, drop int64, err error) {
+	if r.Num == 0 || r.Den == 0 {
+		return 0, 0, ErrFrameRate
+	}
+	fps = int64((uint64(r.Num) + uint64(r.Den)/2) / uint64(r.Den))
+	if fps == 0 {
+		return 0, 0, ErrFrameRate
+	}
+	if r.DropFrame {
+		if fps%30 != 0 || r.Den == 1 {
+			return 0, 0, ErrFrameRate
+		}
+		drop = fps / 15
+	}
+	return fps, drop, nil
+}
+
+// Timecode is a SMPTE time of day in hours, minutes, seconds and frames.
+type Timecode struct {
+	Hours, Minutes, Seconds, Frames int
+	// DropFrame marks drop-frame counting, written with a semicolon before
+	// the frames.
+	DropFrame bool
+}
+
+// String returns tc as HH:MM:SS:FF, or HH:MM:SS;FF for drop-frame.
+func (tc Timecode) String() string {
+	sep := ':'
+	if tc.DropFrame {
+		sep = ';'
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%c%02d", tc.Hours, tc.Minutes, tc.Seconds, sep, tc.Frames)
+}
+
+// ParseTimecode parses a timecode written as HH:MM:SS:FF; a semicolon or
+// period before the frames marks drop-frame counting.
+func ParseTimecode(s string) (Timecode, error) {
+	var tc Timecode
+	if len(s) != 11 || s[2] != ':' || s[5] != ':' {
+		return tc, ErrTimecode
+	}
+	switch s[8] {
+	case ':':
+	case ';', '.':
+		tc.DropFrame = true
+	default:
+		return tc, ErrTimecode
+	}
+	for i, p := range []*int{&tc.Hours, &tc.Minutes, &tc.Seconds, &tc.Frames} {
+		hi, lo := s[3*i], s[3*i+1]
+		if hi < '0' || hi > '9' || lo < '0' || lo > '9' {
+			return tc, ErrTimecode
+		}
+		*p = int(hi-'0')*10 + int(lo-'0')
+	}
+	return tc, nil
+}
+
+// SamplesToTimecode returns the timecode of the frame holding the sample
+// at the given count, e.g. Bext.TimeReference, at sampleRate. Hours wrap at
+// 24 as on a clock.
+func SamplesToTimecode(samples uint64, sampleRate uint32, rate FrameRate) (Timecode, error) {
+	fps, drop, err := rate.nominal()
+	if err != nil {
+		return Timecode{}, err
+	}
+	if sampleRate == 0 {
+		return Timecode{}, ErrFrameRate
+	}
+	// frames = samples * Num / (sampleRate * Den), which overflows 64 bits
+	// for time references far from midnight
+	f := new(big.Int).SetUint64(samples)
+	f.Mul(f, big.NewInt(int64(rate.Num)))
+	f.Quo(f, new(big.Int).Mul(big.NewInt(int64(sampleRate)), big.NewInt(int64(rate.Den))))
+	day := big.NewInt(24 * 3600 * fps)
+	if drop > 0 {
+		day.Sub(day, big.NewInt(24*6*9*drop))
+	}
+	frame := f.Mod(f, day).Int64()
+
+	if drop > 0 {
+		// add back the frame numbers skipped so far
+		perMinute := 60*fps - drop
+		perTen := 10*perMinute + drop
+		tens, rest := frame/perTen, frame%perTen
+		frame += 9 * drop * tens
+		if rest > drop {
+			frame += drop * ((rest - drop) / perMinute)
+		}
+	}
+	return Timecode{
+		Hours:     int(frame / (3600 * fps)),
+		Minutes:   int(frame / (60 * fps) % 60),
+		Seconds:   int(frame / fps % 60),
+		Frames:    int(frame % fps),
+		DropFrame: drop > 0,
+	}, nil
+}
+
+// Samples returns the sample count at sampleRate for the first sample of
+// the frame tc, the inverse of SamplesToTimecode. tc has to use the drop
+// frame counting of rate.
+func (tc Timecode) Samples(sampleRate uint32, rate FrameRate) (uint64, error) {
+	fps, drop, err := rate.nominal()
+	if err != nil {
+		return 0, err
+	}
+	if sampleRate == 0 {
+		return 0, ErrFrameRate
+	}
+	h, m, s, ff := int64(tc.Hours), int64(tc.Minutes), int64(tc.Seconds), int64(tc.Frames)
+	if tc.DropFrame != (drop > 0) || h < 0 || h > 23 || m < 0 || m > 59 || s < 0 || s > 59 || ff < 0 || ff >= fps ||
+		drop > 0 && s == 0 && m%10 != 0 && ff < drop {
+		return 0, ErrTimecode
+	}
+	minutes := 60*h + m
+	frame := (3600*h+60*m+s)*fps + ff - drop*(minutes-minutes/10)
+
+	// round up, so the sample maps back onto the frame
+	n := new(big.Int).Mul(big.NewInt(frame), big.NewInt(int64(sampleRate)))
+	n.Mul(n, big.NewInt(int64(rate.Den)))
+	d := big.NewInt(int64(rate.Num))
+	n.Add(n, d).Sub(n, big.NewInt(1)).Quo(n, d)
+	if !n.IsUint64() {
+		return 0, ErrFrameRate
+	}
+	return n.Uint64(), nil
+}
+
+// Timecode returns the SMPTE timecode of b.TimeReference at the sample rate
+// of the fmt chunk.
+func (b Bext) Timecode(sampleRate uint32, rate FrameRate) (Timecode, error) {
+	return SamplesToTimecode(b.TimeReference, sampleRate, rate)
+}
+
+// SetTimecode sets b.TimeReference to the first sample of the frame tc.
+func (b *Bext) SetTimecode(tc Timecode, sampleRate uint32, rate FrameRate) error {
+	n, err := tc.Samples(sampleRate, rate)
+	if err != nil {
+		return err
+	}
+	b.TimeReference = n
+	return nil
+}
@@ -0,0 +1,102 @@
+package wav
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimecode(t *testing.T) {
+	t.Run("converts time references", func(t *testing.T) {
+		for _, c := range []struct {
+			samples uint64
+			rate    FrameRate
+			want    string
+		}{
+			{TimeReference(10*time.Hour, 48000), FPS25, "10:00:00:00"},
+			{TimeReference(time.Hour+2*time.Second+500*time.Millisecond, 48000), FPS24, "01:00:02:12"},
+			{2882880, FPS2997DF, "00:01:00;02"},
+			{2882879, FPS2997DF, "00:00:59;29"},
+			{28799972, FPS2997DF, "00:10:00;00"},
+			{TimeReference(25*time.Hour, 48000), FPS30, "01:00:00:00"},
+		} {
+			tc, err := SamplesToTimecode(c.samples, 48000, c.rate)
+			if err != nil {
+				t.Fatalf("%d at %v: unexpected error: %v", c.samples, c.rate, err)
+			}
+			if tc.String() != c.want {
+				t.Fatalf("%d at %v: expected %s, got %s", c.samples, c.rate, c.want, tc)
+			}
+		}
+	})
+
+	t.Run("round-trips every rate", func(t *testing.T) {
+		for _, rate := range []FrameRate{FPS23976, FPS24, FPS25, FPS2997, FPS2997DF, FPS30, FPS50, FPS5994, FPS5994DF, FPS60} {
+			for _, frame := range []uint64{0, 1, 1799, 1800, 1801, 17982, 107892, 2589407} {
+				samples := frame * 48000 * uint64(rate.Den) / uint64(rate.Num)
+				tc, err := SamplesToTimecode(samples, 48000, rate)
+				if err != nil {
+					t.Fatalf("%v: unexpected error: %v", rate, err)
+				}
+				back, err := tc.Samples(48000, rate)
+				if err != nil {
+					t.Fatalf("%v %s: unexpected error: %v", rate, tc, err)
+				}
+				if again, _ := SamplesToTimecode(back, 48000, rate); again != tc || back > samples {
+					t.Fatalf("%v: %d became %s, then %d and %s", rate, samples, tc, back, again)
+				}
+			}
+		}
+	})
+
+	t.Run("sets bext time references", func(t *testing.T) {
+		tc, err := ParseTimecode("01:02:03:04")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var b Bext
+		if err := b.SetTimecode(tc, 48000, FPS25); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := TimeReference(time.Hour+2*time.Minute+3*time.Second+160*time.Millisecond, 48000); b.TimeReference != want {
+			t.Fatalf("expected %d, got %d", want, b.TimeReference)
+		}
+		if got, err := b.Timecode(48000, FPS25); err != nil || got != tc {
+			t.Fatalf("expected %s, got %s, %v", tc, got, err)
+		}
+	})
+
+	t.Run("rejects invalid timecodes", func(t *testing.T) {
+		for _, s := range []string{"1:02:03:04", "01:02:03-04", "01:02:03:0x", "01-02:03:04"} {
+			if _, err := ParseTimecode(s); err != ErrTimecode {
+				t.Fatalf("%s: expected ErrTimecode, got %v", s, err)
+			}
+		}
+		for _, s := range []string{"24:00:00:00", "00:60:00:00", "00:00:00:25", "00:01:00;01", "00:00:00;00"} {
+			tc, _ := ParseTimecode(s)
+			if _, err := tc.Samples(48000, FPS25); err != ErrTimecode {
+				t.Fatalf("%s: expected ErrTimecode, got %v", s, err)
+			}
+		}
+		tc, _ := ParseTimecode("00:01:00;01")
+		if _, err := tc.Samples(48000, FPS2997DF); err != ErrTimecode {
+			t.Fatalf("expected ErrTimecode for a dropped frame, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid frame rates", func(t *testing.T) {
+		for _, rate := range []FrameRate{{}, {Num: 1, Den: 1000}, {Num: 25, Den: 1, DropFrame: true}, {Num: 30, Den: 1, DropFrame: true}} {
+			if _, err := SamplesToTimecode(0, 48000, rate); err != ErrFrameRate {
+				t.Fatalf("%v: expected ErrFrameRate, got %v", rate, err)
+			}
+		}
+		if _, err := SamplesToTimecode(0, 0, FPS25); err != ErrFrameRate {
+			t.Fatalf("expected ErrFrameRate for a zero sample rate, got %v", err)
+		}
+	})
+
+	t.Run("formats frame rates", func(t *testing.T) {
+		if FPS2997DF.String() != "29.97 DF" || FPS23976.String() != "23.976" || FPS25.String() != "25" {
+			t.Fatalf("unexpected names %s, %s, %s", FPS2997DF, FPS23976, FPS25)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrUMID is returned for UMIDs that do not start with the SMPTE universal
+// label or whose length byte does not match their content.
+var ErrUMID = errors.New("wav: invalid SMPTE UMID")
+
+// UMID is a SMPTE 330M unique material identifier, as carried by bext
+// chunks of version 1 and later. An extended UMID fills all 64 bytes; a
+// basic UMID the first 32, with the source pack left zero.
+type UMID [64]byte
+
+// umidLabel is the universal label the first 10 bytes of a UMID hold,
+// byte 7 being the registry version.
+var umidLabel = [10]byte{0x06, 0x0a, 0x2b, 0x34, 0x01, 0x01, 0x01, 0x00, 0x01, 0x01}
+
+// Length bytes of basic and extended UMIDs, counting the bytes after them.
+const (
+	umidBasic    = 0x13
+	umidExtended = 0x33
+)
+
+// ParseUMID parses the hex form of a basic or extended UMID, such as the
+// one String returns. Dots, dashes and spaces between the digits are
+// ignored, so the grouped notation of SMPTE 330M is accepted as well.
+func ParseUMID(s string) (UMID, error) {
+	var u UMID
+	s = strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, s)
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 && len(b) != 64 {
+		return u, ErrUMID
+	}
+	copy(u[:], b)
+	if u.Extended() != (len(b) == 64) || u.Validate() != nil {
+		return UMID{}, ErrUMID
+	}
+	return u, nil
+}
+
+// IsZero reports whether u is unset.
+func (u UMID) IsZero() bool {
+	return u == UMID{}
+}
+
+// Extended reports whether u is an extended UMID with a source pack.
+func (u UMID) Extended() bool {
+	return u[12] == umidExtended
+}
+
+// Len returns the length of u in bytes: 32 for basic UMIDs, 64 for
+// extended ones and for invalid ones, which are kept whole, and 0 if u is
+// zero.
+func (u UMID) Len() int {
+	switch {
+	case u.IsZero():
+		return 0
+	case u[12] == umidBasic && u.Validate() == nil:
+		return 32
+	}
+	return 64
+}
+
+// Validate checks the universal label and the length byte of u, and that
+// the source pack of a basic UMID is zero. The zero UMID is valid, as it
+// stands for none.
+func (u UMID) Validate() error {
+	if u.IsZero() {
+		return nil
+	}
+	if !bytes.Equal(u[:7], umidLabel[:7]) || !bytes.Equal(u[8:10], umidLabel[8:]) {
+		return ErrUMID
+	}
+	switch u[12] {
+	case umidExtended:
+	case umidBasic:
+		if [32]byte(u[32:]) != ([32]byte{}) {
+			return ErrUMID
+		}
+	default:
+		return ErrUMID
+	}
+	return nil
+}
+
+// MaterialType returns the material type byte of u, e.g. 0x02 for audio
+// material as SMPTE 330M numbers them.
+func (u UMID) MaterialType() byte {
+	return u[10]
+}
+
+// Instance returns the instance number of u, 0 for the first instance of
+// the material.
+func (u UMID) Instance() uint32 {
+	return uint32(u[13])<<16 | uint32(u[14])<<8 | uint32(u[15])
+}
+
+// Material returns the material number of u, which stays the same across
+// the instances of the material.
+func (u UMID) Material() [16]byte {
+	return [16]byte(u[16:32])
+}
+
+// String returns the first Len bytes of u as lower-case hex, "" if u is
+// zero.
+func (u UMID) String() string {
+	return hex.EncodeToString(u[:u.Len()])
+}
+
+// SetUMID validates u and stores it in b, raising b.Version to 1 if it is
+// lower.
+func (b *Bext) SetUMID(u UMID) error {
+	if err := u.Validate(); err != nil {
+		return err
+	}
+	b.UMID = u
+	b.Version = max(b.Version, 1)
+	return nil
+}
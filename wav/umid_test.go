@@ -0,0 +1,66 @@
+package wav
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUMID(t *testing.T) {
+	const basic = "060a2b340101010501010d2013000000a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	source := "0102030405060708" + strings.Repeat("00", 24)
+
+	t.Run("parses basic UMIDs", func(t *testing.T) {
+		u, err := ParseUMID(strings.ToUpper(basic))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if u.Extended() || u.Len() != 32 || u.String() != basic {
+			t.Fatalf("unexpected UMID %s (%d bytes)", u, u.Len())
+		}
+		if u.MaterialType() != 0x0d || u.Instance() != 0 || u.Material()[0] != 0xa1 {
+			t.Fatalf("unexpected fields %x %d %x", u.MaterialType(), u.Instance(), u.Material())
+		}
+	})
+
+	t.Run("parses extended UMIDs in groups", func(t *testing.T) {
+		ext := strings.Replace(basic, "2013", "2033", 1) + source
+		var grouped []string
+		for i := 0; i < len(ext); i += 8 {
+			grouped = append(grouped, ext[i:i+8])
+		}
+		u, err := ParseUMID(strings.Join(grouped, "."))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !u.Extended() || u.Len() != 64 || u.String() != ext || u[32] != 1 {
+			t.Fatalf("unexpected UMID %s", u)
+		}
+	})
+
+	t.Run("rejects invalid UMIDs", func(t *testing.T) {
+		for _, s := range []string{
+			"",
+			basic[:62],
+			"aa" + basic[2:], // label
+			strings.Replace(basic, "2013", "2020", 1), // length byte
+			strings.Replace(basic, "2013", "2033", 1), // extended without source pack
+			basic + "01" + strings.Repeat("00", 31),   // basic with source pack
+			strings.Replace(basic, "0a2b", "0a2x", 1), // hex
+		} {
+			if _, err := ParseUMID(s); err != ErrUMID {
+				t.Fatalf("%q: expected ErrUMID, got %v", s, err)
+			}
+		}
+	})
+
+	t.Run("zero and broken UMIDs", func(t *testing.T) {
+		var u UMID
+		if !u.IsZero() || u.Validate() != nil || u.Len() != 0 || u.String() != "" {
+			t.Fatalf("unexpected zero UMID %q", u)
+		}
+		u[0], u[63] = 0x06, 0xff
+		if u.Validate() != ErrUMID || u.Len() != 64 || !strings.HasSuffix(u.String(), "ff") {
+			t.Fatalf("expected a broken UMID to be kept whole, got %q", u)
+		}
+	})
+}
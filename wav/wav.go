@@ -0,0 +1,39 @@
+// Package wav encodes and decodes the chunks of RIFF WAVE files: fmt, bext,
+// cue, smpl, LIST/INFO and friends. Encoders write the payload of a chunk
+// opened with container.Writer.BeginChunk; decoders read a *chunk.Reader
+// handed out by a container.Scanner.
+package wav
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Format tags of the fmt chunk.
+const (
+	FormatPCM        uint16 = 0x0001
+	FormatIEEEFloat  uint16 = 0x0003
+	FormatALaw       uint16 = 0x0006
+	FormatMULaw      uint16 = 0x0007
+	FormatExtensible uint16 = 0xFFFE
+)
+
+// ErrShortChunk is returned when a chunk is smaller than its fixed layout.
+var ErrShortChunk = errors.New("wav: chunk too short")
+
+// readBytes reads n bytes from r. The buffer grows with the data actually
+// read instead of being allocated from a declared size up front, so a
+// crafted size cannot exhaust memory.
+func readBytes(r io.Reader, n int64) ([]byte, error) {
+	n = max(n, 0)
+	var buf bytes.Buffer
+	buf.Grow(int(min(n, 64<<10)))
+	if m, err := io.CopyN(&buf, r, n); m < n {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
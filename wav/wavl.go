@@ -0,0 +1,183 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+)
+
+// EncodeSlnt writes a slnt chunk standing for the given number of sample
+// frames of silence in a LIST/wavl group of a RIFF file.
+func EncodeSlnt(w *chunk.Writer, frames uint32) error {
+	return chunk.Write(w, frames, binary.LittleEndian)
+}
+
+// DecodeSlnt reads the number of silent sample frames of a slnt chunk of a
+// RIFF file.
+func DecodeSlnt(r *chunk.Reader) (uint32, error) {
+	return decodeSlnt(r, binary.LittleEndian)
+}
+
+// decodeSlnt is DecodeSlnt for files in the given byte order.
+func decodeSlnt(r *chunk.Reader, order binary.ByteOrder) (uint32, error) {
+	if r.Size < 4 {
+		return 0, ErrShortChunk
+	}
+	return chunk.Read[uint32](r, order)
+}
+
+// SampleReader reads the sample bytes of a WAVE file as one contiguous
+// stream, whether they are stored in a data chunk or in the data and slnt
+// chunks of a LIST/wavl group.
+type SampleReader struct {
+	Format WaveFormat
+	// Size is the number of sample bytes, silence included.
+	Size int64
+
+	r io.Reader
+}
+
+// NewSampleReader returns a SampleReader for the WAVE file in ra. The first
+// data chunk is read if there is one, else the first wavl list, with its
+// slnt chunks played as silence of the format: 0x80 for 8-bit PCM, 0xd5
+// and 0xff for A-law and µ-law, zeros otherwise. The fmt and slnt chunks of
+// RIFX files are read big-endian; the samples are returned as stored.
+func NewSampleReader(ra io.ReaderAt) (*SampleReader, error) {
+	h, entries, err := container.Index(ra)
+	if err != nil {
+		return nil, err
+	}
+	if h.Format == container.IFF {
+		return nil, ErrNotWAVE
+	}
+	var fmtEntry, data, wavl *container.Entry
+	for i, e := range entries {
+		switch e.ID.String() {
+		case "fmt ":
+			if fmtEntry == nil {
+				fmtEntry = &entries[i]
+			}
+		case "data":
+			if data == nil {
+				data = &entries[i]
+			}
+		case "LIST":
+			var typ [4]byte
+			if wavl == nil && e.Size >= 4 {
+				if _, err := ra.ReadAt(typ[:], e.Offset+8); err == nil && string(typ[:]) == "wavl" {
+					wavl = &entries[i]
+				}
+			}
+		}
+	}
+	if fmtEntry == nil {
+		return nil, container.ErrChunkNotFound
+	}
+	order := h.Format.ByteOrder()
+	f, err := decodeFmtOrder(section(ra, *fmtEntry), order)
+	if err != nil {
+		return nil, err
+	}
+	s := &SampleReader{Format: f}
+	switch {
+	case data != nil:
+		s.Size = data.Size
+		s.r = io.NewSectionReader(ra, data.Offset+8, data.Size)
+	case wavl != nil:
+		err = s.alternate(ra, *wavl, order)
+	default:
+		err = ErrNoData
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// alternate chains the chunks of the wavl list at e, whose sizes and slnt
+// counts are stored in order.
+func (s *SampleReader) alternate(ra io.ReaderAt, e container.Entry, order binary.ByteOrder) error {
+	var parts []io.Reader
+	spec := chunk.RIFFHeader
+	spec.Order = order
+	sc := chunk.NewScannerAt(ra, e.Offset+12, e.Size-4, spec)
+	for sc.Next() {
+		ch := sc.Chunk()
+		switch string(ch.ID[:]) {
+		case "data":
+			parts = append(parts, ch)
+			s.Size += int64(ch.Size)
+		case "slnt":
+			frames, err := decodeSlnt(ch, order)
+			if err != nil {
+				return err
+			}
+			n := int64(frames) * int64(s.Format.BlockAlign)
+			parts = append(parts, &silence{n: n, b: silenceByte(s.Format)})
+			s.Size += n
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	s.r = io.MultiReader(parts...)
+	return nil
+}
+
+// decodeFmtOrder is DecodeFmt for fmt chunks in the given byte order, whose
+// fields are swapped first if it is big-endian.
+func decodeFmtOrder(r *chunk.Reader, order binary.ByteOrder) (WaveFormat, error) {
+	if order == binary.LittleEndian {
+		return DecodeFmt(r)
+	}
+	b, err := readBytes(r, int64(r.Size))
+	if err != nil {
+		return WaveFormat{}, err
+	}
+	if len(b) < 16 {
+		return WaveFormat{}, ErrShortChunk
+	}
+	swapFmt(b)
+	return DecodeFmt(&chunk.Reader{ID: r.ID, Size: len(b), R: bytes.NewReader(b)})
+}
+
+// Read implements io.Reader.
+func (s *SampleReader) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+// silenceByte returns the byte value of a silent sample in f.
+func silenceByte(f WaveFormat) byte {
+	switch f.Tag() {
+	case FormatPCM:
+		if f.BitsPerSample <= 8 {
+			return 0x80
+		}
+	case FormatALaw:
+		return 0xd5
+	case FormatMULaw:
+		return 0xff
+	}
+	return 0
+}
+
+// silence reads n bytes of value b.
+type silence struct {
+	n int64
+	b byte
+}
+
+func (s *silence) Read(p []byte) (int, error) {
+	if s.n <= 0 {
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), s.n)]
+	for i := range p {
+		p[i] = s.b
+	}
+	s.n -= int64(len(p))
+	return len(p), nil
+}
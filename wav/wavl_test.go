@@ -0,0 +1,90 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/chunktest"
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestSampleReader(t *testing.T) {
+	fmtChunk := func(tag, bits uint16) []byte {
+		return encode(t, func(w *chunk.Writer) error {
+			return EncodeFmt(w, WaveFormat{FormatTag: tag, Channels: 2, SampleRate: 8000, BitsPerSample: bits})
+		})
+	}
+	slnt := func(frames uint32) []byte {
+		return encode(t, func(w *chunk.Writer) error { return EncodeSlnt(w, frames) })
+	}
+
+	t.Run("reads data chunks", func(t *testing.T) {
+		f := chunktest.RIFF("WAVE").Chunk("fmt ", fmtChunk(FormatPCM, 16)).Chunk("data", "abcd").Reader()
+		s, err := NewSampleReader(f)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b, _ := io.ReadAll(s); string(b) != "abcd" || s.Size != 4 || s.Format.Channels != 2 {
+			t.Fatalf("unexpected samples %q of %d bytes", b, s.Size)
+		}
+	})
+
+	t.Run("fills slnt runs with silence", func(t *testing.T) {
+		for _, c := range []struct {
+			tag, bits uint16
+			silence   byte
+		}{
+			{FormatPCM, 16, 0x00},
+			{FormatPCM, 8, 0x80},
+			{FormatALaw, 8, 0xd5},
+			{FormatMULaw, 8, 0xff},
+		} {
+			f := chunktest.RIFF("WAVE").
+				Chunk("fmt ", fmtChunk(c.tag, c.bits)).
+				List("wavl").Chunk("data", "ab").Chunk("slnt", slnt(3)).Chunk("data", "cde").End().
+				Reader()
+			s, err := NewSampleReader(f)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gap := bytes.Repeat([]byte{c.silence}, 3*int(s.Format.BlockAlign))
+			want := append(append([]byte("ab"), gap...), "cde"...)
+			b, err := io.ReadAll(s)
+			if err != nil || !bytes.Equal(b, want) || s.Size != int64(len(want)) {
+				t.Fatalf("format %d/%d: unexpected samples % x of %d bytes, %v", c.tag, c.bits, b, s.Size, err)
+			}
+		}
+	})
+
+	t.Run("reads RIFX files big-endian", func(t *testing.T) {
+		format := fmtChunk(FormatPCM, 16)
+		swapFmt(format)
+		f := chunktest.RIFX("WAVE").
+			Chunk("fmt ", format).
+			List("wavl").Chunk("data", "ab").Chunk("slnt", uint32(1)).End().
+			Reader()
+		s, err := NewSampleReader(f)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, err := io.ReadAll(s)
+		if err != nil || s.Format.Channels != 2 || string(b) != "ab\x00\x00\x00\x00" || s.Size != 6 {
+			t.Fatalf("unexpected samples % x of %d bytes, %+v, %v", b, s.Size, s.Format, err)
+		}
+	})
+
+	t.Run("reports missing chunks", func(t *testing.T) {
+		if _, err := NewSampleReader(chunktest.RIFF("WAVE").Chunk("fmt ", fmtChunk(FormatPCM, 16)).Reader()); err != ErrNoData {
+			t.Fatalf("expected ErrNoData, got %v", err)
+		}
+		if _, err := NewSampleReader(chunktest.RIFF("WAVE").Chunk("data", "ab").Reader()); err != container.ErrChunkNotFound {
+			t.Fatalf("expected ErrChunkNotFound, got %v", err)
+		}
+		f := chunktest.RIFF("WAVE").Chunk("fmt ", fmtChunk(FormatPCM, 16)).List("wavl").Chunk("slnt", "x").End().Reader()
+		if _, err := NewSampleReader(f); err != ErrShortChunk {
+			t.Fatalf("expected ErrShortChunk, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/CWBudde/chunk"
+	"github.com/CWBudde/chunk/container"
+	"github.com/CWBudde/chunk/pcm"
+)
+
+var (
+	// ErrPartialFrame is returned by WriteFrames when the number of samples
+	// is not a multiple of the channel count.
+	ErrPartialFrame = errors.New("wav: sample count is not a multiple of the channel count")
+	// ErrSampleType is returned by WriteFrames for sample slices that do not
+	// suit the format.
+	ErrSampleType = errors.New("wav: unsupported sample type for format")
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("wav: writer is closed")
+)
+
+// Writer writes a WAVE file: the fmt chunk, a fact chunk for non-PCM
+// formats and a data chunk holding the frames. The sizes are fixed up on
+// Close by seeking when w is an io.WriteSeeker; otherwise the file is
+// buffered in memory and written on Close.
+type Writer struct {
+	cw     *container.Writer
+	format WaveFormat
+	data   *chunk.Writer
+	fact   *container.Reservation
+	buf    []byte
+	closed bool
+}
+
+// NewWriter writes the header of a WAVE file with format f to w and returns
+// a Writer for its sample frames. BlockAlign and ByteRate are derived when
+// zero, as with EncodeFmt.
+func NewWriter(w io.Writer, f WaveFormat) (*Writer, error) {
+	if f.BlockAlign == 0 {
+		f.BlockAlign = f.Channels * ((f.BitsPerSample + 7) / 8)
+	}
+	if f.Channels == 0 || f.BlockAlign == 0 {
+		return nil, ErrSampleType
+	}
+	ww := &Writer{cw: container.NewWriter(w, container.RIFF), format: f}
+	if err := ww.cw.BeginForm("WAVE"); err != nil {
+		return nil, err
+	}
+	ch, err := ww.cw.BeginChunk("fmt ")
+	if err != nil {
+		return nil, err
+	}
+	if err := EncodeFmt(ch, f); err != nil {
+		return nil, err
+	}
+	if err := ww.cw.EndChunk(); err != nil {
+		return nil, err
+	}
+	if f.Tag() != FormatPCM {
+		if ww.fact, err = ww.cw.Reserve("fact", 4); err != nil {
+			return nil, err
+		}
+	}
+	if ww.data, err = ww.cw.BeginChunk("data"); err != nil {
+		return nil, err
+	}
+	return ww, nil
+}
+
+// Format returns the format being written.
+func (ww *Writer) Format() WaveFormat {
+	return ww.format
+}
+
+// Frames returns the number of complete frames written so far.
+func (ww *Writer) Frames() int64 {
+	return int64(ww.data.Size) / int64(ww.format.BlockAlign)
+}
+
+// WriteRaw writes encoded sample bytes to the data chunk as they are.
+func (ww *Writer) WriteRaw(p []byte) (int, error) {
+	if ww.closed {
+		return 0, ErrClosed
+	}
+	return ww.data.Write(p)
+}
+
+// WriteFrames encodes interleaved samples and writes them to the data
+// chunk. Integer formats take []int16 or []int32 holding values in the
+// range of BitsPerSample (8-bit samples are signed here and stored offset),
+// or []float32/[]float64 in [-1, 1] which are scaled and clipped. IEEE
+// float formats take []float32 or []float64.
+func (ww *Writer) WriteFrames(samples any) error {
+	if ww.closed {
+		return ErrClosed
+	}
+	var err error
+	ww.buf, err = appendSamples(ww.buf[:0], samples, ww.format)
+	if err != nil {
+		return err
+	}
+	_, err = ww.data.Write(ww.buf)
+	return err
+}
+
+// Close ends the data chunk, fills in the fact chunk and writes the
+// remaining sizes. It does not close the underlying writer.
+func (ww *Writer) Close() error {
+	if ww.closed {
+		return nil
+	}
+	ww.closed = true
+	if err := ww.cw.EndChunk(); err != nil {
+		return err
+	}
+	if ww.fact != nil {
+		var n [4]byte
+		binary.LittleEndian.PutUint32(n[:], uint32(ww.Frames()))
+		if err := ww.fact.Fill(n[:]); err != nil {
+			return err
+		}
+	}
+	return ww.cw.Close()
+}
+
+// appendSamples encodes interleaved samples in the layout of f.
+func appendSamples(dst []byte, samples any, f WaveFormat) ([]byte, error) {
+	l := f.Layout()
+	if l.Validate() != nil {
+		return dst, ErrSampleType
+	}
+	switch s := samples.(type) {
+	case []int16:
+		return appendFrames(dst, s, f, pcm.AppendInt16s)
+	case []int32:
+		return appendFrames(dst, s, f, pcm.AppendInt32s)
+	case []float32:
+		return appendFrames(dst, s, f, pcm.AppendFloat32s)
+	case []float64:
+		return appendFrames(dst, s, f, pcm.AppendFloat64s)
+	}
+	return dst, ErrSampleType
+}
+
+func appendFrames[T any](dst []byte, s []T, f WaveFormat, enc func([]byte, []T, pcm.Layout) ([]byte, error)) ([]byte, error) {
+	if len(s)%int(f.Channels) != 0 {
+		return dst, ErrPartialFrame
+	}
+	dst, err := enc(dst, s, f.Layout())
+	if err != nil {
+		return dst, ErrSampleType
+	}
+	return dst, nil
+}
@@ -0,0 +1,114 @@
+package wav
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CWBudde/chunk/container"
+)
+
+func TestWriter(t *testing.T) {
+	pcm := WaveFormat{FormatTag: FormatPCM, Channels: 2, SampleRate: 8000, BitsPerSample: 16}
+
+	t.Run("PCM", func(t *testing.T) {
+		var buf bytes.Buffer
+		ww, err := NewWriter(&buf, pcm)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ww.WriteFrames([]int16{1, -1, 256, -256}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ww.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []byte{
+			'R', 'I', 'F', 'F', 44, 0, 0, 0, 'W', 'A', 'V', 'E',
+			'f', 'm', 't', ' ', 16, 0, 0, 0, 1, 0, 2, 0, 0x40, 0x1f, 0, 0, 0, 0x7d, 0, 0, 4, 0, 16, 0,
+			'd', 'a', 't', 'a', 8, 0, 0, 0, 1, 0, 0xff, 0xff, 0, 1, 0, 0xff,
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("unexpected output\n got % x\nwant % x", buf.Bytes(), want)
+		}
+		if ww.Frames() != 2 {
+			t.Fatalf("expected 2 frames, got %d", ww.Frames())
+		}
+	})
+
+	t.Run("float with fact chunk on a seekable file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out.wav")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		ww, err := NewWriter(f, WaveFormat{FormatTag: FormatIEEEFloat, Channels: 1, SampleRate: 48000, BitsPerSample: 32})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ww.WriteFrames([]float32{0, 0.5, -0.5})
+		ww.WriteFrames([]float64{1})
+		if err := ww.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := container.NewScanner(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sizes := map[string]int{}
+		for s.Next() {
+			ch := s.Chunk()
+			sizes[string(ch.ID[:])] = ch.Size
+			if string(ch.ID[:]) == "fact" {
+				var n uint32
+				ch.ReadLE(&n)
+				if n != 4 {
+					t.Fatalf("expected fact sample count 4, got %d", n)
+				}
+			}
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sizes["fmt "] != 18 || sizes["fact"] != 4 || sizes["data"] != 16 {
+			t.Fatalf("unexpected chunk sizes %v", sizes)
+		}
+		if s.Header.Size != int64(len(data)-8) {
+			t.Fatalf("RIFF size %d does not match file size %d", s.Header.Size, len(data))
+		}
+	})
+
+	t.Run("scales floats for 24-bit PCM", func(t *testing.T) {
+		var buf bytes.Buffer
+		ww, _ := NewWriter(&buf, WaveFormat{FormatTag: FormatPCM, Channels: 1, SampleRate: 48000, BitsPerSample: 24})
+		ww.WriteFrames([]float64{1, -2})
+		ww.WriteFrames([]int32{-8388607})
+		ww.Close()
+		got := buf.Bytes()[44:53] // followed by the pad byte
+		want := []byte{0xff, 0xff, 0x7f, 0x01, 0x00, 0x80, 0x01, 0x00, 0x80}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("unexpected samples % x, want % x", got, want)
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		ww, _ := NewWriter(&bytes.Buffer{}, pcm)
+		if err := ww.WriteFrames([]int16{1, 2, 3}); err != ErrPartialFrame {
+			t.Fatalf("expected ErrPartialFrame, got %v", err)
+		}
+		if err := ww.WriteFrames([]uint8{1, 2}); err != ErrSampleType {
+			t.Fatalf("expected ErrSampleType, got %v", err)
+		}
+		ww.Close()
+		if _, err := ww.WriteRaw([]byte{0, 0, 0, 0}); err != ErrClosed {
+			t.Fatalf("expected ErrClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Writer is a struct representing a data chunk being written. Its writer is
+// shared with the container but convenience methods are provided. Size counts
+// the payload bytes written so far; the container fills in the header.
+type Writer struct {
+	ID   [4]byte
+	Size int
+	W    io.Writer
+
+	scratch [8]byte
+}
+
+// Write implements the io.Writer interface.
+func (ch *Writer) Write(p []byte) (n int, err error) {
+	if ch == nil || ch.W == nil {
+		return 0, errors.New("nil Writer/writer pointer")
+	}
+	n, err = ch.W.Write(p)
+	ch.Size += n
+	return n, err
+}
+
+// WriteLE writes the passed data in Little Endian byte order
+func (ch *Writer) WriteLE(src any) error {
+	return ch.writeWithByteOrder(src, binary.LittleEndian)
+}
+
+// WriteBE writes the passed data in Big Endian byte order
+func (ch *Writer) WriteBE(src any) error {
+	return ch.writeWithByteOrder(src, binary.BigEndian)
+}
+
+// WriteByte writes a single byte
+func (ch *Writer) WriteByte(c byte) error {
+	_, err := ch.Write([]byte{c})
+	return err
+}
+
+func (ch *Writer) writeWithByteOrder(src any, byteOrder binary.ByteOrder) error {
+	if ch == nil || ch.W == nil {
+		return errors.New("nil Writer/writer pointer")
+	}
+	if err := binary.Write(ch.W, byteOrder, src); err != nil {
+		return err
+	}
+	ch.Size += binary.Size(src)
+	return nil
+}
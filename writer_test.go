@@ -0,0 +1,89 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriter_Write(t *testing.T) {
+	t.Run("writes data and advances size", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+
+		n, err := w.Write([]byte("hello"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 5 {
+			t.Fatalf("expected n=5, got %d", n)
+		}
+		if w.Size != 5 {
+			t.Fatalf("expected Size=5, got %d", w.Size)
+		}
+		if buf.String() != "hello" {
+			t.Fatalf("expected 'hello', got %q", buf.String())
+		}
+	})
+
+	t.Run("nil writer returns error", func(t *testing.T) {
+		w := &Writer{}
+		if _, err := w.Write([]byte{1}); err == nil {
+			t.Fatal("expected error for nil writer")
+		}
+	})
+
+	t.Run("nil Writer pointer returns error", func(t *testing.T) {
+		var w *Writer
+		if _, err := w.Write([]byte{1}); err == nil {
+			t.Fatal("expected error for nil Writer pointer")
+		}
+	})
+}
+
+func TestWriter_WriteLE(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	if err := w.WriteLE(uint16(0x0102)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), []byte{0x02, 0x01}) {
+		t.Fatalf("expected 02 01, got % x", buf.Bytes())
+	}
+	if w.Size != 2 {
+		t.Fatalf("expected Size=2, got %d", w.Size)
+	}
+}
+
+func TestWriter_WriteBE(t *testing.T) {
+	var buf bytes.Buffer
+	w := &Writer{W: &buf}
+	if err := w.WriteBE(uint32(0x01020304)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), []byte{0x01, 0x02, 0x03, 0x04}) {
+		t.Fatalf("expected 01 02 03 04, got % x", buf.Bytes())
+	}
+	if w.Size != 4 {
+		t.Fatalf("expected Size=4, got %d", w.Size)
+	}
+}
+
+func TestWriter_WriteByte(t *testing.T) {
+	t.Run("writes single byte", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := &Writer{W: &buf}
+		if err := w.WriteByte(0xAA); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if w.Size != 1 || buf.Bytes()[0] != 0xAA {
+			t.Fatalf("expected single 0xAA, got % x", buf.Bytes())
+		}
+	})
+
+	t.Run("nil writer returns error", func(t *testing.T) {
+		var w *Writer
+		if err := w.WriteByte(0); err == nil {
+			t.Fatal("expected error for nil Writer pointer")
+		}
+	})
+}